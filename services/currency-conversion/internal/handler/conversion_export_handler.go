@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// exportDateLayout is the format of the export's from and to dates
+const exportDateLayout = "2006-01-02"
+
+// ExportConversions handles GET /api/v1/currency/conversions/export?from=2024-01-01&to=2024-01-31&format=csv.
+// Both dates are inclusive. The CSV is streamed as it is read, so a failure part way
+// through ends the response early rather than changing its status.
+func (h *CurrencyHandler) ExportConversions(c *gin.Context) {
+	if format := c.DefaultQuery("format", "csv"); format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported export format %q", format), "code": codeInvalidRequest})
+		return
+	}
+
+	start, err := time.Parse(exportDateLayout, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format", "code": codeInvalidRequest})
+		return
+	}
+	end, err := time.Parse(exportDateLayout, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format", "code": codeInvalidRequest})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from", "code": codeInvalidRequest})
+		return
+	}
+
+	filename := fmt.Sprintf("conversions_%s_%s.csv", start.Format(exportDateLayout), end.Format(exportDateLayout))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	err = h.service.ExportConversionsCSV(c.Request.Context(), c.Writer, start, end.AddDate(0, 0, 1), c.Writer.Flush)
+	// A client that disconnects mid-download is not an export failure
+	if err != nil && c.Request.Context().Err() == nil {
+		h.logger.Error("conversion export failed",
+			zap.String("from", start.Format(exportDateLayout)),
+			zap.String("to", end.Format(exportDateLayout)),
+			zap.Error(err))
+	}
+}
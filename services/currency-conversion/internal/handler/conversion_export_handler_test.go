@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+	"currency-conversion/internal/service"
+	"shared/pkg/money"
+)
+
+// conversionHistory is a RateStore holding a fixed conversion history
+type conversionHistory []*models.Conversion
+
+func (h conversionHistory) SaveRate(ctx context.Context, rate *models.ExchangeRate) error {
+	return nil
+}
+
+func (h conversionHistory) GetLatestRate(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	return nil, errors.New("no rate")
+}
+
+func (h conversionHistory) GetRateHistory(ctx context.Context, from, to string, startDate time.Time) ([]*models.ExchangeRate, error) {
+	return nil, nil
+}
+
+func (h conversionHistory) GetRateAt(ctx context.Context, from, to string, at time.Time) (*models.ExchangeRate, error) {
+	return nil, nil
+}
+
+func (h conversionHistory) SaveConversion(ctx context.Context, conversion *models.Conversion) error {
+	return nil
+}
+
+func (h conversionHistory) StreamConversions(ctx context.Context, start, end time.Time, fn func(*models.Conversion) error) error {
+	for _, conversion := range h {
+		if conversion.CreatedAt.Before(start) || !conversion.CreatedAt.Before(end) {
+			continue
+		}
+		if err := fn(conversion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h conversionHistory) ListConversions(ctx context.Context, filter models.ConversionFilter) ([]*models.Conversion, error) {
+	conversions := []*models.Conversion{}
+	for _, conversion := range h {
+		if filter.FromCurrency != "" && conversion.FromCurrency != filter.FromCurrency {
+			continue
+		}
+		if filter.ToCurrency != "" && conversion.ToCurrency != filter.ToCurrency {
+			continue
+		}
+		conversions = append(conversions, conversion)
+	}
+	return conversions, nil
+}
+
+func (h conversionHistory) CountConversions(ctx context.Context, filter models.ConversionFilter) (int, error) {
+	conversions, err := h.ListConversions(ctx, filter)
+	return len(conversions), err
+}
+
+func (h conversionHistory) ClaimConversionIdempotencyKey(ctx context.Context, record *models.ConversionIdempotencyRecord) (bool, error) {
+	return true, nil
+}
+
+func (h conversionHistory) GetConversionByIdempotencyKey(ctx context.Context, key string) (*models.ConversionIdempotencyRecord, error) {
+	return nil, nil
+}
+
+func TestExportConversionsCSV(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	history := conversionHistory{
+		{
+			ID:              "conv_1",
+			FromCurrency:    "USD",
+			ToCurrency:      "EUR",
+			OriginalAmount:  100,
+			ConvertedAmount: 91.54,
+			ExchangeRate:    money.NewDecimal(0.92),
+			Fee:             0.46,
+			CreatedAt:       time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC),
+		},
+		{
+			ID:        "conv_2",
+			CreatedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	h := NewCurrencyHandler(service.NewExchangeService(history, nil, "", zap.NewNop()), zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/currency/conversions/export?from=2024-03-01&to=2024-03-31&format=csv", nil)
+
+	h.ExportConversions(c)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/csv") {
+		t.Errorf("Content-Type = %q, want text/csv", got)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"conversion_id", "created_at", "from_currency", "to_currency", "original_amount", "converted_amount", "exchange_rate", "fee"},
+		{"conv_1", "2024-03-31T23:59:00Z", "USD", "EUR", "100.0000", "91.5400", "0.9200000000", "0.4600"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d CSV records, want %d: %v", len(records), len(want), records)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("record %d = %v, want %v", i, records[i], want[i])
+		}
+	}
+}
+
+func TestExportConversionsRejectsBadParameters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"Unsupported format", "from=2024-03-01&to=2024-03-31&format=xlsx"},
+		{"Missing from", "to=2024-03-31"},
+		{"Malformed to", "from=2024-03-01&to=31/03/2024"},
+		{"Reversed period", "from=2024-03-31&to=2024-03-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/currency/conversions/export?"+tt.query, nil)
+
+			h.ExportConversions(c)
+
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if code := decodeError(t, rec); code != codeInvalidRequest {
+				t.Errorf("code = %q, want %q", code, codeInvalidRequest)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+const (
+	defaultConversionsLimit = 50
+	maxConversionsLimit     = 500
+)
+
+// ListConversions handles GET /api/v1/currency/conversions?from=EUR&to=USD&limit=50&offset=0
+func (h *CurrencyHandler) ListConversions(c *gin.Context) {
+	filter, err := parseConversionFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+
+	page, err := h.service.ListConversions(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list conversions")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// parseConversionFilter reads the from, to, limit and offset query parameters
+func parseConversionFilter(c *gin.Context) (models.ConversionFilter, error) {
+	filter := models.ConversionFilter{
+		FromCurrency: money.NormalizeCurrency(c.Query("from")),
+		ToCurrency:   money.NormalizeCurrency(c.Query("to")),
+		Limit:        defaultConversionsLimit,
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxConversionsLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxConversionsLimit)
+		}
+		filter.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return filter, errors.New("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,224 @@
+// services/currency-conversion/internal/handler/currency_handler.go
+// REST endpoints
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+	"currency-conversion/internal/service"
+	"shared/pkg/money"
+)
+
+// Error codes returned alongside conversion failures
+const (
+	codeInvalidRequest      = "invalid_request"
+	codeUnsupportedCurrency = "unsupported_currency"
+	codeAmountOutOfRange    = "amount_out_of_range"
+	codeUnknownCustomerTier = "unknown_customer_tier"
+	codeRateUnavailable     = "rate_unavailable"
+	codeRateNotFound        = "rate_not_found"
+	codeQuotesDisabled      = "quotes_disabled"
+	codeQuoteNotFound       = "quote_not_found"
+	codeQuoteExpired        = "quote_expired"
+	codeIdempotencyConflict = "idempotency_key_reused"
+	codeInternal            = "internal_error"
+)
+
+// serviceErrors maps ExchangeService errors to a status and error code
+var serviceErrors = []struct {
+	err    error
+	status int
+	code   string
+}{
+	{service.ErrUnsupportedCurrency, http.StatusBadRequest, codeUnsupportedCurrency},
+	{service.ErrConversionAmountOutOfRange, http.StatusBadRequest, codeAmountOutOfRange},
+	{service.ErrUnknownCustomerTier, http.StatusBadRequest, codeUnknownCustomerTier},
+	{service.ErrRateUnavailable, http.StatusServiceUnavailable, codeRateUnavailable},
+	{service.ErrRateNotFound, http.StatusNotFound, codeRateNotFound},
+	{service.ErrQuotesDisabled, http.StatusServiceUnavailable, codeQuotesDisabled},
+	{service.ErrQuoteNotFound, http.StatusNotFound, codeQuoteNotFound},
+	{service.ErrQuoteExpired, http.StatusGone, codeQuoteExpired},
+	{service.ErrIdempotencyKeyReused, http.StatusConflict, codeIdempotencyConflict},
+}
+
+type CurrencyHandler struct {
+	service *service.ExchangeService
+	logger  *zap.Logger
+}
+
+func NewCurrencyHandler(service *service.ExchangeService, logger *zap.Logger) *CurrencyHandler {
+	return &CurrencyHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ConvertCurrency handles POST /api/v1/currency/convert
+func (h *CurrencyHandler) ConvertCurrency(c *gin.Context) {
+	var req models.ConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeFieldError(c, err)
+		return
+	}
+
+	response, err := h.service.Convert(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, err, "Failed to convert currency")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateQuote handles POST /api/v1/currency/quote
+func (h *CurrencyHandler) CreateQuote(c *gin.Context) {
+	var req models.ConversionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeFieldError(c, err)
+		return
+	}
+
+	quote, err := h.service.CreateQuote(c.Request.Context(), &req)
+	if err != nil {
+		h.writeError(c, err, "Failed to create quote")
+		return
+	}
+
+	c.JSON(http.StatusCreated, quote)
+}
+
+// ExecuteQuote handles POST /api/v1/currency/quote/:id/execute
+func (h *CurrencyHandler) ExecuteQuote(c *gin.Context) {
+	response, err := h.service.ExecuteQuote(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.writeError(c, err, "Failed to execute quote")
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetRate handles GET /api/v1/currency/rates/:from/:to
+func (h *CurrencyHandler) GetRate(c *gin.Context) {
+	from := money.NormalizeCurrency(c.Param("from"))
+	to := money.NormalizeCurrency(c.Param("to"))
+
+	rate, err := h.service.GetRate(c.Request.Context(), from, to)
+	if err != nil {
+		h.writeError(c, err, "Failed to get exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rate": rate})
+}
+
+// GetRateAt handles GET /api/v1/currency/rates/:from/:to/at?t=2024-01-15T10:00:00Z,
+// returning the latest recorded rate at or before t
+func (h *CurrencyHandler) GetRateAt(c *gin.Context) {
+	from := money.NormalizeCurrency(c.Param("from"))
+	to := money.NormalizeCurrency(c.Param("to"))
+
+	at, err := time.Parse(time.RFC3339, c.Query("t"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "t must be an RFC3339 timestamp", "code": codeInvalidRequest})
+		return
+	}
+
+	rate, err := h.service.GetRateAt(c.Request.Context(), from, to, at)
+	if err != nil {
+		h.writeError(c, err, "Failed to get exchange rate")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rate": rate})
+}
+
+// StreamRate handles GET /api/v1/currency/rates/:from/:to/stream, pushing the
+// rate as a Server-Sent Event whenever it changes. Streams end when the client
+// disconnects or the server's write timeout elapses; EventSource clients reconnect.
+func (h *CurrencyHandler) StreamRate(c *gin.Context) {
+	from := money.NormalizeCurrency(c.Param("from"))
+	to := money.NormalizeCurrency(c.Param("to"))
+
+	updates, err := h.service.WatchRate(c.Request.Context(), from, to)
+	if err != nil {
+		h.writeError(c, err, "Failed to stream exchange rate")
+		return
+	}
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Stream(func(w io.Writer) bool {
+		rate, ok := <-updates
+		if !ok {
+			return false
+		}
+		c.SSEvent("rate", rate)
+		return true
+	})
+}
+
+// GetRateHistory handles GET /api/v1/currency/rates/history/:from/:to
+func (h *CurrencyHandler) GetRateHistory(c *gin.Context) {
+	from := money.NormalizeCurrency(c.Param("from"))
+	to := money.NormalizeCurrency(c.Param("to"))
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer", "code": codeInvalidRequest})
+		return
+	}
+
+	rates, err := h.service.GetHistoricalRates(c.Request.Context(), from, to, days)
+	if err != nil {
+		h.writeError(c, err, "Failed to get rate history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"rates": rates})
+}
+
+// GetSupportedCurrencies handles GET /api/v1/currency/supported
+func (h *CurrencyHandler) GetSupportedCurrencies(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"currencies": h.service.GetSupportedCurrencies()})
+}
+
+// writeFieldError responds 400, naming the request field that failed validation
+func writeFieldError(c *gin.Context, err error) {
+	var fieldErr *money.FieldError
+	if errors.As(err, &fieldErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fieldErr.Err.Error(), "code": codeInvalidRequest, "field": fieldErr.Field})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "code": codeInvalidRequest})
+}
+
+// writeError responds with the status and code for a known service error,
+// or a 500 with the given message otherwise
+func (h *CurrencyHandler) writeError(c *gin.Context, err error, message string) {
+	for _, known := range serviceErrors {
+		if errors.Is(err, known.err) {
+			c.JSON(known.status, gin.H{"error": err.Error(), "code": known.code})
+			return
+		}
+	}
+
+	h.logger.Error(message, zap.Error(err))
+	c.JSON(http.StatusInternalServerError, gin.H{"error": message, "code": codeInternal})
+}
@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/service"
+)
+
+func newTestHandler() *CurrencyHandler {
+	gin.SetMode(gin.TestMode)
+	return NewCurrencyHandler(service.NewExchangeService(nil, nil, "", zap.NewNop()), zap.NewNop())
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error string `json:"error"`
+		Code  string `json:"code"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response %q: %v", rec.Body.String(), err)
+	}
+	if body.Error == "" {
+		t.Error("response has no error message")
+	}
+	return body.Code
+}
+
+func TestConvertCurrencyErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "Malformed request",
+			body:       `{"amount": 10}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeInvalidRequest,
+		},
+		{
+			name:       "Unsupported currency",
+			body:       `{"amount": 10, "from_currency": "USD", "to_currency": "XYZ"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeUnsupportedCurrency,
+		},
+		{
+			name:       "Amount below minimum",
+			body:       `{"amount": 0.5, "from_currency": "USD", "to_currency": "EUR"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeAmountOutOfRange,
+		},
+		{
+			name:       "Sub-cent USD amount",
+			body:       `{"amount": 10.001, "from_currency": "usd", "to_currency": "EUR"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeInvalidRequest,
+		},
+		{
+			name:       "Fractional JPY amount",
+			body:       `{"amount": 1000.5, "from_currency": "JPY", "to_currency": "USD"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeInvalidRequest,
+		},
+		{
+			name:       "Unknown rounding mode",
+			body:       `{"amount": 10, "from_currency": "USD", "to_currency": "EUR", "rounding_mode": "up"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeInvalidRequest,
+		},
+		{
+			name:       "Unknown fee mode",
+			body:       `{"amount": 10, "from_currency": "USD", "to_currency": "EUR", "fee_mode": "split"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/currency/convert", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.ConvertCurrency(c)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if code := decodeError(t, rec); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "Provider outage",
+			err:        fmt.Errorf("failed to get exchange rate: %w", fmt.Errorf("%w: primary: status 503", service.ErrRateUnavailable)),
+			wantStatus: http.StatusServiceUnavailable,
+			wantCode:   codeRateUnavailable,
+		},
+		{
+			name:       "Unsupported currency",
+			err:        fmt.Errorf("%w: %q", service.ErrUnsupportedCurrency, "XYZ"),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeUnsupportedCurrency,
+		},
+		{
+			name:       "Amount out of range",
+			err:        service.ErrConversionAmountOutOfRange,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   codeAmountOutOfRange,
+		},
+		{
+			name:       "Unknown failure",
+			err:        errors.New("connection reset"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   codeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler()
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+
+			h.writeError(c, tt.err, "Failed to convert currency")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if code := decodeError(t, rec); code != tt.wantCode {
+				t.Errorf("code = %q, want %q", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetProviders handles GET /api/v1/currency/providers
+func (h *CurrencyHandler) GetProviders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"providers": h.service.ProviderStatuses()})
+}
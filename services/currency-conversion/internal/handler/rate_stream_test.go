@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/service"
+)
+
+// staticRateCache is a RateCacheStore holding fixed entries
+type staticRateCache map[string]string
+
+func (c staticRateCache) Get(ctx context.Context, key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", errors.New("key not found")
+	}
+	return value, nil
+}
+
+func (c staticRateCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return nil
+}
+
+func TestStreamRateSendsEvent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cache := staticRateCache{
+		"rate:USD:EUR": `{"from_currency":"USD","to_currency":"EUR","rate":0.92,"source":"test","timestamp":"2024-03-10T12:00:00Z"}`,
+	}
+	exchange := service.NewExchangeService(nil, cache, "", zap.NewNop())
+	exchange.SetRateStreamInterval(10 * time.Millisecond)
+	h := NewCurrencyHandler(exchange, zap.NewNop())
+
+	router := gin.New()
+	router.GET("/api/v1/currency/rates/:from/:to/stream", h.StreamRate)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/currency/rates/usd/eur/stream", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("failed to connect to stream: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if got := resp.Header.Get("Content-Type"); !strings.HasPrefix(got, "text/event-stream") {
+		t.Errorf("Content-Type = %q, want text/event-stream", got)
+	}
+
+	var event, data string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && data == "" {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = line
+		case strings.HasPrefix(line, "data:"):
+			data = line
+		}
+	}
+
+	if event != "event:rate" {
+		t.Errorf("event = %q, want event:rate", event)
+	}
+	if !strings.Contains(data, `"rate":0.92`) {
+		t.Errorf("data = %q, want the cached USD/EUR rate", data)
+	}
+}
+
+func TestStreamRateRejectsUnsupportedCurrency(t *testing.T) {
+	h := newTestHandler()
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/currency/rates/USD/XYZ/stream", nil)
+	c.Params = gin.Params{{Key: "from", Value: "USD"}, {Key: "to", Value: "XYZ"}}
+
+	h.StreamRate(c)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if code := decodeError(t, rec); code != codeUnsupportedCurrency {
+		t.Errorf("code = %q, want %q", code, codeUnsupportedCurrency)
+	}
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"shared/pkg/money"
+)
+
+// Conversion lifecycle event types
+const (
+	EventConversionCompleted = "conversion.completed"
+)
+
+// ConversionEvent is published to other services once a conversion has been recorded
+type ConversionEvent struct {
+	ID              string        `json:"id"`
+	Type            string        `json:"type"`
+	ConversionID    string        `json:"conversion_id"`
+	FromCurrency    string        `json:"from_currency"`
+	ToCurrency      string        `json:"to_currency"`
+	OriginalAmount  float64       `json:"original_amount"`
+	ConvertedAmount float64       `json:"converted_amount"`
+	ExchangeRate    money.Decimal `json:"exchange_rate"`
+	Fee             float64       `json:"fee"`
+	OccurredAt      time.Time     `json:"occurred_at"`
+}
@@ -0,0 +1,161 @@
+// services/currency-conversion/internal/models/currency.go
+// Data structures
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"shared/pkg/money"
+)
+
+type ExchangeRate struct {
+	FromCurrency string        `json:"from_currency" db:"from_currency"`
+	ToCurrency   string        `json:"to_currency" db:"to_currency"`
+	Rate         money.Decimal `json:"rate" db:"rate"`
+	Source       string        `json:"source" db:"source"`
+	Timestamp    time.Time     `json:"timestamp" db:"timestamp"`
+	// Derived is set when the rate was computed from the inverse pair
+	Derived bool `json:"derived,omitempty" db:"-"`
+}
+
+// FeeMode says whether the conversion fee comes out of the converted amount or on top of it
+type FeeMode string
+
+const (
+	// FeeModeInclusive deducts the fee from the converted amount
+	FeeModeInclusive FeeMode = "inclusive"
+	// FeeModeExclusive charges the fee on top, so the recipient gets the full converted amount
+	FeeModeExclusive FeeMode = "exclusive"
+)
+
+var ErrInvalidFeeMode = errors.New("fee mode must be inclusive or exclusive")
+
+// Valid reports whether m is a known fee mode
+func (m FeeMode) Valid() bool {
+	return m == FeeModeInclusive || m == FeeModeExclusive
+}
+
+type ConversionRequest struct {
+	Amount       float64 `json:"amount" binding:"required,gt=0"`
+	FromCurrency string  `json:"from_currency" binding:"required,len=3"`
+	ToCurrency   string  `json:"to_currency" binding:"required,len=3"`
+	CustomerTier string  `json:"customer_tier"`
+	// RoundingMode rounds the converted amount to the target currency's minor unit;
+	// empty means banker's rounding
+	RoundingMode money.RoundingMode `json:"rounding_mode"`
+	// FeeMode is whether the fee is deducted from the converted amount or charged
+	// on top of it; empty means inclusive
+	FeeMode FeeMode `json:"fee_mode"`
+	// IdempotencyKey makes retries of this conversion return the original result
+	// instead of converting again
+	IdempotencyKey string `json:"idempotency_key" binding:"omitempty,max=255"`
+}
+
+// UnmarshalJSON normalizes the currency codes as the request is bound, so binding
+// rules, cache keys and provider URLs all see "usd" and " USD " as USD
+func (r *ConversionRequest) UnmarshalJSON(data []byte) error {
+	type conversionRequest ConversionRequest
+	if err := json.Unmarshal(data, (*conversionRequest)(r)); err != nil {
+		return err
+	}
+	r.FromCurrency = money.NormalizeCurrency(r.FromCurrency)
+	r.ToCurrency = money.NormalizeCurrency(r.ToCurrency)
+	return nil
+}
+
+// Validate checks what binding tags cannot: that the amount suits the source currency
+// and that any rounding or fee mode is known
+func (r *ConversionRequest) Validate() error {
+	if r.RoundingMode != "" && !r.RoundingMode.Valid() {
+		return &money.FieldError{Field: "rounding_mode", Err: money.ErrInvalidRounding}
+	}
+	if r.FeeMode != "" && !r.FeeMode.Valid() {
+		return &money.FieldError{Field: "fee_mode", Err: ErrInvalidFeeMode}
+	}
+	return money.ValidateAmount("amount", r.Amount, r.FromCurrency)
+}
+
+type ConversionResponse struct {
+	ConversionID    string        `json:"conversion_id"`
+	OriginalAmount  float64       `json:"original_amount"`
+	ConvertedAmount float64       `json:"converted_amount"`
+	FromCurrency    string        `json:"from_currency"`
+	ToCurrency      string        `json:"to_currency"`
+	ExchangeRate    money.Decimal `json:"exchange_rate"`
+	Fee             float64       `json:"fee"`
+	FeePercentage   float64       `json:"fee_percentage"`
+	FeeMode         FeeMode       `json:"fee_mode"`
+	FeeBound        string        `json:"fee_bound,omitempty"`
+	CustomerTier    string        `json:"customer_tier"`
+	RateTimestamp   time.Time     `json:"rate_timestamp"`
+	RequiresReview  bool          `json:"requires_review"`
+}
+
+type Conversion struct {
+	ID              string        `json:"id" db:"id"`
+	FromCurrency    string        `json:"from_currency" db:"from_currency"`
+	ToCurrency      string        `json:"to_currency" db:"to_currency"`
+	OriginalAmount  float64       `json:"original_amount" db:"original_amount"`
+	ConvertedAmount float64       `json:"converted_amount" db:"converted_amount"`
+	ExchangeRate    money.Decimal `json:"exchange_rate" db:"exchange_rate"`
+	Fee             float64       `json:"fee" db:"fee"`
+	CreatedAt       time.Time     `json:"created_at" db:"created_at"`
+}
+
+// ConversionFilter selects conversions, optionally between one currency pair
+type ConversionFilter struct {
+	FromCurrency string
+	ToCurrency   string
+	Limit        int
+	Offset       int
+}
+
+// ConversionLimit bounds the amount, in the source currency, a single conversion may move
+type ConversionLimit struct {
+	Min             float64 `json:"min"`
+	Max             float64 `json:"max"`
+	ReviewThreshold float64 `json:"review_threshold"`
+}
+
+// FeeBounds clamps the percentage fee, in the target currency. A zero Min or Max
+// leaves that side unbounded.
+type FeeBounds struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// Fee bounds a conversion's fee was clamped to
+const (
+	FeeBoundMinimum = "minimum"
+	FeeBoundMaximum = "maximum"
+)
+
+// ConversionQuote locks a rate and fee for a conversion until ExpiresAt
+type ConversionQuote struct {
+	QuoteID         string        `json:"quote_id"`
+	OriginalAmount  float64       `json:"original_amount"`
+	ConvertedAmount float64       `json:"converted_amount"`
+	FromCurrency    string        `json:"from_currency"`
+	ToCurrency      string        `json:"to_currency"`
+	ExchangeRate    money.Decimal `json:"exchange_rate"`
+	Fee             float64       `json:"fee"`
+	FeePercentage   float64       `json:"fee_percentage"`
+	FeeMode         FeeMode       `json:"fee_mode"`
+	CustomerTier    string        `json:"customer_tier"`
+	RateTimestamp   time.Time     `json:"rate_timestamp"`
+	RequiresReview  bool          `json:"requires_review"`
+	ExpiresAt       time.Time     `json:"expires_at"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+// ConversionIdempotencyRecord is the conversion first made under an idempotency key.
+// RequestHash identifies the request so the key can't be reused for a different one.
+type ConversionIdempotencyRecord struct {
+	Key          string              `db:"idempotency_key"`
+	RequestHash  string              `db:"request_hash"`
+	ConversionID string              `db:"conversion_id"`
+	Response     *ConversionResponse `db:"response"`
+	CreatedAt    time.Time           `db:"created_at"`
+}
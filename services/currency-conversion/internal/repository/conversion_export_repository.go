@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"currency-conversion/internal/models"
+)
+
+// StreamConversions calls fn for each conversion created in [start, end), oldest
+// first, reading rows as they arrive so large periods are never held in memory
+func (r *RateRepository) StreamConversions(ctx context.Context, start, end time.Time, fn func(*models.Conversion) error) error {
+	query := `
+		SELECT id, from_currency, to_currency, original_amount, converted_amount,
+			   exchange_rate, fee, created_at
+		FROM conversions
+		WHERE created_at >= $1 AND created_at < $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		conversion := &models.Conversion{}
+		if err := rows.Scan(
+			&conversion.ID,
+			&conversion.FromCurrency,
+			&conversion.ToCurrency,
+			&conversion.OriginalAmount,
+			&conversion.ConvertedAmount,
+			&conversion.ExchangeRate,
+			&conversion.Fee,
+			&conversion.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(conversion); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
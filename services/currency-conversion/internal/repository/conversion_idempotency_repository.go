@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"currency-conversion/internal/models"
+)
+
+// ClaimConversionIdempotencyKey stores the conversion made under a key, reporting
+// false without storing anything if the key was already claimed
+func (r *RateRepository) ClaimConversionIdempotencyKey(ctx context.Context, record *models.ConversionIdempotencyRecord) (bool, error) {
+	response, err := json.Marshal(record.Response)
+	if err != nil {
+		return false, err
+	}
+
+	query := `
+		INSERT INTO conversion_idempotency_keys (
+			idempotency_key, request_hash, conversion_id, response, created_at
+		) VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		record.Key,
+		record.RequestHash,
+		record.ConversionID,
+		response,
+		record.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return claimed == 1, nil
+}
+
+// GetConversionByIdempotencyKey returns the conversion made under a key, or nil if there is none
+func (r *RateRepository) GetConversionByIdempotencyKey(ctx context.Context, key string) (*models.ConversionIdempotencyRecord, error) {
+	query := `
+		SELECT idempotency_key, request_hash, conversion_id, response, created_at
+		FROM conversion_idempotency_keys
+		WHERE idempotency_key = $1
+	`
+
+	record := &models.ConversionIdempotencyRecord{}
+	var response []byte
+	err := r.db.QueryRowContext(ctx, query, key).Scan(
+		&record.Key,
+		&record.RequestHash,
+		&record.ConversionID,
+		&response,
+		&record.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(response, &record.Response); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"currency-conversion/internal/models"
+)
+
+// ListConversions returns conversions matching the filter, newest first
+func (r *RateRepository) ListConversions(ctx context.Context, filter models.ConversionFilter) ([]*models.Conversion, error) {
+	where, args := conversionConditions(filter)
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT id, from_currency, to_currency, original_amount, converted_amount,
+			   exchange_rate, fee, created_at
+		FROM conversions
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversions := []*models.Conversion{}
+	for rows.Next() {
+		conversion := &models.Conversion{}
+		if err := rows.Scan(
+			&conversion.ID,
+			&conversion.FromCurrency,
+			&conversion.ToCurrency,
+			&conversion.OriginalAmount,
+			&conversion.ConvertedAmount,
+			&conversion.ExchangeRate,
+			&conversion.Fee,
+			&conversion.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		conversions = append(conversions, conversion)
+	}
+
+	return conversions, rows.Err()
+}
+
+// CountConversions returns how many conversions match the filter, ignoring its limit and offset
+func (r *RateRepository) CountConversions(ctx context.Context, filter models.ConversionFilter) (int, error) {
+	where, args := conversionConditions(filter)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM conversions `+where, args...).Scan(&count)
+	return count, err
+}
+
+// conversionConditions builds the WHERE clause shared by ListConversions and CountConversions
+func conversionConditions(filter models.ConversionFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.FromCurrency != "" {
+		args = append(args, filter.FromCurrency)
+		conditions = append(conditions, fmt.Sprintf("from_currency = $%d", len(args)))
+	}
+	if filter.ToCurrency != "" {
+		args = append(args, filter.ToCurrency)
+		conditions = append(conditions, fmt.Sprintf("to_currency = $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"currency-conversion/internal/models"
+)
+
+// GetRateAt returns the latest stored rate for a pair recorded at or before at,
+// or nil if the pair has no rate that old
+func (r *RateRepository) GetRateAt(ctx context.Context, from, to string, at time.Time) (*models.ExchangeRate, error) {
+	query := `
+		SELECT from_currency, to_currency, rate, source, timestamp
+		FROM exchange_rates
+		WHERE from_currency = $1 AND to_currency = $2 AND timestamp <= $3
+		ORDER BY timestamp DESC
+		LIMIT 1
+	`
+
+	rate := &models.ExchangeRate{}
+	err := r.db.QueryRowContext(ctx, query, from, to, at).Scan(
+		&rate.FromCurrency,
+		&rate.ToCurrency,
+		&rate.Rate,
+		&rate.Source,
+		&rate.Timestamp,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/events"
+	"shared/pkg/ids"
+)
+
+// ConversionEventsChannel is the channel conversion lifecycle events are published on
+const ConversionEventsChannel = "conversion.events"
+
+// SetEventPublisher sets where conversion events are published; the default discards them
+func (s *ExchangeService) SetEventPublisher(publisher events.Publisher) {
+	s.events = publisher
+}
+
+// publishConversionCompleted announces a recorded conversion. Publishing is best
+// effort: the conversion has already happened, so a failure is only logged.
+func (s *ExchangeService) publishConversionCompleted(ctx context.Context, response *models.ConversionResponse) {
+	if s.events == nil {
+		return
+	}
+
+	event := &models.ConversionEvent{
+		ID:              ids.New(),
+		Type:            models.EventConversionCompleted,
+		ConversionID:    response.ConversionID,
+		FromCurrency:    response.FromCurrency,
+		ToCurrency:      response.ToCurrency,
+		OriginalAmount:  response.OriginalAmount,
+		ConvertedAmount: response.ConvertedAmount,
+		ExchangeRate:    response.ExchangeRate,
+		Fee:             response.Fee,
+		OccurredAt:      time.Now(),
+	}
+
+	if err := s.events.Publish(ctx, ConversionEventsChannel, event); err != nil {
+		s.logger.Error("failed to publish conversion event",
+			zap.String("conversion_id", response.ConversionID),
+			zap.Error(err))
+	}
+}
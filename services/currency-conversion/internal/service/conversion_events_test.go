@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"currency-conversion/internal/models"
+)
+
+type publishedEvent struct {
+	channel string
+	event   interface{}
+}
+
+// recordingPublisher keeps every published event
+type recordingPublisher struct {
+	published []publishedEvent
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, channel string, event interface{}) error {
+	p.published = append(p.published, publishedEvent{channel: channel, event: event})
+	return nil
+}
+
+func TestConvertPublishesConversionCompleted(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	s.redisClient = memoryRateCache{}
+	s.repo = &fakeRateStore{}
+	publisher := &recordingPublisher{}
+	s.SetEventPublisher(publisher)
+
+	response, err := s.Convert(context.Background(), &models.ConversionRequest{
+		Amount:       100,
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if len(publisher.published) != 1 {
+		t.Fatalf("published %d events, want 1", len(publisher.published))
+	}
+	published := publisher.published[0]
+	if published.channel != ConversionEventsChannel {
+		t.Errorf("channel = %q, want %q", published.channel, ConversionEventsChannel)
+	}
+	event, ok := published.event.(*models.ConversionEvent)
+	if !ok {
+		t.Fatalf("event is %T, want *models.ConversionEvent", published.event)
+	}
+	if event.Type != models.EventConversionCompleted || event.ID == "" {
+		t.Errorf("event = %+v, want a conversion.completed event with an ID", event)
+	}
+	if event.ConversionID != response.ConversionID ||
+		event.FromCurrency != "USD" || event.ToCurrency != "EUR" ||
+		event.OriginalAmount != response.OriginalAmount ||
+		event.ConvertedAmount != response.ConvertedAmount ||
+		event.ExchangeRate.Cmp(response.ExchangeRate) != 0 ||
+		event.Fee != response.Fee {
+		t.Errorf("event = %+v, want it to match response %+v", event, response)
+	}
+}
+
+func TestFailedConvertPublishesNothing(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	s.redisClient = memoryRateCache{}
+	s.repo = &fakeRateStore{}
+	publisher := &recordingPublisher{}
+	s.SetEventPublisher(publisher)
+
+	if _, err := s.Convert(context.Background(), &models.ConversionRequest{
+		Amount:       100,
+		FromCurrency: "USD",
+		ToCurrency:   "XXX",
+	}); err == nil {
+		t.Fatal("Convert() to an unsupported currency should fail")
+	}
+	if len(publisher.published) != 0 {
+		t.Errorf("published %d events, want none", len(publisher.published))
+	}
+}
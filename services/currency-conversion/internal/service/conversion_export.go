@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"currency-conversion/internal/models"
+)
+
+// exportFlushEvery is how many CSV rows are buffered before they are written out
+const exportFlushEvery = 500
+
+var ErrInvalidExportPeriod = errors.New("invalid export period")
+
+// conversionCSVHeader is the column layout of a conversion export
+var conversionCSVHeader = []string{
+	"conversion_id", "created_at", "from_currency", "to_currency",
+	"original_amount", "converted_amount", "exchange_rate", "fee",
+}
+
+// ExportConversionsCSV writes every conversion created in [start, end) to w as CSV.
+// Rows are streamed from the database and flushed in batches, so the size of the
+// period does not bound memory. flush, if non-nil, is called after each batch.
+func (s *ExchangeService) ExportConversionsCSV(ctx context.Context, w io.Writer, start, end time.Time, flush func()) error {
+	if !end.After(start) {
+		return ErrInvalidExportPeriod
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(conversionCSVHeader); err != nil {
+		return err
+	}
+
+	rows := 0
+	err := s.repo.StreamConversions(ctx, start, end, func(conversion *models.Conversion) error {
+		if err := writer.Write(conversionCSVRow(conversion)); err != nil {
+			return err
+		}
+		rows++
+		if rows%exportFlushEvery == 0 {
+			writer.Flush()
+			if flush != nil {
+				flush()
+			}
+		}
+		return writer.Error()
+	})
+
+	writer.Flush()
+	if flush != nil {
+		flush()
+	}
+	if err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// conversionCSVRow formats amounts at the precision the conversions table stores them
+func conversionCSVRow(conversion *models.Conversion) []string {
+	return []string{
+		conversion.ID,
+		conversion.CreatedAt.UTC().Format(time.RFC3339),
+		conversion.FromCurrency,
+		conversion.ToCurrency,
+		strconv.FormatFloat(conversion.OriginalAmount, 'f', 4, 64),
+		strconv.FormatFloat(conversion.ConvertedAmount, 'f', 4, 64),
+		conversion.ExchangeRate.StringFixed(rateDecimalPlaces),
+		strconv.FormatFloat(conversion.Fee, 'f', 4, 64),
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+var ErrUnknownCustomerTier = errors.New("unknown customer tier")
+
+// CustomerTierStandard is applied when a conversion request names no tier
+const CustomerTierStandard = "standard"
+
+// defaultFeeTiers is the fee schedule used until SetFeeTiers is called
+var defaultFeeTiers = map[string]float64{
+	CustomerTierStandard: 0.005,
+}
+
+// SetFeeTiers replaces the fee percentage charged to each customer tier.
+// The standard tier keeps its default fee unless the schedule overrides it.
+func (s *ExchangeService) SetFeeTiers(tiers map[string]float64) {
+	schedule := make(map[string]float64, len(tiers)+1)
+	schedule[CustomerTierStandard] = defaultFeeTiers[CustomerTierStandard]
+	for tier, fee := range tiers {
+		schedule[strings.ToLower(tier)] = fee
+	}
+
+	s.feesMu.Lock()
+	defer s.feesMu.Unlock()
+	s.feeTiers = schedule
+}
+
+// feePercentage returns the tier a request is charged under and its fee percentage
+func (s *ExchangeService) feePercentage(tier string) (string, float64, error) {
+	tier = strings.ToLower(tier)
+	if tier == "" {
+		tier = CustomerTierStandard
+	}
+
+	s.feesMu.RLock()
+	defer s.feesMu.RUnlock()
+	schedule := s.feeTiers
+	if schedule == nil {
+		schedule = defaultFeeTiers
+	}
+
+	fee, ok := schedule[tier]
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %s", ErrUnknownCustomerTier, tier)
+	}
+	return tier, fee, nil
+}
+
+// SetFeeBounds sets the minimum and maximum fee per target currency. Currencies
+// without bounds are charged the plain percentage fee.
+func (s *ExchangeService) SetFeeBounds(bounds map[string]models.FeeBounds) error {
+	normalized := make(map[string]models.FeeBounds, len(bounds))
+	for currency, bound := range bounds {
+		if bound.Min < 0 || bound.Max < 0 {
+			return fmt.Errorf("fee bounds for %s must not be negative", currency)
+		}
+		if bound.Max > 0 && bound.Min > bound.Max {
+			return fmt.Errorf("minimum fee %v for %s exceeds its maximum %v", bound.Min, currency, bound.Max)
+		}
+		normalized[strings.ToUpper(currency)] = bound
+	}
+
+	s.feesMu.Lock()
+	defer s.feesMu.Unlock()
+	s.feeBounds = normalized
+	return nil
+}
+
+// boundFee clamps a percentage fee to the target currency's bounds, reporting
+// which bound applied, if any
+func (s *ExchangeService) boundFee(fee money.Decimal, currency string) (money.Decimal, string) {
+	s.feesMu.RLock()
+	bound, ok := s.feeBounds[currency]
+	s.feesMu.RUnlock()
+	if !ok {
+		return fee, ""
+	}
+
+	if bound.Min > 0 {
+		if min := money.NewDecimal(bound.Min); fee.Cmp(min) < 0 {
+			return min, models.FeeBoundMinimum
+		}
+	}
+	if bound.Max > 0 {
+		if max := money.NewDecimal(bound.Max); fee.Cmp(max) > 0 {
+			return max, models.FeeBoundMaximum
+		}
+	}
+	return fee, ""
+}
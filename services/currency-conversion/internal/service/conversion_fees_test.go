@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"currency-conversion/internal/models"
+)
+
+func TestFeePercentageByTier(t *testing.T) {
+	s := newTestExchangeService()
+	s.SetFeeTiers(map[string]float64{"Enterprise": 0.002})
+
+	tests := []struct {
+		name     string
+		tier     string
+		wantTier string
+		wantFee  float64
+		wantErr  error
+	}{
+		{name: "Defaults to standard", tier: "", wantTier: "standard", wantFee: 0.005},
+		{name: "Standard", tier: "standard", wantTier: "standard", wantFee: 0.005},
+		{name: "Enterprise", tier: "enterprise", wantTier: "enterprise", wantFee: 0.002},
+		{name: "Tier is case insensitive", tier: "ENTERPRISE", wantTier: "enterprise", wantFee: 0.002},
+		{name: "Unknown tier", tier: "platinum", wantErr: ErrUnknownCustomerTier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tier, fee, err := s.feePercentage(tt.tier)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("feePercentage() error = %v, want %v", err, tt.wantErr)
+			}
+			if tier != tt.wantTier || fee != tt.wantFee {
+				t.Errorf("feePercentage() = %s/%v, want %s/%v", tier, fee, tt.wantTier, tt.wantFee)
+			}
+		})
+	}
+}
+
+func TestFeePercentageWithoutSchedule(t *testing.T) {
+	s := newTestExchangeService()
+
+	if _, fee, err := s.feePercentage(""); err != nil || fee != 0.005 {
+		t.Errorf("feePercentage() = %v, %v, want 0.005", fee, err)
+	}
+	if _, _, err := s.feePercentage("enterprise"); !errors.Is(err, ErrUnknownCustomerTier) {
+		t.Errorf("feePercentage(enterprise) error = %v, want %v", err, ErrUnknownCustomerTier)
+	}
+}
+
+func TestConvertClampsFeeToBounds(t *testing.T) {
+	tests := []struct {
+		name          string
+		amount        float64
+		wantFee       float64
+		wantConverted float64
+		wantBound     string
+	}{
+		// 10 USD converts to 9.20 EUR; 0.5% is 0.046, below the 0.50 floor
+		{name: "Raised to the floor", amount: 10, wantFee: 0.50, wantConverted: 8.70, wantBound: models.FeeBoundMinimum},
+		// 20000 USD converts to 18400 EUR; 0.5% is 92, above the 25 cap
+		{name: "Cut to the cap", amount: 20000, wantFee: 25, wantConverted: 18375, wantBound: models.FeeBoundMaximum},
+		// 1000 USD converts to 920 EUR; 0.5% is 4.60, within bounds
+		{name: "Within bounds", amount: 1000, wantFee: 4.60, wantConverted: 915.40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestExchangeService(&fakeProvider{name: "primary"})
+			s.redisClient = memoryRateCache{}
+			s.repo = &fakeRateStore{}
+			if err := s.SetFeeBounds(map[string]models.FeeBounds{"eur": {Min: 0.50, Max: 25}}); err != nil {
+				t.Fatalf("SetFeeBounds() error = %v", err)
+			}
+
+			response, err := s.Convert(context.Background(), &models.ConversionRequest{
+				Amount:       tt.amount,
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+			})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if response.Fee != tt.wantFee || response.ConvertedAmount != tt.wantConverted || response.FeeBound != tt.wantBound {
+				t.Errorf("Convert() fee = %v, converted = %v, bound = %q, want %v, %v, %q",
+					response.Fee, response.ConvertedAmount, response.FeeBound,
+					tt.wantFee, tt.wantConverted, tt.wantBound)
+			}
+		})
+	}
+}
+
+func TestSetFeeBoundsRejectsInvalidBounds(t *testing.T) {
+	s := newTestExchangeService()
+
+	if err := s.SetFeeBounds(map[string]models.FeeBounds{"EUR": {Min: -1}}); err == nil {
+		t.Error("SetFeeBounds() accepted a negative minimum")
+	}
+	if err := s.SetFeeBounds(map[string]models.FeeBounds{"EUR": {Min: 10, Max: 5}}); err == nil {
+		t.Error("SetFeeBounds() accepted a minimum above the maximum")
+	}
+}
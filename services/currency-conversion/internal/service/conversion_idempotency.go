@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"currency-conversion/internal/models"
+)
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different conversion")
+
+// convertIdempotently converts once per idempotency key. A retry with the same key
+// and request gets the original response back without recording another conversion.
+func (s *ExchangeService) convertIdempotently(ctx context.Context, req *models.ConversionRequest) (*models.ConversionResponse, error) {
+	requestHash := conversionRequestHash(req)
+
+	if replayed, err := s.replayConversion(ctx, req.IdempotencyKey, requestHash); replayed != nil || err != nil {
+		return replayed, err
+	}
+
+	response, err := s.priceConversion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Claiming the key before recording means a concurrent retry can't record a second conversion
+	claimed, err := s.repo.ClaimConversionIdempotencyKey(ctx, &models.ConversionIdempotencyRecord{
+		Key:          req.IdempotencyKey,
+		RequestHash:  requestHash,
+		ConversionID: response.ConversionID,
+		Response:     response,
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store idempotency key: %w", err)
+	}
+	if !claimed {
+		return s.replayConversion(ctx, req.IdempotencyKey, requestHash)
+	}
+
+	s.recordConversion(ctx, response)
+	return response, nil
+}
+
+// replayConversion returns the response stored under key, or nil if the key is unused
+func (s *ExchangeService) replayConversion(ctx context.Context, key, requestHash string) (*models.ConversionResponse, error) {
+	record, err := s.repo.GetConversionByIdempotencyKey(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up idempotency key: %w", err)
+	}
+	if record == nil {
+		return nil, nil
+	}
+	if record.RequestHash != requestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	return record.Response, nil
+}
+
+// conversionRequestHash fingerprints the fields that decide a conversion's result
+func conversionRequestHash(req *models.ConversionRequest) string {
+	fields := []string{
+		fmt.Sprintf("%v", req.Amount),
+		strings.ToUpper(req.FromCurrency),
+		strings.ToUpper(req.ToCurrency),
+		strings.ToLower(req.CustomerTier),
+		string(req.RoundingMode),
+		string(feeMode(req.FeeMode)),
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "|")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"currency-conversion/internal/models"
+)
+
+var ErrConversionAmountOutOfRange = errors.New("conversion amount out of range")
+
+// defaultConversionLimit applies to source currencies without their own limit
+var defaultConversionLimit = models.ConversionLimit{
+	Min:             1,
+	Max:             1000000,
+	ReviewThreshold: 100000,
+}
+
+// SetConversionLimits replaces the per-currency conversion limits, keyed by source currency
+func (s *ExchangeService) SetConversionLimits(limits map[string]models.ConversionLimit) {
+	s.limitsMu.Lock()
+	defer s.limitsMu.Unlock()
+	s.limits = limits
+}
+
+func (s *ExchangeService) conversionLimit(currency string) models.ConversionLimit {
+	s.limitsMu.RLock()
+	defer s.limitsMu.RUnlock()
+	if limit, ok := s.limits[currency]; ok {
+		return limit
+	}
+	return defaultConversionLimit
+}
+
+// checkConversionLimits rejects amounts outside the source currency's limits.
+// It reports whether the amount is large enough to need manual review.
+func (s *ExchangeService) checkConversionLimits(amount float64, currency string) (bool, error) {
+	limit := s.conversionLimit(currency)
+
+	if amount < limit.Min {
+		return false, fmt.Errorf("%w: %.2f %s is below the minimum of %.2f %s",
+			ErrConversionAmountOutOfRange, amount, currency, limit.Min, currency)
+	}
+	if limit.Max > 0 && amount > limit.Max {
+		return false, fmt.Errorf("%w: %.2f %s exceeds the maximum of %.2f %s",
+			ErrConversionAmountOutOfRange, amount, currency, limit.Max, currency)
+	}
+
+	return limit.ReviewThreshold > 0 && amount >= limit.ReviewThreshold, nil
+}
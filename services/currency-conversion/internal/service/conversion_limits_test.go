@@ -0,0 +1,48 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"currency-conversion/internal/models"
+)
+
+func TestCheckConversionLimits(t *testing.T) {
+	s := newTestExchangeService()
+	s.SetConversionLimits(map[string]models.ConversionLimit{
+		"USD": {Min: 10, Max: 50000, ReviewThreshold: 10000},
+		"JPY": {Min: 1000, Max: 5000000},
+	})
+
+	tests := []struct {
+		name       string
+		amount     float64
+		currency   string
+		wantReview bool
+		wantErr    error
+	}{
+		{name: "Below minimum", amount: 9.99, currency: "USD", wantErr: ErrConversionAmountOutOfRange},
+		{name: "At minimum", amount: 10, currency: "USD"},
+		{name: "Below review threshold", amount: 9999.99, currency: "USD"},
+		{name: "At review threshold", amount: 10000, currency: "USD", wantReview: true},
+		{name: "At maximum", amount: 50000, currency: "USD", wantReview: true},
+		{name: "Above maximum", amount: 50000.01, currency: "USD", wantErr: ErrConversionAmountOutOfRange},
+		{name: "Per-currency minimum", amount: 999, currency: "JPY", wantErr: ErrConversionAmountOutOfRange},
+		{name: "Per-currency maximum without review", amount: 5000000, currency: "JPY"},
+		{name: "Default minimum", amount: 0.5, currency: "EUR", wantErr: ErrConversionAmountOutOfRange},
+		{name: "Default review threshold", amount: 100000, currency: "EUR", wantReview: true},
+		{name: "Default maximum", amount: 1000001, currency: "EUR", wantErr: ErrConversionAmountOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review, err := s.checkConversionLimits(tt.amount, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("checkConversionLimits() error = %v, want %v", err, tt.wantErr)
+			}
+			if review != tt.wantReview {
+				t.Errorf("checkConversionLimits() review = %v, want %v", review, tt.wantReview)
+			}
+		})
+	}
+}
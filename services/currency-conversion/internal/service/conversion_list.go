@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/pagination"
+)
+
+// ListConversions returns a page of conversions matching the filter, newest first
+func (s *ExchangeService) ListConversions(ctx context.Context, filter models.ConversionFilter) (pagination.Page[*models.Conversion], error) {
+	var empty pagination.Page[*models.Conversion]
+
+	conversions, err := s.repo.ListConversions(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+	total, err := s.repo.CountConversions(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+
+	return pagination.NewPage(conversions, total, filter.Limit, filter.Offset), nil
+}
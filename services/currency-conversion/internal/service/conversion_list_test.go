@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+)
+
+func TestListConversions(t *testing.T) {
+	store := &fakeRateStore{conversions: []*models.Conversion{
+		{ID: "c1", FromCurrency: "EUR", ToCurrency: "USD"},
+		{ID: "c2", FromCurrency: "GBP", ToCurrency: "USD"},
+		{ID: "c3", FromCurrency: "EUR", ToCurrency: "JPY"},
+	}}
+	s := NewExchangeService(store, nil, "", zap.NewNop())
+
+	tests := []struct {
+		name      string
+		filter    models.ConversionFilter
+		wantIDs   []string
+		wantTotal int
+	}{
+		{"all", models.ConversionFilter{Limit: 50}, []string{"c1", "c2", "c3"}, 3},
+		{"from currency", models.ConversionFilter{FromCurrency: "EUR", Limit: 50}, []string{"c1", "c3"}, 2},
+		{"pair", models.ConversionFilter{FromCurrency: "EUR", ToCurrency: "JPY", Limit: 50}, []string{"c3"}, 1},
+		{"no match", models.ConversionFilter{FromCurrency: "CHF", Limit: 50}, []string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.ListConversions(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("ListConversions() error = %v", err)
+			}
+			if page.Total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", page.Total, tt.wantTotal)
+			}
+			if len(page.Items) != len(tt.wantIDs) {
+				t.Fatalf("got %d conversions, want %d", len(page.Items), len(tt.wantIDs))
+			}
+			for i, conversion := range page.Items {
+				if conversion.ID != tt.wantIDs[i] {
+					t.Errorf("Items[%d].ID = %s, want %s", i, conversion.ID, tt.wantIDs[i])
+				}
+			}
+			if page.HasMore {
+				t.Error("HasMore = true, want false")
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"currency-conversion/internal/models"
+)
+
+func TestConvertMixedCaseCurrenciesShareCacheKey(t *testing.T) {
+	provider := &fakeProvider{name: "primary"}
+	s := newTestExchangeService(provider)
+	cache := memoryRateCache{}
+	s.redisClient = cache
+	s.repo = &fakeRateStore{}
+
+	var responses []*models.ConversionResponse
+	for _, body := range []string{
+		`{"amount": 100, "from_currency": "usd", "to_currency": " eur "}`,
+		`{"amount": 100, "from_currency": "USD", "to_currency": "EUR"}`,
+	} {
+		var req models.ConversionRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("failed to bind %s: %v", body, err)
+		}
+		response, err := s.Convert(context.Background(), &req)
+		if err != nil {
+			t.Fatalf("Convert(%s) error = %v", body, err)
+		}
+		responses = append(responses, response)
+	}
+
+	if provider.calls != 1 {
+		t.Errorf("provider called %d times, want 1", provider.calls)
+	}
+	if _, ok := cache[rateCacheKey("USD", "EUR")]; !ok || len(cache) != 1 {
+		t.Errorf("cache keys = %v, want only %s", cache, rateCacheKey("USD", "EUR"))
+	}
+	if responses[0].FromCurrency != "USD" || responses[0].ToCurrency != "EUR" {
+		t.Errorf("converted %s→%s, want USD→EUR", responses[0].FromCurrency, responses[0].ToCurrency)
+	}
+	if responses[0].ExchangeRate.Cmp(responses[1].ExchangeRate) != 0 {
+		t.Errorf("rates = %v and %v, want the same rate", responses[0].ExchangeRate, responses[1].ExchangeRate)
+	}
+}
@@ -5,87 +5,228 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
-	"net/http"
+	"sync"
 	"time"
 
 	"go.uber.org/zap"
 
 	"currency-conversion/internal/models"
-	"currency-conversion/internal/repository"
-	"shared/pkg/redis"
+	"shared/pkg/events"
+	"shared/pkg/ids"
+	"shared/pkg/money"
 )
 
+var (
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+	ErrRateUnavailable     = errors.New("exchange rate unavailable")
+	ErrRateNotFound        = errors.New("no exchange rate recorded")
+)
+
+// RateStore persists rates and conversions; implemented by repository.RateRepository
+type RateStore interface {
+	SaveRate(ctx context.Context, rate *models.ExchangeRate) error
+	GetLatestRate(ctx context.Context, from, to string) (*models.ExchangeRate, error)
+	GetRateHistory(ctx context.Context, from, to string, startDate time.Time) ([]*models.ExchangeRate, error)
+	GetRateAt(ctx context.Context, from, to string, at time.Time) (*models.ExchangeRate, error)
+	SaveConversion(ctx context.Context, conversion *models.Conversion) error
+	StreamConversions(ctx context.Context, start, end time.Time, fn func(*models.Conversion) error) error
+	ListConversions(ctx context.Context, filter models.ConversionFilter) ([]*models.Conversion, error)
+	CountConversions(ctx context.Context, filter models.ConversionFilter) (int, error)
+	ClaimConversionIdempotencyKey(ctx context.Context, record *models.ConversionIdempotencyRecord) (bool, error)
+	GetConversionByIdempotencyKey(ctx context.Context, key string) (*models.ConversionIdempotencyRecord, error)
+}
+
+// RateCacheStore holds recently fetched rates; implemented by the shared Redis client
+type RateCacheStore interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
 type ExchangeService struct {
-	repo        *repository.RateRepository
-	redisClient *redis.Client
-	apiKey      string
-	apiURL      string
+	repo        RateStore
+	redisClient RateCacheStore
 	logger      *zap.Logger
+
+	providersMu sync.RWMutex
+	providers   []*providerEntry
+
+	limitsMu sync.RWMutex
+	limits   map[string]models.ConversionLimit
+
+	feesMu    sync.RWMutex
+	feeTiers  map[string]float64
+	feeBounds map[string]models.FeeBounds
+
+	streamMu       sync.RWMutex
+	streamInterval time.Duration
+
+	ttlMu    sync.RWMutex
+	rateTTLs RateTTLs
+
+	quotes   QuoteStore
+	quoteTTL time.Duration
+
+	events events.Publisher
 }
 
-func NewExchangeService(repo *repository.RateRepository, redisClient *redis.Client, apiKey string, logger *zap.Logger) *ExchangeService {
-	return &ExchangeService{
+func NewExchangeService(repo RateStore, redisClient RateCacheStore, apiKey string, logger *zap.Logger) *ExchangeService {
+	s := &ExchangeService{
 		repo:        repo,
 		redisClient: redisClient,
-		apiKey:      apiKey,
-		apiURL:      "https://v6.exchangerate-api.com/v6",
 		logger:      logger,
+		events:      events.NopPublisher{},
 	}
+	s.RegisterProvider(NewExchangeRateAPIProvider(apiKey), 1)
+	return s
 }
 
-// Convert converts an amount from one currency to another
+// Convert converts an amount from one currency to another. Requests carrying an
+// idempotency key are converted at most once.
 func (s *ExchangeService) Convert(ctx context.Context, req *models.ConversionRequest) (*models.ConversionResponse, error) {
+	if req.IdempotencyKey != "" {
+		return s.convertIdempotently(ctx, req)
+	}
+
+	response, err := s.priceConversion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordConversion(ctx, response)
+	return response, nil
+}
+
+// priceConversion validates a conversion and prices it at the current rate without recording it
+func (s *ExchangeService) priceConversion(ctx context.Context, req *models.ConversionRequest) (*models.ConversionResponse, error) {
+	if err := s.validatePair(req.FromCurrency, req.ToCurrency); err != nil {
+		return nil, err
+	}
+
+	// Converting a currency to itself moves no money, so it is 1:1 and free
+	if req.FromCurrency == req.ToCurrency {
+		return &models.ConversionResponse{
+			OriginalAmount:  req.Amount,
+			ConvertedAmount: req.Amount,
+			FromCurrency:    req.FromCurrency,
+			ToCurrency:      req.ToCurrency,
+			ExchangeRate:    money.NewDecimal(1),
+			FeeMode:         feeMode(req.FeeMode),
+			RateTimestamp:   time.Now(),
+			ConversionID:    ids.Conversion(),
+		}, nil
+	}
+
+	// Enforce the source currency's conversion limits
+	requiresReview, err := s.checkConversionLimits(req.Amount, req.FromCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve the customer's tier before fetching a rate
+	tier, feePercentage, err := s.feePercentage(req.CustomerTier)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get exchange rate
 	rate, err := s.GetRate(ctx, req.FromCurrency, req.ToCurrency)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get exchange rate: %w", err)
 	}
 
-	// Calculate converted amount
-	convertedAmount := req.Amount * rate.Rate
+	// Calculate in exact decimals, rounding to the target currency's minor unit only at the end
+	convertedAmount := money.NewDecimal(req.Amount).Mul(rate.Rate)
+
+	// Calculate fee from the customer tier's schedule, clamped to the target
+	// currency's fee bounds. An inclusive fee comes out of the converted amount;
+	// an exclusive one is charged on top of it.
+	fee, feeBound := s.boundFee(convertedAmount.Mul(money.NewDecimal(feePercentage)), req.ToCurrency)
+	mode := feeMode(req.FeeMode)
+	finalAmount := convertedAmount
+	if mode == models.FeeModeInclusive {
+		finalAmount = convertedAmount.Sub(fee)
+		if finalAmount.Sign() <= 0 {
+			return nil, fmt.Errorf("%w: %.2f %s does not cover the fee of %s %s",
+				ErrConversionAmountOutOfRange, req.Amount, req.FromCurrency, fee, req.ToCurrency)
+		}
+	}
 
-	// Calculate fee (0.5% for example)
-	feePercentage := 0.005
-	fee := convertedAmount * feePercentage
-	finalAmount := convertedAmount - fee
+	rounding := req.RoundingMode
+	if rounding == "" {
+		rounding = money.RoundBanker
+	}
 
-	response := &models.ConversionResponse{
+	places := money.Exponent(req.ToCurrency)
+	return &models.ConversionResponse{
 		OriginalAmount:   req.Amount,
-		ConvertedAmount:  finalAmount,
+		ConvertedAmount:  finalAmount.RoundMode(places, rounding).Float64(),
 		FromCurrency:     req.FromCurrency,
 		ToCurrency:       req.ToCurrency,
 		ExchangeRate:     rate.Rate,
-		Fee:              fee,
+		Fee:              fee.Round(places).Float64(),
 		FeePercentage:    feePercentage,
+		FeeMode:          mode,
+		FeeBound:         feeBound,
+		CustomerTier:     tier,
 		RateTimestamp:    rate.Timestamp,
-		ConversionID:     generateConversionID(),
+		ConversionID:     ids.Conversion(),
+		RequiresReview:   requiresReview,
+	}, nil
+}
+
+// feeMode resolves a request's fee mode, defaulting to inclusive
+func feeMode(mode models.FeeMode) models.FeeMode {
+	if mode == "" {
+		return models.FeeModeInclusive
+	}
+	return mode
+}
+
+// recordConversion saves a completed conversion to the history; fields are added
+// to the log if saving fails
+func (s *ExchangeService) recordConversion(ctx context.Context, response *models.ConversionResponse, fields ...zap.Field) {
+	if response.RequiresReview {
+		s.logger.Warn("large conversion flagged for manual review",
+			zap.String("conversion_id", response.ConversionID),
+			zap.Float64("amount", response.OriginalAmount),
+			zap.String("from", response.FromCurrency),
+			zap.String("to", response.ToCurrency))
 	}
 
 	// Save conversion history
 	conversion := &models.Conversion{
 		ID:              response.ConversionID,
-		FromCurrency:    req.FromCurrency,
-		ToCurrency:      req.ToCurrency,
-		OriginalAmount:  req.Amount,
-		ConvertedAmount: finalAmount,
-		ExchangeRate:    rate.Rate,
-		Fee:             fee,
+		FromCurrency:    response.FromCurrency,
+		ToCurrency:      response.ToCurrency,
+		OriginalAmount:  response.OriginalAmount,
+		ConvertedAmount: response.ConvertedAmount,
+		ExchangeRate:    response.ExchangeRate,
+		Fee:             response.Fee,
 		CreatedAt:       time.Now(),
 	}
 	
 	if err := s.repo.SaveConversion(ctx, conversion); err != nil {
-		s.logger.Error("failed to save conversion", zap.Error(err))
+		fields = append(fields, zap.String("conversion_id", conversion.ID), zap.Error(err))
+		s.logger.Error("failed to save conversion", fields...)
 	}
 
-	return response, nil
+	s.publishConversionCompleted(ctx, response)
 }
 
 // GetRate retrieves the exchange rate with caching
 func (s *ExchangeService) GetRate(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	if err := s.validatePair(from, to); err != nil {
+		return nil, err
+	}
+
+	if from == to {
+		return identityRate(from), nil
+	}
+
 	// Check cache first
-	cacheKey := fmt.Sprintf("rate:%s:%s", from, to)
+	cacheKey := rateCacheKey(from, to)
 	
 	if cached, err := s.getCachedRate(ctx, cacheKey); err == nil && cached != nil {
 		s.logger.Debug("cache hit for exchange rate", 
@@ -94,8 +235,16 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to string) (*models
 		return cached, nil
 	}
 
-	// Fetch from API
-	rate, err := s.fetchRateFromAPI(from, to)
+	// Derive the rate from a cached inverse pair before asking a provider
+	if inverse, err := s.getCachedRate(ctx, rateCacheKey(to, from)); err == nil && inverse != nil && inverse.Rate.Sign() > 0 {
+		s.logger.Debug("derived exchange rate from cached inverse",
+			zap.String("from", from),
+			zap.String("to", to))
+		return invertRate(inverse), nil
+	}
+
+	// Fetch from the highest-priority healthy provider
+	rate, err := s.fetchFromProviders(ctx, from, to)
 	if err != nil {
 		// Try to get from database as fallback
 		if dbRate, dbErr := s.repo.GetLatestRate(ctx, from, to); dbErr == nil {
@@ -104,11 +253,11 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to string) (*models
 				zap.String("to", to))
 			return dbRate, nil
 		}
-		return nil, err
+		return nil, fmt.Errorf("%w: %v", ErrRateUnavailable, err)
 	}
 
-	// Cache the rate (5 minutes TTL)
-	s.cacheRate(ctx, cacheKey, rate, 5*time.Minute)
+	// Cache the rate for the pair's TTL
+	s.cacheRate(ctx, cacheKey, rate, s.rateTTL(from, to))
 
 	// Save to database for historical tracking
 	if err := s.repo.SaveRate(ctx, rate); err != nil {
@@ -118,56 +267,35 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to string) (*models
 	return rate, nil
 }
 
-// fetchRateFromAPI fetches exchange rate from external API
-func (s *ExchangeService) fetchRateFromAPI(from, to string) (*models.ExchangeRate, error) {
-	url := fmt.Sprintf("%s/%s/pair/%s/%s", s.apiURL, s.apiKey, from, to)
-	
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("API request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
-	}
+// GetHistoricalRates retrieves historical rates for a currency pair
+func (s *ExchangeService) GetHistoricalRates(ctx context.Context, from, to string, days int) ([]*models.ExchangeRate, error) {
+	startDate := time.Now().AddDate(0, 0, -days)
+	return s.repo.GetRateHistory(ctx, from, to, startDate)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
+// GetRateAt returns the rate for a pair as of a past moment: the latest stored
+// rate recorded at or before at
+func (s *ExchangeService) GetRateAt(ctx context.Context, from, to string, at time.Time) (*models.ExchangeRate, error) {
+	if err := s.validatePair(from, to); err != nil {
+		return nil, err
 	}
 
-	var apiResp struct {
-		Result          string  `json:"result"`
-		ConversionRate  float64 `json:"conversion_rate"`
-		TimeLastUpdate  int64   `json:"time_last_update_unix"`
+	if from == to {
+		rate := identityRate(from)
+		rate.Timestamp = at
+		return rate, nil
 	}
 
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	rate, err := s.repo.GetRateAt(ctx, from, to, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up rate history: %w", err)
 	}
-
-	if apiResp.Result != "success" {
-		return nil, fmt.Errorf("API returned error result")
+	if rate == nil {
+		return nil, fmt.Errorf("%w: %s/%s at or before %s", ErrRateNotFound, from, to, at.UTC().Format(time.RFC3339))
 	}
-
-	rate := &models.ExchangeRate{
-		FromCurrency: from,
-		ToCurrency:   to,
-		Rate:         apiResp.ConversionRate,
-		Timestamp:    time.Unix(apiResp.TimeLastUpdate, 0),
-		Source:       "exchangerate-api.com",
-	}
-
 	return rate, nil
 }
 
-// GetHistoricalRates retrieves historical rates for a currency pair
-func (s *ExchangeService) GetHistoricalRates(ctx context.Context, from, to string, days int) ([]*models.ExchangeRate, error) {
-	startDate := time.Now().AddDate(0, 0, -days)
-	return s.repo.GetRateHistory(ctx, from, to, startDate)
-}
-
 // GetSupportedCurrencies returns list of supported currencies
 func (s *ExchangeService) GetSupportedCurrencies() []string {
 	return []string{
@@ -178,6 +306,21 @@ func (s *ExchangeService) GetSupportedCurrencies() []string {
 	}
 }
 
+// validatePair rejects currencies outside the supported list
+func (s *ExchangeService) validatePair(from, to string) error {
+	supported := make(map[string]bool)
+	for _, currency := range s.GetSupportedCurrencies() {
+		supported[currency] = true
+	}
+
+	for _, currency := range []string{from, to} {
+		if !supported[currency] {
+			return fmt.Errorf("%w: %q", ErrUnsupportedCurrency, currency)
+		}
+	}
+	return nil
+}
+
 // Cache helpers
 
 func (s *ExchangeService) getCachedRate(ctx context.Context, key string) (*models.ExchangeRate, error) {
@@ -199,6 +342,33 @@ func (s *ExchangeService) cacheRate(ctx context.Context, key string, rate *model
 	s.redisClient.Set(ctx, key, data, ttl)
 }
 
-func generateConversionID() string {
-	return fmt.Sprintf("conv_%d", time.Now().UnixNano())
-}
\ No newline at end of file
+func rateCacheKey(from, to string) string {
+	return fmt.Sprintf("rate:%s:%s", from, to)
+}
+
+// invertRate turns a to→from rate into from→to, rounded to the precision rates are stored at
+func invertRate(rate *models.ExchangeRate) *models.ExchangeRate {
+	return &models.ExchangeRate{
+		FromCurrency: rate.ToCurrency,
+		ToCurrency:   rate.FromCurrency,
+		Rate:         money.NewDecimal(1).Div(rate.Rate, rateDecimalPlaces),
+		Source:       rate.Source,
+		Timestamp:    rate.Timestamp,
+		Derived:      true,
+	}
+}
+
+// identityRate is the 1:1 rate of a currency to itself
+func identityRate(currency string) *models.ExchangeRate {
+	return &models.ExchangeRate{
+		FromCurrency: currency,
+		ToCurrency:   currency,
+		Rate:         money.NewDecimal(1),
+		Source:       "identity",
+		Timestamp:    time.Now(),
+		Derived:      true,
+	}
+}
+
+// rateDecimalPlaces is the precision of the exchange_rates.rate column
+const rateDecimalPlaces = 10
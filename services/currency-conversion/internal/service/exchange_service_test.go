@@ -0,0 +1,327 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+// memoryRateCache is an in-memory RateCacheStore
+type memoryRateCache map[string]string
+
+func (c memoryRateCache) Get(ctx context.Context, key string) (string, error) {
+	value, ok := c[key]
+	if !ok {
+		return "", errors.New("key not found")
+	}
+	return value, nil
+}
+
+func (c memoryRateCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	switch v := value.(type) {
+	case []byte:
+		c[key] = string(v)
+	case string:
+		c[key] = v
+	default:
+		return errors.New("unsupported cache value")
+	}
+	return nil
+}
+
+func TestGetRateDerivesFromCachedInverse(t *testing.T) {
+	provider := &fakeProvider{name: "primary"}
+	s := newTestExchangeService(provider)
+	cache := memoryRateCache{}
+	s.redisClient = cache
+
+	cached, _ := json.Marshal(&models.ExchangeRate{
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+		Rate:         money.NewDecimal(0.92),
+		Source:       "primary",
+		Timestamp:    time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC),
+	})
+	cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+	rate, err := s.GetRate(context.Background(), "EUR", "USD")
+	if err != nil {
+		t.Fatalf("GetRate() error = %v", err)
+	}
+
+	if provider.calls != 0 {
+		t.Errorf("provider called %d times, want 0", provider.calls)
+	}
+	if rate.FromCurrency != "EUR" || rate.ToCurrency != "USD" {
+		t.Errorf("pair = %s→%s, want EUR→USD", rate.FromCurrency, rate.ToCurrency)
+	}
+	if rate.Rate.String() != "1.0869565217" {
+		t.Errorf("Rate = %v, want 1.0869565217", rate.Rate)
+	}
+	if !rate.Derived {
+		t.Error("rate should be marked as derived")
+	}
+}
+
+func TestInvertRateRounding(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{rate: 0.5, want: "2"},
+		{rate: 3, want: "0.3333333333"},
+		{rate: 149.5, want: "0.0066889632"},
+		{rate: 0.0066889632, want: "149.5000002392"},
+	}
+
+	for _, tt := range tests {
+		got := invertRate(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "JPY", Rate: money.NewDecimal(tt.rate)})
+		if got.Rate.String() != tt.want {
+			t.Errorf("invertRate(%v) = %v, want %v", tt.rate, got.Rate, tt.want)
+		}
+	}
+}
+
+func TestConvertSameCurrencyIsOneToOneWithoutFee(t *testing.T) {
+	provider := &fakeProvider{name: "primary"}
+	s := newTestExchangeService(provider)
+	s.redisClient = memoryRateCache{}
+	repo := &fakeRateStore{}
+	s.repo = repo
+
+	response, err := s.Convert(context.Background(), &models.ConversionRequest{
+		Amount:       250.75,
+		FromCurrency: "USD",
+		ToCurrency:   "USD",
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	if response.ConvertedAmount != 250.75 {
+		t.Errorf("ConvertedAmount = %v, want 250.75", response.ConvertedAmount)
+	}
+	if response.ExchangeRate.Cmp(money.NewDecimal(1)) != 0 || response.Fee != 0 || response.FeePercentage != 0 {
+		t.Errorf("rate = %v, fee = %v (%v), want 1:1 with no fee", response.ExchangeRate, response.Fee, response.FeePercentage)
+	}
+	if provider.calls != 0 {
+		t.Errorf("provider called %d times, want 0", provider.calls)
+	}
+	if len(repo.conversions) != 1 {
+		t.Errorf("recorded %d conversions, want 1", len(repo.conversions))
+	}
+}
+
+func TestConvertLargeAmountIsExact(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	cache := memoryRateCache{}
+	s.redisClient = cache
+	s.repo = &fakeRateStore{}
+
+	rate, _ := money.ParseDecimal("0.9212345678")
+	cached, _ := json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: rate, Timestamp: time.Now()})
+	cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+	response, err := s.Convert(context.Background(), &models.ConversionRequest{
+		Amount:       1_000_000,
+		FromCurrency: "USD",
+		ToCurrency:   "EUR",
+	})
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	// 1,000,000 × 0.9212345678 = 921,234.5678, the 0.5% fee is 4,606.172839 and
+	// the remainder 916,628.394961; only the results are rounded to cents
+	if response.ExchangeRate.String() != "0.9212345678" {
+		t.Errorf("ExchangeRate = %v, want 0.9212345678", response.ExchangeRate)
+	}
+	if response.Fee != 4606.17 {
+		t.Errorf("Fee = %v, want 4606.17", response.Fee)
+	}
+	if response.ConvertedAmount != 916628.39 {
+		t.Errorf("ConvertedAmount = %v, want 916628.39", response.ConvertedAmount)
+	}
+}
+
+func TestConvertRoundingModes(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		mode   money.RoundingMode
+		want   float64
+	}{
+		// 2.25 × 0.5 = 1.125, halfway between 1.12 and 1.13
+		{name: "Default is banker's", amount: 2.25, want: 1.12},
+		{name: "Banker's rounds half to even", amount: 2.25, mode: money.RoundBanker, want: 1.12},
+		{name: "Banker's rounds half up to even", amount: 2.27, mode: money.RoundBanker, want: 1.14},
+		{name: "Nearest rounds half away from zero", amount: 2.25, mode: money.RoundNearest, want: 1.13},
+		// 2.23 × 0.5 = 1.115
+		{name: "Floor", amount: 2.23, mode: money.RoundFloor, want: 1.11},
+		{name: "Ceil", amount: 2.23, mode: money.RoundCeil, want: 1.12},
+		// 2.27 × 0.5 = 1.135, which banker's takes up to 1.14
+		{name: "Floor where banker's rounds up", amount: 2.27, mode: money.RoundFloor, want: 1.13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestExchangeService(&fakeProvider{name: "primary"})
+			cache := memoryRateCache{}
+			s.redisClient = cache
+			s.repo = &fakeRateStore{}
+			// A fee-free tier so the converted amount is exactly amount × rate
+			s.SetFeeTiers(map[string]float64{"partner": 0})
+
+			cached, _ := json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: money.NewDecimal(0.5), Timestamp: time.Now()})
+			cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+			response, err := s.Convert(context.Background(), &models.ConversionRequest{
+				Amount:       tt.amount,
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				CustomerTier: "partner",
+				RoundingMode: tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if response.ConvertedAmount != tt.want {
+				t.Errorf("ConvertedAmount = %v, want %v", response.ConvertedAmount, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertFeeModes(t *testing.T) {
+	tests := []struct {
+		name          string
+		mode          models.FeeMode
+		wantConverted float64
+		wantMode      models.FeeMode
+	}{
+		// 100 × 0.5 = 50.00 with a 1% fee of 0.50
+		{name: "Default is inclusive", wantConverted: 49.50, wantMode: models.FeeModeInclusive},
+		{name: "Inclusive deducts the fee", mode: models.FeeModeInclusive, wantConverted: 49.50, wantMode: models.FeeModeInclusive},
+		{name: "Exclusive charges the fee on top", mode: models.FeeModeExclusive, wantConverted: 50.00, wantMode: models.FeeModeExclusive},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestExchangeService(&fakeProvider{name: "primary"})
+			cache := memoryRateCache{}
+			s.redisClient = cache
+			s.repo = &fakeRateStore{}
+			s.SetFeeTiers(map[string]float64{"business": 0.01})
+
+			cached, _ := json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: money.NewDecimal(0.5), Timestamp: time.Now()})
+			cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+			response, err := s.Convert(context.Background(), &models.ConversionRequest{
+				Amount:       100,
+				FromCurrency: "USD",
+				ToCurrency:   "EUR",
+				CustomerTier: "business",
+				FeeMode:      tt.mode,
+			})
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if response.ConvertedAmount != tt.wantConverted {
+				t.Errorf("ConvertedAmount = %v, want %v", response.ConvertedAmount, tt.wantConverted)
+			}
+			if response.Fee != 0.50 {
+				t.Errorf("Fee = %v, want 0.50", response.Fee)
+			}
+			if response.FeeMode != tt.wantMode {
+				t.Errorf("FeeMode = %q, want %q", response.FeeMode, tt.wantMode)
+			}
+		})
+	}
+}
+
+func TestConvertReplaysIdempotencyKey(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	cache := memoryRateCache{}
+	s.redisClient = cache
+	repo := &fakeRateStore{}
+	s.repo = repo
+
+	cached, _ := json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: money.NewDecimal(0.5), Timestamp: time.Now()})
+	cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+	req := models.ConversionRequest{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR", IdempotencyKey: "conv-key-1"}
+	first, err := s.Convert(context.Background(), &req)
+	if err != nil {
+		t.Fatalf("Convert() error = %v", err)
+	}
+
+	// The rate moves before the retry, which must still get the original result
+	cached, _ = json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: money.NewDecimal(0.6), Timestamp: time.Now()})
+	cache[rateCacheKey("USD", "EUR")] = string(cached)
+
+	retry := req
+	replayed, err := s.Convert(context.Background(), &retry)
+	if err != nil {
+		t.Fatalf("replayed Convert() error = %v", err)
+	}
+	if replayed.ConversionID != first.ConversionID || replayed.ConvertedAmount != first.ConvertedAmount {
+		t.Errorf("replayed = %+v, want the original %+v", replayed, first)
+	}
+	if len(repo.conversions) != 1 {
+		t.Errorf("recorded %d conversions, want 1", len(repo.conversions))
+	}
+
+	different := req
+	different.Amount = 200
+	if _, err := s.Convert(context.Background(), &different); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("Convert() with the key on another amount error = %v, want ErrIdempotencyKeyReused", err)
+	}
+}
+
+func TestGetRateAt(t *testing.T) {
+	day := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	repo := &fakeRateStore{rates: []*models.ExchangeRate{
+		{FromCurrency: "EUR", ToCurrency: "USD", Rate: money.NewDecimal(1.09), Timestamp: day.Add(8 * time.Hour)},
+		{FromCurrency: "EUR", ToCurrency: "USD", Rate: money.NewDecimal(1.10), Timestamp: day.Add(10 * time.Hour)},
+		{FromCurrency: "EUR", ToCurrency: "USD", Rate: money.NewDecimal(1.11), Timestamp: day.Add(12 * time.Hour)},
+		// Another pair's later rate must not be picked up
+		{FromCurrency: "GBP", ToCurrency: "USD", Rate: money.NewDecimal(1.27), Timestamp: day.Add(11 * time.Hour)},
+	}}
+	s := newTestExchangeService()
+	s.repo = repo
+
+	tests := []struct {
+		name    string
+		at      time.Time
+		want    string
+		wantErr error
+	}{
+		{name: "Exactly at a recorded rate", at: day.Add(10 * time.Hour), want: "1.1"},
+		{name: "Between two rates", at: day.Add(11 * time.Hour), want: "1.1"},
+		{name: "After the last rate", at: day.AddDate(0, 0, 1), want: "1.11"},
+		{name: "Before the first rate", at: day.Add(7 * time.Hour), wantErr: ErrRateNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, err := s.GetRateAt(context.Background(), "EUR", "USD", tt.at)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetRateAt() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if rate.Rate.String() != tt.want {
+				t.Errorf("Rate = %v, want %v", rate.Rate, tt.want)
+			}
+			if rate.Timestamp.After(tt.at) {
+				t.Errorf("Timestamp = %v, want at or before %v", rate.Timestamp, tt.at)
+			}
+		})
+	}
+}
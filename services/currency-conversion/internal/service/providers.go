@@ -0,0 +1,301 @@
+// Rate providers and their health tracking
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+// Circuit breaker states
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+const (
+	// failureThreshold consecutive failures open a provider's circuit
+	failureThreshold = 5
+	// circuitCooldown is how long an open circuit waits before a trial request
+	circuitCooldown = 30 * time.Second
+)
+
+// DefaultExchangeRateAPIURL is where ExchangeRateAPIProvider fetches rates unless configured otherwise
+const DefaultExchangeRateAPIURL = "https://v6.exchangerate-api.com/v6"
+
+var (
+	ErrUnknownProvider    = errors.New("unknown rate provider")
+	ErrInvalidProviderURL = errors.New("invalid rate provider URL")
+	ErrProviderURLFixed   = errors.New("rate provider has no configurable URL")
+)
+
+// RateProvider fetches exchange rates from an upstream source
+type RateProvider interface {
+	Name() string
+	FetchRate(ctx context.Context, from, to string) (*models.ExchangeRate, error)
+}
+
+// baseURLSetter is a RateProvider whose upstream can be moved, e.g. to a sandbox or caching proxy
+type baseURLSetter interface {
+	SetBaseURL(rawURL string) error
+}
+
+// ProviderStatus reports the health of a configured rate provider
+type ProviderStatus struct {
+	Name                string     `json:"name"`
+	Priority            int        `json:"priority"`
+	LastSuccess         *time.Time `json:"last_success,omitempty"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CircuitState        string     `json:"circuit_state"`
+}
+
+// providerEntry tracks a provider's health alongside the provider itself
+type providerEntry struct {
+	provider RateProvider
+	priority int
+
+	mu                  sync.Mutex
+	lastSuccess         time.Time
+	consecutiveFailures int
+	openedAt            time.Time
+}
+
+// available reports whether the provider may be tried, moving an open circuit
+// to half-open once the cooldown has passed
+func (p *providerEntry) available(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.stateLocked(now) != CircuitOpen
+}
+
+func (p *providerEntry) recordSuccess(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastSuccess = now
+	p.consecutiveFailures = 0
+	p.openedAt = time.Time{}
+}
+
+func (p *providerEntry) recordFailure(now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.consecutiveFailures++
+	if p.consecutiveFailures >= failureThreshold {
+		// (Re)open the circuit, including after a failed half-open trial
+		p.openedAt = now
+	}
+}
+
+func (p *providerEntry) status(now time.Time) ProviderStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := ProviderStatus{
+		Name:                p.provider.Name(),
+		Priority:            p.priority,
+		ConsecutiveFailures: p.consecutiveFailures,
+		CircuitState:        p.stateLocked(now),
+	}
+	if !p.lastSuccess.IsZero() {
+		lastSuccess := p.lastSuccess
+		status.LastSuccess = &lastSuccess
+	}
+	return status
+}
+
+func (p *providerEntry) stateLocked(now time.Time) string {
+	if p.consecutiveFailures < failureThreshold {
+		return CircuitClosed
+	}
+	if now.Sub(p.openedAt) >= circuitCooldown {
+		return CircuitHalfOpen
+	}
+	return CircuitOpen
+}
+
+// RegisterProvider adds a rate provider. Lower priority values are tried first.
+func (s *ExchangeService) RegisterProvider(provider RateProvider, priority int) {
+	s.providersMu.Lock()
+	defer s.providersMu.Unlock()
+
+	s.providers = append(s.providers, &providerEntry{
+		provider: provider,
+		priority: priority,
+	})
+	sort.SliceStable(s.providers, func(i, j int) bool {
+		return s.providers[i].priority < s.providers[j].priority
+	})
+}
+
+// SetProviderBaseURL points the named provider at a different upstream base URL
+func (s *ExchangeService) SetProviderBaseURL(name, rawURL string) error {
+	s.providersMu.RLock()
+	defer s.providersMu.RUnlock()
+
+	for _, p := range s.providers {
+		if p.provider.Name() != name {
+			continue
+		}
+		setter, ok := p.provider.(baseURLSetter)
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrProviderURLFixed, name)
+		}
+		return setter.SetBaseURL(rawURL)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownProvider, name)
+}
+
+// ProviderStatuses returns the health of each configured provider in priority order
+func (s *ExchangeService) ProviderStatuses() []ProviderStatus {
+	s.providersMu.RLock()
+	defer s.providersMu.RUnlock()
+
+	now := time.Now()
+	statuses := make([]ProviderStatus, 0, len(s.providers))
+	for _, p := range s.providers {
+		statuses = append(statuses, p.status(now))
+	}
+	return statuses
+}
+
+// fetchFromProviders tries each available provider in priority order,
+// returning the first successful rate
+func (s *ExchangeService) fetchFromProviders(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	s.providersMu.RLock()
+	providers := make([]*providerEntry, len(s.providers))
+	copy(providers, s.providers)
+	s.providersMu.RUnlock()
+
+	lastErr := fmt.Errorf("no rate providers available")
+	for _, p := range providers {
+		if !p.available(time.Now()) {
+			continue
+		}
+
+		rate, err := p.provider.FetchRate(ctx, from, to)
+		if err != nil {
+			p.recordFailure(time.Now())
+			lastErr = fmt.Errorf("%s: %w", p.provider.Name(), err)
+			continue
+		}
+
+		p.recordSuccess(time.Now())
+		return rate, nil
+	}
+
+	return nil, lastErr
+}
+
+// ExchangeRateAPIProvider fetches rates from exchangerate-api.com
+type ExchangeRateAPIProvider struct {
+	apiKey string
+	apiURL string
+	client *http.Client
+}
+
+// NewExchangeRateAPIProvider creates a provider for exchangerate-api.com
+func NewExchangeRateAPIProvider(apiKey string) *ExchangeRateAPIProvider {
+	return &ExchangeRateAPIProvider{
+		apiKey: apiKey,
+		apiURL: DefaultExchangeRateAPIURL,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Name returns the provider name
+func (p *ExchangeRateAPIProvider) Name() string {
+	return "exchangerate-api.com"
+}
+
+// SetBaseURL fetches rates from rawURL instead of DefaultExchangeRateAPIURL.
+// It must be an absolute http or https URL without a query or fragment.
+func (p *ExchangeRateAPIProvider) SetBaseURL(rawURL string) error {
+	baseURL, err := parseProviderURL(rawURL)
+	if err != nil {
+		return err
+	}
+	p.apiURL = baseURL
+	return nil
+}
+
+// FetchRate fetches exchange rate from external API
+func (p *ExchangeRateAPIProvider) FetchRate(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	url := fmt.Sprintf("%s/%s/pair/%s/%s", p.apiURL, p.apiKey, from, to)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiResp struct {
+		Result         string        `json:"result"`
+		ConversionRate money.Decimal `json:"conversion_rate"`
+		TimeLastUpdate int64         `json:"time_last_update_unix"`
+	}
+
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if apiResp.Result != "success" {
+		return nil, fmt.Errorf("API returned error result")
+	}
+
+	rate := &models.ExchangeRate{
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         apiResp.ConversionRate,
+		Timestamp:    time.Unix(apiResp.TimeLastUpdate, 0),
+		Source:       p.Name(),
+	}
+
+	return rate, nil
+}
+
+// parseProviderURL validates a provider base URL and drops any trailing slash
+func parseProviderURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidProviderURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: %q must use http or https", ErrInvalidProviderURL, rawURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: %q has no host", ErrInvalidProviderURL, rawURL)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("%w: %q must not have a query or fragment", ErrInvalidProviderURL, rawURL)
+	}
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
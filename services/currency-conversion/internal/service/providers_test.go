@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+type fakeProvider struct {
+	name  string
+	err   error
+	calls int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) FetchRate(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &models.ExchangeRate{FromCurrency: from, ToCurrency: to, Rate: money.NewDecimal(0.92), Source: p.name, Timestamp: time.Now()}, nil
+}
+
+func newTestExchangeService(providers ...RateProvider) *ExchangeService {
+	s := &ExchangeService{logger: zap.NewNop()}
+	for i, p := range providers {
+		s.RegisterProvider(p, i+1)
+	}
+	return s
+}
+
+func TestFetchFromProvidersFailsOver(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: errors.New("status 503")}
+	secondary := &fakeProvider{name: "secondary"}
+	s := newTestExchangeService(primary, secondary)
+
+	rate, err := s.fetchFromProviders(context.Background(), "USD", "EUR")
+	if err != nil {
+		t.Fatalf("fetchFromProviders() error = %v", err)
+	}
+	if rate.Source != "secondary" {
+		t.Errorf("rate served by %s, want secondary", rate.Source)
+	}
+}
+
+func TestProviderStatusesReflectFailingProvider(t *testing.T) {
+	primary := &fakeProvider{name: "primary", err: errors.New("status 503")}
+	secondary := &fakeProvider{name: "secondary"}
+	s := newTestExchangeService(primary, secondary)
+
+	for i := 0; i < failureThreshold; i++ {
+		if _, err := s.fetchFromProviders(context.Background(), "USD", "EUR"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	statuses := s.ProviderStatuses()
+	if len(statuses) != 2 {
+		t.Fatalf("got %d statuses, want 2", len(statuses))
+	}
+
+	failing := statuses[0]
+	if failing.Name != "primary" || failing.Priority != 1 {
+		t.Errorf("statuses[0] = %s (priority %d), want primary (priority 1)", failing.Name, failing.Priority)
+	}
+	if failing.ConsecutiveFailures != failureThreshold {
+		t.Errorf("ConsecutiveFailures = %d, want %d", failing.ConsecutiveFailures, failureThreshold)
+	}
+	if failing.CircuitState != CircuitOpen {
+		t.Errorf("CircuitState = %s, want %s", failing.CircuitState, CircuitOpen)
+	}
+	if failing.LastSuccess != nil {
+		t.Errorf("LastSuccess = %v, want nil", failing.LastSuccess)
+	}
+
+	healthy := statuses[1]
+	if healthy.CircuitState != CircuitClosed || healthy.LastSuccess == nil {
+		t.Errorf("secondary status = %+v, want closed with a last success", healthy)
+	}
+
+	// Open circuit is skipped without calling the provider
+	calls := primary.calls
+	s.fetchFromProviders(context.Background(), "USD", "EUR")
+	if primary.calls != calls {
+		t.Error("open circuit should not be tried")
+	}
+}
+
+func TestExchangeRateAPIProviderBaseURL(t *testing.T) {
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{"result": "success", "conversion_rate": 0.92, "time_last_update_unix": 1700000000}`))
+	}))
+	defer server.Close()
+
+	s := NewExchangeService(nil, nil, "test-key", zap.NewNop())
+	if err := s.SetProviderBaseURL("exchangerate-api.com", server.URL+"/v6/"); err != nil {
+		t.Fatalf("SetProviderBaseURL() error = %v", err)
+	}
+
+	rate, err := s.fetchFromProviders(context.Background(), "USD", "EUR")
+	if err != nil {
+		t.Fatalf("fetchFromProviders() error = %v", err)
+	}
+	if path != "/v6/test-key/pair/USD/EUR" {
+		t.Errorf("requested %s, want /v6/test-key/pair/USD/EUR", path)
+	}
+	if rate.Rate.String() != "0.92" || rate.Source != "exchangerate-api.com" {
+		t.Errorf("rate = %+v, want 0.92 from exchangerate-api.com", rate)
+	}
+}
+
+func TestSetProviderBaseURLValidates(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		url      string
+		wantErr  error
+	}{
+		{name: "https", provider: "exchangerate-api.com", url: "https://rates-proxy.internal/v6"},
+		{name: "unknown provider", provider: "fixer.io", url: "https://rates-proxy.internal/v6", wantErr: ErrUnknownProvider},
+		{name: "relative", provider: "exchangerate-api.com", url: "/v6", wantErr: ErrInvalidProviderURL},
+		{name: "other scheme", provider: "exchangerate-api.com", url: "ftp://rates-proxy.internal/v6", wantErr: ErrInvalidProviderURL},
+		{name: "query", provider: "exchangerate-api.com", url: "https://rates-proxy.internal/v6?key=1", wantErr: ErrInvalidProviderURL},
+		{name: "not a URL", provider: "exchangerate-api.com", url: "http://[::1", wantErr: ErrInvalidProviderURL},
+		{name: "fixed URL", provider: "primary", url: "https://rates-proxy.internal/v6", wantErr: ErrProviderURLFixed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewExchangeService(nil, nil, "test-key", zap.NewNop())
+			s.RegisterProvider(&fakeProvider{name: "primary"}, 2)
+
+			err := s.SetProviderBaseURL(tt.provider, tt.url)
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("SetProviderBaseURL() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("SetProviderBaseURL() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
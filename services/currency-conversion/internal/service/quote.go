@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/ids"
+)
+
+var (
+	ErrQuotesDisabled = errors.New("conversion quotes are not enabled")
+	ErrQuoteNotFound  = errors.New("quote not found or already used")
+	ErrQuoteExpired   = errors.New("quote has expired")
+)
+
+// defaultQuoteTTL is how long a quote can be executed when no TTL is configured
+const defaultQuoteTTL = 60 * time.Second
+
+// quoteRetention keeps a quote in the store past its expiry so executing it
+// reports ErrQuoteExpired rather than ErrQuoteNotFound
+const quoteRetention = 10 * time.Minute
+
+// QuoteStore holds issued quotes; implemented by the shared Redis client.
+// GetDel returns an empty value for a missing key.
+type QuoteStore interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	GetDel(ctx context.Context, key string) (string, error)
+}
+
+// EnableQuotes stores quotes in store, each executable for ttl after it is issued
+func (s *ExchangeService) EnableQuotes(store QuoteStore, ttl time.Duration) {
+	if ttl <= 0 {
+		ttl = defaultQuoteTTL
+	}
+	s.quotes = store
+	s.quoteTTL = ttl
+}
+
+// CreateQuote prices a conversion at the current rate and locks that rate and fee until the quote expires
+func (s *ExchangeService) CreateQuote(ctx context.Context, req *models.ConversionRequest) (*models.ConversionQuote, error) {
+	if s.quotes == nil {
+		return nil, ErrQuotesDisabled
+	}
+
+	priced, err := s.priceConversion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	quote := &models.ConversionQuote{
+		QuoteID:         ids.WithPrefix(ids.PrefixQuote),
+		OriginalAmount:  priced.OriginalAmount,
+		ConvertedAmount: priced.ConvertedAmount,
+		FromCurrency:    priced.FromCurrency,
+		ToCurrency:      priced.ToCurrency,
+		ExchangeRate:    priced.ExchangeRate,
+		Fee:             priced.Fee,
+		FeePercentage:   priced.FeePercentage,
+		FeeMode:         priced.FeeMode,
+		CustomerTier:    priced.CustomerTier,
+		RateTimestamp:   priced.RateTimestamp,
+		RequiresReview:  priced.RequiresReview,
+		ExpiresAt:       now.Add(s.quoteTTL),
+		CreatedAt:       now,
+	}
+
+	data, err := json.Marshal(quote)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode quote: %w", err)
+	}
+	if err := s.quotes.Set(ctx, quoteKey(quote.QuoteID), data, s.quoteTTL+quoteRetention); err != nil {
+		return nil, fmt.Errorf("failed to store quote: %w", err)
+	}
+
+	return quote, nil
+}
+
+// ExecuteQuote converts at a quote's locked rate. A quote can be executed once;
+// it is consumed even when it turns out to have expired.
+func (s *ExchangeService) ExecuteQuote(ctx context.Context, quoteID string) (*models.ConversionResponse, error) {
+	if s.quotes == nil {
+		return nil, ErrQuotesDisabled
+	}
+
+	// Claiming the quote atomically stops concurrent executions from both succeeding
+	data, err := s.quotes.GetDel(ctx, quoteKey(quoteID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim quote: %w", err)
+	}
+	if data == "" {
+		return nil, ErrQuoteNotFound
+	}
+
+	var quote models.ConversionQuote
+	if err := json.Unmarshal([]byte(data), &quote); err != nil {
+		return nil, fmt.Errorf("failed to decode quote: %w", err)
+	}
+	if !time.Now().Before(quote.ExpiresAt) {
+		return nil, ErrQuoteExpired
+	}
+
+	response := &models.ConversionResponse{
+		ConversionID:    ids.Conversion(),
+		OriginalAmount:  quote.OriginalAmount,
+		ConvertedAmount: quote.ConvertedAmount,
+		FromCurrency:    quote.FromCurrency,
+		ToCurrency:      quote.ToCurrency,
+		ExchangeRate:    quote.ExchangeRate,
+		Fee:             quote.Fee,
+		FeePercentage:   quote.FeePercentage,
+		FeeMode:         quote.FeeMode,
+		CustomerTier:    quote.CustomerTier,
+		RateTimestamp:   quote.RateTimestamp,
+		RequiresReview:  quote.RequiresReview,
+	}
+
+	// The quote is already consumed, so a conversion that fails to save must be traceable to it
+	s.recordConversion(ctx, response, zap.String("quote_id", quoteID))
+	return response, nil
+}
+
+func quoteKey(quoteID string) string {
+	return fmt.Sprintf("quote:%s", quoteID)
+}
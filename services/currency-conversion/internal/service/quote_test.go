@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+// memoryQuoteStore is an in-memory QuoteStore
+type memoryQuoteStore map[string]string
+
+func (q memoryQuoteStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, ok := value.([]byte)
+	if !ok {
+		return errors.New("unsupported quote value")
+	}
+	q[key] = string(data)
+	return nil
+}
+
+func (q memoryQuoteStore) GetDel(ctx context.Context, key string) (string, error) {
+	value := q[key]
+	delete(q, key)
+	return value, nil
+}
+
+// failingQuoteStore is a QuoteStore whose backend is unreachable
+type failingQuoteStore struct{}
+
+func (failingQuoteStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingQuoteStore) GetDel(ctx context.Context, key string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+// fakeRateStore is a RateStore that records saved conversions
+type fakeRateStore struct {
+	rates           []*models.ExchangeRate
+	conversions     []*models.Conversion
+	idempotencyKeys map[string]*models.ConversionIdempotencyRecord
+}
+
+func (r *fakeRateStore) SaveRate(ctx context.Context, rate *models.ExchangeRate) error {
+	return nil
+}
+
+func (r *fakeRateStore) GetLatestRate(ctx context.Context, from, to string) (*models.ExchangeRate, error) {
+	return nil, errors.New("no rate")
+}
+
+func (r *fakeRateStore) GetRateHistory(ctx context.Context, from, to string, startDate time.Time) ([]*models.ExchangeRate, error) {
+	return nil, nil
+}
+
+func (r *fakeRateStore) GetRateAt(ctx context.Context, from, to string, at time.Time) (*models.ExchangeRate, error) {
+	var latest *models.ExchangeRate
+	for _, rate := range r.rates {
+		if rate.FromCurrency != from || rate.ToCurrency != to || rate.Timestamp.After(at) {
+			continue
+		}
+		if latest == nil || rate.Timestamp.After(latest.Timestamp) {
+			latest = rate
+		}
+	}
+	return latest, nil
+}
+
+func (r *fakeRateStore) SaveConversion(ctx context.Context, conversion *models.Conversion) error {
+	r.conversions = append(r.conversions, conversion)
+	return nil
+}
+
+func (r *fakeRateStore) StreamConversions(ctx context.Context, start, end time.Time, fn func(*models.Conversion) error) error {
+	for _, conversion := range r.conversions {
+		if conversion.CreatedAt.Before(start) || !conversion.CreatedAt.Before(end) {
+			continue
+		}
+		if err := fn(conversion); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *fakeRateStore) ListConversions(ctx context.Context, filter models.ConversionFilter) ([]*models.Conversion, error) {
+	conversions := []*models.Conversion{}
+	for _, conversion := range r.conversions {
+		if filter.FromCurrency != "" && conversion.FromCurrency != filter.FromCurrency {
+			continue
+		}
+		if filter.ToCurrency != "" && conversion.ToCurrency != filter.ToCurrency {
+			continue
+		}
+		conversions = append(conversions, conversion)
+	}
+	return conversions, nil
+}
+
+func (r *fakeRateStore) CountConversions(ctx context.Context, filter models.ConversionFilter) (int, error) {
+	conversions, err := r.ListConversions(ctx, filter)
+	return len(conversions), err
+}
+
+func (r *fakeRateStore) ClaimConversionIdempotencyKey(ctx context.Context, record *models.ConversionIdempotencyRecord) (bool, error) {
+	if _, ok := r.idempotencyKeys[record.Key]; ok {
+		return false, nil
+	}
+	if r.idempotencyKeys == nil {
+		r.idempotencyKeys = make(map[string]*models.ConversionIdempotencyRecord)
+	}
+	r.idempotencyKeys[record.Key] = record
+	return true, nil
+}
+
+func (r *fakeRateStore) GetConversionByIdempotencyKey(ctx context.Context, key string) (*models.ConversionIdempotencyRecord, error) {
+	return r.idempotencyKeys[key], nil
+}
+
+func newQuoteTestService() (*ExchangeService, memoryQuoteStore, *fakeRateStore) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	s.redisClient = memoryRateCache{}
+	repo := &fakeRateStore{}
+	s.repo = repo
+	quotes := memoryQuoteStore{}
+	s.EnableQuotes(quotes, time.Minute)
+	return s, quotes, repo
+}
+
+func TestExecuteQuoteWithinWindow(t *testing.T) {
+	s, _, repo := newQuoteTestService()
+	ctx := context.Background()
+
+	quote, err := s.CreateQuote(ctx, &models.ConversionRequest{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("CreateQuote() error = %v", err)
+	}
+	if quote.ExchangeRate.String() != "0.92" {
+		t.Errorf("ExchangeRate = %v, want 0.92", quote.ExchangeRate)
+	}
+	if !quote.ExpiresAt.After(time.Now()) {
+		t.Errorf("ExpiresAt = %v, want in the future", quote.ExpiresAt)
+	}
+	if len(repo.conversions) != 0 {
+		t.Fatalf("quoting saved %d conversions, want 0", len(repo.conversions))
+	}
+
+	// A rate move after quoting must not change the executed conversion
+	cached, _ := json.Marshal(&models.ExchangeRate{FromCurrency: "USD", ToCurrency: "EUR", Rate: money.NewDecimal(1.5), Timestamp: time.Now()})
+	s.redisClient.Set(ctx, rateCacheKey("USD", "EUR"), cached, time.Minute)
+
+	response, err := s.ExecuteQuote(ctx, quote.QuoteID)
+	if err != nil {
+		t.Fatalf("ExecuteQuote() error = %v", err)
+	}
+	if response.ExchangeRate.Cmp(quote.ExchangeRate) != 0 || response.ConvertedAmount != quote.ConvertedAmount || response.Fee != quote.Fee {
+		t.Errorf("executed %+v, want the quoted rate %v, fee %v and amount %v",
+			response, quote.ExchangeRate, quote.Fee, quote.ConvertedAmount)
+	}
+	if len(repo.conversions) != 1 {
+		t.Fatalf("saved %d conversions, want 1", len(repo.conversions))
+	}
+
+	if _, err := s.ExecuteQuote(ctx, quote.QuoteID); !errors.Is(err, ErrQuoteNotFound) {
+		t.Errorf("second ExecuteQuote() error = %v, want %v", err, ErrQuoteNotFound)
+	}
+	if len(repo.conversions) != 1 {
+		t.Errorf("saved %d conversions after reuse, want 1", len(repo.conversions))
+	}
+}
+
+func TestExecuteQuoteExpired(t *testing.T) {
+	s, quotes, repo := newQuoteTestService()
+	ctx := context.Background()
+
+	quote, err := s.CreateQuote(ctx, &models.ConversionRequest{Amount: 100, FromCurrency: "USD", ToCurrency: "EUR"})
+	if err != nil {
+		t.Fatalf("CreateQuote() error = %v", err)
+	}
+
+	// Age the stored quote past its expiry
+	quote.ExpiresAt = time.Now().Add(-time.Second)
+	data, _ := json.Marshal(quote)
+	quotes[quoteKey(quote.QuoteID)] = string(data)
+
+	if _, err := s.ExecuteQuote(ctx, quote.QuoteID); !errors.Is(err, ErrQuoteExpired) {
+		t.Errorf("ExecuteQuote() error = %v, want %v", err, ErrQuoteExpired)
+	}
+	if len(repo.conversions) != 0 {
+		t.Errorf("saved %d conversions, want 0", len(repo.conversions))
+	}
+}
+
+func TestExecuteQuoteUnknown(t *testing.T) {
+	s, _, _ := newQuoteTestService()
+
+	if _, err := s.ExecuteQuote(context.Background(), "quote_missing"); !errors.Is(err, ErrQuoteNotFound) {
+		t.Errorf("ExecuteQuote() error = %v, want %v", err, ErrQuoteNotFound)
+	}
+}
+
+func TestExecuteQuoteStoreUnavailable(t *testing.T) {
+	s, _, _ := newQuoteTestService()
+	s.EnableQuotes(failingQuoteStore{}, time.Minute)
+
+	_, err := s.ExecuteQuote(context.Background(), "quote_1")
+	if err == nil || errors.Is(err, ErrQuoteNotFound) {
+		t.Errorf("ExecuteQuote() error = %v, want a store failure rather than %v", err, ErrQuoteNotFound)
+	}
+}
@@ -20,7 +20,7 @@ type RateCache struct {
 	redis      *redis.Client
 	logger     *zap.Logger
 	memCache   *MemoryCache
-	ttl        time.Duration
+	ttls       RateTTLs
 }
 
 // MemoryCache provides in-memory caching for ultra-fast lookups
@@ -34,6 +34,7 @@ type MemoryCache struct {
 type CacheEntry struct {
 	Rate      *models.ExchangeRate
 	CachedAt  time.Time
+	TTL       time.Duration
 }
 
 // NewRateCache creates a new rate cache instance
@@ -41,11 +42,16 @@ func NewRateCache(redisClient *redis.Client, logger *zap.Logger) *RateCache {
 	return &RateCache{
 		redis:    redisClient,
 		logger:   logger,
-		memCache: NewMemoryCache(5 * time.Minute),
-		ttl:      5 * time.Minute,
+		memCache: NewMemoryCache(defaultRateTTL),
+		ttls:     RateTTLs{Default: defaultRateTTL},
 	}
 }
 
+// SetTTLs sets how long each pair is cached in both memory and Redis
+func (rc *RateCache) SetTTLs(ttls RateTTLs) {
+	rc.ttls = ttls
+}
+
 // NewMemoryCache creates a new in-memory cache
 func NewMemoryCache(maxAge time.Duration) *MemoryCache {
 	cache := &MemoryCache{
@@ -81,7 +87,7 @@ func (rc *RateCache) Get(ctx context.Context, from, to string) (*models.Exchange
 				zap.String("to", to))
 			
 			// Store in memory cache for next time
-			rc.memCache.Set(key, &rate)
+			rc.memCache.SetWithTTL(key, &rate, rc.ttls.For(from, to))
 			return &rate, nil
 		}
 	}
@@ -96,9 +102,10 @@ func (rc *RateCache) Get(ctx context.Context, from, to string) (*models.Exchange
 // Set stores a rate in both memory and Redis cache
 func (rc *RateCache) Set(ctx context.Context, from, to string, rate *models.ExchangeRate) error {
 	key := rc.cacheKey(from, to)
+	ttl := rc.ttls.For(from, to)
 
 	// Store in memory cache
-	rc.memCache.Set(key, rate)
+	rc.memCache.SetWithTTL(key, rate, ttl)
 
 	// Store in Redis
 	data, err := json.Marshal(rate)
@@ -106,7 +113,7 @@ func (rc *RateCache) Set(ctx context.Context, from, to string, rate *models.Exch
 		return fmt.Errorf("failed to marshal rate: %w", err)
 	}
 
-	if err := rc.redis.Set(ctx, key, data, rc.ttl); err != nil {
+	if err := rc.redis.Set(ctx, key, data, ttl); err != nil {
 		rc.logger.Error("failed to cache rate in redis", 
 			zap.Error(err),
 			zap.String("key", key))
@@ -116,7 +123,7 @@ func (rc *RateCache) Set(ctx context.Context, from, to string, rate *models.Exch
 	rc.logger.Debug("rate cached", 
 		zap.String("from", from), 
 		zap.String("to", to),
-		zap.Float64("rate", rate.Rate))
+		zap.Stringer("rate", rate.Rate))
 
 	return nil
 }
@@ -159,7 +166,8 @@ func (rc *RateCache) GetStats() map[string]interface{} {
 	return map[string]interface{}{
 		"memory_cache_size": len(rc.memCache.data),
 		"memory_cache_ttl":  rc.memCache.maxAge.String(),
-		"redis_ttl":         rc.ttl.String(),
+		"redis_ttl":         rc.ttls.DefaultTTL().String(),
+		"redis_pair_ttls":   len(rc.ttls.Pairs),
 	}
 }
 
@@ -181,22 +189,37 @@ func (mc *MemoryCache) Get(key string) *models.ExchangeRate {
 	}
 
 	// Check if entry is still valid
-	if time.Since(entry.CachedAt) > mc.maxAge {
+	if entry.expired(time.Now(), mc.maxAge) {
 		return nil
 	}
 
 	return entry.Rate
 }
 
-// Set stores in memory cache
+// Set stores in memory cache for the cache's max age
 func (mc *MemoryCache) Set(key string, rate *models.ExchangeRate) {
+	mc.SetWithTTL(key, rate, 0)
+}
+
+// SetWithTTL stores in memory cache for ttl, or the cache's max age if ttl is zero
+func (mc *MemoryCache) SetWithTTL(key string, rate *models.ExchangeRate, ttl time.Duration) {
 	mc.mu.Lock()
 	defer mc.mu.Unlock()
 
 	mc.data[key] = &CacheEntry{
 		Rate:     rate,
 		CachedAt: time.Now(),
+		TTL:      ttl,
+	}
+}
+
+// expired reports whether the entry has outlived its TTL, or maxAge if it has none
+func (e *CacheEntry) expired(now time.Time, maxAge time.Duration) bool {
+	ttl := e.TTL
+	if ttl <= 0 {
+		ttl = maxAge
 	}
+	return now.Sub(e.CachedAt) > ttl
 }
 
 // Delete removes from memory cache
@@ -216,7 +239,7 @@ func (mc *MemoryCache) cleanup() {
 		mc.mu.Lock()
 		now := time.Now()
 		for key, entry := range mc.data {
-			if now.Sub(entry.CachedAt) > mc.maxAge {
+			if entry.expired(now, mc.maxAge) {
 				delete(mc.data, key)
 			}
 		}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Tracked pairs are refreshed between refreshAt and refreshAt+refreshJitter of
+// the way through their TTL, so the cached rate is replaced before it expires
+// and pairs sharing a TTL don't all hit the providers at once
+const (
+	refreshAt     = 0.8
+	refreshJitter = 0.1
+)
+
+// RatePair is a currency pair whose rate is kept warm in the cache
+type RatePair struct {
+	From string
+	To   string
+}
+
+// ParseRatePairs parses a comma-separated list of pairs such as "EUR/USD,GBP/USD",
+// rejecting currencies outside supported
+func ParseRatePairs(raw string, supported []string) ([]RatePair, error) {
+	known := make(map[string]bool, len(supported))
+	for _, currency := range supported {
+		known[currency] = true
+	}
+
+	var pairs []RatePair
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		from, to, ok := strings.Cut(strings.ToUpper(item), "/")
+		if !ok || from == "" || to == "" || from == to {
+			return nil, fmt.Errorf("invalid currency pair %q, want FROM/TO", item)
+		}
+		for _, currency := range []string{from, to} {
+			if !known[currency] {
+				return nil, fmt.Errorf("%w: %q in pair %s", ErrUnsupportedCurrency, currency, item)
+			}
+		}
+		pairs = append(pairs, RatePair{From: from, To: to})
+	}
+	return pairs, nil
+}
+
+// RunRateRefresher keeps each pair's cached rate warm until ctx is cancelled. A pair
+// is fetched straight away, then again shortly before its cached rate expires, so
+// customers asking for it never wait on a provider.
+func (s *ExchangeService) RunRateRefresher(ctx context.Context, pairs []RatePair) {
+	var wg sync.WaitGroup
+	for _, pair := range pairs {
+		wg.Add(1)
+		go func(pair RatePair) {
+			defer wg.Done()
+			s.keepRateWarm(ctx, pair)
+		}(pair)
+	}
+	wg.Wait()
+}
+
+func (s *ExchangeService) keepRateWarm(ctx context.Context, pair RatePair) {
+	for {
+		ttl := s.rateTTL(pair.From, pair.To)
+		delay := refreshDelay(ttl)
+		if err := s.refreshRate(ctx, pair.From, pair.To); err != nil {
+			s.logger.Warn("failed to refresh cached rate",
+				zap.String("from", pair.From),
+				zap.String("to", pair.To),
+				zap.Error(err))
+			// Try again well before the rate still in the cache expires
+			delay = time.Duration(float64(ttl) * refreshJitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// refreshRate fetches a pair's rate and stores it as a cache miss in GetRate would:
+// in the cache for the pair's TTL and in the rate history the database fallback reads
+func (s *ExchangeService) refreshRate(ctx context.Context, from, to string) error {
+	rate, err := s.fetchFromProviders(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	s.cacheRate(ctx, rateCacheKey(from, to), rate, s.rateTTL(from, to))
+	if err := s.repo.SaveRate(ctx, rate); err != nil {
+		s.logger.Error("failed to save refreshed rate to database", zap.Error(err))
+	}
+	return nil
+}
+
+// refreshDelay is how long to wait before refreshing a rate cached for ttl
+func refreshDelay(ttl time.Duration) time.Duration {
+	base := time.Duration(float64(ttl) * refreshAt)
+	jitter := time.Duration(float64(ttl) * refreshJitter)
+	if jitter <= 0 {
+		return base
+	}
+	return base + time.Duration(rand.Int63n(int64(jitter)))
+}
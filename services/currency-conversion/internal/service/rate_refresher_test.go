@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"currency-conversion/internal/models"
+)
+
+// lockedRateCache is a RateCacheStore safe to share with the refresher goroutines
+type lockedRateCache struct {
+	mu    sync.Mutex
+	cache memoryRateCache
+}
+
+func (c *lockedRateCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Get(ctx, key)
+}
+
+func (c *lockedRateCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cache.Set(ctx, key, value, expiration)
+}
+
+// cachedTimestamp returns the timestamp of the cached rate, or the zero time if none is cached
+func (c *lockedRateCache) cachedTimestamp(t *testing.T, from, to string) time.Time {
+	data, err := c.Get(context.Background(), rateCacheKey(from, to))
+	if err != nil {
+		return time.Time{}
+	}
+	var rate models.ExchangeRate
+	if err := json.Unmarshal([]byte(data), &rate); err != nil {
+		t.Fatalf("cached rate is not valid JSON: %v", err)
+	}
+	return rate.Timestamp
+}
+
+func TestRunRateRefresherKeepsTrackedPairWarm(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	s.repo = &fakeRateStore{}
+	cache := &lockedRateCache{cache: memoryRateCache{}}
+	s.redisClient = cache
+	s.SetRateTTLs(RateTTLs{Default: 40 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.RunRateRefresher(ctx, []RatePair{{From: "EUR", To: "USD"}})
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// waitForTimestamp polls the cache until the pair's rate is newer than after
+	waitForTimestamp := func(after time.Time) time.Time {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			if ts := cache.cachedTimestamp(t, "EUR", "USD"); ts.After(after) {
+				return ts
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("cached EUR/USD rate never moved past %v", after)
+		return time.Time{}
+	}
+
+	first := waitForTimestamp(time.Time{})
+	second := waitForTimestamp(first)
+	if !second.After(first) {
+		t.Errorf("cache timestamp = %v, want it to advance past %v", second, first)
+	}
+}
+
+func TestParseRatePairs(t *testing.T) {
+	supported := []string{"USD", "EUR", "GBP"}
+
+	tests := []struct {
+		raw     string
+		want    []RatePair
+		wantErr bool
+	}{
+		{raw: "", want: nil},
+		{raw: "eur/usd, GBP/USD", want: []RatePair{{From: "EUR", To: "USD"}, {From: "GBP", To: "USD"}}},
+		{raw: "EURUSD", wantErr: true},
+		{raw: "USD/USD", wantErr: true},
+		{raw: "EUR/XYZ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseRatePairs(tt.raw, supported)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRatePairs(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRatePairs(%q) = %v, want %v", tt.raw, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseRatePairs("EUR/XYZ", supported); !errors.Is(err, ErrUnsupportedCurrency) {
+		t.Errorf("unsupported currency error = %v, want %v", err, ErrUnsupportedCurrency)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"currency-conversion/internal/models"
+)
+
+// defaultRateStreamInterval is how often a streamed rate is re-read when no interval is configured
+const defaultRateStreamInterval = 5 * time.Second
+
+// SetRateStreamInterval sets how often WatchRate re-reads a rate, bounding how
+// frequently subscribers are sent updates
+func (s *ExchangeService) SetRateStreamInterval(interval time.Duration) {
+	s.streamMu.Lock()
+	defer s.streamMu.Unlock()
+	s.streamInterval = interval
+}
+
+func (s *ExchangeService) rateStreamInterval() time.Duration {
+	s.streamMu.RLock()
+	defer s.streamMu.RUnlock()
+	if s.streamInterval > 0 {
+		return s.streamInterval
+	}
+	return defaultRateStreamInterval
+}
+
+// WatchRate sends the current rate for a pair, then every rate that differs from
+// the last one sent. The channel is closed once ctx is done.
+func (s *ExchangeService) WatchRate(ctx context.Context, from, to string) (<-chan *models.ExchangeRate, error) {
+	if err := s.validatePair(from, to); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *models.ExchangeRate)
+	interval := s.rateStreamInterval()
+
+	go func() {
+		defer close(updates)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var last *models.ExchangeRate
+		for {
+			rate, err := s.GetRate(ctx, from, to)
+			if err != nil {
+				s.logger.Warn("failed to refresh streamed rate",
+					zap.String("from", from),
+					zap.String("to", to),
+					zap.Error(err))
+			} else if last == nil || rate.Rate.Cmp(last.Rate) != 0 || !rate.Timestamp.Equal(last.Timestamp) {
+				select {
+				case updates <- rate:
+					last = rate
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return updates, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultRateTTL is how long a rate is cached when no TTL is configured
+const defaultRateTTL = 5 * time.Minute
+
+// RateTTLs is how long rates are cached: a default, with overrides for pairs
+// that move faster or slower than the majors. Pairs are keyed "FROM/TO"; an
+// override applies to both directions of the pair.
+type RateTTLs struct {
+	Default time.Duration
+	Pairs   map[string]time.Duration
+}
+
+// ParseRateTTLs parses pair overrides such as {"USD/BTC": "30s", "EUR/USD": "15m"}
+func ParseRateTTLs(defaultTTL time.Duration, pairs map[string]string) (RateTTLs, error) {
+	ttls := RateTTLs{Default: defaultTTL, Pairs: make(map[string]time.Duration, len(pairs))}
+	for pair, raw := range pairs {
+		from, to, ok := strings.Cut(strings.ToUpper(pair), "/")
+		if !ok || from == "" || to == "" {
+			return RateTTLs{}, fmt.Errorf("invalid currency pair %q, want FROM/TO", pair)
+		}
+		ttl, err := time.ParseDuration(raw)
+		if err != nil || ttl <= 0 {
+			return RateTTLs{}, fmt.Errorf("invalid TTL %q for %s", raw, pair)
+		}
+		ttls.Pairs[ratePairKey(from, to)] = ttl
+	}
+	return ttls, nil
+}
+
+// For returns the TTL for a pair, falling back to the default
+func (t RateTTLs) For(from, to string) time.Duration {
+	if ttl, ok := t.Pairs[ratePairKey(from, to)]; ok {
+		return ttl
+	}
+	if ttl, ok := t.Pairs[ratePairKey(to, from)]; ok {
+		return ttl
+	}
+	return t.DefaultTTL()
+}
+
+// DefaultTTL is the TTL for pairs without an override
+func (t RateTTLs) DefaultTTL() time.Duration {
+	if t.Default > 0 {
+		return t.Default
+	}
+	return defaultRateTTL
+}
+
+// SetRateTTLs replaces how long each pair's rate is cached
+func (s *ExchangeService) SetRateTTLs(ttls RateTTLs) {
+	s.ttlMu.Lock()
+	defer s.ttlMu.Unlock()
+	s.rateTTLs = ttls
+}
+
+func (s *ExchangeService) rateTTL(from, to string) time.Duration {
+	s.ttlMu.RLock()
+	defer s.ttlMu.RUnlock()
+	return s.rateTTLs.For(from, to)
+}
+
+func ratePairKey(from, to string) string {
+	return from + "/" + to
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"currency-conversion/internal/models"
+	"shared/pkg/money"
+)
+
+// recordingRateCache is a RateCacheStore that records the expiration of each key set
+type recordingRateCache struct {
+	memoryRateCache
+	expirations map[string]time.Duration
+}
+
+func (c *recordingRateCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	c.expirations[key] = expiration
+	return c.memoryRateCache.Set(ctx, key, value, expiration)
+}
+
+func TestRateTTLsFor(t *testing.T) {
+	ttls, err := ParseRateTTLs(5*time.Minute, map[string]string{"usd/btc": "30s", "EUR/USD": "15m"})
+	if err != nil {
+		t.Fatalf("ParseRateTTLs() error = %v", err)
+	}
+
+	tests := []struct {
+		from, to string
+		want     time.Duration
+	}{
+		{"USD", "BTC", 30 * time.Second},
+		{"BTC", "USD", 30 * time.Second},
+		{"EUR", "USD", 15 * time.Minute},
+		{"GBP", "JPY", 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := ttls.For(tt.from, tt.to); got != tt.want {
+			t.Errorf("For(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+
+	if got := (RateTTLs{}).For("USD", "EUR"); got != defaultRateTTL {
+		t.Errorf("unconfigured TTL = %v, want %v", got, defaultRateTTL)
+	}
+}
+
+func TestParseRateTTLsRejectsInvalidEntries(t *testing.T) {
+	for _, pairs := range []map[string]string{
+		{"USDBTC": "30s"},
+		{"USD/BTC": "soon"},
+		{"USD/BTC": "-1s"},
+	} {
+		if _, err := ParseRateTTLs(time.Minute, pairs); err == nil {
+			t.Errorf("ParseRateTTLs(%v) should fail", pairs)
+		}
+	}
+}
+
+func TestGetRateCachesForPairTTL(t *testing.T) {
+	s := newTestExchangeService(&fakeProvider{name: "primary"})
+	s.repo = &fakeRateStore{}
+	cache := &recordingRateCache{memoryRateCache: memoryRateCache{}, expirations: map[string]time.Duration{}}
+	s.redisClient = cache
+	s.SetRateTTLs(RateTTLs{Default: 5 * time.Minute, Pairs: map[string]time.Duration{"USD/MXN": 30 * time.Second}})
+
+	for _, quote := range []string{"MXN", "EUR"} {
+		if _, err := s.GetRate(context.Background(), "USD", quote); err != nil {
+			t.Fatalf("GetRate(USD, %s) error = %v", quote, err)
+		}
+	}
+
+	if got := cache.expirations[rateCacheKey("USD", "MXN")]; got != 30*time.Second {
+		t.Errorf("USD/MXN cached for %v, want 30s", got)
+	}
+	if got := cache.expirations[rateCacheKey("USD", "EUR")]; got != 5*time.Minute {
+		t.Errorf("USD/EUR cached for %v, want 5m", got)
+	}
+}
+
+func TestMemoryCacheShortTTLPairExpiresFirst(t *testing.T) {
+	cache := NewMemoryCache(time.Hour)
+	rate := &models.ExchangeRate{FromCurrency: "USD", ToCurrency: "BTC", Rate: money.NewDecimal(0.000016)}
+
+	cache.SetWithTTL("rate:USD:BTC", rate, 10*time.Millisecond)
+	cache.SetWithTTL("rate:EUR:USD", rate, time.Hour)
+
+	time.Sleep(20 * time.Millisecond)
+
+	if cache.Get("rate:USD:BTC") != nil {
+		t.Error("short-TTL pair should have expired")
+	}
+	if cache.Get("rate:EUR:USD") == nil {
+		t.Error("long-TTL pair should still be cached")
+	}
+}
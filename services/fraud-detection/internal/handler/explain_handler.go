@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/service"
+)
+
+// ExplainFraudResult handles GET /api/v1/fraud/results/:transaction_id/explain
+func (h *FraudHandler) ExplainFraudResult(c *gin.Context) {
+	explanation, err := h.service.ExplainDecision(c.Request.Context(), c.Param("transaction_id"))
+	if err != nil {
+		if errors.Is(err, service.ErrFraudResultNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Fraud result not found"})
+			return
+		}
+		h.logger.Error("failed to explain fraud result", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to explain fraud result"})
+		return
+	}
+
+	c.JSON(http.StatusOK, explanation)
+}
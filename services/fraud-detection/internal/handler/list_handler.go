@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"fraud-detection/internal/service"
+)
+
+// AddToBlacklist handles POST /api/v1/fraud/blacklist
+func (h *FraudHandler) AddToBlacklist(c *gin.Context) {
+	h.addToList(c, models.ListTypeBlacklist)
+}
+
+// AddToWhitelist handles POST /api/v1/fraud/whitelist
+func (h *FraudHandler) AddToWhitelist(c *gin.Context) {
+	h.addToList(c, models.ListTypeWhitelist)
+}
+
+// addToList answers 201 for a new entry and 200 when the entry was already listed
+func (h *FraudHandler) addToList(c *gin.Context, list models.ListType) {
+	var req models.ListEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	entry, created, err := h.service.AddToList(c.Request.Context(), list, &req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidListEntry) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to add list entry", zap.String("list", string(list)), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add list entry"})
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, entry)
+}
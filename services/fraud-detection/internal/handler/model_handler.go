@@ -0,0 +1,13 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ListModels handles GET /api/v1/fraud/models, listing the models in the served ensemble
+func (h *FraudHandler) ListModels(c *gin.Context) {
+	models := h.service.ListModels()
+	c.JSON(http.StatusOK, gin.H{"models": models, "count": len(models)})
+}
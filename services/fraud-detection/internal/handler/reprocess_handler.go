@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"fraud-detection/internal/service"
+)
+
+// StartReprocess handles POST /api/v1/fraud/admin/reprocess, re-scoring the checks in
+// [start, end) into the shadow results table in the background
+func (h *FraudHandler) StartReprocess(c *gin.Context) {
+	var req models.ReprocessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := h.service.StartReprocess(req.Start, req.End)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidReprocessRange):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrReprocessNotConfigured):
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to start reprocessing", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start reprocessing"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, job)
+}
+
+// GetReprocessJob handles GET /api/v1/fraud/admin/reprocess/:id, reporting a job's progress
+func (h *FraudHandler) GetReprocessJob(c *gin.Context) {
+	job, err := h.service.GetReprocessJob(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrReprocessJobNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reprocess job not found"})
+			return
+		}
+		h.logger.Error("failed to get reprocess job", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get reprocess job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"fraud-detection/internal/service"
+)
+
+// resultsDateLayout is the format of the from and to query dates
+const resultsDateLayout = "2006-01-02"
+
+// ListFraudResults handles GET /api/v1/fraud/results?risk_level=high&decision=&from=&to=&limit=&offset=.
+// from and to are inclusive dates.
+func (h *FraudHandler) ListFraudResults(c *gin.Context) {
+	filter := models.FraudResultFilter{
+		RiskLevel: models.RiskLevel(c.Query("risk_level")),
+		Decision:  models.Decision(c.Query("decision")),
+	}
+
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse(resultsDateLayout, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+			return
+		}
+		filter.From = from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse(resultsDateLayout, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+			return
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		filter.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be an integer"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	page, err := h.service.ListResults(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidResultFilter) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to list fraud results", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list fraud results"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SelfTest handles GET /api/v1/fraud/selftest, answering 503 if any canned decision is wrong
+func (h *FraudHandler) SelfTest(c *gin.Context) {
+	report := h.service.RunSelfTest(c.Request.Context())
+
+	status := http.StatusOK
+	if !report.Passed {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"fraud-detection/internal/service"
+)
+
+func TestSelfTest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// The self-test uses its own fixture history, so the engine needs no store
+	engine := service.NewFraudEngine(nil, nil, zap.NewNop())
+	h := NewFraudHandler(engine, zap.NewNop())
+
+	router := gin.New()
+	router.GET("/api/v1/fraud/selftest", h.SelfTest)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/fraud/selftest", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+
+	var report models.SelfTestReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatalf("failed to decode report: %v", err)
+	}
+	if !report.Passed {
+		t.Errorf("report.Passed = false, cases = %+v", report.Cases)
+	}
+
+	want := map[string]models.Decision{"benign": models.DecisionApprove, "risky": models.DecisionBlock}
+	if len(report.Cases) != len(want) {
+		t.Fatalf("got %d cases, want %d", len(report.Cases), len(want))
+	}
+	for _, tc := range report.Cases {
+		if tc.Decision != want[tc.Name] {
+			t.Errorf("case %s decision = %s, want %s", tc.Name, tc.Decision, want[tc.Name])
+		}
+	}
+}
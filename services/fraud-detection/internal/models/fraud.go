@@ -0,0 +1,177 @@
+// services/fraud-detection/internal/models/fraud.go
+// Data structures
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type RiskLevel string
+
+const (
+	RiskLevelLow    RiskLevel = "low"
+	RiskLevelMedium RiskLevel = "medium"
+	RiskLevelHigh   RiskLevel = "high"
+)
+
+type Decision string
+
+const (
+	DecisionApprove Decision = "approve"
+	DecisionReview  Decision = "review"
+	DecisionBlock   Decision = "block"
+)
+
+// MerchantMode is how a merchant's fraud decisions are applied
+type MerchantMode string
+
+const (
+	// MerchantModeEnforce returns the computed decision; the default
+	MerchantModeEnforce MerchantMode = "enforce"
+	// MerchantModeMonitor computes and records the decision but always returns approve,
+	// for merchants running their own fraud systems
+	MerchantModeMonitor MerchantMode = "monitor"
+	// MerchantModeOff skips fraud checks and approves without recording anything
+	MerchantModeOff MerchantMode = "off"
+)
+
+var ErrInvalidMerchantMode = errors.New("merchant fraud mode must be enforce, monitor or off")
+
+// Valid reports whether m is a known merchant mode
+func (m MerchantMode) Valid() bool {
+	return m == MerchantModeEnforce || m == MerchantModeMonitor || m == MerchantModeOff
+}
+
+type FraudCheckRequest struct {
+	TransactionID     string  `json:"transaction_id" binding:"required"`
+	Amount            float64 `json:"amount" binding:"required,gt=0"`
+	Currency          string  `json:"currency" binding:"required,len=3"`
+	CustomerEmail     string  `json:"customer_email" binding:"required,email"`
+	CardLast4         string  `json:"card_last4"`
+	Country           string  `json:"country"`
+	IssuerCountry     string  `json:"issuer_country"`
+	DeviceFingerprint string  `json:"device_fingerprint"`
+	// MerchantID selects the merchant's fraud mode; unknown or empty merchants are enforced
+	MerchantID string `json:"merchant_id"`
+	// Timestamp is when the transaction happened; defaults to now when absent
+	Timestamp time.Time `json:"timestamp"`
+	// ForceRecheck bypasses the cached decision for a replayed transaction ID
+	ForceRecheck bool `json:"force_recheck"`
+}
+
+// FraudCheckResponse is the outcome of a fraud check. Score is the sum of the rule
+// scores clamped to [0, 100]; each entry in Rules keeps the score that rule
+// contributed before clamping.
+type FraudCheckResponse struct {
+	TransactionID string       `json:"transaction_id"`
+	Score         int          `json:"score"`
+	RiskLevel     RiskLevel    `json:"risk_level"`
+	Decision      Decision     `json:"decision"`
+	Flags         []string     `json:"flags"`
+	Rules         []RuleResult `json:"rules"`
+	Cached        bool         `json:"cached"`
+	Timestamp     time.Time    `json:"timestamp"`
+	// ModelVersion is the fraud model that scored the check, when one is served
+	ModelVersion string `json:"model_version,omitempty"`
+	// Mode is the merchant's fraud mode; in monitor mode Decision is always approve
+	// and ComputedDecision is what the rules decided
+	Mode             MerchantMode `json:"mode"`
+	ComputedDecision Decision     `json:"computed_decision,omitempty"`
+	// RuleProfile is the merchant's rule profile; empty when every rule ran
+	RuleProfile string `json:"rule_profile,omitempty"`
+}
+
+type RuleResult struct {
+	RuleName    string `json:"rule_name"`
+	Triggered   bool   `json:"triggered"`
+	Score       int    `json:"score"`
+	Description string `json:"description"`
+	// Errored is set when the rule's data couldn't be read, so it contributed no score
+	Errored bool `json:"errored,omitempty"`
+	// Skipped is set when the rule is disabled or outside the merchant's rule profile, so it didn't run
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+type FraudCheckResult struct {
+	ID            string    `json:"id" db:"id"`
+	TransactionID string    `json:"transaction_id" db:"transaction_id"`
+	Score         int       `json:"score" db:"score"`
+	RiskLevel     string    `json:"risk_level" db:"risk_level"`
+	Decision      string    `json:"decision" db:"decision"`
+	Flags         []string  `json:"flags" db:"flags"`
+	ProcessingMS  int64     `json:"processing_ms" db:"processing_ms"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// FraudAlert is the webhook payload sent for high-risk transactions
+type FraudAlert struct {
+	// AlertID is stable for a transaction and decision so receivers can dedup retries
+	AlertID       string    `json:"alert_id"`
+	TransactionID string    `json:"transaction_id"`
+	Score         int       `json:"score"`
+	RiskLevel     RiskLevel `json:"risk_level"`
+	Decision      Decision  `json:"decision"`
+	Flags         []string  `json:"flags"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// ListType is a list customers and cards are screened against
+type ListType string
+
+const (
+	ListTypeBlacklist ListType = "blacklist"
+	ListTypeWhitelist ListType = "whitelist"
+)
+
+// ListEntryKind is what a list entry matches on
+type ListEntryKind string
+
+const (
+	ListEntryKindEmail     ListEntryKind = "email"
+	ListEntryKindCardLast4 ListEntryKind = "card_last4"
+)
+
+// ListEntry is a blacklisted or whitelisted email or card; (list, kind, value) is unique
+type ListEntry struct {
+	ID        string        `json:"id" db:"id"`
+	List      ListType      `json:"list" db:"list"`
+	Kind      ListEntryKind `json:"kind" db:"kind"`
+	Value     string        `json:"value" db:"value"`
+	Reason    string        `json:"reason" db:"reason"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at" db:"updated_at"`
+}
+
+type ListEntryRequest struct {
+	Kind   ListEntryKind `json:"kind" binding:"required,oneof=email card_last4"`
+	Value  string        `json:"value" binding:"required"`
+	Reason string        `json:"reason"`
+}
+
+// SelfTestCase is the outcome of one canned transaction in the fraud self-test
+type SelfTestCase struct {
+	Name     string     `json:"name"`
+	Decision Decision   `json:"decision"`
+	Score    int        `json:"score"`
+	Expected []Decision `json:"expected"`
+	Passed   bool       `json:"passed"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// SelfTestReport is the result of running the canned transactions through the rules
+type SelfTestReport struct {
+	Passed bool           `json:"passed"`
+	Cases  []SelfTestCase `json:"cases"`
+	RanAt  time.Time      `json:"ran_at"`
+}
+
+// FraudResultFilter selects stored fraud check results; zero fields match everything
+type FraudResultFilter struct {
+	RiskLevel RiskLevel
+	Decision  Decision
+	From      time.Time
+	To        time.Time
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// ModelFactor is one feature the fraud model scored. Contribution is Weight × Value,
+// in the model's log-odds units.
+type ModelFactor struct {
+	Feature      string  `json:"feature"`
+	Value        float64 `json:"value"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+}
+
+// FraudCheckDetails is what a fraud check was decided on, kept so the decision can
+// be explained later
+type FraudCheckDetails struct {
+	TransactionID string        `json:"transaction_id" db:"transaction_id"`
+	Score         int           `json:"score" db:"score"`
+	RiskLevel     RiskLevel     `json:"risk_level" db:"risk_level"`
+	Decision      Decision      `json:"decision" db:"decision"`
+	Rules         []RuleResult  `json:"rules" db:"rules"`
+	ModelVersion  string        `json:"model_version,omitempty" db:"model_version"`
+	ModelFactors  []ModelFactor `json:"model_factors" db:"model_factors"`
+	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
+	// Request is the transaction as checked, kept so it can be re-scored later
+	Request *FraudCheckRequest `json:"request,omitempty" db:"request"`
+}
+
+// FraudExplanation is why a transaction scored as it did. Rules lists the triggered
+// rules by score contribution and ModelFactors the model's features by contribution,
+// each largest first; the two are on different scales so are ranked separately.
+type FraudExplanation struct {
+	TransactionID string        `json:"transaction_id"`
+	Score         int           `json:"score"`
+	RiskLevel     RiskLevel     `json:"risk_level"`
+	Decision      Decision      `json:"decision"`
+	Rules         []RuleResult  `json:"rules"`
+	ModelVersion  string        `json:"model_version,omitempty"`
+	ModelFactors  []ModelFactor `json:"model_factors"`
+	CheckedAt     time.Time     `json:"checked_at"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// CustomerLocation is where a customer transacted from, recorded after each check
+// so later checks can tell how far and how fast they would have had to travel
+type CustomerLocation struct {
+	CustomerEmail string    `json:"customer_email"`
+	TransactionID string    `json:"transaction_id"`
+	Country       string    `json:"country"`
+	SeenAt        time.Time `json:"seen_at"`
+}
@@ -0,0 +1,9 @@
+package models
+
+// ModelInfo describes one model in the fraud model ensemble
+type ModelInfo struct {
+	Name    string  `json:"name"`
+	Version string  `json:"version"`
+	Weight  float64 `json:"weight"`
+	Trained bool    `json:"trained"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ReprocessStatus is where a reprocessing job is
+type ReprocessStatus string
+
+const (
+	ReprocessStatusRunning   ReprocessStatus = "running"
+	ReprocessStatusCompleted ReprocessStatus = "completed"
+	ReprocessStatusFailed    ReprocessStatus = "failed"
+)
+
+// ReprocessRequest re-scores the transactions checked in [Start, End)
+type ReprocessRequest struct {
+	Start time.Time `json:"start" binding:"required"`
+	End   time.Time `json:"end" binding:"required"`
+}
+
+// ReprocessJob is the progress of re-scoring historical checks into the shadow results table.
+// Processed counts transactions re-scored so far, including the Failed ones.
+type ReprocessJob struct {
+	ID         string          `json:"id"`
+	Status     ReprocessStatus `json:"status"`
+	Start      time.Time       `json:"start"`
+	End        time.Time       `json:"end"`
+	Total      int             `json:"total"`
+	Processed  int             `json:"processed"`
+	Failed     int             `json:"failed"`
+	Error      string          `json:"error,omitempty"`
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"fraud-detection/internal/models"
+)
+
+// SaveCheckDetails stores the rules and model factors a check was decided on
+func (r *FraudRepository) SaveCheckDetails(ctx context.Context, details *models.FraudCheckDetails) error {
+	rules, err := json.Marshal(details.Rules)
+	if err != nil {
+		return err
+	}
+	factors, err := json.Marshal(details.ModelFactors)
+	if err != nil {
+		return err
+	}
+	// Checks saved without their request store NULL and can't be re-scored
+	var request interface{}
+	if details.Request != nil {
+		data, err := json.Marshal(details.Request)
+		if err != nil {
+			return err
+		}
+		request = data
+	}
+
+	query := `
+		INSERT INTO fraud_check_details (
+			transaction_id, score, risk_level, decision, rules,
+			model_version, model_factors, request, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		details.TransactionID,
+		details.Score,
+		details.RiskLevel,
+		details.Decision,
+		rules,
+		details.ModelVersion,
+		factors,
+		request,
+		details.CreatedAt,
+	)
+	return err
+}
+
+// GetCheckDetails returns the most recent check details for a transaction, or nil if there are none
+func (r *FraudRepository) GetCheckDetails(ctx context.Context, transactionID string) (*models.FraudCheckDetails, error) {
+	query := `
+		SELECT transaction_id, score, risk_level, decision, rules,
+			   COALESCE(model_version, ''), model_factors, created_at
+		FROM fraud_check_details
+		WHERE transaction_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	details := &models.FraudCheckDetails{}
+	var rules, factors []byte
+	err := r.db.QueryRowContext(ctx, query, transactionID).Scan(
+		&details.TransactionID,
+		&details.Score,
+		&details.RiskLevel,
+		&details.Decision,
+		&rules,
+		&details.ModelVersion,
+		&factors,
+		&details.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(rules, &details.Rules); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(factors, &details.ModelFactors); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
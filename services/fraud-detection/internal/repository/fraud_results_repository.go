@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"fraud-detection/internal/models"
+)
+
+// ListFraudChecks returns stored results matching the filter, newest first
+func (r *FraudRepository) ListFraudChecks(ctx context.Context, filter models.FraudResultFilter) ([]*models.FraudCheckResult, error) {
+	where, args := fraudResultConditions(filter)
+
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT id, transaction_id, score, risk_level, decision, flags,
+			   COALESCE(processing_ms, 0), created_at
+		FROM fraud_check_results
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []*models.FraudCheckResult{}
+	for rows.Next() {
+		result := &models.FraudCheckResult{}
+		if err := rows.Scan(
+			&result.ID,
+			&result.TransactionID,
+			&result.Score,
+			&result.RiskLevel,
+			&result.Decision,
+			pq.Array(&result.Flags),
+			&result.ProcessingMS,
+			&result.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// CountFraudChecks returns how many stored results match the filter, ignoring its limit and offset
+func (r *FraudRepository) CountFraudChecks(ctx context.Context, filter models.FraudResultFilter) (int, error) {
+	where, args := fraudResultConditions(filter)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fraud_check_results `+where, args...).Scan(&count)
+	return count, err
+}
+
+// fraudResultConditions builds the WHERE clause for the filter and its arguments
+func fraudResultConditions(filter models.FraudResultFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.RiskLevel != "" {
+		args = append(args, filter.RiskLevel)
+		conditions = append(conditions, fmt.Sprintf("risk_level = $%d", len(args)))
+	}
+	if filter.Decision != "" {
+		args = append(args, filter.Decision)
+		conditions = append(conditions, fmt.Sprintf("decision = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("created_at < $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	return where, args
+}
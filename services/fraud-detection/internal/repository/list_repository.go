@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+
+	"fraud-detection/internal/models"
+)
+
+// UpsertListEntry adds an entry to its list, or refreshes the reason and updated_at
+// of the existing row on a repeat add. It reports whether a new row was created and
+// fills in the stored ID and created_at.
+func (r *FraudRepository) UpsertListEntry(ctx context.Context, entry *models.ListEntry) (bool, error) {
+	query := `
+		INSERT INTO fraud_list_entries (id, list, kind, value, reason, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (list, kind, value) DO UPDATE
+		SET reason = EXCLUDED.reason, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at, (xmax = 0)
+	`
+
+	var created bool
+	err := r.db.QueryRowContext(ctx, query,
+		entry.ID,
+		entry.List,
+		entry.Kind,
+		entry.Value,
+		entry.Reason,
+		entry.CreatedAt,
+		entry.UpdatedAt,
+	).Scan(&entry.ID, &entry.CreatedAt, &created)
+
+	return created, err
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"fraud-detection/internal/models"
+)
+
+// SaveLocation records where a transaction came from. A re-checked transaction
+// replaces its earlier location rather than adding a second one.
+func (r *FraudRepository) SaveLocation(ctx context.Context, location *models.CustomerLocation) error {
+	query := `
+		INSERT INTO customer_locations (transaction_id, customer_email, country, seen_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (transaction_id) DO UPDATE
+		SET customer_email = EXCLUDED.customer_email, country = EXCLUDED.country, seen_at = EXCLUDED.seen_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		location.TransactionID,
+		location.CustomerEmail,
+		location.Country,
+		location.SeenAt,
+	)
+	return err
+}
+
+// GetLastLocation returns the customer's most recent location at or before the
+// given time, ignoring the transaction being checked, or nil if there is none
+func (r *FraudRepository) GetLastLocation(ctx context.Context, customerEmail, transactionID string, before time.Time) (*models.CustomerLocation, error) {
+	query := `
+		SELECT customer_email, transaction_id, country, seen_at
+		FROM customer_locations
+		WHERE customer_email = $1 AND transaction_id <> $2 AND seen_at <= $3
+		ORDER BY seen_at DESC
+		LIMIT 1
+	`
+
+	location := &models.CustomerLocation{}
+	err := r.db.QueryRowContext(ctx, query, customerEmail, transactionID, before).Scan(
+		&location.CustomerEmail,
+		&location.TransactionID,
+		&location.Country,
+		&location.SeenAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/lib/pq"
+
+	"fraud-detection/internal/models"
+	"shared/pkg/ids"
+)
+
+// ListCheckRequests returns the request behind the latest check of each transaction
+// checked in [start, end), oldest first. Checks saved without their request are skipped.
+func (r *FraudRepository) ListCheckRequests(ctx context.Context, start, end time.Time) ([]*models.FraudCheckRequest, error) {
+	query := `
+		SELECT request FROM (
+			SELECT DISTINCT ON (transaction_id) request, created_at
+			FROM fraud_check_details
+			WHERE created_at >= $1 AND created_at < $2 AND request IS NOT NULL
+			ORDER BY transaction_id, created_at DESC
+		) latest
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	requests := []*models.FraudCheckRequest{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		request := &models.FraudCheckRequest{}
+		if err := json.Unmarshal(data, request); err != nil {
+			return nil, err
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+// SaveShadowFraudCheck stores a re-scored result in the shadow table, apart from the
+// results production decisions are read from
+func (r *FraudRepository) SaveShadowFraudCheck(ctx context.Context, runID string, result *models.FraudCheckResult) error {
+	if result.ID == "" {
+		result.ID = ids.New()
+	}
+
+	query := `
+		INSERT INTO fraud_check_results_shadow (
+			id, run_id, transaction_id, score, risk_level, decision,
+			flags, processing_ms, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		result.ID,
+		runID,
+		result.TransactionID,
+		result.Score,
+		result.RiskLevel,
+		result.Decision,
+		pq.Array(result.Flags),
+		result.ProcessingMS,
+		result.CreatedAt,
+	)
+	return err
+}
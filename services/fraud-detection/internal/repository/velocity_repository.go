@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// SumRecentAmounts totals what the customer was checked for in the window, once per
+// transaction and excluding the transaction being checked
+func (r *FraudRepository) SumRecentAmounts(ctx context.Context, customerEmail, transactionID string, window time.Duration) (float64, error) {
+	query := `
+		SELECT COALESCE(SUM(amount), 0) FROM (
+			SELECT DISTINCT ON (transaction_id) (request->>'amount')::NUMERIC AS amount
+			FROM fraud_check_details
+			WHERE request->>'customer_email' = $1
+				AND transaction_id <> $2
+				AND created_at >= $3
+			ORDER BY transaction_id, created_at DESC
+		) latest
+	`
+
+	var total float64
+	err := r.db.QueryRowContext(ctx, query, customerEmail, transactionID, time.Now().Add(-window)).Scan(&total)
+	return total, err
+}
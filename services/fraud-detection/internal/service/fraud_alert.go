@@ -0,0 +1,112 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fraud-detection/internal/models"
+)
+
+const (
+	alertMaxAttempts  = 3
+	alertRetryBackoff = 500 * time.Millisecond
+	alertSendTimeout  = 30 * time.Second
+)
+
+// AlertSender delivers fraud alerts to an external receiver
+type AlertSender interface {
+	SendAlert(ctx context.Context, alert *models.FraudAlert) error
+}
+
+// WebhookAlertSender POSTs fraud alerts to a webhook URL, retrying failed deliveries
+type WebhookAlertSender struct {
+	url        string
+	httpClient *http.Client
+	backoff    time.Duration
+}
+
+// NewWebhookAlertSender creates a sender for the given webhook URL
+func NewWebhookAlertSender(url string) *WebhookAlertSender {
+	return &WebhookAlertSender{
+		url:        url,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		backoff:    alertRetryBackoff,
+	}
+}
+
+// SendAlert delivers an alert, retrying network errors and 5xx responses.
+// Every attempt carries the same body and Idempotency-Key.
+func (w *WebhookAlertSender) SendAlert(ctx context.Context, alert *models.FraudAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= alertMaxAttempts; attempt++ {
+		retry, err := w.post(ctx, alert.AlertID, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == alertMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.backoff * time.Duration(attempt)):
+		}
+	}
+
+	return fmt.Errorf("fraud alert %s not delivered: %w", alert.AlertID, lastErr)
+}
+
+// post makes a single delivery attempt and reports whether a failure is worth retrying
+func (w *WebhookAlertSender) post(ctx context.Context, alertID string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", alertID)
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode >= 300 {
+		return false, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// fraudAlertID derives a stable alert ID from the transaction and its decision
+func fraudAlertID(transactionID string, decision models.Decision) string {
+	sum := sha256.Sum256([]byte(transactionID + ":" + string(decision)))
+	return "alert_" + hex.EncodeToString(sum[:16])
+}
+
+func newFraudAlert(response *models.FraudCheckResponse) *models.FraudAlert {
+	return &models.FraudAlert{
+		AlertID:       fraudAlertID(response.TransactionID, response.Decision),
+		TransactionID: response.TransactionID,
+		Score:         response.Score,
+		RiskLevel:     response.RiskLevel,
+		Decision:      response.Decision,
+		Flags:         response.Flags,
+		Timestamp:     response.Timestamp,
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"fraud-detection/internal/models"
+)
+
+func TestSendAlertRetriesWithSameAlertID(t *testing.T) {
+	var alertIDs, idempotencyKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert models.FraudAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("failed to decode alert: %v", err)
+		}
+		alertIDs = append(alertIDs, alert.AlertID)
+		idempotencyKeys = append(idempotencyKeys, r.Header.Get("Idempotency-Key"))
+
+		// Fail the first delivery so the sender retries
+		if len(alertIDs) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := NewWebhookAlertSender(server.URL)
+	sender.backoff = time.Millisecond
+
+	alert := newFraudAlert(&models.FraudCheckResponse{
+		TransactionID: "txn_1",
+		Score:         95,
+		RiskLevel:     models.RiskLevelHigh,
+		Decision:      models.DecisionBlock,
+		Timestamp:     time.Now(),
+	})
+	if err := sender.SendAlert(context.Background(), alert); err != nil {
+		t.Fatalf("SendAlert() error = %v", err)
+	}
+
+	if len(alertIDs) != 2 {
+		t.Fatalf("webhook received %d deliveries, want 2", len(alertIDs))
+	}
+	for i := range alertIDs {
+		if alertIDs[i] != alert.AlertID || idempotencyKeys[i] != alert.AlertID {
+			t.Errorf("delivery %d carried alert_id %q and Idempotency-Key %q, want %q",
+				i+1, alertIDs[i], idempotencyKeys[i], alert.AlertID)
+		}
+	}
+}
+
+func TestFraudAlertIDIsStable(t *testing.T) {
+	first := fraudAlertID("txn_1", models.DecisionBlock)
+
+	if got := fraudAlertID("txn_1", models.DecisionBlock); got != first {
+		t.Errorf("alert ID changed between calls: %q vs %q", got, first)
+	}
+	if got := fraudAlertID("txn_1", models.DecisionReview); got == first {
+		t.Error("a different decision should produce a different alert ID")
+	}
+	if got := fraudAlertID("txn_2", models.DecisionBlock); got == first {
+		t.Error("a different transaction should produce a different alert ID")
+	}
+}
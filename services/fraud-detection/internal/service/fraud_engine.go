@@ -4,31 +4,104 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
 
 	"fraud-detection/internal/models"
-	"fraud-detection/internal/repository"
 )
 
+// decisionCacheTTL is how long a decision is replayed for a retried transaction ID
+const decisionCacheTTL = 10 * time.Minute
+
+// FraudStore is the persistence the fraud rules depend on; implemented by repository.FraudRepository
+type FraudStore interface {
+	SaveFraudCheck(ctx context.Context, result *models.FraudCheckResult) error
+	CountRecentTransactions(ctx context.Context, customerEmail string, window time.Duration) (int, error)
+	SumRecentAmounts(ctx context.Context, customerEmail, transactionID string, window time.Duration) (float64, error)
+	GetRecentLocations(ctx context.Context, customerEmail string, window time.Duration) ([]string, error)
+	GetLastLocation(ctx context.Context, customerEmail, transactionID string, before time.Time) (*models.CustomerLocation, error)
+	SaveLocation(ctx context.Context, location *models.CustomerLocation) error
+	IsBlacklisted(ctx context.Context, customerEmail, cardLast4 string) (bool, error)
+	IsKnownDevice(ctx context.Context, customerEmail, deviceFingerprint string) (bool, error)
+	UpsertListEntry(ctx context.Context, entry *models.ListEntry) (bool, error)
+	ListFraudChecks(ctx context.Context, filter models.FraudResultFilter) ([]*models.FraudCheckResult, error)
+	CountFraudChecks(ctx context.Context, filter models.FraudResultFilter) (int, error)
+	SaveCheckDetails(ctx context.Context, details *models.FraudCheckDetails) error
+	GetCheckDetails(ctx context.Context, transactionID string) (*models.FraudCheckDetails, error)
+}
+
+// DecisionCache stores recent fraud decisions; implemented by the shared Redis client
+type DecisionCache interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+}
+
 type FraudEngine struct {
-	repo   *repository.FraudRepository
-	logger *zap.Logger
+	repo          FraudStore
+	cache         DecisionCache
+	alerter       AlertSender
+	ensemble      *ModelEnsemble
+	featureBounds FeatureBounds
+	ruleBackoff   time.Duration
+	merchantModes map[string]models.MerchantMode
+	disabledRules map[string]bool
+	logger        *zap.Logger
+
+	ruleProfiles     map[string]map[string]bool
+	merchantProfiles map[string]string
+
+	velocityThresholds VelocityThresholds
+
+	reprocessStore ReprocessStore
+	reprocessJobs  reprocessJobs
 }
 
-func NewFraudEngine(repo *repository.FraudRepository, logger *zap.Logger) *FraudEngine {
+func NewFraudEngine(repo FraudStore, cache DecisionCache, logger *zap.Logger) *FraudEngine {
 	return &FraudEngine{
-		repo:   repo,
-		logger: logger,
+		repo:          repo,
+		cache:         cache,
+		featureBounds: DefaultFeatureBounds,
+		ruleBackoff:   ruleQueryRetryBackoff,
+		logger:        logger,
+
+		velocityThresholds: DefaultVelocityThresholds,
 	}
 }
 
 // AnalyzeTransaction performs fraud analysis on a transaction
 func (s *FraudEngine) AnalyzeTransaction(ctx context.Context, req *models.FraudCheckRequest) (*models.FraudCheckResponse, error) {
 	startTime := time.Now()
-	
+
+	// Rules judge the transaction at the time it happened, so replays are deterministic
+	if req.Timestamp.IsZero() {
+		req.Timestamp = startTime
+	}
+
+	// Replay the decision for a retried transaction instead of re-running every rule
+	if !req.ForceRecheck {
+		if cached := s.getCachedDecision(ctx, req.TransactionID); cached != nil {
+			return cached, nil
+		}
+	}
+
+	// Merchants that turned fraud checks off are approved without running or recording anything
+	mode := s.merchantMode(req.MerchantID)
+	if mode == models.MerchantModeOff {
+		return &models.FraudCheckResponse{
+			TransactionID: req.TransactionID,
+			RiskLevel:     models.RiskLevelLow,
+			Decision:      models.DecisionApprove,
+			Flags:         []string{},
+			Rules:         []models.RuleResult{},
+			Timestamp:     time.Now(),
+			Mode:          mode,
+		}, nil
+	}
+
 	// Initialize response
 	response := &models.FraudCheckResponse{
 		TransactionID: req.TransactionID,
@@ -38,28 +111,62 @@ func (s *FraudEngine) AnalyzeTransaction(ctx context.Context, req *models.FraudC
 		Rules:         []models.RuleResult{},
 		Timestamp:     time.Now(),
 	}
-
-	// Run all fraud detection rules
-	rules := []func(context.Context, *models.FraudCheckRequest, *models.FraudCheckResponse) error{
-		s.checkVelocity,
-		s.checkAmountThreshold,
-		s.checkGeolocation,
-		s.checkBlacklist,
-		s.checkTimePattern,
-		s.checkDeviceFingerprint,
-	}
-
-	for _, rule := range rules {
-		if err := rule(ctx, req, response); err != nil {
-			s.logger.Error("fraud rule execution failed", 
+	profileName, profile := s.ruleProfile(req.MerchantID)
+	response.RuleProfile = profileName
+
+	// Run all fraud detection rules. A rule that couldn't be evaluated is reported
+	// as errored rather than as not triggered
+	errored := false
+	for _, rule := range s.rules() {
+		// Disabled rules are still listed so the response shows what wasn't checked
+		if s.disabledRules[rule.name] {
+			response.Rules = append(response.Rules, models.RuleResult{
+				RuleName:    rule.name,
+				Skipped:     true,
+				Description: "Rule disabled",
+			})
+			continue
+		}
+		if profile != nil && !profile[rule.name] {
+			response.Rules = append(response.Rules, models.RuleResult{
+				RuleName:    rule.name,
+				Skipped:     true,
+				Description: fmt.Sprintf("Rule not in the %s profile", profileName),
+			})
+			continue
+		}
+		if err := rule.check(ctx, req, response); err != nil {
+			s.logger.Error("fraud rule execution failed",
 				zap.Error(err),
+				zap.String("rule", rule.name),
 				zap.String("transaction_id", req.TransactionID))
+			errored = true
+			response.Rules = append(response.Rules, models.RuleResult{
+				RuleName:    rule.name,
+				Errored:     true,
+				Description: "Rule could not be evaluated",
+			})
 		}
 	}
+	if errored {
+		response.Flags = append(response.Flags, "rule_errored")
+	}
+
+	// Rule scores add up past 100, e.g. a blacklisted card at an unusual hour, so the
+	// total is clamped to read as a percentage. Rules keep their own contributions.
+	response.Score = clampScore(response.Score)
 
 	// Calculate final risk level
 	response.RiskLevel = s.calculateRiskLevel(response.Score)
 	response.Decision = s.makeDecision(response.RiskLevel, response.Score)
+	if errored && response.Decision == models.DecisionApprove {
+		// Without every rule's verdict, a clean score can't be trusted
+		response.Decision = models.DecisionReview
+	}
+	modelFactors := s.scoreWithModel(ctx, req, response)
+	if mode == models.MerchantModeMonitor {
+		response.Flags = append(response.Flags, "monitor_mode")
+	}
 	
 	// Save fraud check result
 	result := &models.FraudCheckResult{
@@ -75,43 +182,116 @@ func (s *FraudEngine) AnalyzeTransaction(ctx context.Context, req *models.FraudC
 	if err := s.repo.SaveFraudCheck(ctx, result); err != nil {
 		s.logger.Error("failed to save fraud check", zap.Error(err))
 	}
+	s.saveCheckDetails(ctx, req, response, modelFactors, result.CreatedAt)
+	s.recordLocation(ctx, req)
 
 	// Send webhook if high risk
 	if response.RiskLevel == models.RiskLevelHigh {
 		s.sendFraudAlert(ctx, response)
 	}
 
+	// The computed decision is recorded and alerted on above; monitored merchants are still approved
+	applyMerchantMode(response, mode)
+
+	// A decision made without every rule is re-evaluated when the transaction is retried
+	if !errored {
+		s.cacheDecision(ctx, response)
+	}
+
 	return response, nil
 }
 
-// checkVelocity checks transaction velocity (transactions per time window)
+// fraudRule is a named check that adds its score and flags to the response
+type fraudRule struct {
+	name  string
+	check func(context.Context, *models.FraudCheckRequest, *models.FraudCheckResponse) error
+}
+
+// rules lists every fraud rule in the order they run
+func (s *FraudEngine) rules() []fraudRule {
+	return []fraudRule{
+		{"velocity_check", s.checkVelocity},
+		{"amount_threshold", s.checkAmountThreshold},
+		{"geolocation_check", s.checkGeolocation},
+		{"impossible_travel", s.checkImpossibleTravel},
+		{"issuer_country", s.checkIssuerCountry},
+		{"blacklist_check", s.checkBlacklist},
+		{"time_pattern", s.checkTimePattern},
+		{"device_fingerprint", s.checkDeviceFingerprint},
+	}
+}
+
+// SetDisabledRules turns the named rules off; they contribute no score and are
+// reported as skipped. Unknown rule names are rejected.
+func (s *FraudEngine) SetDisabledRules(names []string) error {
+	known := s.knownRules()
+
+	disabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if !known[name] {
+			return fmt.Errorf("unknown fraud rule %q", name)
+		}
+		disabled[name] = true
+	}
+	s.disabledRules = disabled
+	return nil
+}
+
+// checkVelocity checks transaction velocity (transactions per time window) and amount
+// velocity (spend per time window), flagging each separately
 func (s *FraudEngine) checkVelocity(ctx context.Context, req *models.FraudCheckRequest, resp *models.FraudCheckResponse) error {
 	// Check transactions in last hour
-	count, err := s.repo.CountRecentTransactions(ctx, req.CustomerEmail, 1*time.Hour)
+	count, err := retryRuleQuery(ctx, s.ruleBackoff, func() (int, error) {
+		return s.repo.CountRecentTransactions(ctx, req.CustomerEmail, 1*time.Hour)
+	})
+	if err != nil {
+		return err
+	}
+	previousSpend, err := retryRuleQuery(ctx, s.ruleBackoff, func() (float64, error) {
+		return s.repo.SumRecentAmounts(ctx, req.CustomerEmail, req.TransactionID, 1*time.Hour)
+	})
 	if err != nil {
 		return err
 	}
+	spend := previousSpend + req.Amount
 
 	ruleResult := models.RuleResult{
 		RuleName:    "velocity_check",
 		Triggered:   false,
 		Score:       0,
-		Description: fmt.Sprintf("Transaction count in last hour: %d", count),
+		Description: fmt.Sprintf("Transaction count in last hour: %d, amount in last hour: %.2f", count, spend),
 	}
 
 	// Thresholds
-	if count > 10 {
+	thresholds := s.velocityThresholds
+	if count > thresholds.HighCount {
 		ruleResult.Triggered = true
-		ruleResult.Score = 40
+		ruleResult.Score += 40
 		resp.Flags = append(resp.Flags, "high_velocity")
 		resp.Score += 40
-	} else if count > 5 {
+	} else if count > thresholds.ModerateCount {
 		ruleResult.Triggered = true
-		ruleResult.Score = 20
+		ruleResult.Score += 20
 		resp.Flags = append(resp.Flags, "moderate_velocity")
 		resp.Score += 20
 	}
 
+	// A lone large transaction is amount_threshold's to score, not velocity's
+	if previousSpend > 0 {
+		if spend > thresholds.HighAmount {
+			ruleResult.Triggered = true
+			ruleResult.Score += 30
+			resp.Flags = append(resp.Flags, "high_amount_velocity")
+			resp.Score += 30
+		} else if spend > thresholds.ModerateAmount {
+			ruleResult.Triggered = true
+			ruleResult.Score += 15
+			resp.Flags = append(resp.Flags, "moderate_amount_velocity")
+			resp.Score += 15
+		}
+	}
+
 	resp.Rules = append(resp.Rules, ruleResult)
 	return nil
 }
@@ -158,7 +338,9 @@ func (s *FraudEngine) checkGeolocation(ctx context.Context, req *models.FraudChe
 	}
 
 	// Get customer's usual locations
-	recentLocations, err := s.repo.GetRecentLocations(ctx, req.CustomerEmail, 30*24*time.Hour)
+	recentLocations, err := retryRuleQuery(ctx, s.ruleBackoff, func() ([]string, error) {
+		return s.repo.GetRecentLocations(ctx, req.CustomerEmail, 30*24*time.Hour)
+	})
 	if err != nil {
 		return err
 	}
@@ -198,6 +380,27 @@ func (s *FraudEngine) checkGeolocation(ctx context.Context, req *models.FraudChe
 	return nil
 }
 
+// checkIssuerCountry flags cards used outside the country they were issued in
+func (s *FraudEngine) checkIssuerCountry(ctx context.Context, req *models.FraudCheckRequest, resp *models.FraudCheckResponse) error {
+	ruleResult := models.RuleResult{
+		RuleName:    "issuer_country",
+		Triggered:   false,
+		Score:       0,
+		Description: fmt.Sprintf("Issuer country: %s, transaction country: %s", req.IssuerCountry, req.Country),
+	}
+
+	// Either country may be unknown; only a known mismatch is suspicious
+	if req.IssuerCountry != "" && req.Country != "" && !strings.EqualFold(req.IssuerCountry, req.Country) {
+		ruleResult.Triggered = true
+		ruleResult.Score = 20
+		resp.Flags = append(resp.Flags, "issuer_country_mismatch")
+		resp.Score += 20
+	}
+
+	resp.Rules = append(resp.Rules, ruleResult)
+	return nil
+}
+
 // checkBlacklist checks if customer/card is blacklisted
 func (s *FraudEngine) checkBlacklist(ctx context.Context, req *models.FraudCheckRequest, resp *models.FraudCheckResponse) error {
 	ruleResult := models.RuleResult{
@@ -207,7 +410,9 @@ func (s *FraudEngine) checkBlacklist(ctx context.Context, req *models.FraudCheck
 		Description: "Checking blacklist status",
 	}
 
-	isBlacklisted, err := s.repo.IsBlacklisted(ctx, req.CustomerEmail, req.CardLast4)
+	isBlacklisted, err := retryRuleQuery(ctx, s.ruleBackoff, func() (bool, error) {
+		return s.repo.IsBlacklisted(ctx, req.CustomerEmail, req.CardLast4)
+	})
 	if err != nil {
 		return err
 	}
@@ -216,7 +421,7 @@ func (s *FraudEngine) checkBlacklist(ctx context.Context, req *models.FraudCheck
 		ruleResult.Triggered = true
 		ruleResult.Score = 100 // Automatic block
 		resp.Flags = append(resp.Flags, "blacklisted")
-		resp.Score = 100
+		resp.Score += 100
 	}
 
 	resp.Rules = append(resp.Rules, ruleResult)
@@ -229,10 +434,10 @@ func (s *FraudEngine) checkTimePattern(ctx context.Context, req *models.FraudChe
 		RuleName:    "time_pattern",
 		Triggered:   false,
 		Score:       0,
-		Description: fmt.Sprintf("Transaction hour: %d", time.Now().Hour()),
+		Description: fmt.Sprintf("Transaction hour: %d", req.Timestamp.Hour()),
 	}
 
-	hour := time.Now().Hour()
+	hour := req.Timestamp.Hour()
 	
 	// Transactions between 2 AM and 5 AM are more suspicious
 	if hour >= 2 && hour <= 5 {
@@ -256,7 +461,9 @@ func (s *FraudEngine) checkDeviceFingerprint(ctx context.Context, req *models.Fr
 	}
 
 	if req.DeviceFingerprint != "" {
-		isKnownDevice, err := s.repo.IsKnownDevice(ctx, req.CustomerEmail, req.DeviceFingerprint)
+		isKnownDevice, err := retryRuleQuery(ctx, s.ruleBackoff, func() (bool, error) {
+			return s.repo.IsKnownDevice(ctx, req.CustomerEmail, req.DeviceFingerprint)
+		})
 		if err != nil {
 			return err
 		}
@@ -273,6 +480,23 @@ func (s *FraudEngine) checkDeviceFingerprint(ctx context.Context, req *models.Fr
 	return nil
 }
 
+// Fraud scores are reported as a percentage
+const (
+	minFraudScore = 0
+	maxFraudScore = 100
+)
+
+// clampScore bounds a summed rule score to [minFraudScore, maxFraudScore]
+func clampScore(score int) int {
+	if score < minFraudScore {
+		return minFraudScore
+	}
+	if score > maxFraudScore {
+		return maxFraudScore
+	}
+	return score
+}
+
 // calculateRiskLevel determines risk level based on score
 func (s *FraudEngine) calculateRiskLevel(score int) models.RiskLevel {
 	switch {
@@ -300,11 +524,78 @@ func (s *FraudEngine) makeDecision(riskLevel models.RiskLevel, score int) models
 	}
 }
 
+// SetAlertSender configures where high-risk alerts are delivered
+func (s *FraudEngine) SetAlertSender(alerter AlertSender) {
+	s.alerter = alerter
+}
+
 // sendFraudAlert sends webhook notification for high-risk transactions
 func (s *FraudEngine) sendFraudAlert(ctx context.Context, response *models.FraudCheckResponse) {
-	// In production, send to webhook endpoint
+	alert := newFraudAlert(response)
+
 	s.logger.Warn("high-risk transaction detected",
+		zap.String("alert_id", alert.AlertID),
 		zap.String("transaction_id", response.TransactionID),
 		zap.Int("score", response.Score),
 		zap.Strings("flags", response.Flags))
+
+	if s.alerter == nil {
+		return
+	}
+
+	// Deliver in the background so retries don't hold up the fraud check
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), alertSendTimeout)
+		defer cancel()
+
+		if err := s.alerter.SendAlert(ctx, alert); err != nil {
+			s.logger.Error("failed to send fraud alert",
+				zap.String("alert_id", alert.AlertID),
+				zap.Error(err))
+		}
+	}()
+}
+
+func decisionCacheKey(transactionID string) string {
+	return fmt.Sprintf("fraud:check:%s", transactionID)
+}
+
+// getCachedDecision returns a previously computed decision annotated as cached
+func (s *FraudEngine) getCachedDecision(ctx context.Context, transactionID string) *models.FraudCheckResponse {
+	if s.cache == nil {
+		return nil
+	}
+
+	data, err := s.cache.Get(ctx, decisionCacheKey(transactionID))
+	if err != nil {
+		return nil
+	}
+
+	var response models.FraudCheckResponse
+	if err := json.Unmarshal([]byte(data), &response); err != nil {
+		s.logger.Warn("failed to decode cached fraud decision",
+			zap.String("transaction_id", transactionID),
+			zap.Error(err))
+		return nil
+	}
+
+	response.Cached = true
+	return &response
+}
+
+func (s *FraudEngine) cacheDecision(ctx context.Context, response *models.FraudCheckResponse) {
+	if s.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	if err := s.cache.Set(ctx, decisionCacheKey(response.TransactionID), data, decisionCacheTTL); err != nil {
+		s.logger.Warn("failed to cache fraud decision",
+			zap.String("transaction_id", response.TransactionID),
+			zap.Error(err))
+	}
 }
\ No newline at end of file
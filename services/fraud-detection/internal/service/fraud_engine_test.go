@@ -0,0 +1,482 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func newTestRequest() *models.FraudCheckRequest {
+	return &models.FraudCheckRequest{
+		TransactionID: "txn_1",
+		Amount:        100,
+		Currency:      "USD",
+		CustomerEmail: "customer@example.com",
+		CardLast4:     "4242",
+		Country:       "US",
+	}
+}
+
+func TestAnalyzeTransactionReturnsCachedDecision(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{recentCount: 7}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+
+	first, err := engine.AnalyzeTransaction(ctx, newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Cached {
+		t.Error("first check should not be cached")
+	}
+
+	second, err := engine.AnalyzeTransaction(ctx, newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !second.Cached {
+		t.Error("second check should be served from cache")
+	}
+	if store.velocityCalls != 1 {
+		t.Errorf("rules ran %d times, want 1", store.velocityCalls)
+	}
+	if len(store.saved) != 1 {
+		t.Errorf("saved %d results, want 1", len(store.saved))
+	}
+	if second.Score != first.Score || second.Decision != first.Decision {
+		t.Errorf("cached decision %d/%s differs from original %d/%s",
+			second.Score, second.Decision, first.Score, first.Decision)
+	}
+}
+
+func TestAnalyzeTransactionForceRecheckBypassesCache(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+
+	if _, err := engine.AnalyzeTransaction(ctx, newTestRequest()); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest()
+	req.ForceRecheck = true
+	resp, err := engine.AnalyzeTransaction(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Cached {
+		t.Error("force recheck should not return a cached decision")
+	}
+	if store.velocityCalls != 2 {
+		t.Errorf("rules ran %d times, want 2", store.velocityCalls)
+	}
+}
+
+func TestAnalyzeTransactionUsesTransactionTimestamp(t *testing.T) {
+	tests := []struct {
+		name        string
+		timestamp   time.Time
+		wantFlagged bool
+	}{
+		{
+			name:        "3 AM",
+			timestamp:   time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC),
+			wantFlagged: true,
+		},
+		{
+			name:      "3 PM",
+			timestamp: time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+			req := newTestRequest()
+			req.Timestamp = tt.timestamp
+
+			resp, err := engine.AnalyzeTransaction(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			flagged := false
+			for _, flag := range resp.Flags {
+				if flag == "unusual_hour" {
+					flagged = true
+				}
+			}
+			if flagged != tt.wantFlagged {
+				t.Errorf("unusual_hour flagged = %v, want %v (flags %v)", flagged, tt.wantFlagged, resp.Flags)
+			}
+		})
+	}
+}
+
+func TestCheckIssuerCountry(t *testing.T) {
+	tests := []struct {
+		name          string
+		country       string
+		issuerCountry string
+		wantFlagged   bool
+	}{
+		{
+			name:          "Issued where used",
+			country:       "US",
+			issuerCountry: "us",
+		},
+		{
+			name:          "Issued abroad",
+			country:       "US",
+			issuerCountry: "BR",
+			wantFlagged:   true,
+		},
+		{
+			name:    "Issuer unknown",
+			country: "US",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+			req := newTestRequest()
+			req.Country = tt.country
+			req.IssuerCountry = tt.issuerCountry
+			resp := &models.FraudCheckResponse{}
+
+			if err := engine.checkIssuerCountry(context.Background(), req, resp); err != nil {
+				t.Fatal(err)
+			}
+
+			flagged := len(resp.Flags) == 1 && resp.Flags[0] == "issuer_country_mismatch"
+			if flagged != tt.wantFlagged {
+				t.Errorf("issuer_country_mismatch flagged = %v, want %v (flags %v)", flagged, tt.wantFlagged, resp.Flags)
+			}
+			if tt.wantFlagged && resp.Score != 20 {
+				t.Errorf("Score = %d, want 20", resp.Score)
+			}
+		})
+	}
+}
+
+func TestCheckImpossibleTravel(t *testing.T) {
+	now := time.Date(2024, 3, 10, 15, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		lastCountry string
+		elapsed     time.Duration
+		country     string
+		wantFlagged bool
+	}{
+		{
+			name:        "London then New York an hour later",
+			lastCountry: "GB",
+			elapsed:     time.Hour,
+			country:     "US",
+			wantFlagged: true,
+		},
+		{
+			name:        "London then New York a day later",
+			lastCountry: "GB",
+			elapsed:     24 * time.Hour,
+			country:     "US",
+		},
+		{
+			name:        "Neighbouring countries minutes apart",
+			lastCountry: "FR",
+			elapsed:     10 * time.Minute,
+			country:     "DE",
+		},
+		{
+			name:        "Unknown previous country",
+			lastCountry: "ZZ",
+			elapsed:     time.Minute,
+			country:     "US",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStore{}
+			store.locationLog = append(store.locationLog, &models.CustomerLocation{
+				CustomerEmail: "customer@example.com",
+				TransactionID: "txn_0",
+				Country:       tt.lastCountry,
+				SeenAt:        now.Add(-tt.elapsed),
+			})
+			engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+			req := newTestRequest()
+			req.Country = tt.country
+			req.Timestamp = now
+			resp := &models.FraudCheckResponse{}
+
+			if err := engine.checkImpossibleTravel(context.Background(), req, resp); err != nil {
+				t.Fatal(err)
+			}
+
+			flagged := len(resp.Flags) == 1 && resp.Flags[0] == "impossible_travel"
+			if flagged != tt.wantFlagged {
+				t.Errorf("impossible_travel flagged = %v, want %v (%s)", flagged, tt.wantFlagged, resp.Rules[0].Description)
+			}
+		})
+	}
+}
+
+func TestAnalyzeTransactionRecordsLocation(t *testing.T) {
+	store := &mockStore{}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	start := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	first := newTestRequest()
+	first.Country = "JP"
+	first.Timestamp = start
+	if _, err := engine.AnalyzeTransaction(context.Background(), first); err != nil {
+		t.Fatal(err)
+	}
+
+	second := newTestRequest()
+	second.TransactionID = "txn_2"
+	second.Timestamp = start.Add(2 * time.Hour)
+	resp, err := engine.AnalyzeTransaction(context.Background(), second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flagged := false
+	for _, flag := range resp.Flags {
+		if flag == "impossible_travel" {
+			flagged = true
+		}
+	}
+	if !flagged {
+		t.Errorf("Tokyo then the US two hours later was not flagged (flags %v)", resp.Flags)
+	}
+	if len(store.locationLog) != 2 {
+		t.Errorf("recorded %d locations, want 2", len(store.locationLog))
+	}
+}
+
+func TestAnalyzeTransactionSkipsDisabledRule(t *testing.T) {
+	// 3 AM would trigger the unusual-hour rule
+	threeAM := time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC)
+
+	enabled := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+	req := newTestRequest()
+	req.Timestamp = threeAM
+	baseline, err := enabled.AnalyzeTransaction(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	disabled := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+	if err := disabled.SetDisabledRules([]string{"time_pattern"}); err != nil {
+		t.Fatal(err)
+	}
+	req = newTestRequest()
+	req.Timestamp = threeAM
+	resp, err := disabled.AnalyzeTransaction(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Score != baseline.Score-10 {
+		t.Errorf("Score = %d, want %d without the unusual-hour rule", resp.Score, baseline.Score-10)
+	}
+	for _, flag := range resp.Flags {
+		if flag == "unusual_hour" {
+			t.Errorf("disabled rule still flagged unusual_hour (flags %v)", resp.Flags)
+		}
+	}
+
+	var skipped *models.RuleResult
+	for i := range resp.Rules {
+		if resp.Rules[i].RuleName == "time_pattern" {
+			skipped = &resp.Rules[i]
+		}
+	}
+	if skipped == nil || !skipped.Skipped || skipped.Triggered || skipped.Score != 0 {
+		t.Errorf("time_pattern rule = %+v, want it listed as skipped", skipped)
+	}
+}
+
+func TestSetDisabledRulesRejectsUnknownRule(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+	if err := engine.SetDisabledRules([]string{"time_pattern", "no_such_rule"}); err == nil {
+		t.Error("expected an error for an unknown rule name")
+	}
+}
+
+func TestCheckVelocityBandsByAmount(t *testing.T) {
+	tests := []struct {
+		name         string
+		recentCount  int
+		recentAmount float64
+		amount       float64
+		wantFlags    []string
+		wantScore    int
+	}{
+		{
+			name:         "Many small transactions",
+			recentCount:  11,
+			recentAmount: 50,
+			amount:       5,
+			wantFlags:    []string{"high_velocity"},
+			wantScore:    40,
+		},
+		{
+			name:         "Few large transactions",
+			recentCount:  2,
+			recentAmount: 10000,
+			amount:       5000,
+			wantFlags:    []string{"high_amount_velocity"},
+			wantScore:    30,
+		},
+		{
+			name:         "Moderate count and spend",
+			recentCount:  6,
+			recentAmount: 5000,
+			amount:       100,
+			wantFlags:    []string{"moderate_velocity", "moderate_amount_velocity"},
+			wantScore:    35,
+		},
+		{
+			name:      "Lone large transaction",
+			amount:    20000,
+			wantFlags: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStore{recentCount: tt.recentCount, recentAmount: tt.recentAmount}
+			engine := NewFraudEngine(store, nil, zap.NewNop())
+			req := newTestRequest()
+			req.Amount = tt.amount
+			resp := &models.FraudCheckResponse{Flags: []string{}}
+
+			if err := engine.checkVelocity(context.Background(), req, resp); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(resp.Flags, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", resp.Flags, tt.wantFlags)
+			}
+			if resp.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d", resp.Score, tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestSetVelocityThresholds(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{recentAmount: 300}, nil, zap.NewNop())
+	if err := engine.SetVelocityThresholds(VelocityThresholds{ModerateCount: 5, HighCount: 10, ModerateAmount: 100, HighAmount: 200}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp := &models.FraudCheckResponse{}
+	if err := engine.checkVelocity(context.Background(), newTestRequest(), resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Flags) != 1 || resp.Flags[0] != "high_amount_velocity" {
+		t.Errorf("flags = %v, want high_amount_velocity under the lowered thresholds", resp.Flags)
+	}
+
+	if err := engine.SetVelocityThresholds(VelocityThresholds{ModerateCount: 10, HighCount: 5, ModerateAmount: 100, HighAmount: 200}); err == nil {
+		t.Error("expected an error when the moderate count is above the high count")
+	}
+}
+
+func TestAnalyzeTransactionRetriesFailedRuleQuery(t *testing.T) {
+	store := &mockStore{recentCount: 7, velocityErrs: 2}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	engine.ruleBackoff = time.Millisecond
+
+	response, err := engine.AnalyzeTransaction(context.Background(), newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if store.velocityCalls != 3 {
+		t.Errorf("velocity queried %d times, want 3", store.velocityCalls)
+	}
+	for _, rule := range response.Rules {
+		if rule.Errored {
+			t.Errorf("rule %s errored after the query recovered", rule.RuleName)
+		}
+		if rule.RuleName == "velocity_check" && !rule.Triggered {
+			t.Error("velocity_check not triggered by 7 recent transactions")
+		}
+	}
+}
+
+func TestAnalyzeTransactionReportsErroredRule(t *testing.T) {
+	store := &mockStore{velocityErrs: ruleQueryMaxAttempts}
+	cache := newMemoryCache()
+	engine := NewFraudEngine(store, cache, zap.NewNop())
+	engine.ruleBackoff = time.Millisecond
+
+	response, err := engine.AnalyzeTransaction(context.Background(), newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var velocity *models.RuleResult
+	for i := range response.Rules {
+		if response.Rules[i].RuleName == "velocity_check" {
+			velocity = &response.Rules[i]
+		}
+	}
+	if velocity == nil || !velocity.Errored {
+		t.Fatalf("velocity_check = %+v, want it reported as errored", velocity)
+	}
+	// The clean score would approve, but a missing rule sends it to review
+	if response.Decision != models.DecisionReview {
+		t.Errorf("Decision = %s, want review when a rule errored", response.Decision)
+	}
+	if len(cache.data) != 0 {
+		t.Error("decision with an errored rule was cached")
+	}
+}
+
+func TestAnalyzeTransactionCapsScore(t *testing.T) {
+	// A blacklisted card, high velocity, 3 AM and a new device sum to well over 100
+	store := &mockStore{blacklisted: true, recentCount: 12}
+	engine := NewFraudEngine(store, nil, zap.NewNop())
+	req := newTestRequest()
+	req.Timestamp = time.Date(2024, 3, 10, 3, 0, 0, 0, time.UTC)
+	req.DeviceFingerprint = "fp_new"
+
+	resp, err := engine.AnalyzeTransaction(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.Score != 100 {
+		t.Errorf("Score = %d, want it capped at 100", resp.Score)
+	}
+
+	contributions := map[string]int{}
+	sum := 0
+	for _, rule := range resp.Rules {
+		contributions[rule.RuleName] = rule.Score
+		sum += rule.Score
+	}
+	if sum <= 100 {
+		t.Fatalf("rule scores sum to %d, want more than 100 for the cap to apply", sum)
+	}
+	want := map[string]int{"blacklist_check": 100, "velocity_check": 40, "time_pattern": 10, "device_fingerprint": 15}
+	for rule, score := range want {
+		if contributions[rule] != score {
+			t.Errorf("%s contributed %d, want %d", rule, contributions[rule], score)
+		}
+	}
+}
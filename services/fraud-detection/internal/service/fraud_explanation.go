@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+var ErrFraudResultNotFound = errors.New("fraud result not found")
+
+// saveCheckDetails keeps what a decision was made on so it can be explained later
+func (s *FraudEngine) saveCheckDetails(ctx context.Context, req *models.FraudCheckRequest, response *models.FraudCheckResponse, modelFactors []models.ModelFactor, at time.Time) {
+	details := &models.FraudCheckDetails{
+		TransactionID: response.TransactionID,
+		Score:         response.Score,
+		RiskLevel:     response.RiskLevel,
+		Decision:      response.Decision,
+		Rules:         response.Rules,
+		ModelVersion:  response.ModelVersion,
+		ModelFactors:  modelFactors,
+		CreatedAt:     at,
+		Request:       req,
+	}
+	if details.ModelFactors == nil {
+		details.ModelFactors = []models.ModelFactor{}
+	}
+
+	if err := s.repo.SaveCheckDetails(ctx, details); err != nil {
+		s.logger.Error("failed to save fraud check details",
+			zap.Error(err),
+			zap.String("transaction_id", response.TransactionID))
+	}
+}
+
+// ExplainDecision returns the triggered rules and model factors behind the latest
+// check of a transaction, each ranked by contribution
+func (s *FraudEngine) ExplainDecision(ctx context.Context, transactionID string) (*models.FraudExplanation, error) {
+	details, err := s.repo.GetCheckDetails(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, ErrFraudResultNotFound
+	}
+
+	rules := []models.RuleResult{}
+	for _, rule := range details.Rules {
+		if rule.Triggered {
+			rules = append(rules, rule)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Score > rules[j].Score })
+
+	factors := append([]models.ModelFactor{}, details.ModelFactors...)
+	sortModelFactors(factors)
+
+	return &models.FraudExplanation{
+		TransactionID: details.TransactionID,
+		Score:         details.Score,
+		RiskLevel:     details.RiskLevel,
+		Decision:      details.Decision,
+		Rules:         rules,
+		ModelVersion:  details.ModelVersion,
+		ModelFactors:  factors,
+		CheckedAt:     details.CreatedAt,
+	}, nil
+}
+
+// sortModelFactors orders factors by contribution, largest first, breaking ties by name
+func sortModelFactors(factors []models.ModelFactor) {
+	sort.Slice(factors, func(i, j int) bool {
+		if factors[i].Contribution != factors[j].Contribution {
+			return factors[i].Contribution > factors[j].Contribution
+		}
+		return factors[i].Feature < factors[j].Feature
+	})
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestExplainDecisionRanksTopFactorFirst(t *testing.T) {
+	ctx := context.Background()
+	// Moderate velocity (20) runs before the large amount (30) and the new device (15)
+	store := &mockStore{recentCount: 7}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	engine.SetModel(LoadPretrainedModel())
+
+	req := newTestRequest()
+	req.Amount = 20000
+	req.DeviceFingerprint = "device_1"
+	response, err := engine.AnalyzeTransaction(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	explanation, err := engine.ExplainDecision(ctx, req.TransactionID)
+	if err != nil {
+		t.Fatalf("ExplainDecision() error = %v", err)
+	}
+
+	wantRules := []string{"amount_threshold", "velocity_check", "device_fingerprint"}
+	if len(explanation.Rules) != len(wantRules) {
+		t.Fatalf("explained %d rules, want %d: %+v", len(explanation.Rules), len(wantRules), explanation.Rules)
+	}
+	for i, name := range wantRules {
+		if explanation.Rules[i].RuleName != name {
+			t.Errorf("rule %d = %s, want %s", i, explanation.Rules[i].RuleName, name)
+		}
+	}
+
+	if len(explanation.ModelFactors) == 0 || explanation.ModelFactors[0].Feature != "amount" {
+		t.Fatalf("model factors = %+v, want amount first", explanation.ModelFactors)
+	}
+	for i := 1; i < len(explanation.ModelFactors); i++ {
+		if explanation.ModelFactors[i].Contribution > explanation.ModelFactors[i-1].Contribution {
+			t.Errorf("model factors not sorted by contribution: %+v", explanation.ModelFactors)
+		}
+	}
+
+	if explanation.Decision != response.Decision || explanation.Score != response.Score || explanation.ModelVersion != "1.0.0" {
+		t.Errorf("explanation = %+v, want the decision %s at score %d from model 1.0.0", explanation, response.Decision, response.Score)
+	}
+}
+
+func TestExplainDecisionNotFound(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+
+	if _, err := engine.ExplainDecision(context.Background(), "txn_missing"); !errors.Is(err, ErrFraudResultNotFound) {
+		t.Errorf("ExplainDecision() error = %v, want ErrFraudResultNotFound", err)
+	}
+}
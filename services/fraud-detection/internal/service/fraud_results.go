@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"fraud-detection/internal/models"
+	"shared/pkg/pagination"
+)
+
+const (
+	defaultResultsLimit = 50
+	maxResultsLimit     = 200
+)
+
+var ErrInvalidResultFilter = errors.New("invalid fraud result filter")
+
+// ListResults returns a page of stored fraud check results for triage, newest first
+func (s *FraudEngine) ListResults(ctx context.Context, filter models.FraudResultFilter) (pagination.Page[*models.FraudCheckResult], error) {
+	var empty pagination.Page[*models.FraudCheckResult]
+
+	switch filter.RiskLevel {
+	case "", models.RiskLevelLow, models.RiskLevelMedium, models.RiskLevelHigh:
+	default:
+		return empty, fmt.Errorf("%w: unknown risk_level %q", ErrInvalidResultFilter, filter.RiskLevel)
+	}
+
+	switch filter.Decision {
+	case "", models.DecisionApprove, models.DecisionReview, models.DecisionBlock:
+	default:
+		return empty, fmt.Errorf("%w: unknown decision %q", ErrInvalidResultFilter, filter.Decision)
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.To.After(filter.From) {
+		return empty, fmt.Errorf("%w: to must be after from", ErrInvalidResultFilter)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultResultsLimit
+	}
+	if filter.Limit > maxResultsLimit {
+		return empty, fmt.Errorf("%w: limit must be at most %d", ErrInvalidResultFilter, maxResultsLimit)
+	}
+	if filter.Offset < 0 {
+		return empty, fmt.Errorf("%w: offset must not be negative", ErrInvalidResultFilter)
+	}
+
+	results, err := s.repo.ListFraudChecks(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+	total, err := s.repo.CountFraudChecks(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+
+	return pagination.NewPage(results, total, filter.Limit, filter.Offset), nil
+}
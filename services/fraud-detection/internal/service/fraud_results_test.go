@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestListResultsByRiskLevel(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	store := &mockStore{saved: []*models.FraudCheckResult{
+		{ID: "r1", RiskLevel: string(models.RiskLevelHigh), Decision: string(models.DecisionBlock), CreatedAt: day.Add(time.Hour)},
+		{ID: "r2", RiskLevel: string(models.RiskLevelLow), Decision: string(models.DecisionApprove), CreatedAt: day.Add(2 * time.Hour)},
+		{ID: "r3", RiskLevel: string(models.RiskLevelHigh), Decision: string(models.DecisionReview), CreatedAt: day.Add(3 * time.Hour)},
+		{ID: "r4", RiskLevel: string(models.RiskLevelMedium), Decision: string(models.DecisionReview), CreatedAt: day.Add(4 * time.Hour)},
+		{ID: "r5", RiskLevel: string(models.RiskLevelHigh), Decision: string(models.DecisionBlock), CreatedAt: day.AddDate(0, 0, 2)},
+	}}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+
+	tests := []struct {
+		name      string
+		filter    models.FraudResultFilter
+		want      []string
+		wantTotal int
+	}{
+		{
+			name:      "High risk",
+			filter:    models.FraudResultFilter{RiskLevel: models.RiskLevelHigh},
+			want:      []string{"r5", "r3", "r1"},
+			wantTotal: 3,
+		},
+		{
+			name:      "High risk within a day",
+			filter:    models.FraudResultFilter{RiskLevel: models.RiskLevelHigh, From: day, To: day.AddDate(0, 0, 1)},
+			want:      []string{"r3", "r1"},
+			wantTotal: 2,
+		},
+		{
+			name:      "High risk held for review",
+			filter:    models.FraudResultFilter{RiskLevel: models.RiskLevelHigh, Decision: models.DecisionReview},
+			want:      []string{"r3"},
+			wantTotal: 1,
+		},
+		{
+			name:      "Paginated",
+			filter:    models.FraudResultFilter{RiskLevel: models.RiskLevelHigh, Limit: 1, Offset: 1},
+			want:      []string{"r3"},
+			wantTotal: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := engine.ListResults(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("ListResults() error = %v", err)
+			}
+			if page.Total != tt.wantTotal {
+				t.Errorf("total = %d, want %d", page.Total, tt.wantTotal)
+			}
+			if len(page.Items) != len(tt.want) {
+				t.Fatalf("got %d results, want %d", len(page.Items), len(tt.want))
+			}
+			for i, result := range page.Items {
+				if result.ID != tt.want[i] {
+					t.Errorf("result %d = %s, want %s", i, result.ID, tt.want[i])
+				}
+				if result.RiskLevel != string(models.RiskLevelHigh) {
+					t.Errorf("result %s has risk level %s", result.ID, result.RiskLevel)
+				}
+			}
+		})
+	}
+}
+
+func TestListResultsRejectsInvalidFilters(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	for _, filter := range []models.FraudResultFilter{
+		{RiskLevel: "critical"},
+		{Decision: "allow"},
+		{From: day, To: day},
+		{Limit: maxResultsLimit + 1},
+		{Offset: -1},
+	} {
+		if _, err := engine.ListResults(context.Background(), filter); !errors.Is(err, ErrInvalidResultFilter) {
+			t.Errorf("ListResults(%+v) error = %v, want %v", filter, err, ErrInvalidResultFilter)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+const (
+	// maxTravelSpeedKPH is faster than a commercial flight; covering the distance
+	// between two transactions any quicker means they weren't made by one person
+	maxTravelSpeedKPH = 1000.0
+
+	// minImpossibleTravelKM ignores neighbouring countries, whose centroids can be
+	// far apart while a customer near the border crosses in minutes
+	minImpossibleTravelKM = 1000.0
+
+	earthRadiusKM = 6371.0
+)
+
+// countryCentroids are approximate geographic centres by ISO 3166-1 alpha-2 code.
+// Distances between centroids are coarse, which the thresholds above allow for.
+var countryCentroids = map[string][2]float64{
+	"AE": {23.4, 53.8}, "AR": {-38.4, -63.6}, "AT": {47.5, 14.6}, "AU": {-25.3, 133.8},
+	"BE": {50.5, 4.5}, "BR": {-14.2, -51.9}, "CA": {56.1, -106.3}, "CH": {46.8, 8.2},
+	"CL": {-35.7, -71.5}, "CN": {35.9, 104.2}, "CO": {4.6, -74.3}, "CZ": {49.8, 15.5},
+	"DE": {51.2, 10.5}, "DK": {56.3, 9.5}, "EG": {26.8, 30.8}, "ES": {40.5, -3.7},
+	"FI": {61.9, 25.7}, "FR": {46.2, 2.2}, "GB": {55.4, -3.4}, "GR": {39.1, 21.8},
+	"HK": {22.3, 114.2}, "ID": {-0.8, 113.9}, "IE": {53.4, -8.2}, "IL": {31.0, 34.9},
+	"IN": {20.6, 79.0}, "IT": {41.9, 12.6}, "JP": {36.2, 138.3}, "KE": {0.0, 37.9},
+	"KR": {35.9, 127.8}, "MA": {31.8, -7.1}, "MX": {23.6, -102.6}, "MY": {4.2, 102.0},
+	"NG": {9.1, 8.7}, "NL": {52.1, 5.3}, "NO": {60.5, 8.5}, "NZ": {-40.9, 174.9},
+	"PE": {-9.2, -75.0}, "PH": {12.9, 121.8}, "PK": {30.4, 69.3}, "PL": {51.9, 19.1},
+	"PT": {39.4, -8.2}, "RO": {45.9, 25.0}, "RU": {61.5, 105.3}, "SA": {23.9, 45.1},
+	"SE": {60.1, 18.6}, "SG": {1.35, 103.8}, "TH": {15.9, 101.0}, "TR": {39.0, 35.2},
+	"TW": {23.7, 121.0}, "UA": {48.4, 31.2}, "US": {39.8, -98.6}, "VN": {14.1, 108.3},
+	"ZA": {-30.6, 22.9},
+}
+
+// checkImpossibleTravel flags a transaction made further from the customer's last
+// location than they could have travelled since
+func (s *FraudEngine) checkImpossibleTravel(ctx context.Context, req *models.FraudCheckRequest, resp *models.FraudCheckResponse) error {
+	ruleResult := models.RuleResult{
+		RuleName:    "impossible_travel",
+		Triggered:   false,
+		Score:       0,
+		Description: "No previous location to compare",
+	}
+
+	last, err := retryRuleQuery(ctx, s.ruleBackoff, func() (*models.CustomerLocation, error) {
+		return s.repo.GetLastLocation(ctx, req.CustomerEmail, req.TransactionID, req.Timestamp)
+	})
+	if err != nil {
+		return err
+	}
+
+	if last != nil && req.Country != "" {
+		distance, known := countryDistanceKM(last.Country, req.Country)
+		elapsed := req.Timestamp.Sub(last.SeenAt)
+		ruleResult.Description = fmt.Sprintf("%s to %s, %.0f km in %s", last.Country, req.Country, distance, elapsed.Round(time.Minute))
+
+		if known && distance >= minImpossibleTravelKM && travelSpeedKPH(distance, elapsed) > maxTravelSpeedKPH {
+			ruleResult.Triggered = true
+			ruleResult.Score = 50
+			resp.Flags = append(resp.Flags, "impossible_travel")
+			resp.Score += 50
+		}
+	}
+
+	resp.Rules = append(resp.Rules, ruleResult)
+	return nil
+}
+
+// recordLocation saves where the transaction came from for later impossible travel checks
+func (s *FraudEngine) recordLocation(ctx context.Context, req *models.FraudCheckRequest) {
+	if req.Country == "" {
+		return
+	}
+
+	location := &models.CustomerLocation{
+		CustomerEmail: req.CustomerEmail,
+		TransactionID: req.TransactionID,
+		Country:       strings.ToUpper(req.Country),
+		SeenAt:        req.Timestamp,
+	}
+	if err := s.repo.SaveLocation(ctx, location); err != nil {
+		s.logger.Error("failed to save customer location",
+			zap.Error(err),
+			zap.String("transaction_id", req.TransactionID))
+	}
+}
+
+// countryDistanceKM is the great-circle distance between two countries' centroids,
+// and whether both countries are known
+func countryDistanceKM(from, to string) (float64, bool) {
+	a, ok := countryCentroids[strings.ToUpper(from)]
+	if !ok {
+		return 0, false
+	}
+	b, ok := countryCentroids[strings.ToUpper(to)]
+	if !ok {
+		return 0, false
+	}
+
+	lat1, lat2 := a[0]*math.Pi/180, b[0]*math.Pi/180
+	dLat := lat2 - lat1
+	dLon := (b[1] - a[1]) * math.Pi / 180
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Sqrt(h)), true
+}
+
+// travelSpeedKPH is the speed needed to cover distance in elapsed; simultaneous
+// transactions need infinite speed
+func travelSpeedKPH(distance float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return math.Inf(1)
+	}
+	return distance / elapsed.Hours()
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"shared/pkg/ids"
+)
+
+var ErrInvalidListEntry = errors.New("invalid list entry")
+
+// AddToList adds an email or card to the blacklist or whitelist. Adding an entry
+// that is already listed updates its reason instead of failing, and the returned
+// bool reports whether the entry is new.
+func (s *FraudEngine) AddToList(ctx context.Context, list models.ListType, req *models.ListEntryRequest) (*models.ListEntry, bool, error) {
+	value, err := normalizeListValue(req.Kind, req.Value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := time.Now()
+	entry := &models.ListEntry{
+		ID:        ids.New(),
+		List:      list,
+		Kind:      req.Kind,
+		Value:     value,
+		Reason:    req.Reason,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	created, err := s.repo.UpsertListEntry(ctx, entry)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save %s entry: %w", list, err)
+	}
+
+	s.logger.Info("list entry saved",
+		zap.String("list", string(list)),
+		zap.String("kind", string(entry.Kind)),
+		zap.Bool("created", created))
+
+	return entry, created, nil
+}
+
+// normalizeListValue canonicalizes a value so repeat adds hit the same row
+func normalizeListValue(kind models.ListEntryKind, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch kind {
+	case models.ListEntryKindEmail:
+		if !strings.Contains(value, "@") {
+			return "", fmt.Errorf("%w: %q is not an email address", ErrInvalidListEntry, value)
+		}
+		return strings.ToLower(value), nil
+	case models.ListEntryKindCardLast4:
+		if len(value) != 4 || strings.Trim(value, "0123456789") != "" {
+			return "", fmt.Errorf("%w: card_last4 must be 4 digits", ErrInvalidListEntry)
+		}
+		return value, nil
+	default:
+		return "", fmt.Errorf("%w: unknown kind %q", ErrInvalidListEntry, kind)
+	}
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestAddToListIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+
+	first, created, err := engine.AddToList(ctx, models.ListTypeBlacklist, &models.ListEntryRequest{
+		Kind:   models.ListEntryKindEmail,
+		Value:  "fraudster@example.com",
+		Reason: "chargeback",
+	})
+	if err != nil {
+		t.Fatalf("first AddToList() error = %v", err)
+	}
+	if !created {
+		t.Error("first add should create the entry")
+	}
+
+	second, created, err := engine.AddToList(ctx, models.ListTypeBlacklist, &models.ListEntryRequest{
+		Kind:   models.ListEntryKindEmail,
+		Value:  " Fraudster@Example.com",
+		Reason: "confirmed stolen card",
+	})
+	if err != nil {
+		t.Fatalf("repeat AddToList() error = %v", err)
+	}
+	if created {
+		t.Error("repeat add should not create a new entry")
+	}
+
+	if len(store.listEntries) != 1 {
+		t.Fatalf("stored %d entries, want 1", len(store.listEntries))
+	}
+	stored := store.listEntries[0]
+	if stored.Reason != "confirmed stolen card" {
+		t.Errorf("reason = %q, want the repeat add's reason", stored.Reason)
+	}
+	if stored.UpdatedAt.Before(first.UpdatedAt) || !stored.UpdatedAt.Equal(second.UpdatedAt) {
+		t.Errorf("updated_at = %v, want %v", stored.UpdatedAt, second.UpdatedAt)
+	}
+	if second.ID != first.ID || !second.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("repeat add returned id %s created %v, want original %s created %v",
+			second.ID, second.CreatedAt, first.ID, first.CreatedAt)
+	}
+}
+
+func TestAddToListKeepsListsSeparate(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+
+	req := &models.ListEntryRequest{Kind: models.ListEntryKindCardLast4, Value: "4242"}
+	for _, list := range []models.ListType{models.ListTypeBlacklist, models.ListTypeWhitelist} {
+		if _, created, err := engine.AddToList(ctx, list, req); err != nil || !created {
+			t.Errorf("AddToList(%s) created = %v, error = %v", list, created, err)
+		}
+	}
+}
+
+func TestAddToListRejectsInvalidValues(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+
+	tests := []struct {
+		name string
+		req  models.ListEntryRequest
+	}{
+		{"Email without @", models.ListEntryRequest{Kind: models.ListEntryKindEmail, Value: "not-an-email"}},
+		{"Short card", models.ListEntryRequest{Kind: models.ListEntryKindCardLast4, Value: "42"}},
+		{"Non-digit card", models.ListEntryRequest{Kind: models.ListEntryKindCardLast4, Value: "42a2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := engine.AddToList(context.Background(), models.ListTypeBlacklist, &tt.req)
+			if !errors.Is(err, ErrInvalidListEntry) {
+				t.Errorf("AddToList() error = %v, want %v", err, ErrInvalidListEntry)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"fmt"
+
+	"fraud-detection/internal/models"
+)
+
+// SetMerchantModes sets each merchant's fraud mode; merchants not listed are enforced
+func (s *FraudEngine) SetMerchantModes(modes map[string]models.MerchantMode) error {
+	for merchantID, mode := range modes {
+		if !mode.Valid() {
+			return fmt.Errorf("%w: merchant %s has mode %q", models.ErrInvalidMerchantMode, merchantID, mode)
+		}
+	}
+	s.merchantModes = modes
+	return nil
+}
+
+// merchantMode returns the fraud mode for a merchant
+func (s *FraudEngine) merchantMode(merchantID string) models.MerchantMode {
+	if mode, ok := s.merchantModes[merchantID]; ok && merchantID != "" {
+		return mode
+	}
+	return models.MerchantModeEnforce
+}
+
+// applyMerchantMode approves a monitored merchant's transaction whatever the rules decided,
+// keeping the computed decision on the response
+func applyMerchantMode(response *models.FraudCheckResponse, mode models.MerchantMode) {
+	response.Mode = mode
+	if mode != models.MerchantModeMonitor {
+		return
+	}
+	response.ComputedDecision = response.Decision
+	response.Decision = models.DecisionApprove
+}
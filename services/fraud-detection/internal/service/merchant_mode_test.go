@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestAnalyzeTransactionMonitorModeApproves(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{blacklisted: true}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	if err := engine.SetMerchantModes(map[string]models.MerchantMode{"merchant_1": models.MerchantModeMonitor}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest()
+	req.MerchantID = "merchant_1"
+	response, err := engine.AnalyzeTransaction(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.Decision != models.DecisionApprove {
+		t.Errorf("decision = %s, want approve", response.Decision)
+	}
+	if response.ComputedDecision != models.DecisionBlock || response.Mode != models.MerchantModeMonitor {
+		t.Errorf("computed decision = %s in mode %s, want block in monitor", response.ComputedDecision, response.Mode)
+	}
+	if len(store.saved) != 1 {
+		t.Fatalf("saved %d results, want 1", len(store.saved))
+	}
+	if saved := store.saved[0]; saved.Decision != string(models.DecisionBlock) || saved.Score < 90 {
+		t.Errorf("recorded %s with score %d, want block with score >= 90", saved.Decision, saved.Score)
+	}
+}
+
+func TestAnalyzeTransactionMerchantModes(t *testing.T) {
+	tests := []struct {
+		name         string
+		merchantID   string
+		wantDecision models.Decision
+		wantMode     models.MerchantMode
+		wantSaved    int
+	}{
+		{
+			name:         "Unlisted merchant is enforced",
+			merchantID:   "merchant_2",
+			wantDecision: models.DecisionBlock,
+			wantMode:     models.MerchantModeEnforce,
+			wantSaved:    1,
+		},
+		{
+			name:         "No merchant is enforced",
+			wantDecision: models.DecisionBlock,
+			wantMode:     models.MerchantModeEnforce,
+			wantSaved:    1,
+		},
+		{
+			name:         "Off skips the check",
+			merchantID:   "merchant_off",
+			wantDecision: models.DecisionApprove,
+			wantMode:     models.MerchantModeOff,
+			wantSaved:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &mockStore{blacklisted: true}
+			engine := NewFraudEngine(store, nil, zap.NewNop())
+			if err := engine.SetMerchantModes(map[string]models.MerchantMode{"merchant_off": models.MerchantModeOff}); err != nil {
+				t.Fatal(err)
+			}
+
+			req := newTestRequest()
+			req.MerchantID = tt.merchantID
+			response, err := engine.AnalyzeTransaction(context.Background(), req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if response.Decision != tt.wantDecision || response.Mode != tt.wantMode {
+				t.Errorf("got %s in mode %s, want %s in mode %s", response.Decision, response.Mode, tt.wantDecision, tt.wantMode)
+			}
+			if len(store.saved) != tt.wantSaved {
+				t.Errorf("saved %d results, want %d", len(store.saved), tt.wantSaved)
+			}
+		})
+	}
+}
+
+func TestSetMerchantModesRejectsUnknownMode(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+	err := engine.SetMerchantModes(map[string]models.MerchantMode{"merchant_1": "shadow"})
+	if !errors.Is(err, models.ErrInvalidMerchantMode) {
+		t.Errorf("err = %v, want ErrInvalidMerchantMode", err)
+	}
+}
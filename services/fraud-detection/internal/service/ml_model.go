@@ -58,6 +58,11 @@ func LoadPretrainedModel() *MLModel {
 	}
 }
 
+// Version identifies the model's weights
+func (m *MLModel) Version() string {
+	return m.version
+}
+
 // TrainModel trains the model using gradient descent
 func (m *MLModel) TrainModel(ctx context.Context, trainingData []map[string]float64, labels []float64) error {
 	if len(trainingData) == 0 || len(trainingData) != len(labels) {
@@ -149,20 +154,72 @@ func (m *MLModel) Predict(ctx context.Context, features map[string]float64) floa
 	return probability * 100 // Convert to [0, 100]
 }
 
+// Contributions breaks a prediction down into each weighted feature's share of the
+// log-odds, largest first
+func (m *MLModel) Contributions(features map[string]float64) []models.ModelFactor {
+	factors := make([]models.ModelFactor, 0, len(features))
+	for feature, value := range features {
+		weight, exists := m.weights[feature]
+		if !exists {
+			continue
+		}
+		factors = append(factors, models.ModelFactor{
+			Feature:      feature,
+			Value:        value,
+			Weight:       weight,
+			Contribution: weight * value,
+		})
+	}
+	sortModelFactors(factors)
+	return factors
+}
+
 // sigmoid activation
 func (m *MLModel) sigmoid(x float64) float64 {
 	return 1.0 / (1.0 + math.Exp(-x))
 }
 
-// ExtractFeatures creates feature vector from transaction
-func ExtractFeatures(req *models.FraudCheckRequest, velocityCount int, isNewLocation, isUnusualHour, isNewDevice bool) map[string]float64 {
+// FeatureBounds are the raw values ExtractFeatures maps to 1. Anything larger is
+// clipped to 1, so bounds set too low make large values indistinguishable.
+type FeatureBounds struct {
+	MaxAmount   float64 `json:"max_amount"`
+	MaxVelocity int     `json:"max_velocity"`
+}
+
+// DefaultFeatureBounds are the bounds the pretrained weights were fitted with
+var DefaultFeatureBounds = FeatureBounds{MaxAmount: 10000, MaxVelocity: 20}
+
+// Validate reports whether both bounds are positive and finite
+func (b FeatureBounds) Validate() error {
+	if b.MaxAmount <= 0 || math.IsInf(b.MaxAmount, 0) || math.IsNaN(b.MaxAmount) {
+		return fmt.Errorf("max amount must be a positive number, got %v", b.MaxAmount)
+	}
+	if b.MaxVelocity <= 0 {
+		return fmt.Errorf("max velocity must be positive, got %d", b.MaxVelocity)
+	}
+	return nil
+}
+
+// ExtractFeatures creates feature vector from transaction, scaling amount and
+// velocity into [0, 1] by bounds. It also returns the features that fell outside
+// their range and were clipped; negative inputs are clipped to 0.
+func ExtractFeatures(req *models.FraudCheckRequest, bounds FeatureBounds, velocityCount int, isNewLocation, isUnusualHour, isNewDevice bool) (map[string]float64, []string) {
 	features := make(map[string]float64)
+	var clipped []string
 
 	// Normalize amount [0, 1]
-	features["amount"] = math.Min(req.Amount/10000.0, 1.0)
+	amount, ok := normalizeFeature(req.Amount, bounds.MaxAmount)
+	features["amount"] = amount
+	if !ok {
+		clipped = append(clipped, "amount")
+	}
 
 	// Normalize velocity [0, 1]
-	features["velocity"] = math.Min(float64(velocityCount)/20.0, 1.0)
+	velocity, ok := normalizeFeature(float64(velocityCount), float64(bounds.MaxVelocity))
+	features["velocity"] = velocity
+	if !ok {
+		clipped = append(clipped, "velocity")
+	}
 
 	// Binary features
 	if isNewLocation {
@@ -183,7 +240,19 @@ func ExtractFeatures(req *models.FraudCheckRequest, velocityCount int, isNewLoca
 		features["new_device"] = 0.0
 	}
 
-	return features
+	return features, clipped
+}
+
+// normalizeFeature scales value by max into [0, 1]. ok is false if value had to
+// be clipped, including when it is negative or NaN.
+func normalizeFeature(value, max float64) (normalized float64, ok bool) {
+	switch {
+	case math.IsNaN(value) || value < 0:
+		return 0, false
+	case value > max:
+		return 1, false
+	}
+	return value / max, true
 }
 
 // SaveModel saves weights to JSON file
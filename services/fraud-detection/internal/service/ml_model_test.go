@@ -0,0 +1,84 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"fraud-detection/internal/models"
+)
+
+func TestExtractFeaturesBounds(t *testing.T) {
+	bounds := FeatureBounds{MaxAmount: 10000, MaxVelocity: 20}
+
+	tests := []struct {
+		name         string
+		amount       float64
+		velocity     int
+		wantAmount   float64
+		wantVelocity float64
+		wantClipped  []string
+	}{
+		{name: "below bounds", amount: 2500, velocity: 5, wantAmount: 0.25, wantVelocity: 0.25},
+		{name: "at bounds", amount: 10000, velocity: 20, wantAmount: 1, wantVelocity: 1},
+		{name: "zero", amount: 0, velocity: 0, wantAmount: 0, wantVelocity: 0},
+		{name: "above bounds", amount: 25000, velocity: 40, wantAmount: 1, wantVelocity: 1, wantClipped: []string{"amount", "velocity"}},
+		{name: "amount above bound only", amount: 10000.01, velocity: 10, wantAmount: 1, wantVelocity: 0.5, wantClipped: []string{"amount"}},
+		{name: "negative", amount: -50, velocity: -3, wantAmount: 0, wantVelocity: 0, wantClipped: []string{"amount", "velocity"}},
+		{name: "not a number", amount: math.NaN(), velocity: 1, wantAmount: 0, wantVelocity: 0.05, wantClipped: []string{"amount"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &models.FraudCheckRequest{Amount: tt.amount}
+
+			features, clipped := ExtractFeatures(req, bounds, tt.velocity, false, false, false)
+
+			if features["amount"] != tt.wantAmount {
+				t.Errorf("amount = %v, want %v", features["amount"], tt.wantAmount)
+			}
+			if features["velocity"] != tt.wantVelocity {
+				t.Errorf("velocity = %v, want %v", features["velocity"], tt.wantVelocity)
+			}
+			if len(clipped) != len(tt.wantClipped) {
+				t.Fatalf("clipped = %v, want %v", clipped, tt.wantClipped)
+			}
+			for i := range clipped {
+				if clipped[i] != tt.wantClipped[i] {
+					t.Errorf("clipped = %v, want %v", clipped, tt.wantClipped)
+				}
+			}
+		})
+	}
+}
+
+func TestExtractFeaturesCustomBounds(t *testing.T) {
+	req := &models.FraudCheckRequest{Amount: 25000}
+
+	features, clipped := ExtractFeatures(req, FeatureBounds{MaxAmount: 50000, MaxVelocity: 40}, 30, false, false, false)
+
+	if features["amount"] != 0.5 || features["velocity"] != 0.75 || len(clipped) != 0 {
+		t.Errorf("features = %v, clipped = %v; want amount 0.5 and velocity 0.75 unclipped", features, clipped)
+	}
+}
+
+func TestFeatureBoundsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		bounds  FeatureBounds
+		wantErr bool
+	}{
+		{name: "default", bounds: DefaultFeatureBounds},
+		{name: "zero amount", bounds: FeatureBounds{MaxAmount: 0, MaxVelocity: 20}, wantErr: true},
+		{name: "negative amount", bounds: FeatureBounds{MaxAmount: -1, MaxVelocity: 20}, wantErr: true},
+		{name: "infinite amount", bounds: FeatureBounds{MaxAmount: math.Inf(1), MaxVelocity: 20}, wantErr: true},
+		{name: "zero velocity", bounds: FeatureBounds{MaxAmount: 10000, MaxVelocity: 0}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.bounds.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
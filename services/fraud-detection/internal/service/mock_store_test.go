@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"fraud-detection/internal/models"
+)
+
+// mockStore is an in-memory FraudStore for engine tests
+type mockStore struct {
+	recentCount   int
+	recentAmount  float64
+	locations     []string
+	blacklisted   bool
+	knownDevice   bool
+	saved         []*models.FraudCheckResult
+	velocityCalls int
+	listEntries   []*models.ListEntry
+	details       []*models.FraudCheckDetails
+	shadow        []*models.FraudCheckResult
+	locationLog   []*models.CustomerLocation
+
+	// velocityErrs fails that many velocity queries before they start succeeding
+	velocityErrs int
+}
+
+func (m *mockStore) SaveFraudCheck(ctx context.Context, result *models.FraudCheckResult) error {
+	m.saved = append(m.saved, result)
+	return nil
+}
+
+func (m *mockStore) CountRecentTransactions(ctx context.Context, customerEmail string, window time.Duration) (int, error) {
+	m.velocityCalls++
+	if m.velocityCalls <= m.velocityErrs {
+		return 0, errors.New("connection reset by peer")
+	}
+	return m.recentCount, nil
+}
+
+func (m *mockStore) SumRecentAmounts(ctx context.Context, customerEmail, transactionID string, window time.Duration) (float64, error) {
+	return m.recentAmount, nil
+}
+
+func (m *mockStore) GetRecentLocations(ctx context.Context, customerEmail string, window time.Duration) ([]string, error) {
+	return m.locations, nil
+}
+
+func (m *mockStore) GetLastLocation(ctx context.Context, customerEmail, transactionID string, before time.Time) (*models.CustomerLocation, error) {
+	var last *models.CustomerLocation
+	for _, location := range m.locationLog {
+		if location.CustomerEmail != customerEmail || location.TransactionID == transactionID || location.SeenAt.After(before) {
+			continue
+		}
+		if last == nil || location.SeenAt.After(last.SeenAt) {
+			last = location
+		}
+	}
+	return last, nil
+}
+
+func (m *mockStore) SaveLocation(ctx context.Context, location *models.CustomerLocation) error {
+	m.locationLog = append(m.locationLog, location)
+	return nil
+}
+
+func (m *mockStore) IsBlacklisted(ctx context.Context, customerEmail, cardLast4 string) (bool, error) {
+	return m.blacklisted, nil
+}
+
+func (m *mockStore) IsKnownDevice(ctx context.Context, customerEmail, deviceFingerprint string) (bool, error) {
+	return m.knownDevice, nil
+}
+
+func (m *mockStore) ListFraudChecks(ctx context.Context, filter models.FraudResultFilter) ([]*models.FraudCheckResult, error) {
+	results := []*models.FraudCheckResult{}
+	for _, result := range m.saved {
+		if filter.RiskLevel != "" && result.RiskLevel != string(filter.RiskLevel) {
+			continue
+		}
+		if filter.Decision != "" && result.Decision != string(filter.Decision) {
+			continue
+		}
+		if (!filter.From.IsZero() && result.CreatedAt.Before(filter.From)) || (!filter.To.IsZero() && !result.CreatedAt.Before(filter.To)) {
+			continue
+		}
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].CreatedAt.After(results[j].CreatedAt) })
+	if filter.Offset >= len(results) {
+		return []*models.FraudCheckResult{}, nil
+	}
+	results = results[filter.Offset:]
+	if len(results) > filter.Limit {
+		results = results[:filter.Limit]
+	}
+	return results, nil
+}
+
+func (m *mockStore) CountFraudChecks(ctx context.Context, filter models.FraudResultFilter) (int, error) {
+	filter.Limit, filter.Offset = len(m.saved), 0
+	results, err := m.ListFraudChecks(ctx, filter)
+	return len(results), err
+}
+
+func (m *mockStore) UpsertListEntry(ctx context.Context, entry *models.ListEntry) (bool, error) {
+	for _, existing := range m.listEntries {
+		if existing.List == entry.List && existing.Kind == entry.Kind && existing.Value == entry.Value {
+			existing.Reason = entry.Reason
+			existing.UpdatedAt = entry.UpdatedAt
+			entry.ID = existing.ID
+			entry.CreatedAt = existing.CreatedAt
+			return false, nil
+		}
+	}
+	stored := *entry
+	m.listEntries = append(m.listEntries, &stored)
+	return true, nil
+}
+
+func (m *mockStore) SaveCheckDetails(ctx context.Context, details *models.FraudCheckDetails) error {
+	m.details = append(m.details, details)
+	return nil
+}
+
+func (m *mockStore) GetCheckDetails(ctx context.Context, transactionID string) (*models.FraudCheckDetails, error) {
+	for i := len(m.details) - 1; i >= 0; i-- {
+		if m.details[i].TransactionID == transactionID {
+			return m.details[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) ListCheckRequests(ctx context.Context, start, end time.Time) ([]*models.FraudCheckRequest, error) {
+	latest := make(map[string]*models.FraudCheckDetails)
+	var order []string
+	for _, details := range m.details {
+		if details.Request == nil || details.CreatedAt.Before(start) || !details.CreatedAt.Before(end) {
+			continue
+		}
+		if _, ok := latest[details.TransactionID]; !ok {
+			order = append(order, details.TransactionID)
+		}
+		latest[details.TransactionID] = details
+	}
+
+	requests := []*models.FraudCheckRequest{}
+	for _, transactionID := range order {
+		requests = append(requests, latest[transactionID].Request)
+	}
+	return requests, nil
+}
+
+func (m *mockStore) SaveShadowFraudCheck(ctx context.Context, runID string, result *models.FraudCheckResult) error {
+	m.shadow = append(m.shadow, result)
+	return nil
+}
+
+// memoryCache is an in-memory DecisionCache
+type memoryCache struct {
+	data map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: make(map[string]string)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	value, ok := c.data[key]
+	if !ok {
+		return "", fmt.Errorf("key not found")
+	}
+	return value, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	switch v := value.(type) {
+	case []byte:
+		c.data[key] = string(v)
+	default:
+		c.data[key] = fmt.Sprint(v)
+	}
+	return nil
+}
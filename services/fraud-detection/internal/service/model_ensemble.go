@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"fraud-detection/internal/models"
+)
+
+var ErrInvalidEnsembleModel = errors.New("invalid ensemble model")
+
+// defaultModelName is the name given to a model served on its own
+const defaultModelName = "default"
+
+// ModelEnsemble combines the predictions of several models, each counting in
+// proportion to its weight
+type ModelEnsemble struct {
+	members []ensembleMember
+}
+
+type ensembleMember struct {
+	name   string
+	model  *MLModel
+	weight float64
+}
+
+// NewModelEnsemble creates an empty ensemble
+func NewModelEnsemble() *ModelEnsemble {
+	return &ModelEnsemble{}
+}
+
+// Add puts model in the ensemble under a unique name with a positive weight
+func (e *ModelEnsemble) Add(name string, model *MLModel, weight float64) error {
+	if model == nil {
+		return fmt.Errorf("%w: model %q is nil", ErrInvalidEnsembleModel, name)
+	}
+	if weight <= 0 || math.IsInf(weight, 0) || math.IsNaN(weight) {
+		return fmt.Errorf("%w: weight of %q must be a positive number, got %v", ErrInvalidEnsembleModel, name, weight)
+	}
+	for _, member := range e.members {
+		if member.name == name {
+			return fmt.Errorf("%w: duplicate model name %q", ErrInvalidEnsembleModel, name)
+		}
+	}
+
+	e.members = append(e.members, ensembleMember{name: name, model: model, weight: weight})
+	return nil
+}
+
+// Len returns the number of models in the ensemble
+func (e *ModelEnsemble) Len() int {
+	return len(e.members)
+}
+
+// Predict returns the weighted mean of the models' fraud probabilities, in [0, 100]
+func (e *ModelEnsemble) Predict(ctx context.Context, features map[string]float64) float64 {
+	var sum, total float64
+	for _, member := range e.members {
+		sum += member.weight * member.model.Predict(ctx, features)
+		total += member.weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return sum / total
+}
+
+// Contributions breaks the ensemble's prediction down by feature, using each
+// feature's weight averaged across the models by ensemble weight
+func (e *ModelEnsemble) Contributions(features map[string]float64) []models.ModelFactor {
+	var total float64
+	for _, member := range e.members {
+		total += member.weight
+	}
+	if total == 0 {
+		return nil
+	}
+
+	combined := make(map[string]float64)
+	for _, member := range e.members {
+		for feature, weight := range member.model.weights {
+			combined[feature] += member.weight / total * weight
+		}
+	}
+
+	factors := make([]models.ModelFactor, 0, len(features))
+	for feature, value := range features {
+		weight, exists := combined[feature]
+		if !exists {
+			continue
+		}
+		factors = append(factors, models.ModelFactor{
+			Feature:      feature,
+			Value:        value,
+			Weight:       weight,
+			Contribution: weight * value,
+		})
+	}
+	sortModelFactors(factors)
+	return factors
+}
+
+// Version identifies the ensemble: a lone model's version, otherwise every
+// model's name@version joined with "+"
+func (e *ModelEnsemble) Version() string {
+	if len(e.members) == 1 {
+		return e.members[0].model.Version()
+	}
+	versions := make([]string, len(e.members))
+	for i, member := range e.members {
+		versions[i] = member.name + "@" + member.model.Version()
+	}
+	return strings.Join(versions, "+")
+}
+
+// Models lists the models in the ensemble in the order they were added
+func (e *ModelEnsemble) Models() []models.ModelInfo {
+	infos := make([]models.ModelInfo, len(e.members))
+	for i, member := range e.members {
+		infos[i] = models.ModelInfo{
+			Name:    member.name,
+			Version: member.model.Version(),
+			Weight:  member.weight,
+			Trained: member.model.trained,
+		}
+	}
+	return infos
+}
+
+// LoadModelDir loads every *.json model in dir into an ensemble, named after its
+// file. A model's weight is its file's optional "ensemble_weight", defaulting to 1.
+func LoadModelDir(dir string) (*ModelEnsemble, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no model files in %s", dir)
+	}
+	sort.Strings(paths)
+
+	ensemble := NewModelEnsemble()
+	for _, path := range paths {
+		model, err := LoadModel(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		weight, err := readEnsembleWeight(path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if err := ensemble.Add(name, model, weight); err != nil {
+			return nil, err
+		}
+	}
+	return ensemble, nil
+}
+
+// readEnsembleWeight returns a model file's ensemble_weight, or 1 if it has none
+func readEnsembleWeight(path string) (float64, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var data struct {
+		EnsembleWeight *float64 `json:"ensemble_weight"`
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return 0, fmt.Errorf("failed to decode model: %w", err)
+	}
+	if data.EnsembleWeight == nil {
+		return 1, nil
+	}
+	return *data.EnsembleWeight, nil
+}
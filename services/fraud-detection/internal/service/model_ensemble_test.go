@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// constantModel predicts the same probability for every input
+func constantModel(version string, probability float64) *MLModel {
+	return &MLModel{
+		weights: map[string]float64{"amount": 0},
+		bias:    math.Log(probability / (1 - probability)),
+		trained: true,
+		version: version,
+	}
+}
+
+func TestModelEnsembleCombinesWeightedPredictions(t *testing.T) {
+	ensemble := NewModelEnsemble()
+	if err := ensemble.Add("cautious", constantModel("1.0.0", 0.5), 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := ensemble.Add("strict", constantModel("2.0.0", 0.75), 3); err != nil {
+		t.Fatal(err)
+	}
+
+	// (1×50 + 3×75) / 4
+	got := ensemble.Predict(context.Background(), map[string]float64{"amount": 1})
+	if math.Abs(got-68.75) > 1e-9 {
+		t.Errorf("Predict() = %v, want 68.75", got)
+	}
+	if version := ensemble.Version(); version != "cautious@1.0.0+strict@2.0.0" {
+		t.Errorf("Version() = %q, want cautious@1.0.0+strict@2.0.0", version)
+	}
+
+	infos := ensemble.Models()
+	if len(infos) != 2 || infos[0].Name != "cautious" || infos[1].Weight != 3 {
+		t.Errorf("Models() = %+v, want cautious then strict with weight 3", infos)
+	}
+}
+
+func TestModelEnsembleContributionsAverageWeights(t *testing.T) {
+	ensemble := NewModelEnsemble()
+	ensemble.Add("a", &MLModel{weights: map[string]float64{"amount": 0.4, "velocity": 0.2}}, 1)
+	ensemble.Add("b", &MLModel{weights: map[string]float64{"amount": 0.8, "velocity": 0}}, 1)
+
+	factors := ensemble.Contributions(map[string]float64{"amount": 0.5, "velocity": 1})
+
+	if len(factors) != 2 || factors[0].Feature != "amount" {
+		t.Fatalf("Contributions() = %+v, want amount first", factors)
+	}
+	if math.Abs(factors[0].Weight-0.6) > 1e-9 || math.Abs(factors[0].Contribution-0.3) > 1e-9 {
+		t.Errorf("amount factor = %+v, want weight 0.6 contributing 0.3", factors[0])
+	}
+	if math.Abs(factors[1].Contribution-0.1) > 1e-9 {
+		t.Errorf("velocity factor = %+v, want contribution 0.1", factors[1])
+	}
+}
+
+func TestModelEnsembleAddRejectsInvalidModels(t *testing.T) {
+	tests := []struct {
+		name   string
+		model  *MLModel
+		weight float64
+	}{
+		{name: "zero weight", model: LoadPretrainedModel(), weight: 0},
+		{name: "negative weight", model: LoadPretrainedModel(), weight: -1},
+		{name: "nil model", model: nil, weight: 1},
+		{name: "duplicate", model: LoadPretrainedModel(), weight: 1},
+	}
+
+	ensemble := NewModelEnsemble()
+	ensemble.Add("duplicate", LoadPretrainedModel(), 1)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ensemble.Add(tt.name, tt.model, tt.weight); !errors.Is(err, ErrInvalidEnsembleModel) {
+				t.Errorf("Add() error = %v, want ErrInvalidEnsembleModel", err)
+			}
+		})
+	}
+}
+
+func TestLoadModelDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := LoadPretrainedModel().SaveModel(filepath.Join(dir, "baseline.json")); err != nil {
+		t.Fatal(err)
+	}
+	weighted := `{"weights": {"amount": 1}, "bias": 0, "trained": true, "version": "2.0.0", "ensemble_weight": 2.5}`
+	if err := os.WriteFile(filepath.Join(dir, "challenger.json"), []byte(weighted), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ensemble, err := LoadModelDir(dir)
+	if err != nil {
+		t.Fatalf("LoadModelDir() error = %v", err)
+	}
+
+	infos := ensemble.Models()
+	if len(infos) != 2 {
+		t.Fatalf("loaded %d models, want 2", len(infos))
+	}
+	if infos[0].Name != "baseline" || infos[0].Weight != 1 || infos[0].Version != "1.0.0" {
+		t.Errorf("first model = %+v, want baseline 1.0.0 with weight 1", infos[0])
+	}
+	if infos[1].Name != "challenger" || infos[1].Weight != 2.5 || infos[1].Version != "2.0.0" {
+		t.Errorf("second model = %+v, want challenger 2.0.0 with weight 2.5", infos[1])
+	}
+
+	if _, err := LoadModelDir(t.TempDir()); err == nil {
+		t.Error("LoadModelDir() of an empty directory succeeded, want an error")
+	}
+}
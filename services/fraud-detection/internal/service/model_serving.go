@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"fraud-detection/internal/models"
+)
+
+var (
+	modelVersionInfo = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "fraud_model_info",
+		Help: "Always 1, labelled with the name and version of each fraud model being served.",
+	}, []string{"model", "version"})
+
+	modelPredictions = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "fraud_model_prediction_probability",
+		Help:    "Fraud probability, from 0 to 1, predicted by the model ensemble for each check.",
+		Buckets: prometheus.LinearBuckets(0.1, 0.1, 10),
+	})
+
+	fraudDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_decisions_total",
+		Help: "Fraud checks evaluated, by decision. Replays from the decision cache are not counted.",
+	}, []string{"decision"})
+
+	modelFeaturesClipped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "fraud_model_features_clipped_total",
+		Help: "Model inputs that fell outside their normalization bounds and were clipped, by feature.",
+	}, []string{"feature"})
+)
+
+// Velocity counts the model is given for the velocity rule's flags; the rule's
+// thresholds are the lowest counts that raise them
+const (
+	modelHighVelocityCount     = 11
+	modelModerateVelocityCount = 6
+)
+
+// SetModel serves model on its own alongside the rules
+func (s *FraudEngine) SetModel(model *MLModel) {
+	ensemble := NewModelEnsemble()
+	ensemble.Add(defaultModelName, model, 1)
+	s.SetEnsemble(ensemble)
+}
+
+// SetEnsemble serves the ensemble alongside the rules. It scores every check and
+// exports its predictions, but decisions are still made by the rules.
+func (s *FraudEngine) SetEnsemble(ensemble *ModelEnsemble) {
+	s.ensemble = ensemble
+	modelVersionInfo.Reset()
+	for _, info := range ensemble.Models() {
+		modelVersionInfo.WithLabelValues(info.Name, info.Version).Set(1)
+	}
+}
+
+// ListModels returns the models being served, empty if there are none
+func (s *FraudEngine) ListModels() []models.ModelInfo {
+	if s.ensemble == nil {
+		return []models.ModelInfo{}
+	}
+	return s.ensemble.Models()
+}
+
+// SetFeatureBounds changes the raw values the model's amount and velocity inputs
+// are scaled by. They should match the bounds the model was trained with.
+func (s *FraudEngine) SetFeatureBounds(bounds FeatureBounds) error {
+	if err := bounds.Validate(); err != nil {
+		return err
+	}
+	s.featureBounds = bounds
+	return nil
+}
+
+// scoreWithModel records the ensemble's prediction and the decision for a freshly
+// evaluated check, and returns how each feature contributed to the prediction
+func (s *FraudEngine) scoreWithModel(ctx context.Context, req *models.FraudCheckRequest, resp *models.FraudCheckResponse) []models.ModelFactor {
+	if s.ensemble == nil || s.ensemble.Len() == 0 {
+		return nil
+	}
+
+	features, clipped := modelFeatures(req, resp, s.featureBounds)
+	for _, feature := range clipped {
+		modelFeaturesClipped.WithLabelValues(feature).Inc()
+	}
+	resp.ModelVersion = s.ensemble.Version()
+	modelPredictions.Observe(s.ensemble.Predict(ctx, features) / 100)
+	fraudDecisions.WithLabelValues(string(resp.Decision)).Inc()
+	return s.ensemble.Contributions(features)
+}
+
+// modelFeatures builds the model's inputs from what the rules found, along with
+// the features that were clipped to their bounds
+func modelFeatures(req *models.FraudCheckRequest, resp *models.FraudCheckResponse, bounds FeatureBounds) (map[string]float64, []string) {
+	flags := make(map[string]bool, len(resp.Flags))
+	for _, flag := range resp.Flags {
+		flags[flag] = true
+	}
+
+	velocityCount := 0
+	switch {
+	case flags["high_velocity"]:
+		velocityCount = modelHighVelocityCount
+	case flags["moderate_velocity"]:
+		velocityCount = modelModerateVelocityCount
+	}
+
+	return ExtractFeatures(req, bounds, velocityCount, flags["new_location"], flags["unusual_hour"], flags["new_device"])
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestAnalyzeTransactionReportsModelVersion(t *testing.T) {
+	ctx := context.Background()
+	engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+	engine.SetModel(LoadPretrainedModel())
+
+	approvals := testutil.ToFloat64(fraudDecisions.WithLabelValues(string(models.DecisionApprove)))
+
+	response, err := engine.AnalyzeTransaction(ctx, newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if response.ModelVersion != "1.0.0" {
+		t.Errorf("ModelVersion = %q, want 1.0.0", response.ModelVersion)
+	}
+	if got := testutil.ToFloat64(fraudDecisions.WithLabelValues(string(models.DecisionApprove))); got != approvals+1 {
+		t.Errorf("approve decisions = %v, want %v", got, approvals+1)
+	}
+	if got := testutil.ToFloat64(modelVersionInfo.WithLabelValues("default", "1.0.0")); got != 1 {
+		t.Errorf("fraud_model_info{model=default,version=1.0.0} = %v, want 1", got)
+	}
+
+	// A replayed decision keeps the version that scored it
+	cached, err := engine.AnalyzeTransaction(ctx, newTestRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cached.Cached || cached.ModelVersion != "1.0.0" {
+		t.Errorf("cached response = %+v, want the cached check with model version 1.0.0", cached)
+	}
+}
+
+func TestModelFeaturesFromRuleFlags(t *testing.T) {
+	req := newTestRequest()
+	req.Amount = 5000
+	resp := &models.FraudCheckResponse{Flags: []string{"high_velocity", "new_device"}}
+
+	features, clipped := modelFeatures(req, resp, DefaultFeatureBounds)
+
+	want := map[string]float64{"amount": 0.5, "velocity": 0.55, "new_location": 0, "unusual_hour": 0, "new_device": 1}
+	for name, value := range want {
+		if features[name] != value {
+			t.Errorf("feature %s = %v, want %v", name, features[name], value)
+		}
+	}
+	if len(clipped) != 0 {
+		t.Errorf("clipped = %v, want none", clipped)
+	}
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+	"shared/pkg/ids"
+)
+
+var (
+	ErrReprocessNotConfigured = errors.New("reprocessing is not configured")
+	ErrInvalidReprocessRange  = errors.New("reprocess end must be after start")
+	ErrReprocessJobNotFound   = errors.New("reprocess job not found")
+)
+
+// ReprocessStore is the persistence reprocessing needs beyond FraudStore;
+// implemented by repository.FraudRepository
+type ReprocessStore interface {
+	ListCheckRequests(ctx context.Context, start, end time.Time) ([]*models.FraudCheckRequest, error)
+	SaveShadowFraudCheck(ctx context.Context, runID string, result *models.FraudCheckResult) error
+}
+
+// reprocessJobs tracks reprocessing jobs so their progress can be polled
+type reprocessJobs struct {
+	mu   sync.Mutex
+	jobs map[string]*models.ReprocessJob
+}
+
+// SetReprocessStore enables re-scoring historical checks into the shadow results table
+func (s *FraudEngine) SetReprocessStore(store ReprocessStore) {
+	s.reprocessStore = store
+}
+
+// StartReprocess re-scores the range in the background, returning the job to poll for progress
+func (s *FraudEngine) StartReprocess(start, end time.Time) (*models.ReprocessJob, error) {
+	job, err := s.newReprocessJob(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	go s.reprocess(context.Background(), job.ID)
+
+	return job, nil
+}
+
+// ReprocessRange re-runs AnalyzeTransaction over the transactions checked in [start, end)
+// with the current rules and model, writing results to the shadow table only. Nothing
+// production reads is saved, cached or alerted on.
+func (s *FraudEngine) ReprocessRange(ctx context.Context, start, end time.Time) (*models.ReprocessJob, error) {
+	job, err := s.newReprocessJob(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	s.reprocess(ctx, job.ID)
+
+	return s.GetReprocessJob(job.ID)
+}
+
+// GetReprocessJob returns a snapshot of a job's progress
+func (s *FraudEngine) GetReprocessJob(id string) (*models.ReprocessJob, error) {
+	s.reprocessJobs.mu.Lock()
+	defer s.reprocessJobs.mu.Unlock()
+
+	job, ok := s.reprocessJobs.jobs[id]
+	if !ok {
+		return nil, ErrReprocessJobNotFound
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (s *FraudEngine) newReprocessJob(start, end time.Time) (*models.ReprocessJob, error) {
+	if s.reprocessStore == nil {
+		return nil, ErrReprocessNotConfigured
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidReprocessRange
+	}
+
+	job := &models.ReprocessJob{
+		ID:        ids.New(),
+		Status:    models.ReprocessStatusRunning,
+		Start:     start,
+		End:       end,
+		StartedAt: time.Now(),
+	}
+
+	s.reprocessJobs.mu.Lock()
+	defer s.reprocessJobs.mu.Unlock()
+	if s.reprocessJobs.jobs == nil {
+		s.reprocessJobs.jobs = make(map[string]*models.ReprocessJob)
+	}
+	s.reprocessJobs.jobs[job.ID] = job
+
+	copied := *job
+	return &copied, nil
+}
+
+// updateReprocessJob applies update to a job under the lock
+func (s *FraudEngine) updateReprocessJob(id string, update func(job *models.ReprocessJob)) {
+	s.reprocessJobs.mu.Lock()
+	defer s.reprocessJobs.mu.Unlock()
+	update(s.reprocessJobs.jobs[id])
+}
+
+func (s *FraudEngine) reprocess(ctx context.Context, jobID string) {
+	job, _ := s.GetReprocessJob(jobID)
+
+	requests, err := s.reprocessStore.ListCheckRequests(ctx, job.Start, job.End)
+	if err != nil {
+		s.logger.Error("failed to list checks to reprocess", zap.String("job_id", jobID), zap.Error(err))
+		s.updateReprocessJob(jobID, func(job *models.ReprocessJob) {
+			finishedAt := time.Now()
+			job.Status = models.ReprocessStatusFailed
+			job.Error = fmt.Sprintf("failed to list checks: %v", err)
+			job.FinishedAt = &finishedAt
+		})
+		return
+	}
+	s.updateReprocessJob(jobID, func(job *models.ReprocessJob) { job.Total = len(requests) })
+
+	store := &shadowStore{FraudStore: s.repo}
+	shadow := s.shadowEngine(store)
+	for _, req := range requests {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// The shadow engine hands back the result instead of saving it, so a failed
+		// shadow write is counted rather than only logged
+		store.result = nil
+		_, err := shadow.AnalyzeTransaction(ctx, req)
+		if err == nil && store.result != nil {
+			err = s.reprocessStore.SaveShadowFraudCheck(ctx, jobID, store.result)
+		}
+		if err != nil {
+			s.logger.Warn("failed to reprocess transaction",
+				zap.String("job_id", jobID),
+				zap.String("transaction_id", req.TransactionID),
+				zap.Error(err))
+		}
+		s.updateReprocessJob(jobID, func(job *models.ReprocessJob) {
+			job.Processed++
+			if err != nil {
+				job.Failed++
+			}
+		})
+	}
+
+	s.updateReprocessJob(jobID, func(job *models.ReprocessJob) {
+		finishedAt := time.Now()
+		job.Status = models.ReprocessStatusCompleted
+		if ctx.Err() != nil {
+			job.Status = models.ReprocessStatusFailed
+			job.Error = ctx.Err().Error()
+		}
+		job.FinishedAt = &finishedAt
+	})
+
+	s.logger.Info("fraud reprocessing finished",
+		zap.String("job_id", jobID),
+		zap.Int("transactions", len(requests)))
+}
+
+// shadowEngine scores with this engine's rules and model against store, with no
+// cache or alerter so production decisions are untouched
+func (s *FraudEngine) shadowEngine(store *shadowStore) *FraudEngine {
+	return &FraudEngine{
+		repo:          store,
+		ensemble:      s.ensemble,
+		featureBounds: s.featureBounds,
+		ruleBackoff:   s.ruleBackoff,
+		merchantModes: s.merchantModes,
+		disabledRules: s.disabledRules,
+		logger:        s.logger,
+
+		ruleProfiles:     s.ruleProfiles,
+		merchantProfiles: s.merchantProfiles,
+
+		velocityThresholds: s.velocityThresholds,
+	}
+}
+
+// shadowStore reads customer history from the production store but keeps the
+// result for the caller and drops everything else the engine saves
+type shadowStore struct {
+	FraudStore
+	result *models.FraudCheckResult
+}
+
+func (s *shadowStore) SaveFraudCheck(ctx context.Context, result *models.FraudCheckResult) error {
+	s.result = result
+	return nil
+}
+
+func (s *shadowStore) SaveCheckDetails(ctx context.Context, details *models.FraudCheckDetails) error {
+	return nil
+}
+
+func (s *shadowStore) SaveLocation(ctx context.Context, location *models.CustomerLocation) error {
+	return nil
+}
+
+func (s *shadowStore) UpsertListEntry(ctx context.Context, entry *models.ListEntry) (bool, error) {
+	return false, errors.New("shadow store is read-only")
+}
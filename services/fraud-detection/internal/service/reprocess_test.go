@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestReprocessRangeWritesShadowResults(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{}
+	cache := newMemoryCache()
+	engine := NewFraudEngine(store, cache, zap.NewNop())
+	engine.SetReprocessStore(store)
+
+	// Seed production checks, all approved under a clean history
+	start := time.Now()
+	for _, id := range []string{"txn_1", "txn_2", "txn_3"} {
+		req := newTestRequest()
+		req.TransactionID = id
+		if _, err := engine.AnalyzeTransaction(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	end := time.Now().Add(time.Second)
+	cached := len(cache.data)
+
+	// The customer has since been blacklisted, so a re-score blocks every transaction
+	store.blacklisted = true
+	job, err := engine.ReprocessRange(ctx, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if job.Status != models.ReprocessStatusCompleted || job.Total != 3 || job.Processed != 3 || job.Failed != 0 {
+		t.Errorf("job = %+v, want completed with 3 of 3 processed", job)
+	}
+	if len(store.shadow) != 3 {
+		t.Fatalf("wrote %d shadow results, want 3", len(store.shadow))
+	}
+	for _, result := range store.shadow {
+		if result.Decision != string(models.DecisionBlock) {
+			t.Errorf("shadow %s decision = %s, want block", result.TransactionID, result.Decision)
+		}
+	}
+
+	// Production results, details and cached decisions are untouched
+	if len(store.saved) != 3 || len(store.details) != 3 {
+		t.Errorf("production has %d results and %d details, want 3 of each", len(store.saved), len(store.details))
+	}
+	for _, result := range store.saved {
+		if result.Decision != string(models.DecisionApprove) {
+			t.Errorf("production %s decision = %s, want approve", result.TransactionID, result.Decision)
+		}
+	}
+	if len(cache.data) != cached {
+		t.Errorf("cache has %d decisions, want %d", len(cache.data), cached)
+	}
+
+	polled, err := engine.GetReprocessJob(job.ID)
+	if err != nil || polled.Processed != 3 {
+		t.Errorf("GetReprocessJob() = %+v, %v, want 3 processed", polled, err)
+	}
+}
+
+func TestReprocessRangeValidation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name    string
+		store   ReprocessStore
+		start   time.Time
+		end     time.Time
+		wantErr error
+	}{
+		{
+			name:    "Not configured",
+			start:   now.Add(-time.Hour),
+			end:     now,
+			wantErr: ErrReprocessNotConfigured,
+		},
+		{
+			name:    "End before start",
+			store:   &mockStore{},
+			start:   now,
+			end:     now.Add(-time.Hour),
+			wantErr: ErrInvalidReprocessRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+			if tt.store != nil {
+				engine.SetReprocessStore(tt.store)
+			}
+
+			_, err := engine.ReprocessRange(context.Background(), tt.start, tt.end)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReprocessRange() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGetReprocessJobNotFound(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, nil, zap.NewNop())
+	if _, err := engine.GetReprocessJob("missing"); !errors.Is(err, ErrReprocessJobNotFound) {
+		t.Errorf("err = %v, want ErrReprocessJobNotFound", err)
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FraudConfig selects which fraud rules run for each merchant, so high-risk
+// merchant categories can run every rule and low-risk ones a subset
+type FraudConfig struct {
+	// RuleProfiles names sets of rules, e.g. {"low_risk": ["velocity_check", "blacklist_check"]}
+	RuleProfiles map[string][]string `json:"rule_profiles"`
+	// MerchantProfiles assigns merchants to a profile; unlisted merchants run every rule
+	MerchantProfiles map[string]string `json:"merchant_profiles"`
+}
+
+// SetFraudConfig replaces the rule profiles and merchant assignments. Unknown
+// rule names and merchants assigned to an undefined profile are rejected.
+func (s *FraudEngine) SetFraudConfig(cfg FraudConfig) error {
+	known := s.knownRules()
+
+	profiles := make(map[string]map[string]bool, len(cfg.RuleProfiles))
+	for name, rules := range cfg.RuleProfiles {
+		profile := make(map[string]bool, len(rules))
+		for _, rule := range rules {
+			rule = strings.TrimSpace(rule)
+			if !known[rule] {
+				return fmt.Errorf("rule profile %q names unknown fraud rule %q", name, rule)
+			}
+			profile[rule] = true
+		}
+		profiles[name] = profile
+	}
+
+	for merchantID, name := range cfg.MerchantProfiles {
+		if _, ok := profiles[name]; !ok {
+			return fmt.Errorf("merchant %s is assigned undefined rule profile %q", merchantID, name)
+		}
+	}
+
+	s.ruleProfiles = profiles
+	s.merchantProfiles = cfg.MerchantProfiles
+	return nil
+}
+
+// ruleProfile returns the name and rules of a merchant's profile; a nil rule
+// set means the merchant runs every rule
+func (s *FraudEngine) ruleProfile(merchantID string) (string, map[string]bool) {
+	name, ok := s.merchantProfiles[merchantID]
+	if !ok || merchantID == "" {
+		return "", nil
+	}
+	return name, s.ruleProfiles[name]
+}
+
+// knownRules returns the set of rule names the engine can run
+func (s *FraudEngine) knownRules() map[string]bool {
+	known := make(map[string]bool)
+	for _, rule := range s.rules() {
+		known[rule.name] = true
+	}
+	return known
+}
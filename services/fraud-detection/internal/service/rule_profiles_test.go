@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"fraud-detection/internal/models"
+)
+
+func TestAnalyzeTransactionRunsOnlyProfileRules(t *testing.T) {
+	ctx := context.Background()
+	// A blacklisted customer with a busy hour would be blocked if every rule ran
+	store := &mockStore{blacklisted: true, recentCount: 15}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	if err := engine.SetFraudConfig(FraudConfig{
+		RuleProfiles:     map[string][]string{"minimal": {"amount_threshold"}},
+		MerchantProfiles: map[string]string{"merchant_1": "minimal"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest()
+	req.MerchantID = "merchant_1"
+	response, err := engine.AnalyzeTransaction(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.RuleProfile != "minimal" {
+		t.Errorf("rule profile = %q, want minimal", response.RuleProfile)
+	}
+	var ran []string
+	for _, rule := range response.Rules {
+		if !rule.Skipped {
+			ran = append(ran, rule.RuleName)
+		}
+	}
+	if len(ran) != 1 || ran[0] != "amount_threshold" {
+		t.Errorf("ran rules %v, want only amount_threshold", ran)
+	}
+	if len(response.Rules) != len(engine.rules()) {
+		t.Errorf("reported %d rules, want every rule listed", len(response.Rules))
+	}
+	if store.velocityCalls != 0 {
+		t.Errorf("velocity queried %d times, want 0", store.velocityCalls)
+	}
+	if response.Decision != models.DecisionApprove || response.Score != 0 {
+		t.Errorf("decision = %s with score %d, want approve with score 0", response.Decision, response.Score)
+	}
+}
+
+func TestAnalyzeTransactionWithoutProfileRunsEveryRule(t *testing.T) {
+	ctx := context.Background()
+	store := &mockStore{blacklisted: true}
+	engine := NewFraudEngine(store, newMemoryCache(), zap.NewNop())
+	if err := engine.SetFraudConfig(FraudConfig{
+		RuleProfiles:     map[string][]string{"minimal": {"amount_threshold"}},
+		MerchantProfiles: map[string]string{"merchant_1": "minimal"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest()
+	req.MerchantID = "merchant_2"
+	response, err := engine.AnalyzeTransaction(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, rule := range response.Rules {
+		if rule.Skipped {
+			t.Errorf("rule %s skipped for a merchant without a profile", rule.RuleName)
+		}
+	}
+	if response.RuleProfile != "" || response.Decision != models.DecisionBlock {
+		t.Errorf("profile %q, decision %s; want no profile and block", response.RuleProfile, response.Decision)
+	}
+}
+
+func TestSetFraudConfigRejectsInvalidProfiles(t *testing.T) {
+	engine := NewFraudEngine(&mockStore{}, newMemoryCache(), zap.NewNop())
+
+	if err := engine.SetFraudConfig(FraudConfig{
+		RuleProfiles: map[string][]string{"minimal": {"coin_flip"}},
+	}); err == nil {
+		t.Error("SetFraudConfig() accepted an unknown rule")
+	}
+	if err := engine.SetFraudConfig(FraudConfig{
+		RuleProfiles:     map[string][]string{"minimal": {"amount_threshold"}},
+		MerchantProfiles: map[string]string{"merchant_1": "strict"},
+	}); err == nil {
+		t.Error("SetFraudConfig() accepted a merchant on an undefined profile")
+	}
+}
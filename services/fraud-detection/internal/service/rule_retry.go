@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	ruleQueryMaxAttempts  = 3
+	ruleQueryRetryBackoff = 25 * time.Millisecond
+)
+
+// retryRuleQuery runs a rule's repository query, retrying failures with a doubling
+// backoff so a transient database error doesn't leave the rule unevaluated
+func retryRuleQuery[T any](ctx context.Context, backoff time.Duration, query func() (T, error)) (T, error) {
+	var result T
+	var err error
+	for attempt := 1; attempt <= ruleQueryMaxAttempts; attempt++ {
+		result, err = query()
+		if err == nil || attempt == ruleQueryMaxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-time.After(backoff << (attempt - 1)):
+		}
+	}
+	return result, err
+}
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"math"
+)
+
+// VelocityThresholds are how many transactions, and how much summed spend, a
+// customer can have in the velocity window before it adds to the fraud score.
+// Counts and amounts are flagged separately, so many small purchases and a few
+// large ones are told apart.
+type VelocityThresholds struct {
+	ModerateCount  int     `json:"moderate_count"`
+	HighCount      int     `json:"high_count"`
+	ModerateAmount float64 `json:"moderate_amount"`
+	HighAmount     float64 `json:"high_amount"`
+}
+
+// DefaultVelocityThresholds flag more than 5 or 10 transactions, or more than
+// 5,000 or 10,000 spent, in an hour
+var DefaultVelocityThresholds = VelocityThresholds{
+	ModerateCount:  5,
+	HighCount:      10,
+	ModerateAmount: 5000,
+	HighAmount:     10000,
+}
+
+// Validate reports whether every threshold is positive and each moderate
+// threshold is below its high one
+func (t VelocityThresholds) Validate() error {
+	if t.ModerateCount <= 0 || t.HighCount <= t.ModerateCount {
+		return fmt.Errorf("velocity counts must satisfy 0 < moderate < high, got %d and %d", t.ModerateCount, t.HighCount)
+	}
+	// Written as negations so NaN amounts fail too
+	if !(t.ModerateAmount > 0) || !(t.HighAmount > t.ModerateAmount) || math.IsInf(t.HighAmount, 0) {
+		return fmt.Errorf("velocity amounts must satisfy 0 < moderate < high, got %v and %v", t.ModerateAmount, t.HighAmount)
+	}
+	return nil
+}
+
+// SetVelocityThresholds changes when transaction count and summed spend are flagged
+func (s *FraudEngine) SetVelocityThresholds(thresholds VelocityThresholds) error {
+	if err := thresholds.Validate(); err != nil {
+		return err
+	}
+	s.velocityThresholds = thresholds
+	return nil
+}
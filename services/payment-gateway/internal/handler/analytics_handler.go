@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"payment-gateway/internal/models"
+	"payment-gateway/internal/service"
+)
+
+// GetPaymentAnalytics handles GET /api/v1/payments/analytics?from=YYYY-MM-DD&to=YYYY-MM-DD&group_by=day|currency|status.
+// to is inclusive.
+func (h *PaymentHandler) GetPaymentAnalytics(c *gin.Context) {
+	from, err := time.Parse("2006-01-02", c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be YYYY-MM-DD"})
+		return
+	}
+	to, err := time.Parse("2006-01-02", c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be YYYY-MM-DD"})
+		return
+	}
+
+	analytics, err := h.service.PaymentAnalytics(c.Request.Context(), models.PaymentAnalyticsFilter{
+		From:    from,
+		To:      to.AddDate(0, 0, 1),
+		GroupBy: c.Query("group_by"),
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAnalyticsGroupBy) || errors.Is(err, service.ErrInvalidAnalyticsRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get payment analytics", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payment analytics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
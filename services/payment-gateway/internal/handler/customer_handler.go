@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"payment-gateway/internal/models"
+	"payment-gateway/internal/service"
+)
+
+// CreateCustomer handles POST /api/v1/customers
+func (h *PaymentHandler) CreateCustomer(c *gin.Context) {
+	var req models.CustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	customer, err := h.service.CreateCustomer(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("failed to create customer", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create customer"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"customer": customer})
+}
+
+// GetCustomer handles GET /api/v1/customers/:id
+func (h *PaymentHandler) GetCustomer(c *gin.Context) {
+	customer, err := h.service.GetCustomer(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+			return
+		}
+		h.logger.Error("failed to get customer", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get customer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"customer": customer})
+}
+
+// AttachPaymentMethod handles POST /api/v1/customers/:id/payment-methods
+func (h *PaymentHandler) AttachPaymentMethod(c *gin.Context) {
+	var req models.AttachPaymentMethodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	method, err := h.service.AttachPaymentMethod(c.Request.Context(), c.Param("id"), req.PaymentMethodID)
+	if err != nil {
+		if errors.Is(err, service.ErrCustomerNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Customer not found"})
+			return
+		}
+		h.logger.Error("failed to attach payment method", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to attach payment method"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"payment_method": method})
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"payment-gateway/internal/models"
+)
+
+// CreateMerchantWebhook handles POST /api/v1/webhooks/endpoints
+func (h *PaymentHandler) CreateMerchantWebhook(c *gin.Context) {
+	var req models.MerchantWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	webhook, err := h.service.RegisterMerchantWebhook(c.Request.Context(), &req)
+	if err != nil {
+		h.logger.Error("failed to register merchant webhook", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register webhook"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhookDeliveries handles GET /api/v1/webhooks/deliveries
+func (h *PaymentHandler) ListWebhookDeliveries(c *gin.Context) {
+	filter := models.WebhookDeliveryFilter{
+		WebhookID: c.Query("webhook_id"),
+		PaymentID: c.Query("payment_id"),
+		Limit:     20,
+	}
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		filter.Limit = parsed
+	}
+
+	deliveries, err := h.service.ListWebhookDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		h.logger.Error("failed to list webhook deliveries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list webhook deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries})
+}
@@ -3,13 +3,20 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 
 	"payment-gateway/internal/models"
 	"payment-gateway/internal/service"
+	"shared/pkg/money"
 )
 
 type PaymentHandler struct {
@@ -31,23 +38,53 @@ func (h *PaymentHandler) CreatePayment(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := req.Validate(); err != nil {
+		writeFieldError(c, err)
+		return
+	}
+
+	if req.DryRun || c.Query("dry_run") == "true" {
+		result, err := h.service.DryRunPayment(c.Request.Context(), &req)
+		if err != nil {
+			h.logger.Error("failed to dry run payment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to validate payment"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "result": result})
+		return
+	}
 
 	payment, err := h.service.CreatePayment(c.Request.Context(), &req)
 	if err != nil {
+		var blocked *service.FraudBlockedError
+		if errors.As(err, &blocked) {
+			c.JSON(http.StatusPaymentRequired, gin.H{
+				"error":      err.Error(),
+				"code":       "fraud_blocked",
+				"reasons":    blocked.Reasons,
+				"payment_id": blocked.PaymentID,
+			})
+			return
+		}
+		if errors.Is(err, service.ErrInvalidCardNumber) || errors.Is(err, service.ErrUnsupportedCardNetwork) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrCustomerLimitExceeded) || errors.Is(err, service.ErrAmountAboveMaximum) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrCustomerNotFound) || errors.Is(err, service.ErrPaymentMethodNotFound) ||
+			errors.Is(err, service.ErrAsyncRequiresPaymentMethod) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("failed to create payment", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process payment"})
 		return
 	}
 
-	response := models.PaymentResponse{
-		Payment: payment,
-	}
-
-	if payment.Requires3DS {
-		response.NextAction = "complete_3ds_authentication"
-	}
-
-	c.JSON(http.StatusCreated, response)
+	c.JSON(http.StatusCreated, newPaymentResponse(payment))
 }
 
 // GetPayment handles GET /api/v1/payments/:id
@@ -63,12 +100,33 @@ func (h *PaymentHandler) GetPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payment": payment})
 }
 
+// GetPaymentStatus handles GET /api/v1/payments/:id/status, for clients polling
+// an async payment until it leaves processing
+func (h *PaymentHandler) GetPaymentStatus(c *gin.Context) {
+	status, err := h.service.GetPaymentStatus(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		h.logger.Error("failed to get payment status", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payment status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
 // ConfirmPayment handles POST /api/v1/payments/:id/confirm
 func (h *PaymentHandler) ConfirmPayment(c *gin.Context) {
 	paymentID := c.Param("id")
 
 	payment, err := h.service.ConfirmPayment(c.Request.Context(), paymentID)
 	if err != nil {
+		if errors.Is(err, service.ErrPaymentUnderReview) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		h.logger.Error("failed to confirm payment", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to confirm payment"})
 		return
@@ -77,6 +135,148 @@ func (h *PaymentHandler) ConfirmPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"payment": payment})
 }
 
+// CapturePayment handles POST /api/v1/payments/:id/capture
+func (h *PaymentHandler) CapturePayment(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	var req models.CaptureRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	payment, err := h.service.CapturePayment(c.Request.Context(), paymentID, req.Amount)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case errors.Is(err, service.ErrInvalidCaptureAmount):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPaymentNotCapturable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to capture payment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to capture payment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"payment":         payment,
+		"released_amount": payment.ReleasedAmount(),
+	})
+}
+
+// RefundPayment handles POST /api/v1/payments/:id/refund
+func (h *PaymentHandler) RefundPayment(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	var req models.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	refund, err := h.service.RefundPayment(c.Request.Context(), paymentID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case errors.Is(err, service.ErrInvalidRefundReason), errors.Is(err, service.ErrInvalidRefundAmount):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPaymentNotRefundable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrRefundFailed):
+			c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to refund payment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refund payment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"refund": refund})
+}
+
+// ThreeDSReturn handles GET /api/v1/payments/:id/3ds/return
+func (h *PaymentHandler) ThreeDSReturn(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	payment, err := h.service.CompleteThreeDS(c.Request.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		h.logger.Error("failed to complete 3DS authentication", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete 3DS authentication"})
+		return
+	}
+
+	// The customer's browser lands here, so send it on to the merchant's page
+	if payment.ReturnURL != "" {
+		returnURL, err := merchantReturnURL(payment)
+		if err == nil {
+			c.Redirect(http.StatusSeeOther, returnURL)
+			return
+		}
+		h.logger.Warn("invalid merchant return URL", zap.String("payment_id", payment.ID), zap.Error(err))
+	}
+
+	c.JSON(http.StatusOK, newPaymentResponse(payment))
+}
+
+// merchantReturnURL is the payment's return URL with its ID and status added to
+// the query, so the merchant's page can show the outcome
+func merchantReturnURL(payment *models.Payment) (string, error) {
+	returnURL, err := url.Parse(payment.ReturnURL)
+	if err != nil {
+		return "", err
+	}
+
+	query := returnURL.Query()
+	query.Set("payment_id", payment.ID)
+	query.Set("status", string(payment.Status))
+	returnURL.RawQuery = query.Encode()
+	return returnURL.String(), nil
+}
+
+// GetTimeline handles GET /api/v1/payments/:id/timeline
+func (h *PaymentHandler) GetTimeline(c *gin.Context) {
+	paymentID := c.Param("id")
+
+	events, err := h.service.GetTimeline(c.Request.Context(), paymentID)
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		h.logger.Error("failed to get payment timeline", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payment timeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment_id": paymentID, "events": events})
+}
+
+// SyncWithStripe handles POST /api/v1/payments/:id/sync
+func (h *PaymentHandler) SyncWithStripe(c *gin.Context) {
+	result, err := h.service.SyncWithStripe(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		h.logger.Error("failed to sync payment with stripe", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sync payment with Stripe"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sync": result})
+}
+
 // CancelPayment handles POST /api/v1/payments/:id/cancel
 func (h *PaymentHandler) CancelPayment(c *gin.Context) {
 	paymentID := c.Param("id")
@@ -90,15 +290,195 @@ func (h *PaymentHandler) CancelPayment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Payment cancelled successfully"})
 }
 
-// ListPayments handles GET /api/v1/payments
+// ListPayments handles GET /api/v1/payments. With ?idempotency_key= it returns
+// the single payment created with that key.
 func (h *PaymentHandler) ListPayments(c *gin.Context) {
-	// In production, add pagination
-	c.JSON(http.StatusOK, gin.H{"payments": []interface{}{}})
+	if key := c.Query("idempotency_key"); key != "" {
+		h.getPaymentByIdempotencyKey(c, key)
+		return
+	}
+
+	filter := models.PaymentListFilter{
+		IncludeArchived: c.Query("include_archived") == "true",
+		Limit:           20,
+		Sort:            c.Query("sort"),
+		Order:           c.Query("order"),
+		EmailPrefix:     strings.TrimSpace(c.Query("email_prefix")),
+	}
+	if _, ok := c.GetQuery("email_prefix"); ok && len(filter.EmailPrefix) < 3 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email_prefix must be at least 3 characters"})
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		filter.Limit = limit
+	}
+	switch mode := models.PaymentMode(c.Query("mode")); mode {
+	case "", models.PaymentModeTest, models.PaymentModeLive:
+		filter.Mode = mode
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be test or live"})
+		return
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be a non-negative integer"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	page, err := h.service.ListPayments(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidPaymentSort) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to list payments", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+func (h *PaymentHandler) getPaymentByIdempotencyKey(c *gin.Context, key string) {
+	payment, err := h.service.GetPaymentByIdempotencyKey(c.Request.Context(), key)
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+			return
+		}
+		h.logger.Error("failed to get payment by idempotency key", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get payment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// ArchivePayment handles POST /api/v1/payments/:id/archive
+func (h *PaymentHandler) ArchivePayment(c *gin.Context) {
+	payment, err := h.service.ArchivePayment(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case errors.Is(err, service.ErrPaymentNotArchivable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to archive payment", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to archive payment"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
+
+// GetReceipt handles GET /api/v1/payments/:id/receipt, returning JSON by default
+// or a PDF with ?format=pdf
+func (h *PaymentHandler) GetReceipt(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or pdf"})
+		return
+	}
+
+	receipt, err := h.service.GetReceipt(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Payment not found"})
+		case errors.Is(err, service.ErrReceiptUnavailable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to get receipt", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get receipt"})
+		}
+		return
+	}
+
+	if format == "pdf" {
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=receipt-%s.pdf", receipt.PaymentID))
+		c.Data(http.StatusOK, "application/pdf", service.RenderReceiptPDF(receipt))
+		return
+	}
+
+	c.JSON(http.StatusOK, receipt)
 }
 
 // StripeWebhook handles POST /api/v1/webhooks/stripe
 func (h *PaymentHandler) StripeWebhook(c *gin.Context) {
-	// Handle Stripe webhook events
-	// Verify signature, process events
+	payload, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+		return
+	}
+
+	// Already-processed events are acknowledged with 200 so Stripe stops retrying
+	if err := h.service.HandleStripeWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature")); err != nil {
+		if errors.Is(err, service.ErrInvalidWebhookSignature) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid signature"})
+			return
+		}
+		h.logger.Error("failed to process stripe webhook", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to process webhook"})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{"received": true})
-}
\ No newline at end of file
+}
+
+// ReplayStripeWebhook handles POST /api/v1/webhooks/stripe/replay/:event_id, re-applying
+// a stored Stripe event. Replaying an event that is already reflected changes nothing.
+func (h *PaymentHandler) ReplayStripeWebhook(c *gin.Context) {
+	eventID := c.Param("event_id")
+
+	if err := h.service.ReplayStripeEvent(c.Request.Context(), eventID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrWebhookEventNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Webhook event not found"})
+		case errors.Is(err, service.ErrWebhookEventNotReplayable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to replay stripe webhook", zap.String("event_id", eventID), zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to replay webhook"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"replayed": true, "event_id": eventID})
+}
+
+// newPaymentResponse wraps a payment with the next action the client must take
+func newPaymentResponse(payment *models.Payment) models.PaymentResponse {
+	response := models.PaymentResponse{
+		Payment: payment,
+	}
+
+	if payment.Status == models.PaymentStatusRequiresAction && payment.Requires3DS {
+		response.NextAction = "complete_3ds_authentication"
+		response.RedirectURL = payment.RedirectURL
+	}
+	if payment.Status == models.PaymentStatusPendingReview {
+		response.NextAction = "await_fraud_review"
+	}
+
+	return response
+}
+
+// writeFieldError responds 400, naming the request field that failed validation
+func writeFieldError(c *gin.Context, err error) {
+	var fieldErr *money.FieldError
+	if errors.As(err, &fieldErr) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fieldErr.Err.Error(), "field": fieldErr.Field})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+}
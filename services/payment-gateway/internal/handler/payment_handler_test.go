@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestNewPaymentResponse(t *testing.T) {
+	tests := []struct {
+		name           string
+		payment        *models.Payment
+		wantNextAction string
+		wantRedirect   string
+	}{
+		{
+			name: "Requires action",
+			payment: &models.Payment{
+				ID:          "pay_1",
+				Status:      models.PaymentStatusRequiresAction,
+				Requires3DS: true,
+				RedirectURL: "https://hooks.stripe.com/3d_secure/abc",
+			},
+			wantNextAction: "complete_3ds_authentication",
+			wantRedirect:   "https://hooks.stripe.com/3d_secure/abc",
+		},
+		{
+			name: "Succeeded after 3DS",
+			payment: &models.Payment{
+				ID:          "pay_2",
+				Status:      models.PaymentStatusSucceeded,
+				Requires3DS: true,
+				RedirectURL: "https://hooks.stripe.com/3d_secure/abc",
+			},
+		},
+		{
+			name: "No 3DS",
+			payment: &models.Payment{
+				ID:     "pay_3",
+				Status: models.PaymentStatusPending,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPaymentResponse(tt.payment)
+			if got.NextAction != tt.wantNextAction {
+				t.Errorf("NextAction = %q, want %q", got.NextAction, tt.wantNextAction)
+			}
+			if got.RedirectURL != tt.wantRedirect {
+				t.Errorf("RedirectURL = %q, want %q", got.RedirectURL, tt.wantRedirect)
+			}
+		})
+	}
+}
+
+func TestPaymentResponseRequiresActionJSON(t *testing.T) {
+	payment := &models.Payment{
+		ID:          "pay_1",
+		Status:      models.PaymentStatusRequiresAction,
+		Requires3DS: true,
+		RedirectURL: "https://hooks.stripe.com/3d_secure/abc",
+	}
+
+	data, err := json.Marshal(newPaymentResponse(payment))
+	if err != nil {
+		t.Fatalf("failed to marshal response: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatalf("failed to unmarshal response: %v", err)
+	}
+
+	if body["next_action"] != "complete_3ds_authentication" {
+		t.Errorf("next_action = %v", body["next_action"])
+	}
+	if body["redirect_url"] != payment.RedirectURL {
+		t.Errorf("redirect_url = %v", body["redirect_url"])
+	}
+	p, ok := body["payment"].(map[string]interface{})
+	if !ok {
+		t.Fatal("response doesn't contain payment object")
+	}
+	if p["status"] != "requires_action" {
+		t.Errorf("payment.status = %v, want requires_action", p["status"])
+	}
+}
+
+func TestMerchantReturnURL(t *testing.T) {
+	payment := &models.Payment{
+		ID:        "pay_1",
+		Status:    models.PaymentStatusSucceeded,
+		ReturnURL: "https://shop.example.com/checkout/done?order=42",
+	}
+
+	got, err := merchantReturnURL(payment)
+	if err != nil {
+		t.Fatalf("merchantReturnURL() error = %v", err)
+	}
+
+	want := "https://shop.example.com/checkout/done?order=42&payment_id=pay_1&status=succeeded"
+	if got != want {
+		t.Errorf("merchantReturnURL() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"payment-gateway/internal/models"
+	"payment-gateway/internal/service"
+	"shared/pkg/middleware"
+)
+
+// ListPendingReviews handles GET /api/v1/reviews
+func (h *PaymentHandler) ListPendingReviews(c *gin.Context) {
+	limit := 20
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+			return
+		}
+		limit = parsed
+	}
+
+	reviews, err := h.service.ListPendingReviews(c.Request.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed to list reviews", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list reviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": reviews})
+}
+
+// ApproveReview handles POST /api/v1/reviews/:payment_id/approve
+func (h *PaymentHandler) ApproveReview(c *gin.Context) {
+	h.decideReview(c, h.service.ApproveReview)
+}
+
+// RejectReview handles POST /api/v1/reviews/:payment_id/reject
+func (h *PaymentHandler) RejectReview(c *gin.Context) {
+	h.decideReview(c, h.service.RejectReview)
+}
+
+type reviewDecision func(ctx context.Context, paymentID string, req *models.ReviewDecisionRequest) (*models.Payment, error)
+
+func (h *PaymentHandler) decideReview(c *gin.Context, decide reviewDecision) {
+	var req models.ReviewDecisionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	req.Reviewer = middleware.AdminIdentity(c)
+
+	payment, err := decide(c.Request.Context(), c.Param("payment_id"), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrReviewNotFound), errors.Is(err, service.ErrPaymentNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Review not found"})
+		case errors.Is(err, service.ErrReviewAlreadyResolved):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to resolve review", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resolve review"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"payment": payment})
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Payment analytics groupings
+const (
+	AnalyticsGroupByDay      = "day"
+	AnalyticsGroupByCurrency = "currency"
+	AnalyticsGroupByStatus   = "status"
+)
+
+// PaymentAnalyticsFilter selects the payments created in [From, To) and how to bucket them
+type PaymentAnalyticsFilter struct {
+	From    time.Time
+	To      time.Time
+	GroupBy string
+}
+
+// PaymentAnalyticsBucket is the payment count and volume for one bucket. Amounts
+// are never summed across currencies, so each bucket is split per currency.
+type PaymentAnalyticsBucket struct {
+	Key         string  `json:"key"`
+	Currency    string  `json:"currency"`
+	Count       int64   `json:"count"`
+	TotalAmount float64 `json:"total_amount"`
+}
+
+// PaymentAnalytics is the bucketed payment volume over a date range
+type PaymentAnalytics struct {
+	From    time.Time                `json:"from"`
+	To      time.Time                `json:"to"`
+	GroupBy string                   `json:"group_by"`
+	Buckets []PaymentAnalyticsBucket `json:"buckets"`
+}
@@ -0,0 +1,17 @@
+package models
+
+// Card funding types reported by a BIN lookup
+const (
+	CardTypeCredit  = "credit"
+	CardTypeDebit   = "debit"
+	CardTypePrepaid = "prepaid"
+)
+
+// BINInfo describes the card range a card number's leading digits (its BIN) fall in
+type BINInfo struct {
+	BIN        string `json:"bin"`
+	Network    string `json:"network"`
+	IssuerBank string `json:"issuer_bank,omitempty"`
+	Country    string `json:"country,omitempty"`
+	CardType   string `json:"card_type,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Customer is a payer with a Stripe customer record for saved payment methods
+type Customer struct {
+	ID               string    `json:"id" db:"id"`
+	Email            string    `json:"email" db:"email"`
+	Name             string    `json:"name,omitempty" db:"name"`
+	StripeCustomerID string    `json:"stripe_customer_id" db:"stripe_customer_id"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+}
+
+type CustomerRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Name  string `json:"name"`
+}
+
+// SavedPaymentMethod is a Stripe payment method attached to a customer
+type SavedPaymentMethod struct {
+	ID          string    `json:"id" db:"id"`
+	CustomerID  string    `json:"customer_id" db:"customer_id"`
+	CardLast4   string    `json:"card_last4" db:"card_last4"`
+	CardNetwork string    `json:"card_network" db:"card_network"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+type AttachPaymentMethodRequest struct {
+	PaymentMethodID string `json:"payment_method_id" binding:"required"`
+}
+
+const CustomerSchema = `
+CREATE TABLE IF NOT EXISTS customers (
+    id VARCHAR(255) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,
+    name VARCHAR(255),
+    stripe_customer_id VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id VARCHAR(255) PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL REFERENCES customers(id),
+    card_last4 VARCHAR(4),
+    card_network VARCHAR(50),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_customer ON payment_methods(customer_id);
+`
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// CustomerLimit caps how much a single customer may pay per day
+type CustomerLimit struct {
+	CustomerEmail string    `json:"customer_email" db:"customer_email"`
+	DailyLimit    float64   `json:"daily_limit" db:"daily_limit"`
+	Currency      string    `json:"currency" db:"currency"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+const CustomerLimitSchema = `
+CREATE TABLE IF NOT EXISTS customer_limits (
+    customer_email VARCHAR(255) PRIMARY KEY,
+    daily_limit DECIMAL(19, 4) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+`
@@ -0,0 +1,41 @@
+package models
+
+// Fraud decisions returned by the fraud-detection service
+const (
+	FraudDecisionApprove = "approve"
+	FraudDecisionReview  = "review"
+	FraudDecisionBlock   = "block"
+)
+
+// FraudCheck is the transaction sent to the fraud-detection service
+type FraudCheck struct {
+	TransactionID     string  `json:"transaction_id"`
+	MerchantID        string  `json:"merchant_id,omitempty"`
+	Amount            float64 `json:"amount"`
+	Currency          string  `json:"currency"`
+	CustomerEmail     string  `json:"customer_email"`
+	CardLast4         string  `json:"card_last4"`
+	Country           string  `json:"country,omitempty"`
+	IssuerCountry     string  `json:"issuer_country,omitempty"`
+	DeviceFingerprint string  `json:"device_fingerprint,omitempty"`
+}
+
+// FraudAssessment is the fraud-detection service's verdict on a payment
+type FraudAssessment struct {
+	TransactionID string   `json:"transaction_id"`
+	Score         int      `json:"score"`
+	RiskLevel     string   `json:"risk_level"`
+	Decision      string   `json:"decision"`
+	Flags         []string `json:"flags"`
+}
+
+// DryRunResult describes what creating a payment would do, without charging or storing it
+type DryRunResult struct {
+	WouldSucceed bool             `json:"would_succeed"`
+	Reason       string           `json:"reason,omitempty"`
+	Amount       float64          `json:"amount"`
+	Currency     string           `json:"currency"`
+	CardLast4    string           `json:"card_last4,omitempty"`
+	CardNetwork  string           `json:"card_network,omitempty"`
+	Fraud        *FraudAssessment `json:"fraud,omitempty"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// MerchantWebhook is a merchant endpoint that receives payment.* events
+type MerchantWebhook struct {
+	ID         string `json:"id" db:"id"`
+	MerchantID string `json:"merchant_id" db:"merchant_id"`
+	URL        string `json:"url" db:"url"`
+	// Secret signs deliveries; it is only returned when the webhook is created
+	Secret    string    `json:"secret,omitempty" db:"secret"`
+	Active    bool      `json:"active" db:"active"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type MerchantWebhookRequest struct {
+	MerchantID string `json:"merchant_id" binding:"required"`
+	URL        string `json:"url" binding:"required,url"`
+}
+
+// WebhookDelivery is one attempt to deliver an event to a merchant webhook
+type WebhookDelivery struct {
+	ID         string    `json:"id" db:"id"`
+	WebhookID  string    `json:"webhook_id" db:"webhook_id"`
+	EventID    string    `json:"event_id" db:"event_id"`
+	EventType  string    `json:"event_type" db:"event_type"`
+	PaymentID  string    `json:"payment_id" db:"payment_id"`
+	URL        string    `json:"url" db:"url"`
+	Attempt    int       `json:"attempt" db:"attempt"`
+	StatusCode int       `json:"status_code,omitempty" db:"status_code"`
+	Error      string    `json:"error,omitempty" db:"error"`
+	Succeeded  bool      `json:"succeeded" db:"succeeded"`
+	DurationMS int64     `json:"duration_ms" db:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// WebhookDeliveryFilter selects deliveries for listing, newest first
+type WebhookDeliveryFilter struct {
+	WebhookID string
+	PaymentID string
+	Limit     int
+}
+
+const MerchantWebhookSchema = `
+CREATE TABLE IF NOT EXISTS merchant_webhooks (
+    id VARCHAR(36) PRIMARY KEY,
+    merchant_id VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_webhooks_merchant ON merchant_webhooks(merchant_id);
+
+CREATE TABLE IF NOT EXISTS merchant_webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    webhook_id VARCHAR(36) NOT NULL REFERENCES merchant_webhooks(id),
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    payment_id VARCHAR(64),
+    url TEXT NOT NULL,
+    attempt INTEGER NOT NULL,
+    status_code INTEGER,
+    error TEXT,
+    succeeded BOOLEAN NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_merchant_webhook_deliveries_webhook ON merchant_webhook_deliveries(webhook_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_merchant_webhook_deliveries_payment ON merchant_webhook_deliveries(payment_id);
+`
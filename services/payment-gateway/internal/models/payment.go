@@ -2,24 +2,50 @@
 // Data structures
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"shared/pkg/money"
+)
 
 type PaymentStatus string
 
 const (
 	PaymentStatusPending         PaymentStatus = "pending"
+	PaymentStatusPendingReview   PaymentStatus = "pending_review"
 	PaymentStatusRequiresAction  PaymentStatus = "requires_action"
+	PaymentStatusRequiresCapture PaymentStatus = "requires_capture"
 	PaymentStatusProcessing      PaymentStatus = "processing"
 	PaymentStatusSucceeded       PaymentStatus = "succeeded"
 	PaymentStatusFailed          PaymentStatus = "failed"
 	PaymentStatusCancelled       PaymentStatus = "cancelled"
 )
 
+// PaymentMode is the Stripe account mode a payment was made in
+type PaymentMode string
+
+const (
+	PaymentModeTest PaymentMode = "test"
+	PaymentModeLive PaymentMode = "live"
+)
+
 type Payment struct {
 	ID                     string                 `json:"id" db:"id"`
+	MerchantID             string                 `json:"merchant_id,omitempty" db:"merchant_id"`
 	Amount                 float64                `json:"amount" db:"amount"`
 	Currency               string                 `json:"currency" db:"currency"`
+	AuthorizedAmount       float64                `json:"authorized_amount" db:"authorized_amount"`
+	CapturedAmount         float64                `json:"captured_amount" db:"captured_amount"`
+	RefundedAmount         float64                `json:"refunded_amount" db:"refunded_amount"`
+	FeeAmount              float64                `json:"fee_amount" db:"fee_amount"`
+	NetAmount              float64                `json:"net_amount" db:"net_amount"`
+	FeeCurrency            string                 `json:"fee_currency,omitempty" db:"fee_currency"`
+	SettlementAmount       float64                `json:"settlement_amount,omitempty" db:"settlement_amount"`
+	SettlementCurrency     string                 `json:"settlement_currency,omitempty" db:"settlement_currency"`
+	SettlementRate         float64                `json:"settlement_rate,omitempty" db:"settlement_rate"`
 	Status                 PaymentStatus          `json:"status" db:"status"`
+	Mode                   PaymentMode            `json:"mode" db:"mode"`
 	CardLast4              string                 `json:"card_last4" db:"card_last4"`
 	CardNetwork            string                 `json:"card_network" db:"card_network"`
 	CustomerEmail          string                 `json:"customer_email" db:"customer_email"`
@@ -27,39 +53,158 @@ type Payment struct {
 	StripePaymentIntentID  string                 `json:"stripe_payment_intent_id,omitempty" db:"stripe_payment_intent_id"`
 	ClientSecret           string                 `json:"client_secret,omitempty" db:"client_secret"`
 	Requires3DS            bool                   `json:"requires_3ds" db:"requires_3ds"`
+	RedirectURL            string                 `json:"redirect_url,omitempty" db:"redirect_url"`
+	ReturnURL              string                 `json:"return_url,omitempty" db:"return_url"`
 	IdempotencyKey         string                 `json:"idempotency_key,omitempty" db:"idempotency_key"`
 	FailureReason          string                 `json:"failure_reason,omitempty" db:"failure_reason"`
+	FraudCheckID           string                 `json:"fraud_check_id,omitempty" db:"fraud_check_id"`
+	FraudDecision          string                 `json:"fraud_decision,omitempty" db:"fraud_decision"`
+	FraudScore             int                    `json:"fraud_score" db:"fraud_score"`
 	Metadata               map[string]interface{} `json:"metadata,omitempty" db:"metadata"`
 	CreatedAt              time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt              time.Time              `json:"updated_at" db:"updated_at"`
 	CompletedAt            time.Time              `json:"completed_at,omitempty" db:"completed_at"`
+	ArchivedAt             *time.Time             `json:"archived_at,omitempty" db:"archived_at"`
+}
+
+// IsFinal reports whether the payment can no longer change status
+func (p *Payment) IsFinal() bool {
+	switch p.Status {
+	case PaymentStatusSucceeded, PaymentStatusFailed, PaymentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// RefundableAmount is what the payment settled less what has been refunded
+func (p *Payment) RefundableAmount() float64 {
+	settled := p.Amount
+	if p.CapturedAmount > 0 {
+		settled = p.CapturedAmount
+	}
+	if p.RefundedAmount >= settled {
+		return 0
+	}
+	return settled - p.RefundedAmount
+}
+
+// ReleasedAmount is the part of the authorization that was not captured
+func (p *Payment) ReleasedAmount() float64 {
+	if p.AuthorizedAmount <= p.CapturedAmount {
+		return 0
+	}
+	return p.AuthorizedAmount - p.CapturedAmount
 }
 
 type PaymentRequest struct {
 	Amount          float64                `json:"amount" binding:"required,gt=0"`
 	Currency        string                 `json:"currency" binding:"required,len=3"`
-	CardNumber      string                 `json:"card_number" binding:"required"`
-	CardExpMonth    int                    `json:"card_exp_month" binding:"required,min=1,max=12"`
-	CardExpYear     int                    `json:"card_exp_year" binding:"required,min=2024"`
-	CardCVC         string                 `json:"card_cvc" binding:"required,len=3"`
+	CardNumber      string                 `json:"card_number" binding:"required_without=PaymentMethodID"`
+	CardExpMonth    int                    `json:"card_exp_month" binding:"required_without=PaymentMethodID,omitempty,min=1,max=12"`
+	CardExpYear     int                    `json:"card_exp_year" binding:"required_without=PaymentMethodID,omitempty,min=2024"`
+	CardCVC         string                 `json:"card_cvc" binding:"required_without=PaymentMethodID,omitempty,len=3"`
+	CustomerID      string                 `json:"customer_id" binding:"required_with=PaymentMethodID"`
+	PaymentMethodID string                 `json:"payment_method_id"`
 	CustomerEmail   string                 `json:"customer_email" binding:"required,email"`
+	Country         string                 `json:"country" binding:"omitempty,len=2"`
 	Description     string                 `json:"description"`
 	IdempotencyKey  string                 `json:"idempotency_key"`
+	CaptureMethod   string                 `json:"capture_method" binding:"omitempty,oneof=automatic manual"`
+	ReturnURL       string                 `json:"return_url" binding:"omitempty,url"`
+	DryRun          bool                   `json:"dry_run"`
 	Metadata        map[string]interface{} `json:"metadata"`
+
+	// MerchantID is whose payment this is; only that merchant's webhooks hear about it
+	MerchantID string `json:"merchant_id"`
+
+	// Async charges a saved payment method, such as a bank debit, in the background:
+	// the payment is returned as processing and a webhook or poll finalizes it
+	Async bool `json:"async"`
+
+	// SettlementCurrency is what the merchant is paid out in, when it differs from
+	// Currency; the amount is converted at charge time
+	SettlementCurrency string `json:"settlement_currency" binding:"omitempty,len=3"`
 }
 
+// UnmarshalJSON normalizes the currency codes as the request is bound, so "usd"
+// and " USD " both bind as USD
+func (r *PaymentRequest) UnmarshalJSON(data []byte) error {
+	type paymentRequest PaymentRequest
+	if err := json.Unmarshal(data, (*paymentRequest)(r)); err != nil {
+		return err
+	}
+	r.Currency = money.NormalizeCurrency(r.Currency)
+	if r.SettlementCurrency != "" {
+		r.SettlementCurrency = money.NormalizeCurrency(r.SettlementCurrency)
+	}
+	return nil
+}
+
+// Validate checks what binding tags cannot: that the amount suits its currency
+func (r *PaymentRequest) Validate() error {
+	return money.ValidateAmount("amount", r.Amount, r.Currency)
+}
+
+type CaptureRequest struct {
+	// Amount to capture; zero captures the full authorization
+	Amount float64 `json:"amount" binding:"omitempty,gt=0"`
+}
+
+// PaymentListFilter selects payments for listing
+type PaymentListFilter struct {
+	IncludeArchived bool
+	Limit           int
+	Offset          int
+	// Mode limits the list to test or live payments; empty lists both
+	Mode PaymentMode
+	// EmailPrefix limits the list to payments whose customer email starts with it
+	EmailPrefix string
+	// Sort is created_at or amount and Order is asc or desc; empty means created_at desc
+	Sort  string
+	Order string
+}
+
+const (
+	PaymentSortCreatedAt = "created_at"
+	PaymentSortAmount    = "amount"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
 type PaymentResponse struct {
 	Payment      *Payment `json:"payment"`
 	NextAction   string   `json:"next_action,omitempty"`
+	RedirectURL  string   `json:"redirect_url,omitempty"`
+}
+
+// PaymentStatusResponse is what clients polling an async payment need to know
+type PaymentStatusResponse struct {
+	PaymentID     string        `json:"payment_id"`
+	Status        PaymentStatus `json:"status"`
+	FailureReason string        `json:"failure_reason,omitempty"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
 }
 
 // Database schema
 const PaymentSchema = `
 CREATE TABLE IF NOT EXISTS payments (
-    id VARCHAR(36) PRIMARY KEY,
+    id VARCHAR(64) PRIMARY KEY,
+    merchant_id VARCHAR(255),
     amount DECIMAL(19, 4) NOT NULL,
     currency VARCHAR(3) NOT NULL,
+    authorized_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    captured_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    refunded_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    fee_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    fee_currency VARCHAR(3),
+    settlement_amount DECIMAL(19, 4),
+    settlement_currency VARCHAR(3),
+    settlement_rate DECIMAL(19, 10),
     status VARCHAR(20) NOT NULL,
+    mode VARCHAR(4) NOT NULL DEFAULT 'test',
     card_last4 VARCHAR(4),
     card_network VARCHAR(20),
     customer_email VARCHAR(255),
@@ -67,14 +212,22 @@ CREATE TABLE IF NOT EXISTS payments (
     stripe_payment_intent_id VARCHAR(255),
     client_secret TEXT,
     requires_3ds BOOLEAN DEFAULT FALSE,
+    redirect_url TEXT,
+    return_url TEXT,
     idempotency_key VARCHAR(255) UNIQUE,
     failure_reason TEXT,
+    fraud_check_id VARCHAR(255),
+    fraud_decision VARCHAR(20),
+    fraud_score INTEGER NOT NULL DEFAULT 0,
     metadata JSONB,
     created_at TIMESTAMP NOT NULL DEFAULT NOW(),
     updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
     completed_at TIMESTAMP,
+    archived_at TIMESTAMP,
     
     INDEX idx_status (status),
+    INDEX idx_mode (mode),
+    INDEX idx_merchant_id (merchant_id),
     INDEX idx_customer_email (customer_email),
     INDEX idx_created_at (created_at)
 );
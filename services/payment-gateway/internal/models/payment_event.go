@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// PaymentEvent is a single status transition in a payment's timeline
+type PaymentEvent struct {
+	ID        string        `json:"id" db:"id"`
+	PaymentID string        `json:"payment_id" db:"payment_id"`
+	OldStatus PaymentStatus `json:"old_status,omitempty" db:"old_status"`
+	NewStatus PaymentStatus `json:"new_status" db:"new_status"`
+	Actor     string        `json:"actor" db:"actor"`
+	Reason    string        `json:"reason,omitempty" db:"reason"`
+	CreatedAt time.Time     `json:"created_at" db:"created_at"`
+}
+
+// PaymentLifecycleEvent is published to other services when a payment changes state
+type PaymentLifecycleEvent struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	PaymentID string `json:"payment_id"`
+	// MerchantID selects which merchant's webhooks receive the event
+	MerchantID string  `json:"merchant_id,omitempty"`
+	Amount     float64 `json:"amount"`
+	Currency   string  `json:"currency"`
+	// Mode is "test" or "live", so test payments stay out of live reports
+	Mode string `json:"mode,omitempty"`
+	// Processor fee and net settlement, in FeeCurrency; zero until Stripe reports them
+	FeeAmount   float64 `json:"fee_amount,omitempty"`
+	NetAmount   float64 `json:"net_amount,omitempty"`
+	FeeCurrency string  `json:"fee_currency,omitempty"`
+	// RefundReason is the reason code of a payment.refunded event
+	RefundReason RefundReason `json:"refund_reason,omitempty"`
+	OccurredAt   time.Time    `json:"occurred_at"`
+}
+
+const PaymentEventSchema = `
+CREATE TABLE IF NOT EXISTS payment_events (
+    id VARCHAR(36) PRIMARY KEY,
+    payment_id VARCHAR(64) NOT NULL REFERENCES payments(id),
+    old_status VARCHAR(20),
+    new_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(50) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_events_payment ON payment_events(payment_id, created_at);
+`
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// Receipt is what a customer is shown for a succeeded payment
+type Receipt struct {
+	PaymentID   string    `json:"payment_id"`
+	Merchant    string    `json:"merchant"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	CardLast4   string    `json:"card_last4"`
+	CardNetwork string    `json:"card_network"`
+	Description string    `json:"description"`
+	PaidAt      time.Time `json:"paid_at"`
+}
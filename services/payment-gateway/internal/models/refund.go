@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// RefundReason is the standardized reason a refund is issued for, used in
+// reporting and passed on to Stripe
+type RefundReason string
+
+const (
+	RefundReasonDuplicate           RefundReason = "duplicate"
+	RefundReasonFraudulent          RefundReason = "fraudulent"
+	RefundReasonRequestedByCustomer RefundReason = "requested_by_customer"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+	RefundStatusFailed    RefundStatus = "failed"
+)
+
+// Refund is money returned on a succeeded payment. A pending refund holds its
+// amount against the payment until Stripe accepts or declines it.
+type Refund struct {
+	ID             string       `json:"id" db:"id"`
+	PaymentID      string       `json:"payment_id" db:"payment_id"`
+	Amount         float64      `json:"amount" db:"amount"`
+	Currency       string       `json:"currency" db:"currency"`
+	Reason         RefundReason `json:"reason" db:"reason"`
+	Status         RefundStatus `json:"status" db:"status"`
+	StripeRefundID string       `json:"stripe_refund_id,omitempty" db:"stripe_refund_id"`
+	IdempotencyKey string       `json:"idempotency_key,omitempty" db:"idempotency_key"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// RefundRequest refunds all or part of a succeeded payment
+type RefundRequest struct {
+	// Amount to refund; zero refunds everything not yet refunded
+	Amount float64 `json:"amount" binding:"omitempty,gt=0"`
+	Reason string  `json:"reason" binding:"required"`
+	// IdempotencyKey makes a retried request return the first refund instead of refunding again
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+const RefundSchema = `
+CREATE TABLE IF NOT EXISTS refunds (
+    id VARCHAR(64) PRIMARY KEY,
+    payment_id VARCHAR(64) NOT NULL REFERENCES payments(id),
+    amount DECIMAL(19, 4) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    stripe_refund_id VARCHAR(255),
+    idempotency_key VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (payment_id, idempotency_key)
+);
+
+CREATE INDEX IF NOT EXISTS idx_refunds_payment ON refunds(payment_id, created_at);
+`
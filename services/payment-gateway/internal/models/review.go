@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+// ReviewItem is a payment held in the manual review queue
+type ReviewItem struct {
+	ID         string       `json:"id" db:"id"`
+	PaymentID  string       `json:"payment_id" db:"payment_id"`
+	Status     ReviewStatus `json:"status" db:"status"`
+	Reason     string       `json:"reason" db:"reason"`
+	FraudScore int          `json:"fraud_score" db:"fraud_score"`
+	Reviewer   string       `json:"reviewer,omitempty" db:"reviewer"`
+	Notes      string       `json:"notes,omitempty" db:"notes"`
+	CreatedAt  time.Time    `json:"created_at" db:"created_at"`
+	ResolvedAt *time.Time   `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+type ReviewDecisionRequest struct {
+	// Reviewer is the authenticated admin, never taken from the request body
+	Reviewer string `json:"-"`
+	Notes    string `json:"notes"`
+}
+
+const ReviewQueueSchema = `
+CREATE TABLE IF NOT EXISTS review_queue (
+    id VARCHAR(36) PRIMARY KEY,
+    payment_id VARCHAR(64) NOT NULL UNIQUE REFERENCES payments(id),
+    status VARCHAR(20) NOT NULL,
+    reason TEXT,
+    fraud_score INTEGER NOT NULL DEFAULT 0,
+    reviewer VARCHAR(255),
+    notes TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_review_queue_status ON review_queue(status, created_at);
+`
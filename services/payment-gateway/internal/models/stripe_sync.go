@@ -0,0 +1,15 @@
+package models
+
+// StripeSyncResult compares a payment's status with its Stripe payment intent
+type StripeSyncResult struct {
+	PaymentID    string        `json:"payment_id"`
+	LocalStatus  PaymentStatus `json:"local_status"`
+	StripeStatus string        `json:"stripe_status"`
+	// Diverged is set when our status contradicts Stripe's; the payment is flagged for review
+	Diverged bool `json:"diverged"`
+	// FundsCaptured is set when Stripe captured funds we did not record as succeeded
+	FundsCaptured bool `json:"funds_captured"`
+	// Corrected is set when the payment was moved to succeeded and posted to the ledger
+	Corrected bool   `json:"corrected"`
+	Reason    string `json:"reason,omitempty"`
+}
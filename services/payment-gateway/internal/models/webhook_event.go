@@ -0,0 +1,24 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// WebhookEvent records a Stripe event that has already been processed, along with
+// the verified payload it arrived in
+type WebhookEvent struct {
+	ID          string          `json:"id" db:"id"`
+	Type        string          `json:"type" db:"type"`
+	Payload     json.RawMessage `json:"payload,omitempty" db:"payload"`
+	ProcessedAt time.Time       `json:"processed_at" db:"processed_at"`
+}
+
+const WebhookEventSchema = `
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id VARCHAR(255) PRIMARY KEY,
+    type VARCHAR(100) NOT NULL,
+    payload JSONB,
+    processed_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+`
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"payment-gateway/internal/models"
+)
+
+// analyticsBucketKeys allow-lists the expression each grouping buckets payments by
+var analyticsBucketKeys = map[string]string{
+	models.AnalyticsGroupByDay:      `to_char(date_trunc('day', created_at), 'YYYY-MM-DD')`,
+	models.AnalyticsGroupByCurrency: `currency`,
+	models.AnalyticsGroupByStatus:   `status`,
+}
+
+// PaymentAnalytics counts and sums payments created in the filter's range, per bucket
+// and currency. The range scan uses idx_payments_created_at.
+func (r *PaymentRepository) PaymentAnalytics(ctx context.Context, filter models.PaymentAnalyticsFilter) ([]models.PaymentAnalyticsBucket, error) {
+	query, args := paymentAnalyticsQuery(filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []models.PaymentAnalyticsBucket{}
+	for rows.Next() {
+		var bucket models.PaymentAnalyticsBucket
+		if err := rows.Scan(&bucket.Key, &bucket.Currency, &bucket.Count, &bucket.TotalAmount); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets, rows.Err()
+}
+
+func paymentAnalyticsQuery(filter models.PaymentAnalyticsFilter) (string, []interface{}) {
+	key, ok := analyticsBucketKeys[filter.GroupBy]
+	if !ok {
+		key = analyticsBucketKeys[models.AnalyticsGroupByDay]
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS bucket, currency, COUNT(*), COALESCE(SUM(amount), 0)
+		FROM payments
+		WHERE created_at >= $1 AND created_at < $2
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, key)
+
+	return query, []interface{}{filter.From, filter.To}
+}
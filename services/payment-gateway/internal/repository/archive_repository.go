@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// finalStatuses are the statuses a payment must have reached to be archived
+var finalStatuses = []models.PaymentStatus{
+	models.PaymentStatusSucceeded,
+	models.PaymentStatusFailed,
+	models.PaymentStatusCancelled,
+}
+
+// paymentSortColumns allow-lists the columns payments may be ordered by, so a
+// caller-supplied sort never reaches the query unchecked
+var paymentSortColumns = map[string]string{
+	models.PaymentSortCreatedAt: "created_at",
+	models.PaymentSortAmount:    "amount",
+}
+
+// List returns payments in the filter's order, newest first by default, hiding archived payments unless asked
+func (r *PaymentRepository) List(ctx context.Context, filter models.PaymentListFilter) ([]*models.Payment, error) {
+	query, args := listPaymentsQuery(filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*models.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// Count returns how many payments match the filter, ignoring its limit and offset
+func (r *PaymentRepository) Count(ctx context.Context, filter models.PaymentListFilter) (int, error) {
+	conditions, args := paymentListConditions(filter, nil)
+
+	query := `SELECT COUNT(*) FROM payments`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, ` AND `)
+	}
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
+	return count, err
+}
+
+func listPaymentsQuery(filter models.PaymentListFilter) (string, []interface{}) {
+	var b strings.Builder
+	conditions, args := paymentListConditions(filter, []interface{}{filter.Limit, filter.Offset})
+
+	b.WriteString(`SELECT ` + paymentColumns + ` FROM payments`)
+	if len(conditions) > 0 {
+		b.WriteString(` WHERE ` + strings.Join(conditions, ` AND `))
+	}
+	b.WriteString(` ORDER BY ` + paymentOrderBy(filter) + ` LIMIT $1 OFFSET $2`)
+
+	return b.String(), args
+}
+
+// paymentListConditions builds the WHERE conditions for the filter, appending
+// their values to args so placeholders number on from any already there
+func paymentListConditions(filter models.PaymentListFilter, args []interface{}) ([]string, []interface{}) {
+	var conditions []string
+	if !filter.IncludeArchived {
+		conditions = append(conditions, `archived_at IS NULL`)
+	}
+	if filter.Mode != "" {
+		args = append(args, filter.Mode)
+		conditions = append(conditions, fmt.Sprintf(`mode = $%d`, len(args)))
+	}
+	if filter.EmailPrefix != "" {
+		// A prefix-only pattern can use the varchar_pattern_ops index
+		args = append(args, escapeLike(filter.EmailPrefix)+"%")
+		conditions = append(conditions, fmt.Sprintf(`customer_email LIKE $%d`, len(args)))
+	}
+	return conditions, args
+}
+
+// likeEscaper escapes LIKE wildcards so user input only ever matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// paymentOrderBy builds the ORDER BY clause, falling back to created_at DESC for
+// anything outside the allow-list. id breaks ties so pages are stable.
+func paymentOrderBy(filter models.PaymentListFilter) string {
+	column, ok := paymentSortColumns[filter.Sort]
+	if !ok {
+		column = "created_at"
+	}
+	direction := "DESC"
+	if filter.Order == models.SortOrderAsc {
+		direction = "ASC"
+	}
+	return column + " " + direction + ", id " + direction
+}
+
+// Archive marks a single payment as archived
+func (r *PaymentRepository) Archive(ctx context.Context, id string, at time.Time) error {
+	query := `UPDATE payments SET archived_at = $1 WHERE id = $2 AND archived_at IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, at, id)
+	return err
+}
+
+// ArchiveOlderThan archives final payments created before the cutoff, returning how many were archived
+func (r *PaymentRepository) ArchiveOlderThan(ctx context.Context, cutoff, at time.Time) (int64, error) {
+	placeholders := make([]string, len(finalStatuses))
+	args := []interface{}{at, cutoff}
+	for i, status := range finalStatuses {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		args = append(args, status)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE payments SET archived_at = $1
+		WHERE archived_at IS NULL AND created_at < $2 AND status IN (%s)
+	`, strings.Join(placeholders, ", "))
+
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestListPaymentsQuery(t *testing.T) {
+	tests := []struct {
+		name         string
+		filter       models.PaymentListFilter
+		wantArchived bool
+	}{
+		{
+			name:   "Archived hidden by default",
+			filter: models.PaymentListFilter{Limit: 20},
+		},
+		{
+			name:         "Include archived",
+			filter:       models.PaymentListFilter{IncludeArchived: true, Limit: 20},
+			wantArchived: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := listPaymentsQuery(tt.filter)
+
+			hidesArchived := strings.Contains(query, "archived_at IS NULL")
+			if hidesArchived == tt.wantArchived {
+				t.Errorf("query %q hides archived = %v, want %v", query, hidesArchived, !tt.wantArchived)
+			}
+			if len(args) != 2 || args[0] != tt.filter.Limit || args[1] != tt.filter.Offset {
+				t.Errorf("args = %v, want [%d %d]", args, tt.filter.Limit, tt.filter.Offset)
+			}
+		})
+	}
+}
+
+func TestListPaymentsQueryFiltersMode(t *testing.T) {
+	query, args := listPaymentsQuery(models.PaymentListFilter{Mode: models.PaymentModeLive, Limit: 20})
+
+	if !strings.Contains(query, "archived_at IS NULL AND mode = $3") {
+		t.Errorf("query %q does not filter on mode", query)
+	}
+	if len(args) != 3 || args[2] != models.PaymentModeLive {
+		t.Errorf("args = %v, want mode live as $3", args)
+	}
+}
+
+func TestListPaymentsQuerySort(t *testing.T) {
+	tests := []struct {
+		name        string
+		sort, order string
+		wantOrderBy string
+	}{
+		{"Default", "", "", "ORDER BY created_at DESC, id DESC"},
+		{"Created ascending", models.PaymentSortCreatedAt, models.SortOrderAsc, "ORDER BY created_at ASC, id ASC"},
+		{"Created descending", models.PaymentSortCreatedAt, models.SortOrderDesc, "ORDER BY created_at DESC, id DESC"},
+		{"Amount ascending", models.PaymentSortAmount, models.SortOrderAsc, "ORDER BY amount ASC, id ASC"},
+		{"Amount descending", models.PaymentSortAmount, models.SortOrderDesc, "ORDER BY amount DESC, id DESC"},
+		{"Unknown column falls back", "amount; DROP TABLE payments", "", "ORDER BY created_at DESC, id DESC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := listPaymentsQuery(models.PaymentListFilter{Limit: 20, Sort: tt.sort, Order: tt.order})
+
+			if !strings.Contains(query, tt.wantOrderBy+" LIMIT $1") {
+				t.Errorf("query %q does not contain %q", query, tt.wantOrderBy)
+			}
+			if strings.Contains(query, "DROP") {
+				t.Errorf("query %q includes the caller's sort verbatim", query)
+			}
+		})
+	}
+}
+
+func TestListPaymentsQueryEmailPrefix(t *testing.T) {
+	query, args := listPaymentsQuery(models.PaymentListFilter{EmailPrefix: "jo_n%", Limit: 20})
+
+	if !strings.Contains(query, "customer_email LIKE $3") {
+		t.Errorf("query %q does not match on email prefix", query)
+	}
+	// Wildcards in the prefix are escaped and the only wildcard is trailing
+	if len(args) != 3 || args[2] != `jo\_n\%%` {
+		t.Errorf("args = %v, want escaped prefix as $3", args)
+	}
+}
+
+func TestPaymentAnalyticsQuery(t *testing.T) {
+	tests := []struct {
+		groupBy string
+		wantKey string
+	}{
+		{models.AnalyticsGroupByDay, "date_trunc('day', created_at)"},
+		{models.AnalyticsGroupByCurrency, "SELECT currency AS bucket"},
+		{models.AnalyticsGroupByStatus, "SELECT status AS bucket"},
+		{"created_at; DROP TABLE payments", "date_trunc('day', created_at)"},
+	}
+
+	for _, tt := range tests {
+		query, args := paymentAnalyticsQuery(models.PaymentAnalyticsFilter{GroupBy: tt.groupBy})
+		if !strings.Contains(query, tt.wantKey) {
+			t.Errorf("group_by %q: query %q does not bucket by %q", tt.groupBy, query, tt.wantKey)
+		}
+		if !strings.Contains(query, "created_at >= $1 AND created_at < $2") || !strings.Contains(query, "GROUP BY 1, 2") {
+			t.Errorf("group_by %q: query %q is not a ranged GROUP BY", tt.groupBy, query)
+		}
+		if len(args) != 2 {
+			t.Errorf("args = %v, want from and to", args)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// ListProcessingUpdatedBefore returns Stripe payments still processing that have not
+// changed since before, oldest first
+func (r *PaymentRepository) ListProcessingUpdatedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments
+		WHERE status = $1 AND updated_at < $2
+			AND stripe_payment_intent_id IS NOT NULL AND stripe_payment_intent_id <> ''
+		ORDER BY updated_at
+		LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, models.PaymentStatusProcessing, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*models.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
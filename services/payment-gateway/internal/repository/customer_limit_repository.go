@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// GetCustomerLimit returns the daily limit for a customer, or nil if none is configured
+func (r *PaymentRepository) GetCustomerLimit(ctx context.Context, customerEmail string) (*models.CustomerLimit, error) {
+	query := `
+		SELECT customer_email, daily_limit, currency, created_at, updated_at
+		FROM customer_limits WHERE customer_email = $1
+	`
+
+	limit := &models.CustomerLimit{}
+	err := r.db.QueryRowContext(ctx, query, customerEmail).Scan(
+		&limit.CustomerEmail,
+		&limit.DailyLimit,
+		&limit.Currency,
+		&limit.CreatedAt,
+		&limit.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return limit, err
+}
+
+// SumSucceededSince totals a customer's succeeded payments since a point in time, per currency
+func (r *PaymentRepository) SumSucceededSince(ctx context.Context, customerEmail string, since time.Time) (map[string]float64, error) {
+	query := `
+		SELECT currency, SUM(amount)
+		FROM payments
+		WHERE customer_email = $1 AND status = $2 AND created_at >= $3
+		GROUP BY currency
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, customerEmail, models.PaymentStatusSucceeded, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := make(map[string]float64)
+	for rows.Next() {
+		var currency string
+		var total float64
+		if err := rows.Scan(&currency, &total); err != nil {
+			return nil, err
+		}
+		totals[currency] = total
+	}
+
+	return totals, rows.Err()
+}
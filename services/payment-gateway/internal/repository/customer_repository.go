@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"payment-gateway/internal/models"
+)
+
+func (r *PaymentRepository) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	query := `
+		INSERT INTO customers (id, email, name, stripe_customer_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		customer.ID,
+		customer.Email,
+		customer.Name,
+		customer.StripeCustomerID,
+		customer.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PaymentRepository) GetCustomer(ctx context.Context, id string) (*models.Customer, error) {
+	query := `
+		SELECT id, email, COALESCE(name, ''), stripe_customer_id, created_at
+		FROM customers WHERE id = $1
+	`
+
+	customer := &models.Customer{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&customer.ID,
+		&customer.Email,
+		&customer.Name,
+		&customer.StripeCustomerID,
+		&customer.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return customer, err
+}
+
+func (r *PaymentRepository) SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error {
+	query := `
+		INSERT INTO payment_methods (id, customer_id, card_last4, card_network, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE SET customer_id = EXCLUDED.customer_id
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		method.ID,
+		method.CustomerID,
+		method.CardLast4,
+		method.CardNetwork,
+		method.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PaymentRepository) GetPaymentMethod(ctx context.Context, id string) (*models.SavedPaymentMethod, error) {
+	query := `
+		SELECT id, customer_id, COALESCE(card_last4, ''), COALESCE(card_network, ''), created_at
+		FROM payment_methods WHERE id = $1
+	`
+
+	method := &models.SavedPaymentMethod{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&method.ID,
+		&method.CustomerID,
+		&method.CardLast4,
+		&method.CardNetwork,
+		&method.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return method, err
+}
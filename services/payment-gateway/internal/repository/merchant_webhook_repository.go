@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"payment-gateway/internal/models"
+)
+
+func (r *PaymentRepository) CreateMerchantWebhook(ctx context.Context, webhook *models.MerchantWebhook) error {
+	query := `
+		INSERT INTO merchant_webhooks (id, merchant_id, url, secret, active, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		webhook.ID,
+		webhook.MerchantID,
+		webhook.URL,
+		webhook.Secret,
+		webhook.Active,
+		webhook.CreatedAt,
+	)
+	return err
+}
+
+func (r *PaymentRepository) ListActiveMerchantWebhooks(ctx context.Context, merchantID string) ([]*models.MerchantWebhook, error) {
+	query := `
+		SELECT id, merchant_id, url, secret, active, created_at
+		FROM merchant_webhooks
+		WHERE active AND merchant_id = $1
+		ORDER BY created_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, merchantID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*models.MerchantWebhook{}
+	for rows.Next() {
+		webhook := &models.MerchantWebhook{}
+		if err := rows.Scan(
+			&webhook.ID,
+			&webhook.MerchantID,
+			&webhook.URL,
+			&webhook.Secret,
+			&webhook.Active,
+			&webhook.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, rows.Err()
+}
+
+func (r *PaymentRepository) SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO merchant_webhook_deliveries
+			(id, webhook_id, event_id, event_type, payment_id, url, attempt,
+			 status_code, error, succeeded, duration_ms, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.WebhookID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.PaymentID,
+		delivery.URL,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Succeeded,
+		delivery.DurationMS,
+		delivery.CreatedAt,
+	)
+	return err
+}
+
+func (r *PaymentRepository) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	args := []interface{}{filter.Limit}
+	var conditions []string
+	if filter.WebhookID != "" {
+		args = append(args, filter.WebhookID)
+		conditions = append(conditions, fmt.Sprintf(`webhook_id = $%d`, len(args)))
+	}
+	if filter.PaymentID != "" {
+		args = append(args, filter.PaymentID)
+		conditions = append(conditions, fmt.Sprintf(`payment_id = $%d`, len(args)))
+	}
+
+	var b strings.Builder
+	b.WriteString(`
+		SELECT id, webhook_id, event_id, event_type, COALESCE(payment_id, ''), url, attempt,
+			COALESCE(status_code, 0), COALESCE(error, ''), succeeded, duration_ms, created_at
+		FROM merchant_webhook_deliveries`)
+	if len(conditions) > 0 {
+		b.WriteString(` WHERE ` + strings.Join(conditions, ` AND `))
+	}
+	b.WriteString(` ORDER BY created_at DESC LIMIT $1`)
+
+	rows, err := r.db.QueryContext(ctx, b.String(), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		delivery := &models.WebhookDelivery{}
+		if err := rows.Scan(
+			&delivery.ID,
+			&delivery.WebhookID,
+			&delivery.EventID,
+			&delivery.EventType,
+			&delivery.PaymentID,
+			&delivery.URL,
+			&delivery.Attempt,
+			&delivery.StatusCode,
+			&delivery.Error,
+			&delivery.Succeeded,
+			&delivery.DurationMS,
+			&delivery.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, rows.Err()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"payment-gateway/internal/models"
+)
+
+// CreateEvent appends a status transition to a payment's timeline
+func (r *PaymentRepository) CreateEvent(ctx context.Context, event *models.PaymentEvent) error {
+	query := `
+		INSERT INTO payment_events (
+			id, payment_id, old_status, new_status, actor, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		event.ID,
+		event.PaymentID,
+		event.OldStatus,
+		event.NewStatus,
+		event.Actor,
+		event.Reason,
+		event.CreatedAt,
+	)
+
+	return err
+}
+
+// ListEvents returns a payment's timeline, oldest first
+func (r *PaymentRepository) ListEvents(ctx context.Context, paymentID string) ([]*models.PaymentEvent, error) {
+	query := `
+		SELECT id, payment_id, COALESCE(old_status, ''), new_status, actor,
+			   COALESCE(reason, ''), created_at
+		FROM payment_events
+		WHERE payment_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.PaymentEvent{}
+	for rows.Next() {
+		event := &models.PaymentEvent{}
+		if err := rows.Scan(
+			&event.ID,
+			&event.PaymentID,
+			&event.OldStatus,
+			&event.NewStatus,
+			&event.Actor,
+			&event.Reason,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
@@ -20,17 +20,23 @@ func NewPaymentRepository(db *sql.DB) *PaymentRepository {
 func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
 	query := `
 		INSERT INTO payments (
-			id, amount, currency, status, card_last4, card_network,
-			customer_email, description, stripe_payment_intent_id,
-			client_secret, requires_3ds, idempotency_key, created_at, updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			id, amount, currency, authorized_amount, captured_amount, status, mode,
+			card_last4, card_network, customer_email, description,
+			stripe_payment_intent_id, client_secret, requires_3ds, redirect_url,
+			idempotency_key, failure_reason, fraud_check_id, fraud_decision, fraud_score,
+			created_at, updated_at, settlement_amount, settlement_currency, settlement_rate, merchant_id, return_url
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22,
+			NULLIF($23::DECIMAL, 0), NULLIF($24, ''), NULLIF($25::DECIMAL, 0), NULLIF($26, ''), NULLIF($27, ''))
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		payment.ID,
 		payment.Amount,
 		payment.Currency,
+		payment.AuthorizedAmount,
+		payment.CapturedAmount,
 		payment.Status,
+		payment.Mode,
 		payment.CardLast4,
 		payment.CardNetwork,
 		payment.CustomerEmail,
@@ -38,28 +44,55 @@ func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment)
 		payment.StripePaymentIntentID,
 		payment.ClientSecret,
 		payment.Requires3DS,
+		payment.RedirectURL,
 		payment.IdempotencyKey,
+		payment.FailureReason,
+		payment.FraudCheckID,
+		payment.FraudDecision,
+		payment.FraudScore,
 		payment.CreatedAt,
 		payment.UpdatedAt,
+		payment.SettlementAmount,
+		payment.SettlementCurrency,
+		payment.SettlementRate,
+		payment.MerchantID,
+		payment.ReturnURL,
 	)
 
 	return err
 }
 
-func (r *PaymentRepository) GetByID(ctx context.Context, id string) (*models.Payment, error) {
-	query := `
-		SELECT id, amount, currency, status, card_last4, card_network,
-			   customer_email, description, stripe_payment_intent_id,
-			   client_secret, requires_3ds, created_at, updated_at
-		FROM payments WHERE id = $1
-	`
+// paymentColumns is the column list scanPayment expects
+const paymentColumns = `
+	id, amount, currency, authorized_amount, captured_amount, refunded_amount,
+	fee_amount, net_amount, COALESCE(fee_currency, ''), status, mode,
+	card_last4, card_network, customer_email, description,
+	stripe_payment_intent_id, client_secret, requires_3ds,
+	COALESCE(redirect_url, ''), COALESCE(failure_reason, ''),
+	COALESCE(fraud_check_id, ''), COALESCE(fraud_decision, ''), fraud_score,
+	created_at, updated_at, archived_at,
+	COALESCE(settlement_amount, 0), COALESCE(settlement_currency, ''), COALESCE(settlement_rate, 0),
+	COALESCE(merchant_id, ''), COALESCE(return_url, '')
+`
 
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanPayment(row rowScanner) (*models.Payment, error) {
 	payment := &models.Payment{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := row.Scan(
 		&payment.ID,
 		&payment.Amount,
 		&payment.Currency,
+		&payment.AuthorizedAmount,
+		&payment.CapturedAmount,
+		&payment.RefundedAmount,
+		&payment.FeeAmount,
+		&payment.NetAmount,
+		&payment.FeeCurrency,
 		&payment.Status,
+		&payment.Mode,
 		&payment.CardLast4,
 		&payment.CardNetwork,
 		&payment.CustomerEmail,
@@ -67,10 +100,39 @@ func (r *PaymentRepository) GetByID(ctx context.Context, id string) (*models.Pay
 		&payment.StripePaymentIntentID,
 		&payment.ClientSecret,
 		&payment.Requires3DS,
+		&payment.RedirectURL,
+		&payment.FailureReason,
+		&payment.FraudCheckID,
+		&payment.FraudDecision,
+		&payment.FraudScore,
 		&payment.CreatedAt,
 		&payment.UpdatedAt,
+		&payment.ArchivedAt,
+		&payment.SettlementAmount,
+		&payment.SettlementCurrency,
+		&payment.SettlementRate,
+		&payment.MerchantID,
+		&payment.ReturnURL,
 	)
+	return payment, err
+}
 
+func (r *PaymentRepository) GetByID(ctx context.Context, id string) (*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE id = $1`
+
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return payment, err
+}
+
+// GetByIdempotencyKey looks a payment up by its unique idempotency key
+func (r *PaymentRepository) GetByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments WHERE idempotency_key = $1`
+
+	payment, err := scanPayment(r.db.QueryRowContext(ctx, query, key))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -81,16 +143,95 @@ func (r *PaymentRepository) GetByID(ctx context.Context, id string) (*models.Pay
 func (r *PaymentRepository) Update(ctx context.Context, payment *models.Payment) error {
 	query := `
 		UPDATE payments
-		SET status = $1, updated_at = $2, completed_at = $3
-		WHERE id = $4
+		SET status = $1, updated_at = $2, completed_at = $3, failure_reason = $4,
+			requires_3ds = $5, redirect_url = $6, authorized_amount = $7,
+			captured_amount = $8, fee_amount = $9, net_amount = $10, fee_currency = $11
+		WHERE id = $12
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
 		payment.Status,
 		payment.UpdatedAt,
 		payment.CompletedAt,
+		payment.FailureReason,
+		payment.Requires3DS,
+		payment.RedirectURL,
+		payment.AuthorizedAmount,
+		payment.CapturedAmount,
+		payment.FeeAmount,
+		payment.NetAmount,
+		payment.FeeCurrency,
 		payment.ID,
 	)
 
 	return err
+}
+
+func (r *PaymentRepository) GetByStripeIntentID(ctx context.Context, intentID string) (*models.Payment, error) {
+	query := `SELECT id FROM payments WHERE stripe_payment_intent_id = $1`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, intentID).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// MarkEventProcessed records a webhook event and its payload, returning false if
+// the event was already recorded
+func (r *PaymentRepository) MarkEventProcessed(ctx context.Context, eventID, eventType string, payload []byte) (bool, error) {
+	query := `
+		INSERT INTO webhook_events (id, type, payload, processed_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (id) DO NOTHING
+	`
+
+	storedPayload := sql.NullString{String: string(payload), Valid: len(payload) > 0}
+	result, err := r.db.ExecContext(ctx, query, eventID, eventType, storedPayload)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// UnmarkEvent removes a webhook event record so a failed event can be retried
+func (r *PaymentRepository) UnmarkEvent(ctx context.Context, eventID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM webhook_events WHERE id = $1`, eventID)
+	return err
+}
+
+// GetWebhookEvent returns a recorded webhook event, or nil if it was never recorded.
+// Events recorded before payloads were stored have an empty payload.
+func (r *PaymentRepository) GetWebhookEvent(ctx context.Context, eventID string) (*models.WebhookEvent, error) {
+	query := `
+		SELECT id, type, COALESCE(payload::TEXT, ''), processed_at
+		FROM webhook_events
+		WHERE id = $1
+	`
+
+	event := &models.WebhookEvent{}
+	var payload string
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&event.ID, &event.Type, &payload, &event.ProcessedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if payload != "" {
+		event.Payload = []byte(payload)
+	}
+	return event, nil
 }
\ No newline at end of file
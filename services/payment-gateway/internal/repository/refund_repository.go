@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// CreateRefund saves a pending refund and adds its amount to the payment's refunded
+// amount in one transaction. It returns false, saving nothing, when the payment has
+// not succeeded, when refunds would then exceed what it settled, or when a refund
+// with the same idempotency key already exists.
+func (r *PaymentRepository) CreateRefund(ctx context.Context, refund *models.Refund) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE payments
+		SET refunded_amount = refunded_amount + $1, updated_at = $2
+		WHERE id = $3 AND status = $4
+		  AND refunded_amount + $1 <= CASE WHEN captured_amount > 0 THEN captured_amount ELSE amount END
+	`, refund.Amount, refund.CreatedAt, refund.PaymentID, models.PaymentStatusSucceeded)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows != 1 {
+		return false, err
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		INSERT INTO refunds (
+			id, payment_id, amount, currency, reason, status, idempotency_key, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $8)
+		ON CONFLICT (payment_id, idempotency_key) DO NOTHING
+	`,
+		refund.ID,
+		refund.PaymentID,
+		refund.Amount,
+		refund.Currency,
+		refund.Reason,
+		refund.Status,
+		refund.IdempotencyKey,
+		refund.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+	if rows, err := result.RowsAffected(); err != nil || rows != 1 {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
+
+// GetRefundByIdempotencyKey returns the refund of a payment made with key, or nil
+func (r *PaymentRepository) GetRefundByIdempotencyKey(ctx context.Context, paymentID, key string) (*models.Refund, error) {
+	query := `
+		SELECT id, payment_id, amount, currency, reason, status,
+			   COALESCE(stripe_refund_id, ''), COALESCE(idempotency_key, ''), created_at, updated_at
+		FROM refunds
+		WHERE payment_id = $1 AND idempotency_key = $2
+	`
+
+	refund := &models.Refund{}
+	err := r.db.QueryRowContext(ctx, query, paymentID, key).Scan(
+		&refund.ID,
+		&refund.PaymentID,
+		&refund.Amount,
+		&refund.Currency,
+		&refund.Reason,
+		&refund.Status,
+		&refund.StripeRefundID,
+		&refund.IdempotencyKey,
+		&refund.CreatedAt,
+		&refund.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return refund, err
+}
+
+// CompleteRefund marks a pending refund as accepted by Stripe
+func (r *PaymentRepository) CompleteRefund(ctx context.Context, refundID, stripeRefundID string) error {
+	query := `
+		UPDATE refunds
+		SET status = $1, stripe_refund_id = $2, updated_at = $3
+		WHERE id = $4 AND status = $5
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		models.RefundStatusSucceeded, stripeRefundID, time.Now(), refundID, models.RefundStatusPending)
+	return err
+}
+
+// FailRefund marks a pending refund as declined and releases its amount from the
+// payment's refunded amount, in one transaction
+func (r *PaymentRepository) FailRefund(ctx context.Context, refund *models.Refund) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE refunds SET status = $1, updated_at = $2 WHERE id = $3 AND status = $4
+	`, models.RefundStatusFailed, time.Now(), refund.ID, models.RefundStatusPending)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		// Already settled one way or the other
+		return nil
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE payments SET refunded_amount = refunded_amount - $1 WHERE id = $2
+	`, refund.Amount, refund.PaymentID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
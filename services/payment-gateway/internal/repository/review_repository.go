@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"payment-gateway/internal/models"
+)
+
+const reviewColumns = `
+	id, payment_id, status, COALESCE(reason, ''), fraud_score,
+	COALESCE(reviewer, ''), COALESCE(notes, ''), created_at, resolved_at
+`
+
+func scanReview(row rowScanner) (*models.ReviewItem, error) {
+	item := &models.ReviewItem{}
+	err := row.Scan(
+		&item.ID,
+		&item.PaymentID,
+		&item.Status,
+		&item.Reason,
+		&item.FraudScore,
+		&item.Reviewer,
+		&item.Notes,
+		&item.CreatedAt,
+		&item.ResolvedAt,
+	)
+	return item, err
+}
+
+func (r *PaymentRepository) EnqueueReview(ctx context.Context, item *models.ReviewItem) error {
+	query := `
+		INSERT INTO review_queue (id, payment_id, status, reason, fraud_score, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (payment_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		item.ID,
+		item.PaymentID,
+		item.Status,
+		item.Reason,
+		item.FraudScore,
+		item.CreatedAt,
+	)
+
+	return err
+}
+
+func (r *PaymentRepository) GetReview(ctx context.Context, paymentID string) (*models.ReviewItem, error) {
+	query := `SELECT ` + reviewColumns + ` FROM review_queue WHERE payment_id = $1`
+
+	item, err := scanReview(r.db.QueryRowContext(ctx, query, paymentID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return item, err
+}
+
+// ListPendingReviews returns unresolved reviews, oldest first
+func (r *PaymentRepository) ListPendingReviews(ctx context.Context, limit int) ([]*models.ReviewItem, error) {
+	query := `
+		SELECT ` + reviewColumns + ` FROM review_queue
+		WHERE status = $1
+		ORDER BY created_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.ReviewStatusPending, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*models.ReviewItem{}
+	for rows.Next() {
+		item, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// ResolveReview records a reviewer's decision, returning false if the review
+// was no longer pending
+func (r *PaymentRepository) ResolveReview(ctx context.Context, item *models.ReviewItem) (bool, error) {
+	query := `
+		UPDATE review_queue
+		SET status = $1, reviewer = $2, notes = $3, resolved_at = $4
+		WHERE payment_id = $5 AND status = $6
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		item.Status,
+		item.Reviewer,
+		item.Notes,
+		item.ResolvedAt,
+		item.PaymentID,
+		models.ReviewStatusPending,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
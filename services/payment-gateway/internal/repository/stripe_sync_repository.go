@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// ListFinalUpdatedSince returns finished Stripe payments updated at or after since, oldest first
+func (r *PaymentRepository) ListFinalUpdatedSince(ctx context.Context, since time.Time, limit int) ([]*models.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payments
+		WHERE updated_at >= $1
+			AND stripe_payment_intent_id IS NOT NULL AND stripe_payment_intent_id <> ''
+			AND status IN ($2, $3, $4)
+		ORDER BY updated_at
+		LIMIT $5`
+
+	rows, err := r.db.QueryContext(ctx, query, since,
+		models.PaymentStatusSucceeded, models.PaymentStatusFailed, models.PaymentStatusCancelled, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*models.Payment{}
+	for rows.Next() {
+		payment, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
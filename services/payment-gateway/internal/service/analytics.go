@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"payment-gateway/internal/models"
+)
+
+// maxAnalyticsDays bounds the range a single analytics query may scan
+const maxAnalyticsDays = 366
+
+var (
+	ErrInvalidAnalyticsGroupBy = errors.New("group_by must be day, currency or status")
+	ErrInvalidAnalyticsRange   = fmt.Errorf("from must be before to and the range at most %d days", maxAnalyticsDays)
+)
+
+// PaymentAnalytics buckets the payments created in [filter.From, filter.To) by day,
+// currency or status. An empty GroupBy buckets by day.
+func (s *PaymentService) PaymentAnalytics(ctx context.Context, filter models.PaymentAnalyticsFilter) (*models.PaymentAnalytics, error) {
+	switch filter.GroupBy {
+	case "":
+		filter.GroupBy = models.AnalyticsGroupByDay
+	case models.AnalyticsGroupByDay, models.AnalyticsGroupByCurrency, models.AnalyticsGroupByStatus:
+	default:
+		return nil, ErrInvalidAnalyticsGroupBy
+	}
+	if !filter.From.Before(filter.To) || filter.To.After(filter.From.AddDate(0, 0, maxAnalyticsDays)) {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	buckets, err := s.repo.PaymentAnalytics(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query payment analytics: %w", err)
+	}
+
+	return &models.PaymentAnalytics{
+		From:    filter.From,
+		To:      filter.To,
+		GroupBy: filter.GroupBy,
+		Buckets: buckets,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+func TestPaymentAnalyticsDailyBuckets(t *testing.T) {
+	store := newMockStore()
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	for i, p := range []struct {
+		at       time.Time
+		amount   float64
+		currency string
+	}{
+		{day.Add(9 * time.Hour), 100, "USD"},
+		{day.Add(23 * time.Hour), 25.5, "USD"},
+		{day.Add(12 * time.Hour), 40, "EUR"},
+		{day.AddDate(0, 0, 1).Add(time.Hour), 10, "USD"},
+		{day.AddDate(0, 0, 1).Add(2 * time.Hour), 15.25, "USD"},
+		// Outside the range
+		{day.AddDate(0, 0, -1), 999, "USD"},
+		{day.AddDate(0, 0, 2), 999, "USD"},
+	} {
+		id := fmt.Sprintf("pay_%d", i)
+		store.payments[id] = &models.Payment{ID: id, Amount: p.amount, Currency: p.currency, Status: models.PaymentStatusSucceeded, CreatedAt: p.at}
+	}
+
+	s := &PaymentService{repo: store}
+	analytics, err := s.PaymentAnalytics(context.Background(), models.PaymentAnalyticsFilter{From: day, To: day.AddDate(0, 0, 2)})
+	if err != nil {
+		t.Fatalf("PaymentAnalytics() error = %v", err)
+	}
+
+	if analytics.GroupBy != models.AnalyticsGroupByDay {
+		t.Errorf("GroupBy = %q, want day by default", analytics.GroupBy)
+	}
+	want := []models.PaymentAnalyticsBucket{
+		{Key: "2024-03-10", Currency: "EUR", Count: 1, TotalAmount: 40},
+		{Key: "2024-03-10", Currency: "USD", Count: 2, TotalAmount: 125.5},
+		{Key: "2024-03-11", Currency: "USD", Count: 2, TotalAmount: 25.25},
+	}
+	if len(analytics.Buckets) != len(want) {
+		t.Fatalf("got %d buckets %+v, want %d", len(analytics.Buckets), analytics.Buckets, len(want))
+	}
+	for i := range want {
+		if analytics.Buckets[i] != want[i] {
+			t.Errorf("bucket %d = %+v, want %+v", i, analytics.Buckets[i], want[i])
+		}
+	}
+}
+
+func TestPaymentAnalyticsValidation(t *testing.T) {
+	from := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter models.PaymentAnalyticsFilter
+		want   error
+	}{
+		{"Unknown grouping", models.PaymentAnalyticsFilter{From: from, To: from.AddDate(0, 0, 1), GroupBy: "customer"}, ErrInvalidAnalyticsGroupBy},
+		{"Empty range", models.PaymentAnalyticsFilter{From: from, To: from}, ErrInvalidAnalyticsRange},
+		{"Range too long", models.PaymentAnalyticsFilter{From: from, To: from.AddDate(0, 0, maxAnalyticsDays+1)}, ErrInvalidAnalyticsRange},
+		{"Longest range", models.PaymentAnalyticsFilter{From: from, To: from.AddDate(0, 0, maxAnalyticsDays), GroupBy: models.AnalyticsGroupByStatus}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PaymentService{repo: newMockStore()}
+			if _, err := s.PaymentAnalytics(context.Background(), tt.filter); !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/pagination"
+)
+
+var (
+	ErrPaymentNotArchivable = errors.New("only succeeded, failed or cancelled payments can be archived")
+	ErrInvalidPaymentSort   = errors.New("sort must be created_at or amount and order must be asc or desc")
+)
+
+// ListPayments lists a page of payments, excluding archived ones unless the filter includes them
+func (s *PaymentService) ListPayments(ctx context.Context, filter models.PaymentListFilter) (pagination.Page[*models.Payment], error) {
+	switch filter.Sort {
+	case "", models.PaymentSortCreatedAt, models.PaymentSortAmount:
+	default:
+		return pagination.Page[*models.Payment]{}, ErrInvalidPaymentSort
+	}
+	switch filter.Order {
+	case "", models.SortOrderAsc, models.SortOrderDesc:
+	default:
+		return pagination.Page[*models.Payment]{}, ErrInvalidPaymentSort
+	}
+
+	payments, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return pagination.Page[*models.Payment]{}, err
+	}
+	total, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return pagination.Page[*models.Payment]{}, err
+	}
+
+	return pagination.NewPage(payments, total, filter.Limit, filter.Offset), nil
+}
+
+// ArchivePayment hides a finished payment from default listings
+func (s *PaymentService) ArchivePayment(ctx context.Context, paymentID string) (*models.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.ArchivedAt != nil {
+		return payment, nil
+	}
+	if !payment.IsFinal() {
+		return nil, ErrPaymentNotArchivable
+	}
+
+	now := time.Now()
+	if err := s.repo.Archive(ctx, paymentID, now); err != nil {
+		return nil, fmt.Errorf("failed to archive payment: %w", err)
+	}
+	payment.ArchivedAt = &now
+
+	return payment, nil
+}
+
+// ArchiveExpiredPayments archives finished payments created more than retention ago
+func (s *PaymentService) ArchiveExpiredPayments(ctx context.Context, retention time.Duration) (int64, error) {
+	now := time.Now()
+	return s.repo.ArchiveOlderThan(ctx, now.Add(-retention), now)
+}
+
+// RunArchiver archives expired payments every interval until ctx is cancelled
+func (s *PaymentService) RunArchiver(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		archived, err := s.ArchiveExpiredPayments(ctx, retention)
+		if err != nil {
+			fmt.Printf("Failed to archive expired payments: %v\n", err)
+		} else if archived > 0 {
+			fmt.Printf("Archived %d payments older than %s\n", archived, retention)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
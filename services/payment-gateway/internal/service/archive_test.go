@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+func TestArchivedPaymentsHiddenByDefault(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	store.payments["pay_old"] = &models.Payment{ID: "pay_old", Status: models.PaymentStatusSucceeded, CreatedAt: time.Now().AddDate(0, 0, -120)}
+	store.payments["pay_pending"] = &models.Payment{ID: "pay_pending", Status: models.PaymentStatusPending, CreatedAt: time.Now().AddDate(0, 0, -120)}
+	store.payments["pay_recent"] = &models.Payment{ID: "pay_recent", Status: models.PaymentStatusSucceeded, CreatedAt: time.Now()}
+	s := &PaymentService{repo: store}
+
+	archived, err := s.ArchiveExpiredPayments(ctx, 90*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if archived != 1 {
+		t.Errorf("archived %d payments, want 1", archived)
+	}
+
+	visible, err := s.ListPayments(ctx, models.PaymentListFilter{Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(visible.Items) != 2 || visible.Total != 2 {
+		t.Fatalf("listed %d of %d payments, want 2 of 2", len(visible.Items), visible.Total)
+	}
+	for _, payment := range visible.Items {
+		if payment.ID == "pay_old" {
+			t.Error("archived payment listed by default")
+		}
+	}
+
+	all, err := s.ListPayments(ctx, models.PaymentListFilter{IncludeArchived: true, Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all.Items) != 3 {
+		t.Errorf("listed %d payments with include_archived, want 3", len(all.Items))
+	}
+
+	// Archived payments stay reachable by ID
+	payment, err := s.GetPayment(ctx, "pay_old")
+	if err != nil || payment == nil || payment.ArchivedAt == nil {
+		t.Errorf("GetPayment() = %+v, %v; want archived payment", payment, err)
+	}
+}
+
+func TestArchivePayment(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  models.PaymentStatus
+		wantErr error
+	}{
+		{name: "Succeeded", status: models.PaymentStatusSucceeded},
+		{name: "Cancelled", status: models.PaymentStatusCancelled},
+		{name: "Still processing", status: models.PaymentStatusProcessing, wantErr: ErrPaymentNotArchivable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.payments["pay_1"] = &models.Payment{ID: "pay_1", Status: tt.status}
+			s := &PaymentService{repo: store}
+
+			_, err := s.ArchivePayment(context.Background(), "pay_1")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ArchivePayment() error = %v, want %v", err, tt.wantErr)
+			}
+			if archived := store.payments["pay_1"].ArchivedAt != nil; archived != (tt.wantErr == nil) {
+				t.Errorf("archived = %v, want %v", archived, tt.wantErr == nil)
+			}
+		})
+	}
+
+	s := &PaymentService{repo: newMockStore()}
+	if _, err := s.ArchivePayment(context.Background(), "pay_missing"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("ArchivePayment() for unknown payment error = %v, want %v", err, ErrPaymentNotFound)
+	}
+}
+
+func TestListPaymentsRejectsInvalidSort(t *testing.T) {
+	tests := []struct {
+		name        string
+		sort, order string
+		wantErr     error
+	}{
+		{"Default", "", "", nil},
+		{"Created at", models.PaymentSortCreatedAt, models.SortOrderAsc, nil},
+		{"Amount", models.PaymentSortAmount, models.SortOrderDesc, nil},
+		{"Unknown field", "customer_email", "", ErrInvalidPaymentSort},
+		{"Injected field", "amount; DROP TABLE payments", "", ErrInvalidPaymentSort},
+		{"Unknown order", models.PaymentSortAmount, "sideways", ErrInvalidPaymentSort},
+	}
+
+	s := &PaymentService{repo: newMockStore()}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.ListPayments(context.Background(), models.PaymentListFilter{Limit: 20, Sort: tt.sort, Order: tt.order})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ListPayments() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestListPaymentsByEmailPrefix(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{ID: "pay_1", CustomerEmail: "john.doe@example.com"}
+	store.payments["pay_2"] = &models.Payment{ID: "pay_2", CustomerEmail: "johanna@example.com"}
+	store.payments["pay_3"] = &models.Payment{ID: "pay_3", CustomerEmail: "mary.john@example.com"}
+	s := &PaymentService{repo: store}
+
+	payments, err := s.ListPayments(context.Background(), models.PaymentListFilter{EmailPrefix: "john", Limit: 20})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(payments.Items) != 1 || payments.Items[0].ID != "pay_1" {
+		t.Errorf("listed %+v, want only pay_1", payments.Items)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+// asyncPollBatchSize caps how many processing payments one poll checks with Stripe
+const asyncPollBatchSize = 500
+
+// asyncPaymentMethodTypes are charged in the background; bank debits take days to settle
+var asyncPaymentMethodTypes = []string{"card", "us_bank_account", "sepa_debit"}
+
+var ErrAsyncRequiresPaymentMethod = errors.New("async payments must be charged to a saved payment method")
+
+// GetPaymentStatus reports where a payment is, for clients polling an async payment
+func (s *PaymentService) GetPaymentStatus(ctx context.Context, paymentID string) (*models.PaymentStatusResponse, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	status := &models.PaymentStatusResponse{
+		PaymentID:     payment.ID,
+		Status:        payment.Status,
+		FailureReason: payment.FailureReason,
+		UpdatedAt:     payment.UpdatedAt,
+	}
+	if !payment.CompletedAt.IsZero() {
+		status.CompletedAt = &payment.CompletedAt
+	}
+	return status, nil
+}
+
+// PollProcessingPayments finalizes processing payments whose webhook has not arrived
+// after staleAfter, by asking Stripe for their payment intent. It returns how many
+// were finalized.
+func (s *PaymentService) PollProcessingPayments(ctx context.Context, staleAfter time.Duration) (int, error) {
+	payments, err := s.repo.ListProcessingUpdatedBefore(ctx, time.Now().Add(-staleAfter), asyncPollBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	finalized := 0
+	for _, payment := range payments {
+		done, err := s.finalizeProcessingPayment(ctx, payment)
+		if err != nil {
+			fmt.Printf("Failed to poll processing payment %s: %v\n", payment.ID, err)
+			continue
+		}
+		if done {
+			finalized++
+		}
+	}
+	return finalized, nil
+}
+
+// finalizeProcessingPayment moves a processing payment to the outcome Stripe
+// reports, returning false while Stripe is still processing it
+func (s *PaymentService) finalizeProcessingPayment(ctx context.Context, payment *models.Payment) (bool, error) {
+	intent, err := s.processor.GetPaymentIntent(payment.StripePaymentIntentID)
+	if err != nil {
+		return false, fmt.Errorf("failed to fetch payment intent: %w", err)
+	}
+
+	switch intent.Status {
+	case stripe.PaymentIntentStatusSucceeded:
+		if err := s.correctToSucceeded(ctx, payment, intent, "async payment settled"); err != nil {
+			return false, err
+		}
+	case stripe.PaymentIntentStatusRequiresPaymentMethod:
+		// Stripe returns a failed debit to requires_payment_method
+		if intent.LastPaymentError != nil {
+			payment.FailureReason = intent.LastPaymentError.Msg
+		}
+		if err := s.transition(ctx, payment, models.PaymentStatusFailed, actorStripeSync, "async payment failed"); err != nil {
+			return false, err
+		}
+		s.publishPaymentEvent(ctx, "payment.failed", payment)
+	case stripe.PaymentIntentStatusCanceled:
+		if err := s.transition(ctx, payment, models.PaymentStatusCancelled, actorStripeSync, "async payment cancelled"); err != nil {
+			return false, err
+		}
+		s.publishPaymentEvent(ctx, "payment.cancelled", payment)
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// RunAsyncPoller finalizes stale processing payments every interval until ctx is
+// cancelled. Webhooks normally finalize them first; this covers lost deliveries.
+func (s *PaymentService) RunAsyncPoller(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.PollProcessingPayments(ctx, interval); err != nil {
+				fmt.Printf("Failed to poll processing payments: %v\n", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func newAsyncPaymentService(status stripe.PaymentIntentStatus) (*PaymentService, *mockStore, *mockProcessor) {
+	store := newMockStore()
+	store.customers["cus_1"] = &models.Customer{ID: "cus_1", StripeCustomerID: "cus_stripe_1"}
+	store.methods["pm_1"] = &models.SavedPaymentMethod{ID: "pm_1", CustomerID: "cus_1", CardLast4: "6789"}
+	processor := &mockProcessor{intentStatus: status}
+	return &PaymentService{repo: store, processor: processor}, store, processor
+}
+
+func TestAsyncPaymentSettlesOnWebhook(t *testing.T) {
+	ctx := context.Background()
+	s, store, processor := newAsyncPaymentService(stripe.PaymentIntentStatusProcessing)
+
+	payment, err := s.CreatePayment(ctx, &models.PaymentRequest{
+		Amount:          25,
+		Currency:        "USD",
+		CustomerID:      "cus_1",
+		PaymentMethodID: "pm_1",
+		Async:           true,
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	if payment.Status != models.PaymentStatusProcessing {
+		t.Fatalf("status = %s, want %s", payment.Status, models.PaymentStatusProcessing)
+	}
+
+	params := processor.intentParams[0]
+	if params.Confirm == nil || !*params.Confirm {
+		t.Error("async payment intent should be confirmed on creation")
+	}
+
+	event := stripe.Event{
+		ID:   "evt_async",
+		Type: "payment_intent.succeeded",
+		Data: &stripe.EventData{
+			Raw: json.RawMessage(`{"id":"pi_test","object":"payment_intent","status":"succeeded","amount_received":2500}`),
+		},
+	}
+	if _, err := s.ProcessStripeEvent(ctx, event, nil); err != nil {
+		t.Fatalf("ProcessStripeEvent() error = %v", err)
+	}
+
+	status, err := s.GetPaymentStatus(ctx, payment.ID)
+	if err != nil {
+		t.Fatalf("GetPaymentStatus() error = %v", err)
+	}
+	if status.Status != models.PaymentStatusSucceeded || status.CompletedAt == nil {
+		t.Errorf("status = %+v, want succeeded with a completion time", status)
+	}
+	if got := store.payments[payment.ID].CapturedAmount; got != 25 {
+		t.Errorf("captured = %v, want 25", got)
+	}
+}
+
+func TestAsyncPaymentRequiresPaymentMethod(t *testing.T) {
+	s, _, processor := newAsyncPaymentService(stripe.PaymentIntentStatusProcessing)
+
+	_, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:   25,
+		Currency: "USD",
+		Async:    true,
+	})
+	if !errors.Is(err, ErrAsyncRequiresPaymentMethod) {
+		t.Errorf("error = %v, want %v", err, ErrAsyncRequiresPaymentMethod)
+	}
+	if len(processor.intentParams) != 0 {
+		t.Error("rejected payment reached Stripe")
+	}
+}
+
+func TestPollProcessingPaymentsFinalizesStale(t *testing.T) {
+	ctx := context.Background()
+	s, store, _ := newAsyncPaymentService(stripe.PaymentIntentStatusSucceeded)
+	store.payments["pay_stale"] = &models.Payment{
+		ID:                    "pay_stale",
+		Amount:                40,
+		Currency:              "USD",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_stale",
+		UpdatedAt:             time.Now().Add(-time.Hour),
+	}
+	store.payments["pay_fresh"] = &models.Payment{
+		ID:                    "pay_fresh",
+		Amount:                40,
+		Currency:              "USD",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_fresh",
+		UpdatedAt:             time.Now(),
+	}
+
+	finalized, err := s.PollProcessingPayments(ctx, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("PollProcessingPayments() error = %v", err)
+	}
+	if finalized != 1 {
+		t.Errorf("finalized = %d, want 1", finalized)
+	}
+	if got := store.payments["pay_stale"].Status; got != models.PaymentStatusSucceeded {
+		t.Errorf("stale status = %s, want %s", got, models.PaymentStatusSucceeded)
+	}
+	if got := store.payments["pay_fresh"].Status; got != models.PaymentStatusProcessing {
+		t.Errorf("fresh status = %s, want %s", got, models.PaymentStatusProcessing)
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"payment-gateway/internal/models"
+)
+
+var ErrBINNotFound = errors.New("BIN not found")
+
+// binLength is how many leading digits of a card number are sent for BIN lookup
+const binLength = 8
+
+// BINResolver resolves a BIN to its card range, returning ErrBINNotFound for unknown BINs
+type BINResolver interface {
+	ResolveBIN(ctx context.Context, bin string) (*models.BINInfo, error)
+}
+
+// BINLookup identifies a card's network and issuer from its BIN. Cards the resolver
+// doesn't know still get a network from DetectCardNetwork.
+type BINLookup struct {
+	resolver BINResolver
+}
+
+// NewBINLookup creates a lookup backed by resolver; a nil resolver only detects the network
+func NewBINLookup(resolver BINResolver) *BINLookup {
+	return &BINLookup{resolver: resolver}
+}
+
+// Lookup returns what is known about a card number's BIN
+func (l *BINLookup) Lookup(ctx context.Context, cardNumber string) *models.BINInfo {
+	info := &models.BINInfo{
+		BIN:     cardBIN(cardNumber),
+		Network: DetectCardNetwork(cardNumber),
+	}
+	if l == nil || l.resolver == nil || info.BIN == "" {
+		return info
+	}
+
+	resolved, err := l.resolver.ResolveBIN(ctx, info.BIN)
+	if err != nil {
+		if !errors.Is(err, ErrBINNotFound) {
+			fmt.Printf("BIN lookup failed for %s: %v\n", info.BIN, err)
+		}
+		return info
+	}
+
+	if resolved.Network != "" {
+		info.Network = resolved.Network
+	}
+	info.IssuerBank = resolved.IssuerBank
+	info.Country = resolved.Country
+	info.CardType = resolved.CardType
+	return info
+}
+
+// cardBIN returns the leading digits of a card number used for BIN lookup
+func cardBIN(cardNumber string) string {
+	if len(cardNumber) < binLength {
+		return ""
+	}
+	return cardNumber[:binLength]
+}
+
+// BINTable is an in-memory BIN database. Each entry's BIN is a prefix of any
+// length; a lookup matches the longest prefix.
+type BINTable struct {
+	entries   map[string]models.BINInfo
+	maxPrefix int
+}
+
+// NewBINTable creates a BIN database from its entries
+func NewBINTable(entries []models.BINInfo) *BINTable {
+	table := &BINTable{entries: make(map[string]models.BINInfo, len(entries))}
+	for _, entry := range entries {
+		table.entries[entry.BIN] = entry
+		if len(entry.BIN) > table.maxPrefix {
+			table.maxPrefix = len(entry.BIN)
+		}
+	}
+	return table
+}
+
+// LoadBINTable reads a BIN database from a JSON array of entries
+func LoadBINTable(path string) (*BINTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read BIN database: %w", err)
+	}
+
+	var entries []models.BINInfo
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse BIN database: %w", err)
+	}
+
+	return NewBINTable(entries), nil
+}
+
+// ResolveBIN returns the entry with the longest prefix of bin
+func (t *BINTable) ResolveBIN(ctx context.Context, bin string) (*models.BINInfo, error) {
+	n := len(bin)
+	if n > t.maxPrefix {
+		n = t.maxPrefix
+	}
+
+	for ; n > 0; n-- {
+		if entry, ok := t.entries[bin[:n]]; ok {
+			return &entry, nil
+		}
+	}
+	return nil, ErrBINNotFound
+}
+
+// SetBINResolver replaces the BIN database cards are looked up in
+func (s *PaymentService) SetBINResolver(resolver BINResolver) {
+	s.bins = NewBINLookup(resolver)
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+// failingBINResolver is a BINResolver whose database is unreachable
+type failingBINResolver struct{}
+
+func (failingBINResolver) ResolveBIN(ctx context.Context, bin string) (*models.BINInfo, error) {
+	return nil, errors.New("connection refused")
+}
+
+func newTestBINTable() *BINTable {
+	return NewBINTable([]models.BINInfo{
+		{BIN: "424242", Network: "visa", IssuerBank: "Stripe Test Bank", Country: "US", CardType: models.CardTypeCredit},
+		{BIN: "400005", Network: "visa", IssuerBank: "Stripe Test Bank", Country: "US", CardType: models.CardTypeDebit},
+		{BIN: "4000", Network: "visa", Country: "US"},
+		{BIN: "40000007", Network: "visa", IssuerBank: "Banco Teste", Country: "BR", CardType: models.CardTypeCredit},
+		{BIN: "222300", Network: "mastercard", IssuerBank: "Example Bank", Country: "GB", CardType: models.CardTypeDebit},
+	})
+}
+
+func TestBINLookup(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolver   BINResolver
+		cardNumber string
+		want       models.BINInfo
+	}{
+		{
+			name:       "Visa credit",
+			resolver:   newTestBINTable(),
+			cardNumber: "4242424242424242",
+			want:       models.BINInfo{BIN: "42424242", Network: "visa", IssuerBank: "Stripe Test Bank", Country: "US", CardType: models.CardTypeCredit},
+		},
+		{
+			name:       "Visa debit",
+			resolver:   newTestBINTable(),
+			cardNumber: "4000056655665556",
+			want:       models.BINInfo{BIN: "40000566", Network: "visa", IssuerBank: "Stripe Test Bank", Country: "US", CardType: models.CardTypeDebit},
+		},
+		{
+			name:       "Longest prefix wins",
+			resolver:   newTestBINTable(),
+			cardNumber: "4000000760000002",
+			want:       models.BINInfo{BIN: "40000007", Network: "visa", IssuerBank: "Banco Teste", Country: "BR", CardType: models.CardTypeCredit},
+		},
+		{
+			name:       "Mastercard 2-series",
+			resolver:   newTestBINTable(),
+			cardNumber: "2223003122003222",
+			want:       models.BINInfo{BIN: "22230031", Network: "mastercard", IssuerBank: "Example Bank", Country: "GB", CardType: models.CardTypeDebit},
+		},
+		{
+			name:       "Unknown BIN falls back to network detection",
+			resolver:   newTestBINTable(),
+			cardNumber: "5555555555554444",
+			want:       models.BINInfo{BIN: "55555555", Network: "mastercard"},
+		},
+		{
+			name:       "Resolver failure falls back to network detection",
+			resolver:   failingBINResolver{},
+			cardNumber: "4242424242424242",
+			want:       models.BINInfo{BIN: "42424242", Network: "visa"},
+		},
+		{
+			name:       "No resolver",
+			cardNumber: "378282246310005",
+			want:       models.BINInfo{BIN: "37828224", Network: "amex"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewBINLookup(tt.resolver).Lookup(context.Background(), tt.cardNumber)
+			if *got != tt.want {
+				t.Errorf("Lookup() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreatePaymentSendsIssuerCountryToFraudCheck(t *testing.T) {
+	fraud := &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionApprove}}
+	s := &PaymentService{repo: newMockStore(), processor: &mockProcessor{}, fraud: fraud}
+	s.SetBINResolver(newTestBINTable())
+
+	payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:        100,
+		Currency:      "USD",
+		CardNumber:    "4000000760000002",
+		CustomerEmail: "customer@example.com",
+		Country:       "US",
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	if payment.CardNetwork != "visa" {
+		t.Errorf("CardNetwork = %q, want visa", payment.CardNetwork)
+	}
+	if len(fraud.checks) != 1 {
+		t.Fatalf("sent %d fraud checks, want 1", len(fraud.checks))
+	}
+	check := fraud.checks[0]
+	if check.Country != "US" || check.IssuerCountry != "BR" {
+		t.Errorf("fraud check country = %q, issuer country = %q, want US and BR", check.Country, check.IssuerCountry)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestApplyCapture(t *testing.T) {
+	tests := []struct {
+		name         string
+		authorized   float64
+		capture      float64
+		wantErr      error
+		wantCaptured float64
+		wantReleased float64
+	}{
+		{
+			name:         "Full capture",
+			authorized:   100.00,
+			capture:      100.00,
+			wantCaptured: 100.00,
+			wantReleased: 0,
+		},
+		{
+			name:         "Partial capture",
+			authorized:   100.00,
+			capture:      60.00,
+			wantCaptured: 60.00,
+			wantReleased: 40.00,
+		},
+		{
+			name:       "Over capture",
+			authorized: 100.00,
+			capture:    100.01,
+			wantErr:    ErrInvalidCaptureAmount,
+		},
+		{
+			name:       "Negative capture",
+			authorized: 100.00,
+			capture:    -5,
+			wantErr:    ErrInvalidCaptureAmount,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payment := &models.Payment{
+				Amount:           tt.authorized,
+				AuthorizedAmount: tt.authorized,
+				Status:           models.PaymentStatusRequiresCapture,
+			}
+
+			err := applyCapture(payment, tt.capture)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("applyCapture() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if payment.CapturedAmount != 0 {
+					t.Errorf("CapturedAmount = %v after rejected capture", payment.CapturedAmount)
+				}
+				return
+			}
+
+			if payment.CapturedAmount != tt.wantCaptured {
+				t.Errorf("CapturedAmount = %v, want %v", payment.CapturedAmount, tt.wantCaptured)
+			}
+			if got := payment.ReleasedAmount(); got != tt.wantReleased {
+				t.Errorf("ReleasedAmount() = %v, want %v", got, tt.wantReleased)
+			}
+		})
+	}
+}
+
+func TestToStripeAmount(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		currency string
+		want     int64
+	}{
+		{10.00, "USD", 1000},
+		{19.99, "USD", 1999},
+		{0.29, "EUR", 29},
+		{1000, "JPY", 1000},
+		{1000, "krw", 1000},
+		{12.345, "KWD", 12350},
+	}
+
+	for _, tt := range tests {
+		if got := toStripeAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("toStripeAmount(%v, %s) = %d, want %d", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
+
+func TestFromStripeAmount(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		currency string
+		want     float64
+	}{
+		{1000, "USD", 10},
+		{1000, "JPY", 1000},
+		{12350, "KWD", 12.35},
+	}
+
+	for _, tt := range tests {
+		if got := fromStripeAmount(tt.amount, tt.currency); got != tt.want {
+			t.Errorf("fromStripeAmount(%d, %s) = %v, want %v", tt.amount, tt.currency, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// CurrencyConverter looks up exchange rates between currencies
+type CurrencyConverter interface {
+	GetRate(ctx context.Context, from, to string) (float64, error)
+}
+
+// CurrencyClient calls the currency-conversion service over HTTP
+type CurrencyClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewCurrencyClient creates a client for the currency-conversion service
+func NewCurrencyClient(baseURL string) *CurrencyClient {
+	return &CurrencyClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// GetRate returns the exchange rate from one currency to another
+func (c *CurrencyClient) GetRate(ctx context.Context, from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+
+	url := fmt.Sprintf("%s/api/v1/currency/rates/%s/%s", c.baseURL, from, to)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("currency service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("currency service returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Rate struct {
+			Rate float64 `json:"rate"`
+		} `json:"rate"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return 0, fmt.Errorf("failed to parse currency service response: %w", err)
+	}
+
+	return body.Rate.Rate, nil
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+var (
+	ErrCustomerNotFound      = errors.New("customer not found")
+	ErrPaymentMethodNotFound = errors.New("payment method not found for customer")
+)
+
+// chargeSource describes what a payment is charged against
+type chargeSource struct {
+	CardLast4        string
+	CardNetwork      string
+	IssuerCountry    string
+	StripeCustomerID string
+	PaymentMethodID  string
+}
+
+// CreateCustomer creates a Stripe customer and stores its ID for later charges
+func (s *PaymentService) CreateCustomer(ctx context.Context, req *models.CustomerRequest) (*models.Customer, error) {
+	params := &stripe.CustomerParams{
+		Email: stripe.String(req.Email),
+	}
+	if req.Name != "" {
+		params.Name = stripe.String(req.Name)
+	}
+
+	stripeCustomer, err := s.processor.CreateCustomer(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Stripe customer: %w", err)
+	}
+
+	customer := &models.Customer{
+		ID:               ids.WithPrefix(ids.PrefixCustomer),
+		Email:            req.Email,
+		Name:             req.Name,
+		StripeCustomerID: stripeCustomer.ID,
+		CreatedAt:        time.Now(),
+	}
+
+	if err := s.repo.CreateCustomer(ctx, customer); err != nil {
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+// GetCustomer returns a stored customer
+func (s *PaymentService) GetCustomer(ctx context.Context, customerID string) (*models.Customer, error) {
+	customer, err := s.repo.GetCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	return customer, nil
+}
+
+// AttachPaymentMethod attaches a Stripe payment method to a customer so it can be charged later
+func (s *PaymentService) AttachPaymentMethod(ctx context.Context, customerID, paymentMethodID string) (*models.SavedPaymentMethod, error) {
+	customer, err := s.GetCustomer(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pm, err := s.processor.AttachPaymentMethod(paymentMethodID, &stripe.PaymentMethodAttachParams{
+		Customer: stripe.String(customer.StripeCustomerID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to attach payment method: %w", err)
+	}
+
+	method := &models.SavedPaymentMethod{
+		ID:         pm.ID,
+		CustomerID: customer.ID,
+		CreatedAt:  time.Now(),
+	}
+	if pm.Card != nil {
+		method.CardLast4 = pm.Card.Last4
+		method.CardNetwork = string(pm.Card.Brand)
+	}
+
+	if err := s.repo.SavePaymentMethod(ctx, method); err != nil {
+		return nil, fmt.Errorf("failed to save payment method: %w", err)
+	}
+
+	return method, nil
+}
+
+// savedChargeSource resolves a saved payment method, checking it belongs to the requesting customer
+func (s *PaymentService) savedChargeSource(ctx context.Context, req *models.PaymentRequest) (*chargeSource, error) {
+	customer, err := s.GetCustomer(ctx, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := s.repo.GetPaymentMethod(ctx, req.PaymentMethodID)
+	if err != nil {
+		return nil, err
+	}
+	if method == nil || method.CustomerID != customer.ID {
+		return nil, ErrPaymentMethodNotFound
+	}
+
+	return &chargeSource{
+		CardLast4:        method.CardLast4,
+		CardNetwork:      method.CardNetwork,
+		StripeCustomerID: customer.StripeCustomerID,
+		PaymentMethodID:  method.ID,
+	}, nil
+}
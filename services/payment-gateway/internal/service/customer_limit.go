@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+var ErrCustomerLimitExceeded = errors.New("customer daily limit exceeded")
+
+// checkCustomerLimit rejects a payment that would take the customer's succeeded
+// payments for the current UTC day over their configured daily limit
+func (s *PaymentService) checkCustomerLimit(ctx context.Context, req *models.PaymentRequest) error {
+	limit, err := s.repo.GetCustomerLimit(ctx, req.CustomerEmail)
+	if err != nil {
+		return fmt.Errorf("failed to load customer limit: %w", err)
+	}
+	if limit == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	totals, err := s.repo.SumSucceededSince(ctx, req.CustomerEmail, startOfDay)
+	if err != nil {
+		return fmt.Errorf("failed to sum customer payments: %w", err)
+	}
+
+	spent := 0.0
+	for currency, total := range totals {
+		converted, err := s.convertAmount(ctx, total, currency, limit.Currency)
+		if err != nil {
+			return err
+		}
+		spent += converted
+	}
+
+	requested, err := s.convertAmount(ctx, req.Amount, req.Currency, limit.Currency)
+	if err != nil {
+		return err
+	}
+
+	// Compare in cents so conversion rounding noise doesn't reject an at-limit payment
+	total := math.Round((spent+requested)*100) / 100
+	if total > limit.DailyLimit {
+		return fmt.Errorf("%w: %.2f %s already spent today, %.2f %s requested, limit is %.2f %s",
+			ErrCustomerLimitExceeded,
+			spent, limit.Currency,
+			requested, limit.Currency,
+			limit.DailyLimit, limit.Currency)
+	}
+
+	return nil
+}
+
+// convertAmount converts an amount between currencies using the currency service
+func (s *PaymentService) convertAmount(ctx context.Context, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	rate, err := s.converter.GetRate(ctx, from, to)
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert %s to %s: %w", from, to, err)
+	}
+
+	return amount * rate, nil
+}
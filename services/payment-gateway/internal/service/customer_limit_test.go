@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+func TestCheckCustomerLimit(t *testing.T) {
+	const email = "customer@example.com"
+
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		wantErr  error
+	}{
+		{
+			name:     "Under limit",
+			amount:   100,
+			currency: "USD",
+		},
+		{
+			name:     "At limit",
+			amount:   390,
+			currency: "USD",
+		},
+		{
+			name:     "Over limit",
+			amount:   390.01,
+			currency: "USD",
+			wantErr:  ErrCustomerLimitExceeded,
+		},
+		{
+			name:     "At limit in another currency",
+			amount:   300,
+			currency: "GBP",
+		},
+		{
+			name:     "Over limit in another currency",
+			amount:   301,
+			currency: "GBP",
+			wantErr:  ErrCustomerLimitExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.limits[email] = &models.CustomerLimit{
+				CustomerEmail: email,
+				DailyLimit:    1000,
+				Currency:      "USD",
+			}
+			// 500 USD + 100 EUR (110 USD) already spent today
+			store.payments["pay_1"] = &models.Payment{
+				ID: "pay_1", CustomerEmail: email, Amount: 500, Currency: "USD",
+				Status: models.PaymentStatusSucceeded, CreatedAt: time.Now(),
+			}
+			store.payments["pay_2"] = &models.Payment{
+				ID: "pay_2", CustomerEmail: email, Amount: 100, Currency: "EUR",
+				Status: models.PaymentStatusSucceeded, CreatedAt: time.Now(),
+			}
+			// Failed payments do not count towards the limit
+			store.payments["pay_3"] = &models.Payment{
+				ID: "pay_3", CustomerEmail: email, Amount: 900, Currency: "USD",
+				Status: models.PaymentStatusFailed, CreatedAt: time.Now(),
+			}
+
+			s := &PaymentService{
+				repo:      store,
+				converter: fixedRates{"EUR:USD": 1.10, "GBP:USD": 1.30},
+			}
+
+			req := &models.PaymentRequest{
+				Amount:        tt.amount,
+				Currency:      tt.currency,
+				CustomerEmail: email,
+			}
+
+			err := s.checkCustomerLimit(context.Background(), req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("checkCustomerLimit() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckCustomerLimitWithoutLimit(t *testing.T) {
+	s := &PaymentService{repo: newMockStore(), converter: fixedRates{}}
+
+	req := &models.PaymentRequest{Amount: 1e6, Currency: "USD", CustomerEmail: "customer@example.com"}
+	if err := s.checkCustomerLimit(context.Background(), req); err != nil {
+		t.Errorf("checkCustomerLimit() error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestAttachPaymentMethod(t *testing.T) {
+	store := newMockStore()
+	processor := &mockProcessor{card: &stripe.PaymentMethodCard{Last4: "4242", Brand: stripe.PaymentMethodCardBrandVisa}}
+	s := &PaymentService{repo: store, processor: processor}
+	ctx := context.Background()
+
+	customer, err := s.CreateCustomer(ctx, &models.CustomerRequest{Email: "customer@example.com"})
+	if err != nil {
+		t.Fatalf("CreateCustomer() error = %v", err)
+	}
+	if customer.StripeCustomerID != "cus_stripe_1" {
+		t.Errorf("StripeCustomerID = %q, want cus_stripe_1", customer.StripeCustomerID)
+	}
+
+	method, err := s.AttachPaymentMethod(ctx, customer.ID, "pm_card_visa")
+	if err != nil {
+		t.Fatalf("AttachPaymentMethod() error = %v", err)
+	}
+	if method.CardLast4 != "4242" || method.CardNetwork != "visa" {
+		t.Errorf("saved method = %+v, want visa ending 4242", method)
+	}
+	if got := stripe.StringValue(processor.attachParams[0].Customer); got != "cus_stripe_1" {
+		t.Errorf("attached to customer %q, want cus_stripe_1", got)
+	}
+	if _, ok := store.methods["pm_card_visa"]; !ok {
+		t.Error("payment method was not stored")
+	}
+
+	if _, err := s.AttachPaymentMethod(ctx, "cus_missing", "pm_card_visa"); !errors.Is(err, ErrCustomerNotFound) {
+		t.Errorf("AttachPaymentMethod() for unknown customer error = %v, want %v", err, ErrCustomerNotFound)
+	}
+}
+
+func TestCreatePaymentWithSavedMethod(t *testing.T) {
+	newService := func() (*PaymentService, *mockStore, *mockProcessor) {
+		store := newMockStore()
+		store.customers["cus_1"] = &models.Customer{ID: "cus_1", StripeCustomerID: "cus_stripe_1"}
+		store.customers["cus_2"] = &models.Customer{ID: "cus_2", StripeCustomerID: "cus_stripe_2"}
+		store.methods["pm_1"] = &models.SavedPaymentMethod{ID: "pm_1", CustomerID: "cus_1", CardLast4: "4242", CardNetwork: "visa"}
+		processor := &mockProcessor{}
+		return &PaymentService{repo: store, processor: processor}, store, processor
+	}
+
+	tests := []struct {
+		name            string
+		customerID      string
+		paymentMethodID string
+		wantErr         error
+	}{
+		{
+			name:            "Saved method",
+			customerID:      "cus_1",
+			paymentMethodID: "pm_1",
+		},
+		{
+			name:            "Method belongs to another customer",
+			customerID:      "cus_2",
+			paymentMethodID: "pm_1",
+			wantErr:         ErrPaymentMethodNotFound,
+		},
+		{
+			name:            "Unknown method",
+			customerID:      "cus_1",
+			paymentMethodID: "pm_missing",
+			wantErr:         ErrPaymentMethodNotFound,
+		},
+		{
+			name:            "Unknown customer",
+			customerID:      "cus_missing",
+			paymentMethodID: "pm_1",
+			wantErr:         ErrCustomerNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, processor := newService()
+
+			payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+				Amount:          25,
+				Currency:        "USD",
+				CustomerEmail:   "customer@example.com",
+				CustomerID:      tt.customerID,
+				PaymentMethodID: tt.paymentMethodID,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePayment() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(processor.intentParams) != 0 {
+					t.Error("Stripe was called for a rejected payment method")
+				}
+				return
+			}
+
+			params := processor.intentParams[0]
+			if got := stripe.StringValue(params.PaymentMethod); got != "pm_1" {
+				t.Errorf("PaymentMethod = %q, want pm_1", got)
+			}
+			if got := stripe.StringValue(params.Customer); got != "cus_stripe_1" {
+				t.Errorf("Customer = %q, want cus_stripe_1", got)
+			}
+			if payment.CardLast4 != "4242" || payment.CardNetwork != "visa" {
+				t.Errorf("payment card = %s %s, want visa 4242", payment.CardNetwork, payment.CardLast4)
+			}
+			if _, ok := store.payments[payment.ID]; !ok {
+				t.Error("payment was not stored")
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+// validationErrors are the checks a dry run reports as a rejected payment rather than a failure
+var validationErrors = []error{
+	ErrInvalidCardNumber,
+	ErrUnsupportedCardNetwork,
+	ErrCustomerLimitExceeded,
+	ErrAmountAboveMaximum,
+	ErrAsyncRequiresPaymentMethod,
+	ErrCustomerNotFound,
+	ErrPaymentMethodNotFound,
+}
+
+// DryRunPayment runs the validation and fraud checks CreatePayment would run and
+// reports the outcome, without calling Stripe or persisting anything
+func (s *PaymentService) DryRunPayment(ctx context.Context, req *models.PaymentRequest) (*models.DryRunResult, error) {
+	result := &models.DryRunResult{
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}
+
+	source, err := s.validatePayment(ctx, req)
+	if err != nil {
+		for _, validationErr := range validationErrors {
+			if errors.Is(err, validationErr) {
+				result.Reason = err.Error()
+				return result, nil
+			}
+		}
+		return nil, err
+	}
+	result.CardLast4 = source.CardLast4
+	result.CardNetwork = source.CardNetwork
+
+	if s.fraud != nil {
+		// A throwaway ID so the fraud service doesn't cache this decision for a real payment
+		assessment, err := s.fraud.CheckPayment(ctx, newFraudCheck("dry_run_"+ids.New(), req, source))
+		if err != nil {
+			return nil, fmt.Errorf("fraud check failed: %w", err)
+		}
+		result.Fraud = assessment
+
+		if assessment.Decision == models.FraudDecisionBlock {
+			result.Reason = "blocked by fraud check"
+			return result, nil
+		}
+	}
+
+	result.WouldSucceed = true
+	return result, nil
+}
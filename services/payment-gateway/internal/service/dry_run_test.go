@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestDryRunPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		cardNumber  string
+		fraud       FraudChecker
+		wantSucceed bool
+		wantReason  string
+	}{
+		{
+			name:        "Valid card",
+			cardNumber:  "4242424242424242",
+			wantSucceed: true,
+		},
+		{
+			name:        "Valid card approved by fraud check",
+			cardNumber:  "4242424242424242",
+			fraud:       &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionApprove}},
+			wantSucceed: true,
+		},
+		{
+			name:       "Invalid card number",
+			cardNumber: "4242424242424241",
+			wantReason: ErrInvalidCardNumber.Error(),
+		},
+		{
+			name:       "Blocked by fraud check",
+			cardNumber: "4242424242424242",
+			fraud:      &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionBlock}},
+			wantReason: "blocked by fraud check",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			processor := &mockProcessor{}
+			s := &PaymentService{repo: store, processor: processor, fraud: tt.fraud}
+
+			result, err := s.DryRunPayment(context.Background(), &models.PaymentRequest{
+				Amount:        100,
+				Currency:      "USD",
+				CardNumber:    tt.cardNumber,
+				CustomerEmail: "customer@example.com",
+				DryRun:        true,
+			})
+			if err != nil {
+				t.Fatalf("DryRunPayment() error = %v", err)
+			}
+
+			if result.WouldSucceed != tt.wantSucceed {
+				t.Errorf("WouldSucceed = %v, want %v", result.WouldSucceed, tt.wantSucceed)
+			}
+			if result.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+			if len(processor.intentParams) != 0 {
+				t.Errorf("dry run created %d payment intents, want 0", len(processor.intentParams))
+			}
+			if len(store.payments) != 0 || len(store.paymentEvents) != 0 {
+				t.Errorf("dry run stored %d payments and %d events, want none", len(store.payments), len(store.paymentEvents))
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// FraudChecker scores a payment for fraud risk
+type FraudChecker interface {
+	CheckPayment(ctx context.Context, check *models.FraudCheck) (*models.FraudAssessment, error)
+}
+
+// FraudClient calls the fraud-detection service over HTTP
+type FraudClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewFraudClient creates a client for the fraud-detection service
+func NewFraudClient(baseURL string) *FraudClient {
+	return &FraudClient{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// CheckPayment asks the fraud service for a decision on a payment
+func (c *FraudClient) CheckPayment(ctx context.Context, check *models.FraudCheck) (*models.FraudAssessment, error) {
+	body, err := json.Marshal(check)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/api/v1/fraud/check", c.baseURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fraud service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fraud service returned status %d", resp.StatusCode)
+	}
+
+	var assessment models.FraudAssessment
+	if err := json.NewDecoder(resp.Body).Decode(&assessment); err != nil {
+		return nil, fmt.Errorf("failed to parse fraud service response: %w", err)
+	}
+
+	return &assessment, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"payment-gateway/internal/models"
+)
+
+var (
+	ErrPaymentBlocked     = errors.New("payment blocked by fraud check")
+	ErrPaymentUnderReview = errors.New("payment is pending fraud review")
+)
+
+// FraudBlockedError rejects a payment the fraud service blocked, carrying its reason codes
+type FraudBlockedError struct {
+	PaymentID string
+	Reasons   []string
+}
+
+func (e *FraudBlockedError) Error() string {
+	if len(e.Reasons) == 0 {
+		return ErrPaymentBlocked.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrPaymentBlocked, strings.Join(e.Reasons, ", "))
+}
+
+func (e *FraudBlockedError) Unwrap() error {
+	return ErrPaymentBlocked
+}
+
+// assessFraud asks the fraud service about a new payment and records its decision on
+// the payment. A fraud service outage sends the payment to review rather than failing it.
+func (s *PaymentService) assessFraud(ctx context.Context, payment *models.Payment, check *models.FraudCheck) *models.FraudAssessment {
+	if s.fraud == nil {
+		return nil
+	}
+
+	assessment, err := s.fraud.CheckPayment(ctx, check)
+	if err != nil {
+		fmt.Printf("Fraud check unavailable for payment %s: %v\n", payment.ID, err)
+		assessment = &models.FraudAssessment{
+			TransactionID: payment.ID,
+			Decision:      models.FraudDecisionReview,
+			Flags:         []string{"fraud_check_unavailable"},
+		}
+	}
+
+	payment.FraudCheckID = assessment.TransactionID
+	payment.FraudDecision = assessment.Decision
+	payment.FraudScore = assessment.Score
+	return assessment
+}
+
+// newFraudCheck describes a payment request and what it is charged against to the fraud service
+func newFraudCheck(transactionID string, req *models.PaymentRequest, source *chargeSource) *models.FraudCheck {
+	return &models.FraudCheck{
+		TransactionID: transactionID,
+		MerchantID:    req.MerchantID,
+		Amount:        req.Amount,
+		Currency:      req.Currency,
+		CustomerEmail: req.CustomerEmail,
+		CardLast4:     source.CardLast4,
+		Country:       req.Country,
+		IssuerCountry: source.IssuerCountry,
+	}
+}
+
+// rejectBlockedPayment stores a payment the fraud service blocked as failed
+func (s *PaymentService) rejectBlockedPayment(ctx context.Context, payment *models.Payment, assessment *models.FraudAssessment) error {
+	blocked := &FraudBlockedError{PaymentID: payment.ID, Reasons: assessment.Flags}
+
+	payment.Status = models.PaymentStatusFailed
+	payment.FailureReason = blocked.Error()
+	if err := s.repo.Create(ctx, payment); err != nil {
+		return fmt.Errorf("failed to save payment: %w", err)
+	}
+	if err := s.recordEvent(ctx, payment, "", actorFraudCheck, "blocked by fraud check"); err != nil {
+		return fmt.Errorf("failed to record payment event: %w", err)
+	}
+
+	s.publishPaymentEvent(ctx, "payment.failed", payment)
+	return blocked
+}
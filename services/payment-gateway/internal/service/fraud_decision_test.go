@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+// stubFraudChecker records fraud checks and returns a canned assessment or error
+type stubFraudChecker struct {
+	assessment *models.FraudAssessment
+	err        error
+	checks     []*models.FraudCheck
+}
+
+func (f *stubFraudChecker) CheckPayment(ctx context.Context, check *models.FraudCheck) (*models.FraudAssessment, error) {
+	f.checks = append(f.checks, check)
+	if f.err != nil {
+		return nil, f.err
+	}
+	assessment := *f.assessment
+	assessment.TransactionID = check.TransactionID
+	return &assessment, nil
+}
+
+func TestCreatePaymentFraudDecisions(t *testing.T) {
+	tests := []struct {
+		name         string
+		fraud        *stubFraudChecker
+		wantStatus   models.PaymentStatus
+		wantDecision string
+		wantIntents  int
+		wantBlocked  bool
+	}{
+		{
+			name:         "Approve proceeds",
+			fraud:        &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionApprove, Score: 10}},
+			wantStatus:   models.PaymentStatusPending,
+			wantDecision: models.FraudDecisionApprove,
+			wantIntents:  1,
+		},
+		{
+			name:         "Review holds the payment",
+			fraud:        &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionReview, Score: 55}},
+			wantStatus:   models.PaymentStatusPendingReview,
+			wantDecision: models.FraudDecisionReview,
+			wantIntents:  1,
+		},
+		{
+			name: "Block rejects without reaching Stripe",
+			fraud: &stubFraudChecker{assessment: &models.FraudAssessment{
+				Decision: models.FraudDecisionBlock,
+				Score:    95,
+				Flags:    []string{"blacklisted", "high_velocity"},
+			}},
+			wantStatus:   models.PaymentStatusFailed,
+			wantDecision: models.FraudDecisionBlock,
+			wantBlocked:  true,
+		},
+		{
+			name:         "Fraud service outage holds the payment",
+			fraud:        &stubFraudChecker{err: errors.New("connection refused")},
+			wantStatus:   models.PaymentStatusPendingReview,
+			wantDecision: models.FraudDecisionReview,
+			wantIntents:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			processor := &mockProcessor{}
+			s := &PaymentService{repo: store, processor: processor, fraud: tt.fraud}
+
+			payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+				Amount:        100,
+				Currency:      "USD",
+				CardNumber:    "4242424242424242",
+				CustomerEmail: "customer@example.com",
+			})
+
+			var blocked *FraudBlockedError
+			if tt.wantBlocked {
+				if !errors.As(err, &blocked) || !errors.Is(err, ErrPaymentBlocked) {
+					t.Fatalf("CreatePayment() error = %v, want %v", err, ErrPaymentBlocked)
+				}
+				if len(blocked.Reasons) != 2 {
+					t.Errorf("reasons = %v, want the fraud flags", blocked.Reasons)
+				}
+				payment = store.payments[blocked.PaymentID]
+				if payment == nil {
+					t.Fatal("blocked payment was not stored")
+				}
+			} else if err != nil {
+				t.Fatalf("CreatePayment() error = %v", err)
+			}
+
+			if payment.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", payment.Status, tt.wantStatus)
+			}
+			if payment.FraudDecision != tt.wantDecision {
+				t.Errorf("fraud decision = %q, want %q", payment.FraudDecision, tt.wantDecision)
+			}
+			if payment.FraudCheckID != payment.ID {
+				t.Errorf("fraud check ID = %q, want payment ID %q", payment.FraudCheckID, payment.ID)
+			}
+			if len(processor.intentParams) != tt.wantIntents {
+				t.Errorf("created %d payment intents, want %d", len(processor.intentParams), tt.wantIntents)
+			}
+		})
+	}
+}
+
+func TestConfirmPaymentRejectsPendingReview(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{ID: "pay_1", Status: models.PaymentStatusPendingReview}
+	s := &PaymentService{repo: store, processor: &mockProcessor{}}
+
+	if _, err := s.ConfirmPayment(context.Background(), "pay_1"); !errors.Is(err, ErrPaymentUnderReview) {
+		t.Errorf("ConfirmPayment() error = %v, want %v", err, ErrPaymentUnderReview)
+	}
+}
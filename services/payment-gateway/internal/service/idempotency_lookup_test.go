@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestGetPaymentByIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	s := &PaymentService{repo: newMockStore(), processor: &mockProcessor{}}
+
+	created, err := s.CreatePayment(ctx, &models.PaymentRequest{
+		Amount:         25,
+		Currency:       "USD",
+		CardNumber:     "4242424242424242",
+		CustomerEmail:  "customer@example.com",
+		IdempotencyKey: "order-1001",
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	found, err := s.GetPaymentByIdempotencyKey(ctx, "order-1001")
+	if err != nil {
+		t.Fatalf("GetPaymentByIdempotencyKey() error = %v", err)
+	}
+	if found.ID != created.ID {
+		t.Errorf("found payment %s, want %s", found.ID, created.ID)
+	}
+
+	if _, err := s.GetPaymentByIdempotencyKey(ctx, "order-unknown"); !errors.Is(err, ErrPaymentNotFound) {
+		t.Errorf("unknown key error = %v, want %v", err, ErrPaymentNotFound)
+	}
+}
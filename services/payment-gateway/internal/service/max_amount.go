@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/money"
+)
+
+var ErrAmountAboveMaximum = errors.New("payment amount exceeds the maximum allowed")
+
+// MaxAmounts caps the amount of a single payment
+type MaxAmounts struct {
+	// Limit applies to every payment once converted into Currency; zero disables it
+	Limit    float64
+	Currency string
+	// PerCurrency overrides Limit for payments in those currencies, compared without converting
+	PerCurrency map[string]float64
+}
+
+// SetMaxAmounts sets the largest amount a single payment may be charged
+func (s *PaymentService) SetMaxAmounts(limits MaxAmounts) error {
+	if limits.Limit < 0 {
+		return fmt.Errorf("maximum payment amount must not be negative, got %v", limits.Limit)
+	}
+	if limits.Limit > 0 && len(limits.Currency) != 3 {
+		return fmt.Errorf("maximum payment amount needs a three-letter currency, got %q", limits.Currency)
+	}
+
+	perCurrency := make(map[string]float64, len(limits.PerCurrency))
+	for currency, limit := range limits.PerCurrency {
+		if limit <= 0 {
+			return fmt.Errorf("maximum payment amount for %s must be positive, got %v", currency, limit)
+		}
+		perCurrency[money.NormalizeCurrency(currency)] = limit
+	}
+
+	s.maxAmounts = MaxAmounts{
+		Limit:       limits.Limit,
+		Currency:    money.NormalizeCurrency(limits.Currency),
+		PerCurrency: perCurrency,
+	}
+	return nil
+}
+
+// checkMaxAmount rejects a payment above the maximum for its currency, converting
+// it into the default limit's currency when the currency has no limit of its own
+func (s *PaymentService) checkMaxAmount(ctx context.Context, req *models.PaymentRequest) error {
+	currency := money.NormalizeCurrency(req.Currency)
+	if limit, ok := s.maxAmounts.PerCurrency[currency]; ok {
+		if req.Amount > limit {
+			return fmt.Errorf("%w: %.2f %s requested, maximum is %.2f %s",
+				ErrAmountAboveMaximum, req.Amount, currency, limit, currency)
+		}
+		return nil
+	}
+
+	limit := s.maxAmounts
+	if limit.Limit == 0 {
+		return nil
+	}
+
+	requested, err := s.convertAmount(ctx, req.Amount, currency, limit.Currency)
+	if err != nil {
+		return err
+	}
+
+	// Compare in cents so conversion rounding noise doesn't reject an at-limit payment
+	if math.Round(requested*100)/100 > limit.Limit {
+		return fmt.Errorf("%w: %.2f %s (%.2f %s) requested, maximum is %.2f %s",
+			ErrAmountAboveMaximum, req.Amount, currency, requested, limit.Currency, limit.Limit, limit.Currency)
+	}
+	return nil
+}
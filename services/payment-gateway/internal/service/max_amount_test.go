@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestCreatePaymentMaxAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		wantErr  error
+	}{
+		{name: "At limit", amount: 10000, currency: "USD"},
+		{name: "Above limit", amount: 10000.01, currency: "USD", wantErr: ErrAmountAboveMaximum},
+		// 8000 GBP is 10000 USD at 1.25
+		{name: "At limit once converted", amount: 8000, currency: "GBP"},
+		{name: "Above limit once converted", amount: 8000.01, currency: "GBP", wantErr: ErrAmountAboveMaximum},
+		{name: "At a currency's own limit", amount: 1500000, currency: "JPY"},
+		{name: "Above a currency's own limit", amount: 1500001, currency: "JPY", wantErr: ErrAmountAboveMaximum},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			processor := &mockProcessor{}
+			s := &PaymentService{repo: store, processor: processor, converter: fixedRates{"GBP:USD": 1.25}}
+			if err := s.SetMaxAmounts(MaxAmounts{
+				Limit:       10000,
+				Currency:    "usd",
+				PerCurrency: map[string]float64{"jpy": 1500000},
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+				Amount:        tt.amount,
+				Currency:      tt.currency,
+				CardNumber:    "4242424242424242",
+				CustomerEmail: "customer@example.com",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreatePayment() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(processor.intentParams) != 0 || len(store.payments) != 0) {
+				t.Error("payment above the maximum reached Stripe or was stored")
+			}
+			if tt.wantErr == nil && len(processor.intentParams) != 1 {
+				t.Errorf("sent %d payment intents, want 1", len(processor.intentParams))
+			}
+		})
+	}
+}
+
+func TestSetMaxAmountsRejectsInvalidLimits(t *testing.T) {
+	s := &PaymentService{}
+
+	if err := s.SetMaxAmounts(MaxAmounts{Limit: -1, Currency: "USD"}); err == nil {
+		t.Error("SetMaxAmounts() accepted a negative limit")
+	}
+	if err := s.SetMaxAmounts(MaxAmounts{Limit: 100}); err == nil {
+		t.Error("SetMaxAmounts() accepted a limit without a currency")
+	}
+	if err := s.SetMaxAmounts(MaxAmounts{PerCurrency: map[string]float64{"JPY": 0}}); err == nil {
+		t.Error("SetMaxAmounts() accepted a zero per-currency limit")
+	}
+}
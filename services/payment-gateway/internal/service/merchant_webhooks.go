@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+// MerchantWebhookSignatureHeader carries "t=<unix time>,v1=<hex HMAC-SHA256>" over
+// "<unix time>.<body>", keyed with the webhook's secret
+const MerchantWebhookSignatureHeader = "GlobalPay-Signature"
+
+// DefaultWebhookRetryDelays are the waits between delivery attempts; an event is
+// attempted once more than there are delays
+var DefaultWebhookRetryDelays = []time.Duration{
+	5 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+}
+
+// merchantWebhookSender delivers lifecycle events to merchant webhooks
+type merchantWebhookSender struct {
+	httpClient  *http.Client
+	retryDelays []time.Duration
+}
+
+// EnableMerchantWebhooks sends every payment lifecycle event to the active merchant
+// webhooks, retrying failed deliveries after each of retryDelays
+func (s *PaymentService) EnableMerchantWebhooks(httpClient *http.Client, retryDelays []time.Duration) {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	s.merchantWebhooks = &merchantWebhookSender{httpClient: httpClient, retryDelays: retryDelays}
+}
+
+// RegisterMerchantWebhook adds a merchant endpoint and generates its signing secret
+func (s *PaymentService) RegisterMerchantWebhook(ctx context.Context, req *models.MerchantWebhookRequest) (*models.MerchantWebhook, error) {
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook := &models.MerchantWebhook{
+		ID:         ids.New(),
+		MerchantID: req.MerchantID,
+		URL:        req.URL,
+		Secret:     secret,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}
+	if err := s.repo.CreateMerchantWebhook(ctx, webhook); err != nil {
+		return nil, fmt.Errorf("failed to save merchant webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// ListWebhookDeliveries returns recent delivery attempts, newest first
+func (s *PaymentService) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	return s.repo.ListWebhookDeliveries(ctx, filter)
+}
+
+// DispatchMerchantWebhooks delivers a lifecycle event to the active webhooks of the
+// payment's merchant, retrying each until it succeeds or runs out of attempts. Every
+// attempt is recorded in the delivery log. Payments without a merchant go nowhere.
+func (s *PaymentService) DispatchMerchantWebhooks(ctx context.Context, event *models.PaymentLifecycleEvent) {
+	if s.merchantWebhooks == nil || event.MerchantID == "" {
+		return
+	}
+
+	webhooks, err := s.repo.ListActiveMerchantWebhooks(ctx, event.MerchantID)
+	if err != nil {
+		fmt.Printf("Failed to list merchant webhooks for %s: %v\n", event.Type, err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	// Each webhook retries on its own, so a failing endpoint does not hold up the rest
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		wg.Add(1)
+		go func(webhook *models.MerchantWebhook) {
+			defer wg.Done()
+			s.deliverWithRetries(ctx, webhook, event, payload)
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+func (s *PaymentService) deliverWithRetries(ctx context.Context, webhook *models.MerchantWebhook, event *models.PaymentLifecycleEvent, payload []byte) {
+	sender := s.merchantWebhooks
+	for attempt := 1; ; attempt++ {
+		delivery := sender.deliver(ctx, webhook, event, payload, attempt)
+		if err := s.repo.SaveWebhookDelivery(ctx, delivery); err != nil {
+			fmt.Printf("Failed to log webhook delivery %s: %v\n", delivery.ID, err)
+		}
+		if delivery.Succeeded || attempt > len(sender.retryDelays) {
+			if !delivery.Succeeded {
+				fmt.Printf("Giving up delivering %s to webhook %s after %d attempts\n", event.Type, webhook.ID, attempt)
+			}
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(sender.retryDelays[attempt-1]):
+		}
+	}
+}
+
+// deliver makes one signed POST of payload to the webhook. Any 2xx response is success.
+func (m *merchantWebhookSender) deliver(ctx context.Context, webhook *models.MerchantWebhook, event *models.PaymentLifecycleEvent, payload []byte, attempt int) *models.WebhookDelivery {
+	delivery := &models.WebhookDelivery{
+		ID:        ids.New(),
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		EventType: event.Type,
+		PaymentID: event.PaymentID,
+		URL:       webhook.URL,
+		Attempt:   attempt,
+		CreatedAt: time.Now(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(payload))
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(MerchantWebhookSignatureHeader, SignMerchantWebhook(webhook.Secret, delivery.CreatedAt, payload))
+
+	resp, err := m.httpClient.Do(req)
+	delivery.DurationMS = time.Since(delivery.CreatedAt).Milliseconds()
+	if err != nil {
+		delivery.Error = err.Error()
+		return delivery
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	delivery.StatusCode = resp.StatusCode
+	delivery.Succeeded = resp.StatusCode >= 200 && resp.StatusCode < 300
+	if !delivery.Succeeded {
+		delivery.Error = fmt.Sprintf("webhook returned status %d", resp.StatusCode)
+	}
+	return delivery
+}
+
+// SignMerchantWebhook builds the signature header value for a delivery sent at t
+func SignMerchantWebhook(secret string, t time.Time, payload []byte) string {
+	timestamp := strconv.FormatInt(t.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "gpwhsec_" + hex.EncodeToString(b), nil
+}
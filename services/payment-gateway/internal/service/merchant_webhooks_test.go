@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+func TestDispatchMerchantWebhooksSignsDelivery(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(MerchantWebhookSignatureHeader)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+	s.EnableMerchantWebhooks(server.Client(), nil)
+
+	webhook, err := s.RegisterMerchantWebhook(context.Background(), &models.MerchantWebhookRequest{MerchantID: "merchant_1", URL: server.URL})
+	if err != nil {
+		t.Fatalf("RegisterMerchantWebhook() error = %v", err)
+	}
+
+	s.DispatchMerchantWebhooks(context.Background(), &models.PaymentLifecycleEvent{
+		ID:         "evt_1",
+		Type:       "payment.succeeded",
+		PaymentID:  "pay_1",
+		MerchantID: "merchant_1",
+		Amount:     100,
+		Currency:   "USD",
+	})
+
+	if len(store.deliveries) != 1 {
+		t.Fatalf("logged %d deliveries, want 1", len(store.deliveries))
+	}
+	delivery := store.deliveries[0]
+	if !delivery.Succeeded || delivery.StatusCode != http.StatusOK || delivery.Attempt != 1 {
+		t.Errorf("delivery = %+v, want a successful first attempt", delivery)
+	}
+	if delivery.EventID != "evt_1" || delivery.PaymentID != "pay_1" || delivery.WebhookID != webhook.ID {
+		t.Errorf("delivery = %+v, want it linked to evt_1, pay_1 and the webhook", delivery)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(signature, "t="), ",", 2)
+	unix, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		t.Fatalf("signature %q has no timestamp", signature)
+	}
+	if want := SignMerchantWebhook(webhook.Secret, time.Unix(unix, 0), body); signature != want {
+		t.Errorf("signature = %q, want %q", signature, want)
+	}
+	if !strings.Contains(string(body), `"payment.succeeded"`) {
+		t.Errorf("body = %s, want the lifecycle event", body)
+	}
+}
+
+func TestDispatchMerchantWebhooksRetriesFailures(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+	s.EnableMerchantWebhooks(server.Client(), []time.Duration{0, 0, 0, 0})
+	if _, err := s.RegisterMerchantWebhook(context.Background(), &models.MerchantWebhookRequest{MerchantID: "merchant_1", URL: server.URL}); err != nil {
+		t.Fatalf("RegisterMerchantWebhook() error = %v", err)
+	}
+
+	s.DispatchMerchantWebhooks(context.Background(), &models.PaymentLifecycleEvent{ID: "evt_1", Type: "payment.failed", PaymentID: "pay_1", MerchantID: "merchant_1"})
+
+	if len(store.deliveries) != 3 {
+		t.Fatalf("logged %d deliveries, want 3", len(store.deliveries))
+	}
+	for i, delivery := range store.deliveries {
+		if delivery.Attempt != i+1 {
+			t.Errorf("delivery %d attempt = %d, want %d", i, delivery.Attempt, i+1)
+		}
+	}
+	if first := store.deliveries[0]; first.Succeeded || first.StatusCode != http.StatusInternalServerError || first.Error == "" {
+		t.Errorf("first delivery = %+v, want a logged 500 failure", first)
+	}
+	if last := store.deliveries[2]; !last.Succeeded || last.StatusCode != http.StatusNoContent {
+		t.Errorf("last delivery = %+v, want success", last)
+	}
+
+	deliveries, err := s.ListWebhookDeliveries(context.Background(), models.WebhookDeliveryFilter{PaymentID: "pay_1", Limit: 20})
+	if err != nil || len(deliveries) != 3 || deliveries[0].Attempt != 3 {
+		t.Errorf("ListWebhookDeliveries() = %d deliveries, %v; want 3, newest first", len(deliveries), err)
+	}
+}
+
+func TestDispatchMerchantWebhooksGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+	s.EnableMerchantWebhooks(server.Client(), []time.Duration{0})
+	if _, err := s.RegisterMerchantWebhook(context.Background(), &models.MerchantWebhookRequest{MerchantID: "merchant_1", URL: server.URL}); err != nil {
+		t.Fatalf("RegisterMerchantWebhook() error = %v", err)
+	}
+
+	s.DispatchMerchantWebhooks(context.Background(), &models.PaymentLifecycleEvent{ID: "evt_1", Type: "payment.failed", PaymentID: "pay_1", MerchantID: "merchant_1"})
+
+	if len(store.deliveries) != 2 {
+		t.Fatalf("logged %d deliveries, want 2", len(store.deliveries))
+	}
+	for _, delivery := range store.deliveries {
+		if delivery.Succeeded {
+			t.Errorf("delivery %+v succeeded, want failure", delivery)
+		}
+	}
+}
+
+func TestDispatchMerchantWebhooksOnlyReachesPaymentMerchant(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+	s.EnableMerchantWebhooks(server.Client(), []time.Duration{0})
+	for _, req := range []*models.MerchantWebhookRequest{
+		{MerchantID: "merchant_1", URL: server.URL + "/down"},
+		{MerchantID: "merchant_1", URL: server.URL + "/up"},
+		{MerchantID: "merchant_2", URL: server.URL + "/other"},
+	} {
+		if _, err := s.RegisterMerchantWebhook(context.Background(), req); err != nil {
+			t.Fatalf("RegisterMerchantWebhook() error = %v", err)
+		}
+	}
+
+	s.DispatchMerchantWebhooks(context.Background(), &models.PaymentLifecycleEvent{ID: "evt_1", Type: "payment.succeeded", PaymentID: "pay_1", MerchantID: "merchant_1"})
+	s.DispatchMerchantWebhooks(context.Background(), &models.PaymentLifecycleEvent{ID: "evt_2", Type: "payment.succeeded", PaymentID: "pay_2"})
+
+	if hits["/other"] != 0 {
+		t.Errorf("another merchant's webhook received %d deliveries, want 0", hits["/other"])
+	}
+	if hits["/up"] != 1 || hits["/down"] != 2 {
+		t.Errorf("hits = %v, want one delivery to /up and two attempts at /down", hits)
+	}
+}
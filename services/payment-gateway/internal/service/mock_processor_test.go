@@ -0,0 +1,64 @@
+package service
+
+import (
+	"github.com/stripe/stripe-go/v76"
+)
+
+// mockProcessor is a PaymentProcessor that records requests and returns canned Stripe objects
+type mockProcessor struct {
+	intentParams []*stripe.PaymentIntentParams
+	attachParams []*stripe.PaymentMethodAttachParams
+	refundParams []*stripe.RefundParams
+	refundErr    error
+	intentStatus stripe.PaymentIntentStatus
+	card         *stripe.PaymentMethodCard
+	balanceTxn   *stripe.BalanceTransaction
+	// amountReceived is reported by GetPaymentIntent, in minor units
+	amountReceived int64
+}
+
+func (m *mockProcessor) CreatePaymentIntent(params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error) {
+	m.intentParams = append(m.intentParams, params)
+	status := m.intentStatus
+	if status == "" {
+		status = stripe.PaymentIntentStatusRequiresConfirmation
+	}
+	return &stripe.PaymentIntent{ID: "pi_test", ClientSecret: "pi_test_secret", Status: status}, nil
+}
+
+func (m *mockProcessor) ConfirmPaymentIntent(id string, params *stripe.PaymentIntentConfirmParams) (*stripe.PaymentIntent, error) {
+	return &stripe.PaymentIntent{ID: id, Status: stripe.PaymentIntentStatusSucceeded}, nil
+}
+
+func (m *mockProcessor) GetPaymentIntent(id string) (*stripe.PaymentIntent, error) {
+	return &stripe.PaymentIntent{ID: id, Status: m.intentStatus, AmountReceived: m.amountReceived}, nil
+}
+
+func (m *mockProcessor) CapturePaymentIntent(id string, params *stripe.PaymentIntentCaptureParams) (*stripe.PaymentIntent, error) {
+	return &stripe.PaymentIntent{ID: id, Status: stripe.PaymentIntentStatusSucceeded}, nil
+}
+
+func (m *mockProcessor) CancelPaymentIntent(id string) (*stripe.PaymentIntent, error) {
+	return &stripe.PaymentIntent{ID: id, Status: stripe.PaymentIntentStatusCanceled}, nil
+}
+
+func (m *mockProcessor) CreateCustomer(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	return &stripe.Customer{ID: "cus_stripe_1", Email: stripe.StringValue(params.Email)}, nil
+}
+
+func (m *mockProcessor) AttachPaymentMethod(id string, params *stripe.PaymentMethodAttachParams) (*stripe.PaymentMethod, error) {
+	m.attachParams = append(m.attachParams, params)
+	return &stripe.PaymentMethod{ID: id, Card: m.card}, nil
+}
+
+func (m *mockProcessor) GetBalanceTransaction(chargeID string) (*stripe.BalanceTransaction, error) {
+	return m.balanceTxn, nil
+}
+
+func (m *mockProcessor) CreateRefund(params *stripe.RefundParams) (*stripe.Refund, error) {
+	m.refundParams = append(m.refundParams, params)
+	if m.refundErr != nil {
+		return nil, m.refundErr
+	}
+	return &stripe.Refund{ID: "re_test", Status: stripe.RefundStatusSucceeded}, nil
+}
@@ -0,0 +1,379 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+// mockStore is an in-memory PaymentStore for service tests
+type mockStore struct {
+	payments      map[string]*models.Payment
+	events        map[string]*models.WebhookEvent
+	paymentEvents []*models.PaymentEvent
+	limits        map[string]*models.CustomerLimit
+	customers     map[string]*models.Customer
+	methods       map[string]*models.SavedPaymentMethod
+	reviews       map[string]*models.ReviewItem
+	refunds       map[string]*models.Refund
+	webhooks      []*models.MerchantWebhook
+	deliveries    []*models.WebhookDelivery
+	deliveriesMu  sync.Mutex
+	updateCalls   int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		payments:  make(map[string]*models.Payment),
+		events:    make(map[string]*models.WebhookEvent),
+		limits:    make(map[string]*models.CustomerLimit),
+		customers: make(map[string]*models.Customer),
+		methods:   make(map[string]*models.SavedPaymentMethod),
+		reviews:   make(map[string]*models.ReviewItem),
+		refunds:   make(map[string]*models.Refund),
+	}
+}
+
+func (m *mockStore) Create(ctx context.Context, payment *models.Payment) error {
+	stored := *payment
+	m.payments[payment.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) GetByID(ctx context.Context, id string) (*models.Payment, error) {
+	payment, ok := m.payments[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *payment
+	return &copied, nil
+}
+
+func (m *mockStore) GetByStripeIntentID(ctx context.Context, intentID string) (*models.Payment, error) {
+	for _, payment := range m.payments {
+		if payment.StripePaymentIntentID == intentID {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) GetByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error) {
+	for _, payment := range m.payments {
+		if payment.IdempotencyKey == key {
+			copied := *payment
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) Update(ctx context.Context, payment *models.Payment) error {
+	m.updateCalls++
+	stored := *payment
+	m.payments[payment.ID] = &stored
+	return nil
+}
+
+func (m *mockStore) CreateEvent(ctx context.Context, event *models.PaymentEvent) error {
+	m.paymentEvents = append(m.paymentEvents, event)
+	return nil
+}
+
+func (m *mockStore) ListEvents(ctx context.Context, paymentID string) ([]*models.PaymentEvent, error) {
+	var events []*models.PaymentEvent
+	for _, event := range m.paymentEvents {
+		if event.PaymentID == paymentID {
+			events = append(events, event)
+		}
+	}
+	return events, nil
+}
+
+func (m *mockStore) MarkEventProcessed(ctx context.Context, eventID, eventType string, payload []byte) (bool, error) {
+	if _, ok := m.events[eventID]; ok {
+		return false, nil
+	}
+	m.events[eventID] = &models.WebhookEvent{ID: eventID, Type: eventType, Payload: payload, ProcessedAt: time.Now()}
+	return true, nil
+}
+
+func (m *mockStore) UnmarkEvent(ctx context.Context, eventID string) error {
+	delete(m.events, eventID)
+	return nil
+}
+
+func (m *mockStore) GetWebhookEvent(ctx context.Context, eventID string) (*models.WebhookEvent, error) {
+	return m.events[eventID], nil
+}
+
+func (m *mockStore) GetCustomerLimit(ctx context.Context, customerEmail string) (*models.CustomerLimit, error) {
+	return m.limits[customerEmail], nil
+}
+
+func (m *mockStore) SumSucceededSince(ctx context.Context, customerEmail string, since time.Time) (map[string]float64, error) {
+	totals := make(map[string]float64)
+	for _, payment := range m.payments {
+		if payment.CustomerEmail == customerEmail &&
+			payment.Status == models.PaymentStatusSucceeded &&
+			!payment.CreatedAt.Before(since) {
+			totals[payment.Currency] += payment.Amount
+		}
+	}
+	return totals, nil
+}
+
+func (m *mockStore) CreateCustomer(ctx context.Context, customer *models.Customer) error {
+	m.customers[customer.ID] = customer
+	return nil
+}
+
+func (m *mockStore) GetCustomer(ctx context.Context, id string) (*models.Customer, error) {
+	return m.customers[id], nil
+}
+
+func (m *mockStore) SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error {
+	m.methods[method.ID] = method
+	return nil
+}
+
+func (m *mockStore) GetPaymentMethod(ctx context.Context, id string) (*models.SavedPaymentMethod, error) {
+	return m.methods[id], nil
+}
+
+func (m *mockStore) List(ctx context.Context, filter models.PaymentListFilter) ([]*models.Payment, error) {
+	payments := []*models.Payment{}
+	for _, payment := range m.payments {
+		if payment.ArchivedAt != nil && !filter.IncludeArchived {
+			continue
+		}
+		if filter.Mode != "" && payment.Mode != filter.Mode {
+			continue
+		}
+		if !strings.HasPrefix(payment.CustomerEmail, filter.EmailPrefix) {
+			continue
+		}
+		copied := *payment
+		payments = append(payments, &copied)
+	}
+	return payments, nil
+}
+
+func (m *mockStore) Count(ctx context.Context, filter models.PaymentListFilter) (int, error) {
+	payments, err := m.List(ctx, filter)
+	return len(payments), err
+}
+
+func (m *mockStore) ListFinalUpdatedSince(ctx context.Context, since time.Time, limit int) ([]*models.Payment, error) {
+	var payments []*models.Payment
+	for _, payment := range m.payments {
+		if payment.IsFinal() && payment.StripePaymentIntentID != "" && !payment.UpdatedAt.Before(since) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (m *mockStore) ListProcessingUpdatedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Payment, error) {
+	var payments []*models.Payment
+	for _, payment := range m.payments {
+		if payment.Status == models.PaymentStatusProcessing && payment.StripePaymentIntentID != "" && payment.UpdatedAt.Before(before) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func (m *mockStore) Archive(ctx context.Context, id string, at time.Time) error {
+	if payment, ok := m.payments[id]; ok && payment.ArchivedAt == nil {
+		payment.ArchivedAt = &at
+	}
+	return nil
+}
+
+func (m *mockStore) ArchiveOlderThan(ctx context.Context, cutoff, at time.Time) (int64, error) {
+	var archived int64
+	for _, payment := range m.payments {
+		if payment.ArchivedAt == nil && payment.CreatedAt.Before(cutoff) && payment.IsFinal() {
+			payment.ArchivedAt = &at
+			archived++
+		}
+	}
+	return archived, nil
+}
+
+func (m *mockStore) EnqueueReview(ctx context.Context, item *models.ReviewItem) error {
+	if _, ok := m.reviews[item.PaymentID]; !ok {
+		m.reviews[item.PaymentID] = item
+	}
+	return nil
+}
+
+func (m *mockStore) GetReview(ctx context.Context, paymentID string) (*models.ReviewItem, error) {
+	item, ok := m.reviews[paymentID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *item
+	return &copied, nil
+}
+
+func (m *mockStore) ListPendingReviews(ctx context.Context, limit int) ([]*models.ReviewItem, error) {
+	items := []*models.ReviewItem{}
+	for _, item := range m.reviews {
+		if item.Status == models.ReviewStatusPending && len(items) < limit {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (m *mockStore) ResolveReview(ctx context.Context, item *models.ReviewItem) (bool, error) {
+	stored, ok := m.reviews[item.PaymentID]
+	if !ok || stored.Status != models.ReviewStatusPending {
+		return false, nil
+	}
+	copied := *item
+	m.reviews[item.PaymentID] = &copied
+	return true, nil
+}
+
+func (m *mockStore) CreateMerchantWebhook(ctx context.Context, webhook *models.MerchantWebhook) error {
+	stored := *webhook
+	m.webhooks = append(m.webhooks, &stored)
+	return nil
+}
+
+func (m *mockStore) ListActiveMerchantWebhooks(ctx context.Context, merchantID string) ([]*models.MerchantWebhook, error) {
+	webhooks := []*models.MerchantWebhook{}
+	for _, webhook := range m.webhooks {
+		if webhook.Active && webhook.MerchantID == merchantID {
+			copied := *webhook
+			webhooks = append(webhooks, &copied)
+		}
+	}
+	return webhooks, nil
+}
+
+func (m *mockStore) SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	// Webhooks are delivered concurrently
+	m.deliveriesMu.Lock()
+	defer m.deliveriesMu.Unlock()
+	stored := *delivery
+	m.deliveries = append(m.deliveries, &stored)
+	return nil
+}
+
+func (m *mockStore) ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error) {
+	deliveries := []*models.WebhookDelivery{}
+	for i := len(m.deliveries) - 1; i >= 0 && len(deliveries) < filter.Limit; i-- {
+		delivery := m.deliveries[i]
+		if filter.WebhookID != "" && delivery.WebhookID != filter.WebhookID {
+			continue
+		}
+		if filter.PaymentID != "" && delivery.PaymentID != filter.PaymentID {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
+
+func (m *mockStore) PaymentAnalytics(ctx context.Context, filter models.PaymentAnalyticsFilter) ([]models.PaymentAnalyticsBucket, error) {
+	totals := map[[2]string]*models.PaymentAnalyticsBucket{}
+	for _, payment := range m.payments {
+		if payment.CreatedAt.Before(filter.From) || !payment.CreatedAt.Before(filter.To) {
+			continue
+		}
+		key := payment.CreatedAt.Format("2006-01-02")
+		switch filter.GroupBy {
+		case models.AnalyticsGroupByCurrency:
+			key = payment.Currency
+		case models.AnalyticsGroupByStatus:
+			key = string(payment.Status)
+		}
+		bucket, ok := totals[[2]string{key, payment.Currency}]
+		if !ok {
+			bucket = &models.PaymentAnalyticsBucket{Key: key, Currency: payment.Currency}
+			totals[[2]string{key, payment.Currency}] = bucket
+		}
+		bucket.Count++
+		bucket.TotalAmount += payment.Amount
+	}
+
+	buckets := []models.PaymentAnalyticsBucket{}
+	for _, bucket := range totals {
+		buckets = append(buckets, *bucket)
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Key != buckets[j].Key {
+			return buckets[i].Key < buckets[j].Key
+		}
+		return buckets[i].Currency < buckets[j].Currency
+	})
+	return buckets, nil
+}
+
+func (m *mockStore) CreateRefund(ctx context.Context, refund *models.Refund) (bool, error) {
+	payment, ok := m.payments[refund.PaymentID]
+	if !ok || payment.Status != models.PaymentStatusSucceeded || refund.Amount > payment.RefundableAmount() {
+		return false, nil
+	}
+	if refund.IdempotencyKey != "" {
+		if existing, _ := m.GetRefundByIdempotencyKey(ctx, refund.PaymentID, refund.IdempotencyKey); existing != nil {
+			return false, nil
+		}
+	}
+	payment.RefundedAmount += refund.Amount
+	stored := *refund
+	m.refunds[refund.ID] = &stored
+	return true, nil
+}
+
+func (m *mockStore) GetRefundByIdempotencyKey(ctx context.Context, paymentID, key string) (*models.Refund, error) {
+	for _, refund := range m.refunds {
+		if refund.PaymentID == paymentID && refund.IdempotencyKey == key {
+			copied := *refund
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) CompleteRefund(ctx context.Context, refundID, stripeRefundID string) error {
+	if refund, ok := m.refunds[refundID]; ok && refund.Status == models.RefundStatusPending {
+		refund.Status = models.RefundStatusSucceeded
+		refund.StripeRefundID = stripeRefundID
+	}
+	return nil
+}
+
+func (m *mockStore) FailRefund(ctx context.Context, refund *models.Refund) error {
+	stored, ok := m.refunds[refund.ID]
+	if !ok || stored.Status != models.RefundStatusPending {
+		return nil
+	}
+	stored.Status = models.RefundStatusFailed
+	m.payments[refund.PaymentID].RefundedAmount -= refund.Amount
+	return nil
+}
+
+// fixedRates is a CurrencyConverter with static rates keyed by "FROM:TO"
+type fixedRates map[string]float64
+
+func (r fixedRates) GetRate(ctx context.Context, from, to string) (float64, error) {
+	if from == to {
+		return 1, nil
+	}
+	rate, ok := r[from+":"+to]
+	if !ok {
+		return 0, fmt.Errorf("no rate for %s to %s", from, to)
+	}
+	return rate, nil
+}
@@ -7,31 +7,117 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/stripe/stripe-go/v76"
-	"github.com/stripe/stripe-go/v76/paymentintent"
 
 	"payment-gateway/internal/models"
-	"payment-gateway/internal/repository"
+	"shared/pkg/ids"
 	"shared/pkg/redis"
 )
 
+// paymentEventsChannel is the Redis channel payment lifecycle events are published on
+const paymentEventsChannel = "payment.events"
+
+// Actors recorded in the payment timeline
+const (
+	actorAPI           = "api"
+	actorCustomer      = "customer"
+	actorStripeWebhook = "stripe_webhook"
+	actorFraudCheck    = "fraud_check"
+	actorReviewer      = "reviewer"
+	actorStripeSync    = "stripe_sync"
+)
+
+var (
+	ErrPaymentNotFound         = errors.New("payment not found")
+	ErrInvalidCardNumber       = errors.New("invalid card number")
+	ErrUnsupportedCardNetwork  = errors.New("unsupported card network")
+	ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+	ErrPaymentNotCapturable    = errors.New("payment is not awaiting capture")
+	ErrInvalidCaptureAmount    = errors.New("capture amount must be positive and not exceed the authorized amount")
+)
+
+// PaymentStore persists payments; implemented by repository.PaymentRepository
+type PaymentStore interface {
+	Create(ctx context.Context, payment *models.Payment) error
+	GetByID(ctx context.Context, id string) (*models.Payment, error)
+	GetByStripeIntentID(ctx context.Context, intentID string) (*models.Payment, error)
+	GetByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error)
+	Update(ctx context.Context, payment *models.Payment) error
+	CreateEvent(ctx context.Context, event *models.PaymentEvent) error
+	ListEvents(ctx context.Context, paymentID string) ([]*models.PaymentEvent, error)
+	MarkEventProcessed(ctx context.Context, eventID, eventType string, payload []byte) (bool, error)
+	UnmarkEvent(ctx context.Context, eventID string) error
+	GetWebhookEvent(ctx context.Context, eventID string) (*models.WebhookEvent, error)
+	GetCustomerLimit(ctx context.Context, customerEmail string) (*models.CustomerLimit, error)
+	SumSucceededSince(ctx context.Context, customerEmail string, since time.Time) (map[string]float64, error)
+	CreateCustomer(ctx context.Context, customer *models.Customer) error
+	GetCustomer(ctx context.Context, id string) (*models.Customer, error)
+	SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error
+	GetPaymentMethod(ctx context.Context, id string) (*models.SavedPaymentMethod, error)
+	List(ctx context.Context, filter models.PaymentListFilter) ([]*models.Payment, error)
+	Count(ctx context.Context, filter models.PaymentListFilter) (int, error)
+	Archive(ctx context.Context, id string, at time.Time) error
+	ArchiveOlderThan(ctx context.Context, cutoff, at time.Time) (int64, error)
+	EnqueueReview(ctx context.Context, item *models.ReviewItem) error
+	GetReview(ctx context.Context, paymentID string) (*models.ReviewItem, error)
+	ListPendingReviews(ctx context.Context, limit int) ([]*models.ReviewItem, error)
+	ResolveReview(ctx context.Context, item *models.ReviewItem) (bool, error)
+	ListFinalUpdatedSince(ctx context.Context, since time.Time, limit int) ([]*models.Payment, error)
+	ListProcessingUpdatedBefore(ctx context.Context, before time.Time, limit int) ([]*models.Payment, error)
+	CreateMerchantWebhook(ctx context.Context, webhook *models.MerchantWebhook) error
+	ListActiveMerchantWebhooks(ctx context.Context, merchantID string) ([]*models.MerchantWebhook, error)
+	SaveWebhookDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	ListWebhookDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, error)
+	PaymentAnalytics(ctx context.Context, filter models.PaymentAnalyticsFilter) ([]models.PaymentAnalyticsBucket, error)
+	CreateRefund(ctx context.Context, refund *models.Refund) (bool, error)
+	GetRefundByIdempotencyKey(ctx context.Context, paymentID, key string) (*models.Refund, error)
+	CompleteRefund(ctx context.Context, refundID, stripeRefundID string) error
+	FailRefund(ctx context.Context, refund *models.Refund) error
+}
+
 type PaymentService struct {
-	repo        *repository.PaymentRepository
-	redisClient *redis.Client
-	stripeKey   string
+	repo          PaymentStore
+	redisClient   *redis.Client
+	processor     PaymentProcessor
+	converter     CurrencyConverter
+	fraud         FraudChecker
+	bins          *BINLookup
+	mode          models.PaymentMode
+	stripeKey     string
+	publicURL     string
+	webhookSecret string
+	merchantName  string
+
+	// previousWebhookSecrets still verify webhooks while a rotation rolls out
+	previousWebhookSecrets []WebhookSecret
+	// webhookTolerance is how old a signed webhook may be; zero means Stripe's default
+	webhookTolerance time.Duration
+	// maxAmounts caps a single payment; zero values leave amounts unbounded
+	maxAmounts MaxAmounts
+
+	// merchantWebhooks forwards lifecycle events to merchants; nil until enabled
+	merchantWebhooks *merchantWebhookSender
 }
 
-func NewPaymentService(repo *repository.PaymentRepository, redisClient *redis.Client, cfg interface{}) *PaymentService {
+func NewPaymentService(repo PaymentStore, redisClient *redis.Client, cfg interface{}) *PaymentService {
 	// Set Stripe API key
 	stripe.Key = cfg.(map[string]string)["stripe_key"]
 	
 	return &PaymentService{
-		repo:        repo,
-		redisClient: redisClient,
-		stripeKey:   cfg.(map[string]string)["stripe_key"],
+		repo:          repo,
+		redisClient:   redisClient,
+		processor:     stripeProcessor{},
+		converter:     NewCurrencyClient(cfg.(map[string]string)["currency_service_url"]),
+		fraud:         NewFraudClient(cfg.(map[string]string)["fraud_service_url"]),
+		bins:          NewBINLookup(nil),
+		mode:          models.PaymentMode(cfg.(map[string]string)["stripe_mode"]),
+		stripeKey:     cfg.(map[string]string)["stripe_key"],
+		publicURL:     cfg.(map[string]string)["public_url"],
+		webhookSecret: cfg.(map[string]string)["webhook_secret"],
+		merchantName:  cfg.(map[string]string)["merchant_name"],
 	}
 }
 
@@ -44,55 +130,91 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req *models.PaymentR
 		}
 	}
 
-	// Validate card using Luhn algorithm
-	if !ValidateLuhnChecksum(req.CardNumber) {
-		return nil, errors.New("invalid card number")
-	}
-
-	// Detect card network
-	cardNetwork := DetectCardNetwork(req.CardNumber)
-	if cardNetwork == "" {
-		return nil, errors.New("unsupported card network")
+	source, err := s.validatePayment(ctx, req)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create payment record
 	payment := &models.Payment{
-		ID:              uuid.New().String(),
+		ID:              ids.Payment(),
+		MerchantID:      req.MerchantID,
 		Amount:          req.Amount,
 		Currency:        req.Currency,
 		Status:          models.PaymentStatusPending,
-		CardLast4:       req.CardNumber[len(req.CardNumber)-4:],
-		CardNetwork:     cardNetwork,
+		Mode:            s.mode,
+		CardLast4:       source.CardLast4,
+		CardNetwork:     source.CardNetwork,
 		CustomerEmail:   req.CustomerEmail,
 		Description:     req.Description,
 		IdempotencyKey:  req.IdempotencyKey,
+		ReturnURL:       req.ReturnURL,
 		Metadata:        req.Metadata,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
 	}
 
+	// Lock in the settlement amount before charging
+	if err := s.applySettlement(ctx, payment, req.SettlementCurrency); err != nil {
+		return nil, err
+	}
+
+	// Consult the fraud service before anything reaches Stripe
+	assessment := s.assessFraud(ctx, payment, newFraudCheck(payment.ID, req, source))
+	if assessment != nil && assessment.Decision == models.FraudDecisionBlock {
+		return nil, s.rejectBlockedPayment(ctx, payment, assessment)
+	}
+
+	// Async payments are confirmed right away and settle in the background, unless
+	// they are held for review first
+	held := assessment != nil && assessment.Decision == models.FraudDecisionReview
+	confirm := req.Async && !held
+
 	// Process with Stripe
-	stripeIntent, err := s.createStripePaymentIntent(req)
+	stripeIntent, err := s.createStripePaymentIntent(req, source, confirm)
 	if err != nil {
 		payment.Status = models.PaymentStatusFailed
 		payment.FailureReason = err.Error()
-		s.repo.Create(ctx, payment)
+		if s.repo.Create(ctx, payment) == nil {
+			s.recordEvent(ctx, payment, "", actorAPI, "payment creation failed")
+		}
 		return nil, fmt.Errorf("stripe payment failed: %w", err)
 	}
 
 	payment.StripePaymentIntentID = stripeIntent.ID
 	payment.ClientSecret = stripeIntent.ClientSecret
 
-	// Check if 3DS is required
+	// Check if 3DS is required; otherwise a confirmed intent is already charging
 	if stripeIntent.Status == stripe.PaymentIntentStatusRequiresAction {
 		payment.Requires3DS = true
 		payment.Status = models.PaymentStatusRequiresAction
+		payment.RedirectURL = redirectURLFromIntent(stripeIntent)
+	} else if confirm {
+		payment.Status = models.PaymentStatusProcessing
+	}
+
+	// Hold the payment until a reviewer approves it
+	reason := "payment created"
+	if payment.Status == models.PaymentStatusProcessing {
+		reason = "payment created, processing asynchronously"
+	}
+	if held {
+		payment.Status = models.PaymentStatusPendingReview
+		reason = "payment created, held for fraud review"
 	}
 
 	// Save to database
 	if err := s.repo.Create(ctx, payment); err != nil {
 		return nil, fmt.Errorf("failed to save payment: %w", err)
 	}
+	if err := s.recordEvent(ctx, payment, "", actorAPI, reason); err != nil {
+		return nil, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	if payment.Status == models.PaymentStatusPendingReview {
+		if err := s.enqueueReview(ctx, payment, assessment); err != nil {
+			return nil, fmt.Errorf("failed to queue payment for review: %w", err)
+		}
+	}
 
 	// Cache for idempotency
 	if req.IdempotencyKey != "" {
@@ -105,33 +227,203 @@ func (s *PaymentService) CreatePayment(ctx context.Context, req *models.PaymentR
 	return payment, nil
 }
 
+// validatePayment runs every check a payment must pass before it is charged and
+// resolves what is being charged: a saved payment method or raw card details
+func (s *PaymentService) validatePayment(ctx context.Context, req *models.PaymentRequest) (*chargeSource, error) {
+	// Reject oversized amounts before anything else, so they never reach Stripe
+	if err := s.checkMaxAmount(ctx, req); err != nil {
+		return nil, err
+	}
+	if req.Async && req.PaymentMethodID == "" {
+		return nil, ErrAsyncRequiresPaymentMethod
+	}
+
+	source := &chargeSource{}
+	if req.PaymentMethodID != "" {
+		saved, err := s.savedChargeSource(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		source = saved
+	} else {
+		// Validate card using Luhn algorithm
+		if !ValidateLuhnChecksum(req.CardNumber) {
+			return nil, ErrInvalidCardNumber
+		}
+
+		// Identify the card's network and issuer from its BIN
+		bin := s.bins.Lookup(ctx, req.CardNumber)
+		if bin.Network == "" {
+			return nil, ErrUnsupportedCardNetwork
+		}
+
+		source.CardLast4 = req.CardNumber[len(req.CardNumber)-4:]
+		source.CardNetwork = bin.Network
+		source.IssuerCountry = bin.Country
+	}
+
+	// Enforce the customer's daily spend limit
+	if err := s.checkCustomerLimit(ctx, req); err != nil {
+		return nil, err
+	}
+
+	return source, nil
+}
+
 // ConfirmPayment confirms a payment after 3DS authentication
 func (s *PaymentService) ConfirmPayment(ctx context.Context, paymentID string) (*models.Payment, error) {
 	payment, err := s.repo.GetByID(ctx, paymentID)
 	if err != nil {
 		return nil, err
 	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.Status == models.PaymentStatusPendingReview {
+		return nil, ErrPaymentUnderReview
+	}
 
-	// Confirm with Stripe
-	intent, err := paymentintent.Confirm(payment.StripePaymentIntentID, nil)
+	// Confirm with Stripe, sending the customer back to us after any 3DS challenge
+	params := &stripe.PaymentIntentConfirmParams{}
+	if s.publicURL != "" {
+		params.ReturnURL = stripe.String(s.threeDSReturnURL(payment.ID))
+	}
+
+	intent, err := s.processor.ConfirmPaymentIntent(payment.StripePaymentIntentID, params)
 	if err != nil {
 		return nil, err
 	}
 
 	// Update payment status
+	newStatus := payment.Status
 	if intent.Status == stripe.PaymentIntentStatusSucceeded {
-		payment.Status = models.PaymentStatusSucceeded
+		newStatus = models.PaymentStatusSucceeded
+		payment.AuthorizedAmount = payment.Amount
+		payment.CapturedAmount = payment.Amount
 		payment.CompletedAt = time.Now()
-		s.publishPaymentEvent(ctx, "payment.succeeded", payment)
+	} else if intent.Status == stripe.PaymentIntentStatusRequiresCapture {
+		// Manual capture: funds are authorized and held until CapturePayment
+		newStatus = models.PaymentStatusRequiresCapture
+		payment.AuthorizedAmount = payment.Amount
 	} else if intent.Status == stripe.PaymentIntentStatusProcessing {
-		payment.Status = models.PaymentStatusProcessing
+		newStatus = models.PaymentStatusProcessing
+	} else if intent.Status == stripe.PaymentIntentStatusRequiresAction {
+		payment.Requires3DS = true
+		newStatus = models.PaymentStatusRequiresAction
+		payment.RedirectURL = redirectURLFromIntent(intent)
 	}
 
-	payment.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, payment); err != nil {
+	if err := s.transition(ctx, payment, newStatus, actorAPI, "payment confirmed"); err != nil {
+		return nil, err
+	}
+
+	if payment.Status == models.PaymentStatusSucceeded {
+		s.publishPaymentEvent(ctx, "payment.succeeded", payment)
+	}
+
+	return payment, nil
+}
+
+// CapturePayment captures an authorized payment. An amount below the authorization
+// performs a partial capture and Stripe releases the uncaptured remainder.
+// A zero amount captures the full authorization.
+func (s *PaymentService) CapturePayment(ctx context.Context, paymentID string, amount float64) (*models.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	if payment.Status != models.PaymentStatusRequiresCapture {
+		return nil, ErrPaymentNotCapturable
+	}
+
+	if amount == 0 {
+		amount = payment.AuthorizedAmount
+	}
+	if err := applyCapture(payment, amount); err != nil {
+		return nil, err
+	}
+
+	params := &stripe.PaymentIntentCaptureParams{
+		AmountToCapture: stripe.Int64(toStripeAmount(amount, payment.Currency)),
+	}
+	if _, err := s.processor.CapturePaymentIntent(payment.StripePaymentIntentID, params); err != nil {
+		return nil, fmt.Errorf("stripe capture failed: %w", err)
+	}
+
+	payment.CompletedAt = time.Now()
+	reason := fmt.Sprintf("captured %.2f, released %.2f", payment.CapturedAmount, payment.ReleasedAmount())
+	if err := s.transition(ctx, payment, models.PaymentStatusSucceeded, actorAPI, reason); err != nil {
 		return nil, err
 	}
 
+	s.publishPaymentEvent(ctx, "payment.succeeded", payment)
+	return payment, nil
+}
+
+// applyCapture validates a capture amount against the authorization and records it
+func applyCapture(payment *models.Payment, amount float64) error {
+	if amount <= 0 || amount > payment.AuthorizedAmount {
+		return ErrInvalidCaptureAmount
+	}
+	payment.CapturedAmount = amount
+	return nil
+}
+
+// CompleteThreeDS finalizes a payment once the customer returns from the 3DS redirect.
+// If the customer abandoned the challenge the payment is left in requires_action.
+func (s *PaymentService) CompleteThreeDS(ctx context.Context, paymentID string) (*models.Payment, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	// A webhook may have already finalized the payment
+	if payment.Status != models.PaymentStatusRequiresAction {
+		return payment, nil
+	}
+
+	intent, err := s.processor.GetPaymentIntent(payment.StripePaymentIntentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var newStatus models.PaymentStatus
+	switch intent.Status {
+	case stripe.PaymentIntentStatusSucceeded:
+		newStatus = models.PaymentStatusSucceeded
+		payment.CompletedAt = time.Now()
+	case stripe.PaymentIntentStatusProcessing:
+		newStatus = models.PaymentStatusProcessing
+	case stripe.PaymentIntentStatusRequiresPaymentMethod:
+		// Stripe moves the intent back here when authentication fails
+		newStatus = models.PaymentStatusFailed
+		payment.FailureReason = "3ds_authentication_failed"
+		if intent.LastPaymentError != nil && intent.LastPaymentError.Msg != "" {
+			payment.FailureReason = intent.LastPaymentError.Msg
+		}
+	default:
+		// Still requires_action: the customer has not completed the challenge
+		return payment, nil
+	}
+
+	if err := s.transition(ctx, payment, newStatus, actorCustomer, "3ds authentication completed"); err != nil {
+		return nil, err
+	}
+
+	switch payment.Status {
+	case models.PaymentStatusSucceeded:
+		s.publishPaymentEvent(ctx, "payment.succeeded", payment)
+	case models.PaymentStatusFailed:
+		s.publishPaymentEvent(ctx, "payment.failed", payment)
+	}
+
 	return payment, nil
 }
 
@@ -140,39 +432,216 @@ func (s *PaymentService) GetPayment(ctx context.Context, paymentID string) (*mod
 	return s.repo.GetByID(ctx, paymentID)
 }
 
+// GetPaymentByIdempotencyKey finds the payment created with an idempotency key, for
+// clients that lost the payment ID
+func (s *PaymentService) GetPaymentByIdempotencyKey(ctx context.Context, key string) (*models.Payment, error) {
+	payment, err := s.repo.GetByIdempotencyKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	return payment, nil
+}
+
 // CancelPayment cancels a pending payment
 func (s *PaymentService) CancelPayment(ctx context.Context, paymentID string) error {
 	payment, err := s.repo.GetByID(ctx, paymentID)
 	if err != nil {
 		return err
 	}
+	if payment == nil {
+		return ErrPaymentNotFound
+	}
 
 	if payment.Status != models.PaymentStatusPending && payment.Status != models.PaymentStatusRequiresAction {
 		return errors.New("payment cannot be cancelled")
 	}
 
 	// Cancel with Stripe
-	_, err = paymentintent.Cancel(payment.StripePaymentIntentID, nil)
+	_, err = s.processor.CancelPaymentIntent(payment.StripePaymentIntentID)
 	if err != nil {
 		return err
 	}
 
-	payment.Status = models.PaymentStatusCancelled
+	if err := s.transition(ctx, payment, models.PaymentStatusCancelled, actorAPI, "cancelled by merchant"); err != nil {
+		return err
+	}
+
+	s.publishPaymentEvent(ctx, "payment.cancelled", payment)
+	return nil
+}
+
+// HandleStripeWebhook verifies a Stripe webhook payload and processes the event
+func (s *PaymentService) HandleStripeWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.verifyWebhook(payload, signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhookSignature, err)
+	}
+
+	_, err = s.ProcessStripeEvent(ctx, event, payload)
+	return err
+}
+
+// ProcessStripeEvent applies a verified Stripe event at most once, storing the
+// payload it arrived in so the event can be replayed later.
+// It returns false when the event was already processed.
+func (s *PaymentService) ProcessStripeEvent(ctx context.Context, event stripe.Event, payload []byte) (bool, error) {
+	firstSeen, err := s.repo.MarkEventProcessed(ctx, event.ID, string(event.Type), payload)
+	if err != nil {
+		return false, fmt.Errorf("failed to record webhook event: %w", err)
+	}
+	if !firstSeen {
+		return false, nil
+	}
+
+	if err := s.applyStripeEvent(ctx, event); err != nil {
+		// Forget the event so Stripe's retry can process it again
+		if unmarkErr := s.repo.UnmarkEvent(ctx, event.ID); unmarkErr != nil {
+			return false, fmt.Errorf("%v (and failed to release event: %v)", err, unmarkErr)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (s *PaymentService) applyStripeEvent(ctx context.Context, event stripe.Event) error {
+	switch event.Type {
+	case "payment_intent.succeeded", "payment_intent.payment_failed",
+		"payment_intent.canceled", "payment_intent.requires_action":
+	default:
+		// Not an event we act on
+		return nil
+	}
+
+	var intent stripe.PaymentIntent
+	if err := json.Unmarshal(event.Data.Raw, &intent); err != nil {
+		return fmt.Errorf("failed to parse payment intent: %w", err)
+	}
+
+	payment, err := s.repo.GetByStripeIntentID(ctx, intent.ID)
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return nil
+	}
+
+	// Succeeded and cancelled payments are settled. A stale or replayed event, such as
+	// the payment_failed Stripe sends before a customer's successful retry, must not
+	// move them back; divergence from Stripe is left to SyncWithStripe.
+	if payment.Status == models.PaymentStatusSucceeded || payment.Status == models.PaymentStatusCancelled {
+		// The succeeded event still brings the processor fee of a payment ConfirmPayment settled
+		if event.Type == "payment_intent.succeeded" && payment.Status == models.PaymentStatusSucceeded && payment.FeeCurrency == "" {
+			s.recordProcessorFee(ctx, payment, &intent)
+			if payment.FeeCurrency != "" {
+				return s.repo.Update(ctx, payment)
+			}
+		}
+		return nil
+	}
+
+	from := payment.Status
+	var newStatus models.PaymentStatus
+	var publishType string
+	switch event.Type {
+	case "payment_intent.succeeded":
+		newStatus = models.PaymentStatusSucceeded
+		if payment.CompletedAt.IsZero() {
+			payment.CompletedAt = time.Now()
+		}
+		// An async payment is settled by the webhook rather than ConfirmPayment
+		if payment.CapturedAmount == 0 {
+			captured := payment.Amount
+			if intent.AmountReceived > 0 {
+				captured = fromStripeAmount(intent.AmountReceived, payment.Currency)
+			}
+			payment.AuthorizedAmount = captured
+			payment.CapturedAmount = captured
+		}
+		s.recordProcessorFee(ctx, payment, &intent)
+		publishType = "payment.succeeded"
+	case "payment_intent.payment_failed":
+		newStatus = models.PaymentStatusFailed
+		if intent.LastPaymentError != nil {
+			payment.FailureReason = intent.LastPaymentError.Msg
+		}
+		publishType = "payment.failed"
+	case "payment_intent.canceled":
+		newStatus = models.PaymentStatusCancelled
+		publishType = "payment.cancelled"
+	case "payment_intent.requires_action":
+		payment.Requires3DS = true
+		newStatus = models.PaymentStatusRequiresAction
+		payment.RedirectURL = redirectURLFromIntent(&intent)
+	}
+
+	if err := s.transition(ctx, payment, newStatus, actorStripeWebhook, string(event.Type)); err != nil {
+		return err
+	}
+
+	// A repeated event leaves the status as it was and is not announced again
+	if publishType != "" && from != newStatus {
+		s.publishPaymentEvent(ctx, publishType, payment)
+	}
+	return nil
+}
+
+// GetTimeline returns the recorded status history of a payment, oldest first
+func (s *PaymentService) GetTimeline(ctx context.Context, paymentID string) ([]*models.PaymentEvent, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	return s.repo.ListEvents(ctx, paymentID)
+}
+
+// transition moves a payment to a new status, persists it and records the change
+// in the payment's timeline. Every status change after creation goes through here.
+func (s *PaymentService) transition(ctx context.Context, payment *models.Payment, to models.PaymentStatus, actor, reason string) error {
+	from := payment.Status
+	payment.Status = to
 	payment.UpdatedAt = time.Now()
-	
+
 	if err := s.repo.Update(ctx, payment); err != nil {
 		return err
 	}
 
-	s.publishPaymentEvent(ctx, "payment.cancelled", payment)
+	if from == to {
+		return nil
+	}
+
+	if err := s.recordEvent(ctx, payment, from, actor, reason); err != nil {
+		return fmt.Errorf("failed to record payment event: %w", err)
+	}
 	return nil
 }
 
+func (s *PaymentService) recordEvent(ctx context.Context, payment *models.Payment, from models.PaymentStatus, actor, reason string) error {
+	return s.repo.CreateEvent(ctx, &models.PaymentEvent{
+		ID:        ids.New(),
+		PaymentID: payment.ID,
+		OldStatus: from,
+		NewStatus: payment.Status,
+		Actor:     actor,
+		Reason:    reason,
+		CreatedAt: payment.UpdatedAt,
+	})
+}
+
 // Helper functions
 
-func (s *PaymentService) createStripePaymentIntent(req *models.PaymentRequest) (*stripe.PaymentIntent, error) {
+// createStripePaymentIntent creates the payment's intent; a confirmed intent starts
+// charging immediately rather than waiting for ConfirmPayment
+func (s *PaymentService) createStripePaymentIntent(req *models.PaymentRequest, source *chargeSource, confirm bool) (*stripe.PaymentIntent, error) {
 	params := &stripe.PaymentIntentParams{
-		Amount:   stripe.Int64(int64(req.Amount * 100)), // Convert to cents
+		Amount:   stripe.Int64(toStripeAmount(req.Amount, req.Currency)),
 		Currency: stripe.String(req.Currency),
 		PaymentMethodTypes: stripe.StringSlice([]string{
 			"card",
@@ -184,10 +653,41 @@ func (s *PaymentService) createStripePaymentIntent(req *models.PaymentRequest) (
 		params.ReceiptEmail = stripe.String(req.CustomerEmail)
 	}
 
-	return paymentintent.New(params)
+	if req.CaptureMethod == "manual" {
+		params.CaptureMethod = stripe.String(string(stripe.PaymentIntentCaptureMethodManual))
+	}
+
+	if source.PaymentMethodID != "" {
+		params.Customer = stripe.String(source.StripeCustomerID)
+		params.PaymentMethod = stripe.String(source.PaymentMethodID)
+	}
+
+	if req.Async {
+		params.PaymentMethodTypes = stripe.StringSlice(asyncPaymentMethodTypes)
+	}
+	if confirm {
+		params.Confirm = stripe.Bool(true)
+	}
+
+	return s.processor.CreatePaymentIntent(params)
+}
+
+func (s *PaymentService) threeDSReturnURL(paymentID string) string {
+	return fmt.Sprintf("%s/api/v1/payments/%s/3ds/return", s.publicURL, paymentID)
+}
+
+// redirectURLFromIntent extracts the 3DS redirect URL from Stripe's next_action
+func redirectURLFromIntent(intent *stripe.PaymentIntent) string {
+	if intent.NextAction == nil || intent.NextAction.RedirectToURL == nil {
+		return ""
+	}
+	return intent.NextAction.RedirectToURL.URL
 }
 
 func (s *PaymentService) getIdempotentPayment(ctx context.Context, key string) (*models.Payment, error) {
+	if s.redisClient == nil {
+		return nil, nil
+	}
 	cacheKey := fmt.Sprintf("idempotency:%s", key)
 	data, err := s.redisClient.Get(ctx, cacheKey)
 	if err != nil {
@@ -203,15 +703,62 @@ func (s *PaymentService) getIdempotentPayment(ctx context.Context, key string) (
 }
 
 func (s *PaymentService) cacheIdempotentPayment(ctx context.Context, key string, payment *models.Payment) {
+	if s.redisClient == nil {
+		return
+	}
 	cacheKey := fmt.Sprintf("idempotency:%s", key)
 	data, _ := json.Marshal(payment)
 	s.redisClient.Set(ctx, cacheKey, data, 24*time.Hour)
 }
 
 func (s *PaymentService) publishPaymentEvent(ctx context.Context, eventType string, payment *models.Payment) {
-	// This would publish to Kafka/RabbitMQ
-	// For now, just log
-	fmt.Printf("Event: %s - Payment ID: %s\n", eventType, payment.ID)
+	s.publishLifecycleEvent(ctx, newLifecycleEvent(eventType, payment))
+}
+
+// newLifecycleEvent describes payment's current state as an event of eventType
+func newLifecycleEvent(eventType string, payment *models.Payment) *models.PaymentLifecycleEvent {
+	amount := payment.Amount
+	if payment.CapturedAmount > 0 {
+		amount = payment.CapturedAmount
+	}
+
+	return &models.PaymentLifecycleEvent{
+		ID:          ids.New(),
+		Type:        eventType,
+		PaymentID:   payment.ID,
+		MerchantID:  payment.MerchantID,
+		Amount:      amount,
+		Currency:    payment.Currency,
+		Mode:        string(payment.Mode),
+		FeeAmount:   payment.FeeAmount,
+		NetAmount:   payment.NetAmount,
+		FeeCurrency: payment.FeeCurrency,
+		OccurredAt:  time.Now(),
+	}
+}
+
+func (s *PaymentService) publishLifecycleEvent(ctx context.Context, event *models.PaymentLifecycleEvent) {
+	fmt.Printf("Event: %s - Payment ID: %s\n", event.Type, event.PaymentID)
+	if s.redisClient == nil && s.merchantWebhooks == nil {
+		return
+	}
+
+	// Deliveries retry for minutes, so they outlive the request that triggered them
+	if s.merchantWebhooks != nil {
+		go s.DispatchMerchantWebhooks(context.Background(), event)
+	}
+	if s.redisClient == nil {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	if err := s.redisClient.Publish(ctx, paymentEventsChannel, data); err != nil {
+		fmt.Printf("Failed to publish %s for payment %s: %v\n", event.Type, event.PaymentID, err)
+	}
 }
 
 // ValidateLuhnChecksum validates a card number using Luhn algorithm
@@ -233,26 +780,42 @@ func ValidateLuhnChecksum(cardNumber string) bool {
 	return sum%10 == 0
 }
 
+// cardNetworkRanges are the IIN ranges each network issues from. A range compares
+// the card number's first digits numerically, so prefixes of any length can be mixed.
+var cardNetworkRanges = []struct {
+	network string
+	digits  int
+	low     int
+	high    int
+}{
+	{"visa", 1, 4, 4},
+	{"amex", 2, 34, 34},
+	{"amex", 2, 37, 37},
+	{"mastercard", 2, 51, 55},
+	{"mastercard", 4, 2221, 2720},
+	{"discover", 4, 6011, 6011},
+	{"discover", 3, 644, 649},
+	{"discover", 2, 65, 65},
+	{"jcb", 4, 3528, 3589},
+	{"diners", 3, 300, 305},
+	{"diners", 2, 36, 36},
+	{"diners", 2, 38, 39},
+	{"unionpay", 2, 62, 62},
+}
+
 // DetectCardNetwork detects the card network based on IIN
 func DetectCardNetwork(cardNumber string) string {
-	if len(cardNumber) < 2 {
-		return ""
-	}
-
-	prefix := cardNumber[:2]
-	
-	switch {
-	case prefix == "34" || prefix == "37":
-		return "amex"
-	case prefix >= "40" && prefix <= "49":
-		return "visa"
-	case prefix >= "51" && prefix <= "55":
-		return "mastercard"
-	case prefix >= "22" && prefix <= "27":
-		return "mastercard"
-	case prefix >= "60" && prefix <= "65":
-		return "discover"
-	default:
-		return ""
+	for _, r := range cardNetworkRanges {
+		if len(cardNumber) < r.digits {
+			continue
+		}
+		prefix, err := strconv.Atoi(cardNumber[:r.digits])
+		if err != nil {
+			continue
+		}
+		if prefix >= r.low && prefix <= r.high {
+			return r.network
+		}
 	}
-}
\ No newline at end of file
+	return ""
+}
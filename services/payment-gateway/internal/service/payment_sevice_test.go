@@ -69,6 +69,51 @@ func TestDetectCardNetwork(t *testing.T) {
 			cardNumber: "378282246310005",
 			want:       "amex",
 		},
+		{
+			name:       "Mastercard 2-series",
+			cardNumber: "2223003122003222",
+			want:       "mastercard",
+		},
+		{
+			name:       "Discover 6011",
+			cardNumber: "6011111111111117",
+			want:       "discover",
+		},
+		{
+			name:       "Discover 65",
+			cardNumber: "6500000000000002",
+			want:       "discover",
+		},
+		{
+			name:       "JCB",
+			cardNumber: "3566002020360505",
+			want:       "jcb",
+		},
+		{
+			name:       "Diners 36",
+			cardNumber: "36227206271667",
+			want:       "diners",
+		},
+		{
+			name:       "Diners 38",
+			cardNumber: "38520000023237",
+			want:       "diners",
+		},
+		{
+			name:       "UnionPay",
+			cardNumber: "6200000000000005",
+			want:       "unionpay",
+		},
+		{
+			name:       "Outside Discover 6011",
+			cardNumber: "6012000000000000",
+			want:       "",
+		},
+		{
+			name:       "Outside Mastercard 2-series",
+			cardNumber: "2721000000000000",
+			want:       "",
+		},
 		{
 			name:       "Unknown",
 			cardNumber: "1234567890123456",
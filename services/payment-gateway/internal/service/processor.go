@@ -0,0 +1,70 @@
+package service
+
+import (
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/charge"
+	"github.com/stripe/stripe-go/v76/customer"
+	"github.com/stripe/stripe-go/v76/paymentintent"
+	"github.com/stripe/stripe-go/v76/paymentmethod"
+	"github.com/stripe/stripe-go/v76/refund"
+)
+
+// PaymentProcessor is the card processor payments are charged through
+type PaymentProcessor interface {
+	CreatePaymentIntent(params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error)
+	ConfirmPaymentIntent(id string, params *stripe.PaymentIntentConfirmParams) (*stripe.PaymentIntent, error)
+	GetPaymentIntent(id string) (*stripe.PaymentIntent, error)
+	CapturePaymentIntent(id string, params *stripe.PaymentIntentCaptureParams) (*stripe.PaymentIntent, error)
+	CancelPaymentIntent(id string) (*stripe.PaymentIntent, error)
+	CreateCustomer(params *stripe.CustomerParams) (*stripe.Customer, error)
+	AttachPaymentMethod(id string, params *stripe.PaymentMethodAttachParams) (*stripe.PaymentMethod, error)
+	GetBalanceTransaction(chargeID string) (*stripe.BalanceTransaction, error)
+	CreateRefund(params *stripe.RefundParams) (*stripe.Refund, error)
+}
+
+// stripeProcessor calls the Stripe API using the globally configured key
+type stripeProcessor struct{}
+
+func (stripeProcessor) CreatePaymentIntent(params *stripe.PaymentIntentParams) (*stripe.PaymentIntent, error) {
+	return paymentintent.New(params)
+}
+
+func (stripeProcessor) ConfirmPaymentIntent(id string, params *stripe.PaymentIntentConfirmParams) (*stripe.PaymentIntent, error) {
+	return paymentintent.Confirm(id, params)
+}
+
+func (stripeProcessor) GetPaymentIntent(id string) (*stripe.PaymentIntent, error) {
+	return paymentintent.Get(id, nil)
+}
+
+func (stripeProcessor) CapturePaymentIntent(id string, params *stripe.PaymentIntentCaptureParams) (*stripe.PaymentIntent, error) {
+	return paymentintent.Capture(id, params)
+}
+
+func (stripeProcessor) CancelPaymentIntent(id string) (*stripe.PaymentIntent, error) {
+	return paymentintent.Cancel(id, nil)
+}
+
+func (stripeProcessor) CreateCustomer(params *stripe.CustomerParams) (*stripe.Customer, error) {
+	return customer.New(params)
+}
+
+func (stripeProcessor) AttachPaymentMethod(id string, params *stripe.PaymentMethodAttachParams) (*stripe.PaymentMethod, error) {
+	return paymentmethod.Attach(id, params)
+}
+
+// GetBalanceTransaction returns the balance transaction that settled a charge
+func (stripeProcessor) GetBalanceTransaction(chargeID string) (*stripe.BalanceTransaction, error) {
+	params := &stripe.ChargeParams{}
+	params.AddExpand("balance_transaction")
+
+	ch, err := charge.Get(chargeID, params)
+	if err != nil {
+		return nil, err
+	}
+	return ch.BalanceTransaction, nil
+}
+
+func (stripeProcessor) CreateRefund(params *stripe.RefundParams) (*stripe.Refund, error) {
+	return refund.New(params)
+}
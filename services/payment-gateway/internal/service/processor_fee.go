@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+// recordProcessorFee copies the fee and net amount from the Stripe balance
+// transaction behind a succeeded payment. Stripe reports them in the settlement
+// currency, which can differ from the currency the customer paid in.
+func (s *PaymentService) recordProcessorFee(ctx context.Context, payment *models.Payment, intent *stripe.PaymentIntent) {
+	if s.processor == nil || intent.LatestCharge == nil || intent.LatestCharge.ID == "" {
+		return
+	}
+
+	txn, err := s.processor.GetBalanceTransaction(intent.LatestCharge.ID)
+	if err != nil {
+		fmt.Printf("Failed to fetch balance transaction for payment %s: %v\n", payment.ID, err)
+		return
+	}
+	if txn == nil {
+		return
+	}
+
+	applyBalanceTransaction(payment, txn)
+}
+
+// applyBalanceTransaction sets a payment's fee breakdown from a Stripe balance transaction
+func applyBalanceTransaction(payment *models.Payment, txn *stripe.BalanceTransaction) {
+	payment.FeeAmount = fromStripeAmount(txn.Fee, string(txn.Currency))
+	payment.NetAmount = fromStripeAmount(txn.Net, string(txn.Currency))
+	payment.FeeCurrency = strings.ToUpper(string(txn.Currency))
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/money"
+)
+
+// defaultMerchantName is shown on receipts when no merchant name is configured
+const defaultMerchantName = "GlobalPay"
+
+var ErrReceiptUnavailable = errors.New("receipts are only available for succeeded payments")
+
+// GetReceipt returns the receipt for a succeeded payment
+func (s *PaymentService) GetReceipt(ctx context.Context, paymentID string) (*models.Receipt, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.Status != models.PaymentStatusSucceeded {
+		return nil, ErrReceiptUnavailable
+	}
+
+	merchant := s.merchantName
+	if merchant == "" {
+		merchant = defaultMerchantName
+	}
+
+	// Older payments may predate completed_at being recorded
+	paidAt := payment.CompletedAt
+	if paidAt.IsZero() {
+		paidAt = payment.UpdatedAt
+	}
+
+	return &models.Receipt{
+		PaymentID:   payment.ID,
+		Merchant:    merchant,
+		Amount:      payment.Amount,
+		Currency:    payment.Currency,
+		CardLast4:   payment.CardLast4,
+		CardNetwork: payment.CardNetwork,
+		Description: payment.Description,
+		PaidAt:      paidAt,
+	}, nil
+}
+
+// RenderReceiptPDF lays the receipt out as a single-page PDF using the standard
+// Helvetica font, so no font files or PDF library are needed
+func RenderReceiptPDF(receipt *models.Receipt) []byte {
+	lines := []string{
+		receipt.Merchant,
+		"Receipt for payment " + receipt.PaymentID,
+		"",
+		"Amount: " + money.NewDecimal(receipt.Amount).StringFixed(money.Exponent(receipt.Currency)) + " " + strings.ToUpper(receipt.Currency),
+		"Card: " + receipt.CardNetwork + " ending in " + receipt.CardLast4,
+		"Date: " + receipt.PaidAt.UTC().Format("2 January 2006 15:04 MST"),
+	}
+	if receipt.Description != "" {
+		lines = append(lines, "Description: "+receipt.Description)
+	}
+
+	var content bytes.Buffer
+	content.WriteString("BT\n/F1 12 Tf\n16 TL\n72 760 Td\n")
+	for _, line := range lines {
+		fmt.Fprintf(&content, "(%s) Tj T*\n", escapePDFString(line))
+	}
+	content.WriteString("ET\n")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", content.Len(), content.String()),
+	}
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = pdf.Len()
+		fmt.Fprintf(&pdf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := pdf.Len()
+	fmt.Fprintf(&pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return pdf.Bytes()
+}
+
+// escapePDFString escapes the characters that delimit PDF string literals and
+// drops anything outside printable ASCII, which Helvetica's standard encoding can't show
+func escapePDFString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		default:
+			b.WriteRune('?')
+		}
+	}
+	return b.String()
+}
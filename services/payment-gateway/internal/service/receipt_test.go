@@ -0,0 +1,73 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"payment-gateway/internal/models"
+)
+
+func TestGetReceiptForSucceededPayment(t *testing.T) {
+	paidAt := time.Date(2024, 3, 14, 9, 30, 0, 0, time.UTC)
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:          "pay_1",
+		Amount:      42.5,
+		Currency:    "EUR",
+		Status:      models.PaymentStatusSucceeded,
+		CardLast4:   "4242",
+		CardNetwork: "visa",
+		Description: "Annual plan",
+		CompletedAt: paidAt,
+	}
+	s := &PaymentService{repo: store, merchantName: "Acme Ltd"}
+
+	receipt, err := s.GetReceipt(context.Background(), "pay_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := models.Receipt{
+		PaymentID:   "pay_1",
+		Merchant:    "Acme Ltd",
+		Amount:      42.5,
+		Currency:    "EUR",
+		CardLast4:   "4242",
+		CardNetwork: "visa",
+		Description: "Annual plan",
+		PaidAt:      paidAt,
+	}
+	if *receipt != want {
+		t.Errorf("receipt = %+v, want %+v", *receipt, want)
+	}
+
+	if pdf := RenderReceiptPDF(receipt); !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte("42.50 EUR")) {
+		t.Errorf("RenderReceiptPDF() is not a PDF showing the amount")
+	}
+}
+
+func TestGetReceiptUnavailable(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_pending"] = &models.Payment{ID: "pay_pending", Status: models.PaymentStatusPending}
+	s := &PaymentService{repo: store}
+
+	tests := []struct {
+		name      string
+		paymentID string
+		wantErr   error
+	}{
+		{name: "Not succeeded", paymentID: "pay_pending", wantErr: ErrReceiptUnavailable},
+		{name: "Unknown payment", paymentID: "pay_missing", wantErr: ErrPaymentNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.GetReceipt(context.Background(), tt.paymentID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetReceipt() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
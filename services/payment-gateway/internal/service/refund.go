@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+var (
+	ErrPaymentNotRefundable = errors.New("only succeeded payments can be refunded")
+	ErrInvalidRefundAmount  = errors.New("refund amount must be positive and, with earlier refunds, not exceed the settled amount")
+	ErrRefundFailed         = errors.New("refund was declined")
+)
+
+// RefundPayment refunds part or all of a succeeded payment through Stripe with a
+// reason code, then publishes payment.refunded so the ledger reverses it under that
+// reason. A zero amount refunds everything not yet refunded.
+//
+// The refund is recorded, and its amount held against the payment, before Stripe
+// is called, so refunds together can never exceed what the payment settled. A
+// request repeating an earlier idempotency key returns that refund instead of
+// refunding again; one that is still pending is re-sent to Stripe under the same
+// Stripe idempotency key.
+func (s *PaymentService) RefundPayment(ctx context.Context, paymentID string, req *models.RefundRequest) (*models.Refund, error) {
+	reason, err := ParseRefundReason(req.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	if req.IdempotencyKey != "" {
+		existing, err := s.repo.GetRefundByIdempotencyKey(ctx, paymentID, req.IdempotencyKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up refund: %w", err)
+		}
+		if existing != nil {
+			return s.resumeRefund(ctx, payment, existing)
+		}
+	}
+
+	if payment.Status != models.PaymentStatusSucceeded {
+		return nil, ErrPaymentNotRefundable
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = payment.RefundableAmount()
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidRefundAmount
+	}
+
+	now := time.Now()
+	refund := &models.Refund{
+		ID:             ids.Refund(),
+		PaymentID:      payment.ID,
+		Amount:         amount,
+		Currency:       payment.Currency,
+		Reason:         reason,
+		Status:         models.RefundStatusPending,
+		IdempotencyKey: req.IdempotencyKey,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	created, err := s.repo.CreateRefund(ctx, refund)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save refund: %w", err)
+	}
+	if !created {
+		// A concurrent request with the same key may have won the insert
+		if req.IdempotencyKey != "" {
+			existing, err := s.repo.GetRefundByIdempotencyKey(ctx, paymentID, req.IdempotencyKey)
+			if err != nil {
+				return nil, fmt.Errorf("failed to look up refund: %w", err)
+			}
+			if existing != nil {
+				return s.resumeRefund(ctx, payment, existing)
+			}
+		}
+		return nil, ErrInvalidRefundAmount
+	}
+
+	return s.sendRefund(ctx, payment, refund)
+}
+
+// resumeRefund answers a retried refund request with the refund it first created
+func (s *PaymentService) resumeRefund(ctx context.Context, payment *models.Payment, refund *models.Refund) (*models.Refund, error) {
+	switch refund.Status {
+	case models.RefundStatusSucceeded:
+		return refund, nil
+	case models.RefundStatusFailed:
+		return nil, ErrRefundFailed
+	default:
+		return s.sendRefund(ctx, payment, refund)
+	}
+}
+
+// sendRefund submits a pending refund to Stripe and settles it with the outcome
+func (s *PaymentService) sendRefund(ctx context.Context, payment *models.Payment, refund *models.Refund) (*models.Refund, error) {
+	params := &stripe.RefundParams{
+		PaymentIntent: stripe.String(payment.StripePaymentIntentID),
+		Amount:        stripe.Int64(toStripeAmount(refund.Amount, refund.Currency)),
+	}
+	setStripeRefundReason(params, refund.Reason)
+	// Stripe returns the first refund for a repeated key instead of refunding twice
+	params.SetIdempotencyKey("refund_" + refund.ID)
+
+	stripeRefund, err := s.processor.CreateRefund(params)
+	if err != nil {
+		var stripeErr *stripe.Error
+		if errors.As(err, &stripeErr) && stripeErr.HTTPStatusCode >= 400 && stripeErr.HTTPStatusCode < 500 {
+			// Declined for good; release the held amount
+			if failErr := s.repo.FailRefund(ctx, refund); failErr != nil {
+				fmt.Printf("Failed to release declined refund %s: %v\n", refund.ID, failErr)
+			}
+			return nil, fmt.Errorf("%w: %v", ErrRefundFailed, err)
+		}
+		// The outcome is unknown; the refund stays pending so a retry re-sends it
+		return nil, fmt.Errorf("stripe refund failed: %w", err)
+	}
+
+	if err := s.repo.CompleteRefund(ctx, refund.ID, stripeRefund.ID); err != nil {
+		return nil, fmt.Errorf("failed to complete refund: %w", err)
+	}
+	refund.Status = models.RefundStatusSucceeded
+	refund.StripeRefundID = stripeRefund.ID
+
+	// The event carries the refunded amount rather than the payment's, and no
+	// settlement fee. Its ID is the refund's so the ledger posts each refund once.
+	event := newLifecycleEvent("payment.refunded", payment)
+	event.ID = refund.ID
+	event.Amount = refund.Amount
+	event.FeeAmount, event.NetAmount, event.FeeCurrency = 0, 0, ""
+	event.RefundReason = refund.Reason
+	s.publishLifecycleEvent(ctx, event)
+
+	return refund, nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+var ErrInvalidRefundReason = errors.New("invalid refund reason")
+
+// stripeRefundReasons maps each accepted reason code onto Stripe's
+var stripeRefundReasons = map[models.RefundReason]stripe.RefundReason{
+	models.RefundReasonDuplicate:           stripe.RefundReasonDuplicate,
+	models.RefundReasonFraudulent:          stripe.RefundReasonFraudulent,
+	models.RefundReasonRequestedByCustomer: stripe.RefundReasonRequestedByCustomer,
+}
+
+// ParseRefundReason validates a refund reason code
+func ParseRefundReason(raw string) (models.RefundReason, error) {
+	reason := models.RefundReason(raw)
+	if _, ok := stripeRefundReasons[reason]; !ok {
+		return "", fmt.Errorf("%w: %q, want duplicate, fraudulent or requested_by_customer", ErrInvalidRefundReason, raw)
+	}
+	return reason, nil
+}
+
+// setStripeRefundReason forwards a validated reason code in a Stripe refund request
+func setStripeRefundReason(params *stripe.RefundParams, reason models.RefundReason) {
+	params.Reason = stripe.String(string(stripeRefundReasons[reason]))
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestParseRefundReason(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    models.RefundReason
+		wantErr error
+	}{
+		{raw: "requested_by_customer", want: models.RefundReasonRequestedByCustomer},
+		{raw: "duplicate", want: models.RefundReasonDuplicate},
+		{raw: "changed_mind", wantErr: ErrInvalidRefundReason},
+		{raw: "", wantErr: ErrInvalidRefundReason},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseRefundReason(tt.raw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseRefundReason(%q) error = %v, want %v", tt.raw, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRefundReason(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+			if err != nil {
+				return
+			}
+
+			params := &stripe.RefundParams{}
+			setStripeRefundReason(params, got)
+			if params.Reason == nil || *params.Reason != tt.raw {
+				t.Errorf("Stripe refund reason = %v, want %q", params.Reason, tt.raw)
+			}
+		})
+	}
+}
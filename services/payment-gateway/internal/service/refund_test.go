@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestRefundPayment(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     models.PaymentStatus
+		amount     float64
+		reason     string
+		wantErr    error
+		wantAmount int64
+	}{
+		{name: "Full refund", status: models.PaymentStatusSucceeded, reason: "requested_by_customer", wantAmount: 6000},
+		{name: "Partial refund", status: models.PaymentStatusSucceeded, amount: 10, reason: "duplicate", wantAmount: 1000},
+		{name: "Invalid reason", status: models.PaymentStatusSucceeded, reason: "changed_mind", wantErr: ErrInvalidRefundReason},
+		{name: "More than settled", status: models.PaymentStatusSucceeded, amount: 60.01, reason: "duplicate", wantErr: ErrInvalidRefundAmount},
+		{name: "Not succeeded", status: models.PaymentStatusRequiresCapture, reason: "fraudulent", wantErr: ErrPaymentNotRefundable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.payments["pay_1"] = &models.Payment{
+				ID:                    "pay_1",
+				Amount:                100,
+				CapturedAmount:        60,
+				Currency:              "USD",
+				Status:                tt.status,
+				StripePaymentIntentID: "pi_1",
+			}
+			processor := &mockProcessor{}
+			s := &PaymentService{repo: store, processor: processor}
+
+			_, err := s.RefundPayment(context.Background(), "pay_1", &models.RefundRequest{Amount: tt.amount, Reason: tt.reason})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RefundPayment() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(processor.refundParams) != 0 {
+					t.Error("rejected refund reached Stripe")
+				}
+				return
+			}
+
+			params := processor.refundParams[0]
+			if got := stripe.StringValue(params.PaymentIntent); got != "pi_1" {
+				t.Errorf("payment intent = %q, want pi_1", got)
+			}
+			if got := stripe.Int64Value(params.Amount); got != tt.wantAmount {
+				t.Errorf("amount = %d, want %d", got, tt.wantAmount)
+			}
+			if got := stripe.StringValue(params.Reason); got != tt.reason {
+				t.Errorf("reason = %q, want %q", got, tt.reason)
+			}
+			if stripe.StringValue(params.IdempotencyKey) == "" {
+				t.Error("refund sent to Stripe without an idempotency key")
+			}
+		})
+	}
+}
+
+func TestRefundPaymentCumulative(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Amount:                100,
+		Currency:              "USD",
+		Status:                models.PaymentStatusSucceeded,
+		StripePaymentIntentID: "pi_1",
+	}
+	processor := &mockProcessor{}
+	s := &PaymentService{repo: store, processor: processor}
+	ctx := context.Background()
+
+	if _, err := s.RefundPayment(ctx, "pay_1", &models.RefundRequest{Amount: 70, Reason: "duplicate"}); err != nil {
+		t.Fatalf("first refund: %v", err)
+	}
+	if _, err := s.RefundPayment(ctx, "pay_1", &models.RefundRequest{Amount: 40, Reason: "duplicate"}); !errors.Is(err, ErrInvalidRefundAmount) {
+		t.Fatalf("second refund error = %v, want %v", err, ErrInvalidRefundAmount)
+	}
+
+	// A full refund takes only what is left
+	refund, err := s.RefundPayment(ctx, "pay_1", &models.RefundRequest{Reason: "duplicate"})
+	if err != nil {
+		t.Fatalf("full refund: %v", err)
+	}
+	if refund.Amount != 30 {
+		t.Errorf("full refund amount = %v, want 30", refund.Amount)
+	}
+	if got := store.payments["pay_1"].RefundedAmount; got != 100 {
+		t.Errorf("refunded amount = %v, want 100", got)
+	}
+	if len(processor.refundParams) != 2 {
+		t.Errorf("Stripe refunds = %d, want 2", len(processor.refundParams))
+	}
+}
+
+func TestRefundPaymentRetry(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Amount:                100,
+		Currency:              "USD",
+		Status:                models.PaymentStatusSucceeded,
+		StripePaymentIntentID: "pi_1",
+	}
+	processor := &mockProcessor{}
+	s := &PaymentService{repo: store, processor: processor}
+	ctx := context.Background()
+	req := &models.RefundRequest{Amount: 25, Reason: "requested_by_customer", IdempotencyKey: "rf-1"}
+
+	// Stripe's outcome is unknown, so the refund stays pending and holds its amount
+	processor.refundErr = errors.New("connection reset")
+	if _, err := s.RefundPayment(ctx, "pay_1", req); err == nil {
+		t.Fatal("expected the Stripe error")
+	}
+	if got := store.payments["pay_1"].RefundedAmount; got != 25 {
+		t.Errorf("refunded amount after error = %v, want 25", got)
+	}
+
+	processor.refundErr = nil
+	first, err := s.RefundPayment(ctx, "pay_1", req)
+	if err != nil {
+		t.Fatalf("retried refund: %v", err)
+	}
+	again, err := s.RefundPayment(ctx, "pay_1", req)
+	if err != nil {
+		t.Fatalf("repeated refund: %v", err)
+	}
+
+	if again.ID != first.ID || again.Status != models.RefundStatusSucceeded {
+		t.Errorf("repeated refund = %+v, want %s succeeded", again, first.ID)
+	}
+	if got := store.payments["pay_1"].RefundedAmount; got != 25 {
+		t.Errorf("refunded amount = %v, want 25", got)
+	}
+	if len(processor.refundParams) != 2 {
+		t.Fatalf("Stripe refund calls = %d, want 2", len(processor.refundParams))
+	}
+	key := stripe.StringValue(processor.refundParams[0].IdempotencyKey)
+	if key == "" || key != stripe.StringValue(processor.refundParams[1].IdempotencyKey) {
+		t.Errorf("Stripe idempotency keys differ across retries: %q", key)
+	}
+}
+
+func TestRefundPaymentDeclined(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Amount:                100,
+		Currency:              "USD",
+		Status:                models.PaymentStatusSucceeded,
+		StripePaymentIntentID: "pi_1",
+	}
+	processor := &mockProcessor{refundErr: &stripe.Error{HTTPStatusCode: 402, Msg: "charge disputed"}}
+	s := &PaymentService{repo: store, processor: processor}
+
+	_, err := s.RefundPayment(context.Background(), "pay_1", &models.RefundRequest{Reason: "fraudulent", IdempotencyKey: "rf-1"})
+	if !errors.Is(err, ErrRefundFailed) {
+		t.Fatalf("RefundPayment() error = %v, want %v", err, ErrRefundFailed)
+	}
+	if got := store.payments["pay_1"].RefundedAmount; got != 0 {
+		t.Errorf("refunded amount = %v, want the declined refund released", got)
+	}
+}
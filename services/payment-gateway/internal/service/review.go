@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+var (
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrReviewAlreadyResolved = errors.New("review has already been resolved")
+)
+
+// enqueueReview adds a payment held for fraud review to the manual review queue
+func (s *PaymentService) enqueueReview(ctx context.Context, payment *models.Payment, assessment *models.FraudAssessment) error {
+	reason := "fraud review"
+	if len(assessment.Flags) > 0 {
+		reason = strings.Join(assessment.Flags, ", ")
+	}
+
+	return s.repo.EnqueueReview(ctx, &models.ReviewItem{
+		ID:         ids.New(),
+		PaymentID:  payment.ID,
+		Status:     models.ReviewStatusPending,
+		Reason:     reason,
+		FraudScore: assessment.Score,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// ListPendingReviews returns payments awaiting a reviewer, oldest first
+func (s *PaymentService) ListPendingReviews(ctx context.Context, limit int) ([]*models.ReviewItem, error) {
+	return s.repo.ListPendingReviews(ctx, limit)
+}
+
+// ApproveReview releases a held payment and confirms it with Stripe
+func (s *PaymentService) ApproveReview(ctx context.Context, paymentID string, req *models.ReviewDecisionRequest) (*models.Payment, error) {
+	payment, err := s.resolveReview(ctx, paymentID, models.ReviewStatusApproved, req)
+	if err != nil {
+		return nil, err
+	}
+
+	reason := fmt.Sprintf("approved by %s", req.Reviewer)
+	if err := s.transition(ctx, payment, models.PaymentStatusPending, actorReviewer, reason); err != nil {
+		return nil, err
+	}
+
+	return s.ConfirmPayment(ctx, paymentID)
+}
+
+// RejectReview cancels a held payment
+func (s *PaymentService) RejectReview(ctx context.Context, paymentID string, req *models.ReviewDecisionRequest) (*models.Payment, error) {
+	payment, err := s.resolveReview(ctx, paymentID, models.ReviewStatusRejected, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if payment.StripePaymentIntentID != "" {
+		if _, err := s.processor.CancelPaymentIntent(payment.StripePaymentIntentID); err != nil {
+			return nil, fmt.Errorf("stripe cancel failed: %w", err)
+		}
+	}
+
+	payment.FailureReason = "rejected in fraud review"
+	reason := fmt.Sprintf("rejected by %s", req.Reviewer)
+	if err := s.transition(ctx, payment, models.PaymentStatusCancelled, actorReviewer, reason); err != nil {
+		return nil, err
+	}
+
+	s.publishPaymentEvent(ctx, "payment.cancelled", payment)
+	return payment, nil
+}
+
+// resolveReview records a reviewer's decision on a pending review and returns the
+// held payment. Only one decision can win for a given review.
+func (s *PaymentService) resolveReview(ctx context.Context, paymentID string, status models.ReviewStatus, req *models.ReviewDecisionRequest) (*models.Payment, error) {
+	item, err := s.repo.GetReview(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, ErrReviewNotFound
+	}
+	if item.Status != models.ReviewStatusPending {
+		return nil, ErrReviewAlreadyResolved
+	}
+
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+	if payment.Status != models.PaymentStatusPendingReview {
+		return nil, ErrReviewAlreadyResolved
+	}
+
+	now := time.Now()
+	item.Status = status
+	item.Reviewer = req.Reviewer
+	item.Notes = req.Notes
+	item.ResolvedAt = &now
+
+	resolved, err := s.repo.ResolveReview(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if !resolved {
+		return nil, ErrReviewAlreadyResolved
+	}
+
+	return payment, nil
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+type reviewDecisionFunc func(ctx context.Context, paymentID string, req *models.ReviewDecisionRequest) (*models.Payment, error)
+
+func newReviewedPayment(t *testing.T) (*PaymentService, *mockStore, string) {
+	t.Helper()
+	store := newMockStore()
+	s := &PaymentService{
+		repo:      store,
+		processor: &mockProcessor{},
+		fraud:     &stubFraudChecker{assessment: &models.FraudAssessment{Decision: models.FraudDecisionReview, Score: 60, Flags: []string{"high_amount"}}},
+	}
+
+	payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:        100,
+		Currency:      "USD",
+		CardNumber:    "4242424242424242",
+		CustomerEmail: "customer@example.com",
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+	return s, store, payment.ID
+}
+
+func TestReviewDecisions(t *testing.T) {
+	tests := []struct {
+		name           string
+		decide         func(*PaymentService) reviewDecisionFunc
+		wantStatus     models.PaymentStatus
+		wantReview     models.ReviewStatus
+		wantLastReason string
+	}{
+		{
+			name:           "Approve confirms the payment",
+			decide:         func(s *PaymentService) reviewDecisionFunc { return s.ApproveReview },
+			wantStatus:     models.PaymentStatusSucceeded,
+			wantReview:     models.ReviewStatusApproved,
+			wantLastReason: "payment confirmed",
+		},
+		{
+			name:           "Reject cancels the payment",
+			decide:         func(s *PaymentService) reviewDecisionFunc { return s.RejectReview },
+			wantStatus:     models.PaymentStatusCancelled,
+			wantReview:     models.ReviewStatusRejected,
+			wantLastReason: "rejected by analyst@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, store, paymentID := newReviewedPayment(t)
+			ctx := context.Background()
+
+			pending, err := s.ListPendingReviews(ctx, 20)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 1 || pending[0].PaymentID != paymentID || pending[0].Reason != "high_amount" {
+				t.Fatalf("pending reviews = %+v, want the held payment", pending)
+			}
+
+			req := &models.ReviewDecisionRequest{Reviewer: "analyst@example.com"}
+			payment, err := tt.decide(s)(ctx, paymentID, req)
+			if err != nil {
+				t.Fatalf("decision error = %v", err)
+			}
+
+			if payment.Status != tt.wantStatus {
+				t.Errorf("payment status = %s, want %s", payment.Status, tt.wantStatus)
+			}
+			review := store.reviews[paymentID]
+			if review.Status != tt.wantReview || review.Reviewer != req.Reviewer || review.ResolvedAt == nil {
+				t.Errorf("review = %+v, want %s by %s", review, tt.wantReview, req.Reviewer)
+			}
+			last := store.paymentEvents[len(store.paymentEvents)-1]
+			if last.Reason != tt.wantLastReason {
+				t.Errorf("last timeline reason = %q, want %q", last.Reason, tt.wantLastReason)
+			}
+
+			if pending, _ := s.ListPendingReviews(ctx, 20); len(pending) != 0 {
+				t.Errorf("%d reviews still pending, want 0", len(pending))
+			}
+			if _, err := tt.decide(s)(ctx, paymentID, req); !errors.Is(err, ErrReviewAlreadyResolved) {
+				t.Errorf("second decision error = %v, want %v", err, ErrReviewAlreadyResolved)
+			}
+		})
+	}
+}
+
+func TestReviewDecisionUnknownPayment(t *testing.T) {
+	s := &PaymentService{repo: newMockStore(), processor: &mockProcessor{}}
+
+	_, err := s.ApproveReview(context.Background(), "pay_missing", &models.ReviewDecisionRequest{Reviewer: "analyst"})
+	if !errors.Is(err, ErrReviewNotFound) {
+		t.Errorf("ApproveReview() error = %v, want %v", err, ErrReviewNotFound)
+	}
+}
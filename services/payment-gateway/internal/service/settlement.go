@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/money"
+)
+
+// applySettlement converts the presentment amount into the merchant's settlement
+// currency at today's rate, recording the rate so the payout can be explained later
+func (s *PaymentService) applySettlement(ctx context.Context, payment *models.Payment, settlementCurrency string) error {
+	if settlementCurrency == "" {
+		return nil
+	}
+	settlementCurrency = strings.ToUpper(settlementCurrency)
+
+	rate := 1.0
+	if !strings.EqualFold(payment.Currency, settlementCurrency) {
+		var err error
+		rate, err = s.converter.GetRate(ctx, strings.ToUpper(payment.Currency), settlementCurrency)
+		if err != nil {
+			return fmt.Errorf("failed to convert %s to settlement currency %s: %w", payment.Currency, settlementCurrency, err)
+		}
+	}
+
+	payment.SettlementCurrency = settlementCurrency
+	payment.SettlementRate = rate
+	payment.SettlementAmount = money.NewDecimal(payment.Amount).
+		Mul(money.NewDecimal(rate)).
+		Round(money.Exponent(settlementCurrency)).
+		Float64()
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"payment-gateway/internal/models"
+)
+
+func TestCreatePaymentWithSettlementCurrency(t *testing.T) {
+	store := newMockStore()
+	s := &PaymentService{
+		repo:      store,
+		processor: &mockProcessor{},
+		converter: fixedRates{"EUR:USD": 1.0825},
+	}
+
+	payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:             49.99,
+		Currency:           "EUR",
+		SettlementCurrency: "USD",
+		CardNumber:         "4242424242424242",
+		CustomerEmail:      "customer@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored := store.payments[payment.ID]
+	if stored == nil {
+		t.Fatal("payment was not stored")
+	}
+	if stored.Amount != 49.99 || stored.Currency != "EUR" {
+		t.Errorf("presentment = %v %s, want 49.99 EUR", stored.Amount, stored.Currency)
+	}
+	// 49.99 * 1.0825 = 54.114175, rounded to cents
+	if stored.SettlementAmount != 54.11 || stored.SettlementCurrency != "USD" || stored.SettlementRate != 1.0825 {
+		t.Errorf("settlement = %v %s at %v, want 54.11 USD at 1.0825",
+			stored.SettlementAmount, stored.SettlementCurrency, stored.SettlementRate)
+	}
+}
+
+func TestCreatePaymentWithoutSettlementRate(t *testing.T) {
+	store := newMockStore()
+	processor := &mockProcessor{}
+	s := &PaymentService{repo: store, processor: processor, converter: fixedRates{}}
+
+	_, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:             10,
+		Currency:           "EUR",
+		SettlementCurrency: "JPY",
+		CardNumber:         "4242424242424242",
+		CustomerEmail:      "customer@example.com",
+	})
+	if err == nil {
+		t.Fatal("expected an error when no settlement rate is available")
+	}
+	if len(processor.intentParams) != 0 || len(store.payments) != 0 {
+		t.Error("payment was charged or stored without a settlement rate")
+	}
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"math"
+
+	"shared/pkg/money"
+)
+
+// minorUnitMultiplier is how many of Stripe's smallest units make one unit of currency
+func minorUnitMultiplier(currency string) float64 {
+	return math.Pow10(money.Exponent(currency))
+}
+
+// toStripeAmount converts a decimal amount to Stripe's smallest unit of the currency
+func toStripeAmount(amount float64, currency string) int64 {
+	units := int64(math.Round(amount * minorUnitMultiplier(currency)))
+	// Stripe requires three-decimal amounts to end in zero
+	if money.Exponent(currency) == 3 {
+		units = int64(math.Round(float64(units)/10)) * 10
+	}
+	return units
+}
+
+// fromStripeAmount converts Stripe's smallest unit of the currency to a decimal amount
+func fromStripeAmount(amount int64, currency string) float64 {
+	return float64(amount) / minorUnitMultiplier(currency)
+}
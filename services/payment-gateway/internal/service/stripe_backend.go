@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+var (
+	ErrInvalidStripeMode     = errors.New("stripe mode must be test or live")
+	ErrStripeKeyModeMismatch = errors.New("stripe secret key does not match stripe mode")
+)
+
+// StripeConfig tunes how the Stripe API is called
+type StripeConfig struct {
+	// Mode is the account mode the secret key must belong to
+	Mode models.PaymentMode
+	// SecretKey is checked against Mode; an empty key is not checked
+	SecretKey string
+	// APIVersion overrides the version stripe-go pins; empty keeps stripe.APIVersion
+	APIVersion string
+	// MaxNetworkRetries is how many times a failed request is retried on
+	// connection errors, 409s and 5xx responses
+	MaxNetworkRetries int64
+	// Timeout bounds each HTTP request to Stripe
+	Timeout time.Duration
+}
+
+// ConfigureStripe replaces the default Stripe API backend used by every
+// stripe-go call in the service. It refuses a secret key from the other mode,
+// so test cards can't reach a live account and live cards can't reach a test one.
+func ConfigureStripe(cfg StripeConfig) error {
+	if err := validateStripeKey(cfg.Mode, cfg.SecretKey); err != nil {
+		return err
+	}
+
+	stripe.SetBackend(stripe.APIBackend, newStripeBackend(cfg, http.DefaultTransport))
+	return nil
+}
+
+// validateStripeKey checks a secret or restricted key's prefix against the mode
+func validateStripeKey(mode models.PaymentMode, key string) error {
+	if mode != models.PaymentModeTest && mode != models.PaymentModeLive {
+		return fmt.Errorf("%w: %q", ErrInvalidStripeMode, mode)
+	}
+	if key == "" {
+		return nil
+	}
+
+	for _, prefix := range []string{"sk_", "rk_"} {
+		if strings.HasPrefix(key, prefix+string(mode)+"_") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s mode", ErrStripeKeyModeMismatch, mode)
+}
+
+func newStripeBackend(cfg StripeConfig, transport http.RoundTripper) stripe.Backend {
+	if cfg.APIVersion != "" && cfg.APIVersion != stripe.APIVersion {
+		transport = &stripeVersionTransport{version: cfg.APIVersion, next: transport}
+	}
+
+	return stripe.GetBackendWithConfig(stripe.APIBackend, &stripe.BackendConfig{
+		HTTPClient:        &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		MaxNetworkRetries: stripe.Int64(cfg.MaxNetworkRetries),
+		LeveledLogger:     &stripe.LeveledLogger{Level: stripe.LevelError},
+	})
+}
+
+// stripeVersionTransport pins the Stripe-Version header on every request
+type stripeVersionTransport struct {
+	version string
+	next    http.RoundTripper
+}
+
+func (t *stripeVersionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Stripe-Version", t.version)
+	return t.next.RoundTrip(req)
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76/paymentintent"
+
+	"payment-gateway/internal/models"
+)
+
+// flakyTransport fails the first request with a 500 and answers the rest
+type flakyTransport struct {
+	requests []*http.Request
+}
+
+func (t *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+
+	status, body := http.StatusOK, `{"id":"pi_1","object":"payment_intent","status":"succeeded"}`
+	if len(t.requests) == 1 {
+		status, body = http.StatusInternalServerError, `{"error":{"type":"api_error","message":"internal error"}}`
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestStripeBackendRetriesServerErrors(t *testing.T) {
+	transport := &flakyTransport{}
+	backend := newStripeBackend(StripeConfig{
+		APIVersion:        "2024-04-10",
+		MaxNetworkRetries: 2,
+		Timeout:           5 * time.Second,
+	}, transport)
+
+	client := paymentintent.Client{B: backend, Key: "sk_test_123"}
+	intent, err := client.Get("pi_1", nil)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if intent.ID != "pi_1" {
+		t.Errorf("intent ID = %q, want pi_1", intent.ID)
+	}
+
+	if len(transport.requests) != 2 {
+		t.Fatalf("made %d requests, want 2", len(transport.requests))
+	}
+	for i, req := range transport.requests {
+		if got := req.Header.Get("Stripe-Version"); got != "2024-04-10" {
+			t.Errorf("request %d Stripe-Version = %q, want 2024-04-10", i+1, got)
+		}
+	}
+}
+
+func TestStripeBackendWithoutRetries(t *testing.T) {
+	transport := &flakyTransport{}
+	backend := newStripeBackend(StripeConfig{Timeout: 5 * time.Second}, transport)
+
+	client := paymentintent.Client{B: backend, Key: "sk_test_123"}
+	if _, err := client.Get("pi_1", nil); err == nil {
+		t.Error("Get() should fail when retries are disabled")
+	}
+	if len(transport.requests) != 1 {
+		t.Errorf("made %d requests, want 1", len(transport.requests))
+	}
+}
+
+func TestConfigureStripeRejectsKeyFromOtherMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    models.PaymentMode
+		key     string
+		wantErr error
+	}{
+		{
+			name:    "Live key in test mode",
+			mode:    models.PaymentModeTest,
+			key:     "sk_live_123",
+			wantErr: ErrStripeKeyModeMismatch,
+		},
+		{
+			name:    "Test key in live mode",
+			mode:    models.PaymentModeLive,
+			key:     "sk_test_123",
+			wantErr: ErrStripeKeyModeMismatch,
+		},
+		{
+			name:    "Restricted test key in live mode",
+			mode:    models.PaymentModeLive,
+			key:     "rk_test_123",
+			wantErr: ErrStripeKeyModeMismatch,
+		},
+		{
+			name:    "Unknown mode",
+			mode:    "sandbox",
+			key:     "sk_test_123",
+			wantErr: ErrInvalidStripeMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ConfigureStripe(StripeConfig{Mode: tt.mode, SecretKey: tt.key})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ConfigureStripe() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateStripeKeyAcceptsMatchingMode(t *testing.T) {
+	tests := []struct {
+		mode models.PaymentMode
+		key  string
+	}{
+		{models.PaymentModeTest, "sk_test_123"},
+		{models.PaymentModeTest, "rk_test_123"},
+		{models.PaymentModeLive, "sk_live_123"},
+		{models.PaymentModeLive, ""},
+	}
+
+	for _, tt := range tests {
+		if err := validateStripeKey(tt.mode, tt.key); err != nil {
+			t.Errorf("validateStripeKey(%s, %q) error = %v", tt.mode, tt.key, err)
+		}
+	}
+}
+
+func TestCreatePaymentTagsMode(t *testing.T) {
+	store := newMockStore()
+	s := &PaymentService{repo: store, processor: &mockProcessor{}, mode: models.PaymentModeLive}
+
+	payment, err := s.CreatePayment(context.Background(), &models.PaymentRequest{
+		Amount:        100,
+		Currency:      "USD",
+		CardNumber:    "4242424242424242",
+		CustomerEmail: "customer@example.com",
+	})
+	if err != nil {
+		t.Fatalf("CreatePayment() error = %v", err)
+	}
+
+	if payment.Mode != models.PaymentModeLive || store.payments[payment.ID].Mode != models.PaymentModeLive {
+		t.Errorf("payment mode = %q, stored mode = %q, want live", payment.Mode, store.payments[payment.ID].Mode)
+	}
+}
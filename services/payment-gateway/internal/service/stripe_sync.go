@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+	"shared/pkg/ids"
+)
+
+// stripeSyncBatchSize caps how many payments one sweep checks against Stripe
+const stripeSyncBatchSize = 500
+
+// SyncWithStripe compares a payment with its Stripe payment intent. A cancel racing
+// a successful charge can leave us cancelled while Stripe captured the funds; in that
+// case the payment is corrected to succeeded, posted to the ledger and flagged for
+// review. Any other contradiction is only flagged for review.
+func (s *PaymentService) SyncWithStripe(ctx context.Context, paymentID string) (*models.StripeSyncResult, error) {
+	payment, err := s.repo.GetByID(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if payment == nil {
+		return nil, ErrPaymentNotFound
+	}
+
+	result := &models.StripeSyncResult{PaymentID: payment.ID, LocalStatus: payment.Status}
+	if payment.StripePaymentIntentID == "" {
+		return result, nil
+	}
+
+	intent, err := s.processor.GetPaymentIntent(payment.StripePaymentIntentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payment intent: %w", err)
+	}
+	result.StripeStatus = string(intent.Status)
+
+	result.Reason = stripeDivergence(payment.Status, intent.Status)
+	if result.Reason == "" {
+		return result, nil
+	}
+	result.Diverged = true
+
+	if intent.Status == stripe.PaymentIntentStatusSucceeded {
+		result.FundsCaptured = true
+		if err := s.correctToSucceeded(ctx, payment, intent, result.Reason); err != nil {
+			return nil, err
+		}
+		result.Corrected = true
+	}
+
+	if err := s.repo.EnqueueReview(ctx, &models.ReviewItem{
+		ID:         ids.New(),
+		PaymentID:  payment.ID,
+		Status:     models.ReviewStatusPending,
+		Reason:     "stripe divergence: " + result.Reason,
+		FraudScore: payment.FraudScore,
+		CreatedAt:  time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to flag payment for review: %w", err)
+	}
+
+	return result, nil
+}
+
+// stripeDivergence describes how a payment's status contradicts its intent's, or
+// returns "" when they agree. Only finished payments can contradict Stripe; the
+// rest are still waiting on it.
+func stripeDivergence(local models.PaymentStatus, intent stripe.PaymentIntentStatus) string {
+	switch {
+	case intent == stripe.PaymentIntentStatusSucceeded &&
+		(local == models.PaymentStatusCancelled || local == models.PaymentStatusFailed):
+		return fmt.Sprintf("payment is %s but stripe captured the funds", local)
+	case local == models.PaymentStatusSucceeded && intent != stripe.PaymentIntentStatusSucceeded:
+		return fmt.Sprintf("payment succeeded but stripe intent is %s", intent)
+	case local == models.PaymentStatusCancelled && intent != stripe.PaymentIntentStatusCanceled:
+		return fmt.Sprintf("payment is cancelled but stripe intent is %s", intent)
+	}
+	return ""
+}
+
+// correctToSucceeded records funds Stripe captured and posts them to the ledger
+func (s *PaymentService) correctToSucceeded(ctx context.Context, payment *models.Payment, intent *stripe.PaymentIntent, reason string) error {
+	captured := payment.Amount
+	if intent.AmountReceived > 0 {
+		captured = fromStripeAmount(intent.AmountReceived, payment.Currency)
+	}
+	payment.AuthorizedAmount = captured
+	payment.CapturedAmount = captured
+	payment.CompletedAt = time.Now()
+	payment.FailureReason = ""
+	s.recordProcessorFee(ctx, payment, intent)
+
+	if err := s.transition(ctx, payment, models.PaymentStatusSucceeded, actorStripeSync, reason); err != nil {
+		return err
+	}
+
+	// The ledger posts each payment.succeeded once, so a repeated sync cannot double-post
+	s.publishPaymentEvent(ctx, "payment.succeeded", payment)
+	return nil
+}
+
+// SyncRecentWithStripe checks finished payments updated within lookback against Stripe,
+// returning how many had diverged
+func (s *PaymentService) SyncRecentWithStripe(ctx context.Context, lookback time.Duration) (int, error) {
+	payments, err := s.repo.ListFinalUpdatedSince(ctx, time.Now().Add(-lookback), stripeSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	diverged := 0
+	for _, payment := range payments {
+		result, err := s.SyncWithStripe(ctx, payment.ID)
+		if err != nil {
+			fmt.Printf("Failed to sync payment %s with stripe: %v\n", payment.ID, err)
+			continue
+		}
+		if result.Diverged {
+			diverged++
+			fmt.Printf("Payment %s diverged from stripe: %s\n", payment.ID, result.Reason)
+		}
+	}
+	return diverged, nil
+}
+
+// RunStripeSync sweeps recently finished payments for Stripe divergence every interval
+// until ctx is cancelled
+func (s *PaymentService) RunStripeSync(ctx context.Context, lookback, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.SyncRecentWithStripe(ctx, lookback); err != nil {
+			fmt.Printf("Failed to sync payments with stripe: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
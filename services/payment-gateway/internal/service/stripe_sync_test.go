@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestSyncWithStripeCorrectsCancelledButCaptured(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	// The cancel won locally while Stripe processed the charge
+	store.payments["pay_race"] = &models.Payment{
+		ID:                    "pay_race",
+		Amount:                50,
+		Currency:              "USD",
+		Status:                models.PaymentStatusCancelled,
+		StripePaymentIntentID: "pi_race",
+		UpdatedAt:             time.Now(),
+	}
+	processor := &mockProcessor{intentStatus: stripe.PaymentIntentStatusSucceeded, amountReceived: 5000}
+	s := &PaymentService{repo: store, processor: processor}
+
+	result, err := s.SyncWithStripe(ctx, "pay_race")
+	if err != nil {
+		t.Fatalf("SyncWithStripe() error = %v", err)
+	}
+
+	if !result.Diverged || !result.FundsCaptured || !result.Corrected {
+		t.Errorf("result = %+v, want a corrected divergence with funds captured", result)
+	}
+	if result.LocalStatus != models.PaymentStatusCancelled || result.StripeStatus != "succeeded" {
+		t.Errorf("statuses = %s/%s, want cancelled/succeeded", result.LocalStatus, result.StripeStatus)
+	}
+
+	payment := store.payments["pay_race"]
+	if payment.Status != models.PaymentStatusSucceeded || payment.CapturedAmount != 50 {
+		t.Errorf("payment = %s with %v captured, want succeeded with 50", payment.Status, payment.CapturedAmount)
+	}
+
+	review := store.reviews["pay_race"]
+	if review == nil || review.Status != models.ReviewStatusPending || !strings.HasPrefix(review.Reason, "stripe divergence") {
+		t.Errorf("review = %+v, want a pending stripe divergence review", review)
+	}
+
+	var synced bool
+	for _, event := range store.paymentEvents {
+		if event.PaymentID == "pay_race" && event.Actor == actorStripeSync && event.NewStatus == models.PaymentStatusSucceeded {
+			synced = true
+		}
+	}
+	if !synced {
+		t.Error("correction missing from the payment timeline")
+	}
+
+	// A second sweep finds the payment in agreement with Stripe
+	diverged, err := s.SyncRecentWithStripe(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diverged != 0 {
+		t.Errorf("second sweep found %d diverged payments, want 0", diverged)
+	}
+}
+
+func TestStripeDivergence(t *testing.T) {
+	tests := []struct {
+		local        models.PaymentStatus
+		intent       stripe.PaymentIntentStatus
+		wantDiverged bool
+	}{
+		{models.PaymentStatusSucceeded, stripe.PaymentIntentStatusSucceeded, false},
+		{models.PaymentStatusCancelled, stripe.PaymentIntentStatusCanceled, false},
+		{models.PaymentStatusFailed, stripe.PaymentIntentStatusRequiresPaymentMethod, false},
+		{models.PaymentStatusPending, stripe.PaymentIntentStatusSucceeded, false},
+		{models.PaymentStatusCancelled, stripe.PaymentIntentStatusSucceeded, true},
+		{models.PaymentStatusFailed, stripe.PaymentIntentStatusSucceeded, true},
+		{models.PaymentStatusCancelled, stripe.PaymentIntentStatusRequiresCapture, true},
+		{models.PaymentStatusSucceeded, stripe.PaymentIntentStatusCanceled, true},
+	}
+
+	for _, tt := range tests {
+		if got := stripeDivergence(tt.local, tt.intent) != ""; got != tt.wantDiverged {
+			t.Errorf("stripeDivergence(%s, %s) diverged = %v, want %v", tt.local, tt.intent, got, tt.wantDiverged)
+		}
+	}
+}
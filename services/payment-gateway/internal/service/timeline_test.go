@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestTimelineRecordsLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+
+	// Create
+	payment := &models.Payment{
+		ID:                    "pay_1",
+		Status:                models.PaymentStatusPending,
+		StripePaymentIntentID: "pi_1",
+		CreatedAt:             time.Now(),
+		UpdatedAt:             time.Now(),
+	}
+	if err := store.Create(ctx, payment); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.recordEvent(ctx, payment, "", actorAPI, "payment created"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Confirm
+	if err := s.transition(ctx, payment, models.PaymentStatusProcessing, actorAPI, "payment confirmed"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Succeed via webhook
+	event := stripe.Event{
+		ID:   "evt_1",
+		Type: "payment_intent.succeeded",
+		Data: &stripe.EventData{
+			Raw: json.RawMessage(`{"id":"pi_1","object":"payment_intent","status":"succeeded"}`),
+		},
+	}
+	if _, err := s.ProcessStripeEvent(ctx, event, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	timeline, err := s.GetTimeline(ctx, "pay_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		from  models.PaymentStatus
+		to    models.PaymentStatus
+		actor string
+	}{
+		{"", models.PaymentStatusPending, actorAPI},
+		{models.PaymentStatusPending, models.PaymentStatusProcessing, actorAPI},
+		{models.PaymentStatusProcessing, models.PaymentStatusSucceeded, actorStripeWebhook},
+	}
+
+	if len(timeline) != len(want) {
+		t.Fatalf("timeline has %d events, want %d", len(timeline), len(want))
+	}
+	for i, w := range want {
+		got := timeline[i]
+		if got.OldStatus != w.from || got.NewStatus != w.to || got.Actor != w.actor {
+			t.Errorf("event[%d] = %s->%s by %s, want %s->%s by %s",
+				i, got.OldStatus, got.NewStatus, got.Actor, w.from, w.to, w.actor)
+		}
+	}
+}
+
+func TestTransitionSkipsEventWhenStatusUnchanged(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+
+	payment := &models.Payment{ID: "pay_1", Status: models.PaymentStatusRequiresAction}
+	store.Create(ctx, payment)
+
+	if err := s.transition(ctx, payment, models.PaymentStatusRequiresAction, actorAPI, "payment confirmed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.paymentEvents) != 0 {
+		t.Errorf("recorded %d events, want 0", len(store.paymentEvents))
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/stripe/stripe-go/v76"
+)
+
+var (
+	ErrWebhookEventNotFound      = errors.New("webhook event not found")
+	ErrWebhookEventNotReplayable = errors.New("webhook event was recorded without its payload")
+)
+
+// ReplayStripeEvent re-applies a previously processed Stripe event from its stored
+// payload, e.g. after fixing a bug in how events are handled. The payload was
+// verified when it first arrived, so it is not verified again. Replaying is
+// idempotent: a payment already in the event's status is left as it is, and a
+// succeeded or cancelled payment is never moved by an older event.
+func (s *PaymentService) ReplayStripeEvent(ctx context.Context, eventID string) error {
+	record, err := s.repo.GetWebhookEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return ErrWebhookEventNotFound
+	}
+	if len(record.Payload) == 0 {
+		return ErrWebhookEventNotReplayable
+	}
+
+	var event stripe.Event
+	if err := json.Unmarshal(record.Payload, &event); err != nil {
+		return fmt.Errorf("failed to parse stored webhook event: %w", err)
+	}
+
+	return s.applyStripeEvent(ctx, event)
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+// WebhookSecret is a superseded webhook signing secret that is accepted until ExpiresAt
+type WebhookSecret struct {
+	Secret    string
+	ExpiresAt time.Time
+}
+
+// SetPreviousWebhookSecrets sets the signing secrets accepted alongside the current
+// one. During a rotation Stripe may still sign with the old secret, so keeping it
+// here until it expires makes the switch zero-downtime.
+func (s *PaymentService) SetPreviousWebhookSecrets(secrets []WebhookSecret) {
+	s.previousWebhookSecrets = secrets
+}
+
+// SetWebhookTolerance sets how far a webhook's signed timestamp may lag behind
+// the clock before it is rejected as a possible replay. Stripe's default is 5 minutes;
+// clock-skewed environments may need longer, production can be stricter.
+func (s *PaymentService) SetWebhookTolerance(tolerance time.Duration) error {
+	if tolerance <= 0 {
+		return fmt.Errorf("webhook tolerance must be positive, got %s", tolerance)
+	}
+	s.webhookTolerance = tolerance
+	return nil
+}
+
+func (s *PaymentService) webhookToleranceOrDefault() time.Duration {
+	if s.webhookTolerance > 0 {
+		return s.webhookTolerance
+	}
+	return webhook.DefaultTolerance
+}
+
+// ParseWebhookSecrets parses a comma-separated list of secrets that all expire at expiresAt
+func ParseWebhookSecrets(list string, expiresAt time.Time) []WebhookSecret {
+	var secrets []WebhookSecret
+	for _, secret := range strings.Split(list, ",") {
+		if secret = strings.TrimSpace(secret); secret != "" {
+			secrets = append(secrets, WebhookSecret{Secret: secret, ExpiresAt: expiresAt})
+		}
+	}
+	return secrets
+}
+
+// verifyWebhook checks the signature against the current secret, then against each
+// previous secret that has not yet expired, within the configured tolerance
+func (s *PaymentService) verifyWebhook(payload []byte, signature string) (stripe.Event, error) {
+	tolerance := s.webhookToleranceOrDefault()
+	event, err := webhook.ConstructEventWithTolerance(payload, signature, s.webhookSecret, tolerance)
+	if err == nil {
+		return event, nil
+	}
+
+	now := time.Now()
+	for i, previous := range s.previousWebhookSecrets {
+		if !now.Before(previous.ExpiresAt) {
+			continue
+		}
+		if event, prevErr := webhook.ConstructEventWithTolerance(payload, signature, previous.Secret, tolerance); prevErr == nil {
+			fmt.Printf("Webhook %s verified with previous signing secret %d, which expires at %s\n",
+				event.ID, i+1, previous.ExpiresAt.Format(time.RFC3339))
+			return event, nil
+		}
+	}
+
+	return stripe.Event{}, err
+}
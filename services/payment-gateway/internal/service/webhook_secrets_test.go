@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+	"github.com/stripe/stripe-go/v76/webhook"
+)
+
+func signedWebhook(t *testing.T, secret string) ([]byte, string) {
+	t.Helper()
+	return signedWebhookAt(t, secret, time.Now())
+}
+
+func signedWebhookAt(t *testing.T, secret string, signedAt time.Time) ([]byte, string) {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(`{"id":"evt_1","object":"event","type":"customer.created","api_version":%q,"data":{"object":{}}}`, stripe.APIVersion))
+	signed := webhook.GenerateTestSignedPayload(&webhook.UnsignedPayload{
+		Payload:   payload,
+		Secret:    secret,
+		Timestamp: signedAt,
+	})
+	return signed.Payload, signed.Header
+}
+
+func TestHandleStripeWebhookAcceptsPreviousSecret(t *testing.T) {
+	tests := []struct {
+		name      string
+		signWith  string
+		expiresAt time.Time
+		wantErr   error
+	}{
+		{
+			name:     "Current secret",
+			signWith: "whsec_new",
+		},
+		{
+			name:      "Previous secret before expiry",
+			signWith:  "whsec_old",
+			expiresAt: time.Now().Add(time.Hour),
+		},
+		{
+			name:      "Previous secret after expiry",
+			signWith:  "whsec_old",
+			expiresAt: time.Now().Add(-time.Minute),
+			wantErr:   ErrInvalidWebhookSignature,
+		},
+		{
+			name:      "Unknown secret",
+			signWith:  "whsec_other",
+			expiresAt: time.Now().Add(time.Hour),
+			wantErr:   ErrInvalidWebhookSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PaymentService{repo: newMockStore(), webhookSecret: "whsec_new"}
+			s.SetPreviousWebhookSecrets([]WebhookSecret{{Secret: "whsec_old", ExpiresAt: tt.expiresAt}})
+
+			payload, header := signedWebhook(t, tt.signWith)
+			err := s.HandleStripeWebhook(context.Background(), payload, header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("HandleStripeWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHandleStripeWebhookTolerance(t *testing.T) {
+	const tolerance = 2 * time.Minute
+
+	tests := []struct {
+		name    string
+		age     time.Duration
+		wantErr error
+	}{
+		{name: "Just inside the tolerance", age: tolerance - 10*time.Second},
+		{name: "Just outside the tolerance", age: tolerance + 10*time.Second, wantErr: ErrInvalidWebhookSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PaymentService{repo: newMockStore(), webhookSecret: "whsec_new"}
+			if err := s.SetWebhookTolerance(tolerance); err != nil {
+				t.Fatalf("SetWebhookTolerance() error = %v", err)
+			}
+
+			payload, header := signedWebhookAt(t, "whsec_new", time.Now().Add(-tt.age))
+			err := s.HandleStripeWebhook(context.Background(), payload, header)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("HandleStripeWebhook() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetWebhookToleranceRejectsNonPositive(t *testing.T) {
+	s := &PaymentService{}
+	if err := s.SetWebhookTolerance(0); err == nil {
+		t.Error("SetWebhookTolerance(0) should fail")
+	}
+}
+
+func TestParseWebhookSecrets(t *testing.T) {
+	expiresAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	secrets := ParseWebhookSecrets(" whsec_a, ,whsec_b", expiresAt)
+
+	if len(secrets) != 2 || secrets[0].Secret != "whsec_a" || secrets[1].Secret != "whsec_b" {
+		t.Fatalf("ParseWebhookSecrets() = %+v, want whsec_a and whsec_b", secrets)
+	}
+	for _, secret := range secrets {
+		if !secret.ExpiresAt.Equal(expiresAt) {
+			t.Errorf("%s expires at %v, want %v", secret.Secret, secret.ExpiresAt, expiresAt)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-go/v76"
+
+	"payment-gateway/internal/models"
+)
+
+func TestProcessStripeEventDeduplicates(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_1",
+	}
+	s := &PaymentService{repo: store}
+
+	event := stripe.Event{
+		ID:   "evt_1",
+		Type: "payment_intent.succeeded",
+		Data: &stripe.EventData{
+			Raw: json.RawMessage(`{"id":"pi_1","object":"payment_intent","status":"succeeded"}`),
+		},
+	}
+
+	processed, err := s.ProcessStripeEvent(ctx, event, nil)
+	if err != nil {
+		t.Fatalf("first delivery failed: %v", err)
+	}
+	if !processed {
+		t.Error("first delivery should be processed")
+	}
+
+	processed, err = s.ProcessStripeEvent(ctx, event, nil)
+	if err != nil {
+		t.Fatalf("second delivery failed: %v", err)
+	}
+	if processed {
+		t.Error("second delivery should be skipped")
+	}
+
+	if store.updateCalls != 1 {
+		t.Errorf("payment updated %d times, want 1", store.updateCalls)
+	}
+	if got := store.payments["pay_1"].Status; got != models.PaymentStatusSucceeded {
+		t.Errorf("status = %s, want %s", got, models.PaymentStatusSucceeded)
+	}
+}
+
+func TestSucceededWebhookRecordsProcessorFee(t *testing.T) {
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Amount:                100,
+		Currency:              "EUR",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_1",
+	}
+	// A EUR charge settled into a USD balance
+	processor := &mockProcessor{balanceTxn: &stripe.BalanceTransaction{
+		Amount:   10850,
+		Fee:      345,
+		Net:      10505,
+		Currency: stripe.CurrencyUSD,
+	}}
+	s := &PaymentService{repo: store, processor: processor}
+
+	event := stripe.Event{
+		ID:   "evt_1",
+		Type: "payment_intent.succeeded",
+		Data: &stripe.EventData{
+			Raw: json.RawMessage(`{"id":"pi_1","object":"payment_intent","status":"succeeded","latest_charge":"ch_1"}`),
+		},
+	}
+	if _, err := s.ProcessStripeEvent(context.Background(), event, nil); err != nil {
+		t.Fatalf("ProcessStripeEvent() error = %v", err)
+	}
+
+	payment := store.payments["pay_1"]
+	if payment.FeeAmount != 3.45 || payment.NetAmount != 105.05 || payment.FeeCurrency != "USD" {
+		t.Errorf("fee breakdown = %v/%v %s, want 3.45/105.05 USD",
+			payment.FeeAmount, payment.NetAmount, payment.FeeCurrency)
+	}
+	if payment.Status != models.PaymentStatusSucceeded {
+		t.Errorf("status = %s, want %s", payment.Status, models.PaymentStatusSucceeded)
+	}
+}
+
+func TestReplayStripeEventIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_1",
+	}
+	s := &PaymentService{repo: store}
+
+	payload := []byte(`{"id":"evt_1","object":"event","type":"payment_intent.succeeded",` +
+		`"data":{"object":{"id":"pi_1","object":"payment_intent","status":"succeeded"}}}`)
+	var event stripe.Event
+	if err := json.Unmarshal(payload, &event); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ProcessStripeEvent(ctx, event, payload); err != nil {
+		t.Fatalf("ProcessStripeEvent() error = %v", err)
+	}
+
+	// A buggy handler left the payment behind
+	store.payments["pay_1"].Status = models.PaymentStatusProcessing
+	store.payments["pay_1"].CompletedAt = time.Time{}
+	eventsBefore := len(store.paymentEvents)
+
+	if err := s.ReplayStripeEvent(ctx, "evt_1"); err != nil {
+		t.Fatalf("ReplayStripeEvent() error = %v", err)
+	}
+	payment := store.payments["pay_1"]
+	if payment.Status != models.PaymentStatusSucceeded {
+		t.Fatalf("status after replay = %s, want %s", payment.Status, models.PaymentStatusSucceeded)
+	}
+	if len(store.paymentEvents) != eventsBefore+1 {
+		t.Errorf("replay recorded %d timeline events, want 1", len(store.paymentEvents)-eventsBefore)
+	}
+	completedAt := payment.CompletedAt
+
+	// Replaying again changes nothing
+	if err := s.ReplayStripeEvent(ctx, "evt_1"); err != nil {
+		t.Fatalf("second ReplayStripeEvent() error = %v", err)
+	}
+	payment = store.payments["pay_1"]
+	if payment.Status != models.PaymentStatusSucceeded || !payment.CompletedAt.Equal(completedAt) {
+		t.Errorf("second replay changed the payment to %s completed at %v", payment.Status, payment.CompletedAt)
+	}
+	if len(store.paymentEvents) != eventsBefore+1 {
+		t.Errorf("second replay recorded %d more timeline events, want 0", len(store.paymentEvents)-eventsBefore-1)
+	}
+}
+
+func TestReplayStaleFailureAfterSuccess(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	store.payments["pay_1"] = &models.Payment{
+		ID:                    "pay_1",
+		Status:                models.PaymentStatusProcessing,
+		StripePaymentIntentID: "pi_1",
+	}
+	s := &PaymentService{repo: store}
+
+	// The customer's first attempt failed, then their retry succeeded
+	failed := []byte(`{"id":"evt_failed","object":"event","type":"payment_intent.payment_failed",` +
+		`"data":{"object":{"id":"pi_1","object":"payment_intent","status":"requires_payment_method"}}}`)
+	succeeded := []byte(`{"id":"evt_succeeded","object":"event","type":"payment_intent.succeeded",` +
+		`"data":{"object":{"id":"pi_1","object":"payment_intent","status":"succeeded"}}}`)
+	for _, payload := range [][]byte{failed, succeeded} {
+		var event stripe.Event
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.ProcessStripeEvent(ctx, event, payload); err != nil {
+			t.Fatalf("ProcessStripeEvent(%s) error = %v", event.ID, err)
+		}
+	}
+	eventsBefore := len(store.paymentEvents)
+
+	if err := s.ReplayStripeEvent(ctx, "evt_failed"); err != nil {
+		t.Fatalf("ReplayStripeEvent() error = %v", err)
+	}
+
+	if got := store.payments["pay_1"].Status; got != models.PaymentStatusSucceeded {
+		t.Errorf("status after stale replay = %s, want %s", got, models.PaymentStatusSucceeded)
+	}
+	if len(store.paymentEvents) != eventsBefore {
+		t.Errorf("stale replay recorded %d timeline events, want 0", len(store.paymentEvents)-eventsBefore)
+	}
+}
+
+func TestReplayStripeEventErrors(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	s := &PaymentService{repo: store}
+
+	if _, err := store.MarkEventProcessed(ctx, "evt_old", "payment_intent.succeeded", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		eventID string
+		wantErr error
+	}{
+		{"evt_missing", ErrWebhookEventNotFound},
+		{"evt_old", ErrWebhookEventNotReplayable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.eventID, func(t *testing.T) {
+			if err := s.ReplayStripeEvent(ctx, tt.eventID); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ReplayStripeEvent() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+// CreateAccount handles POST /api/v1/ledger/accounts
+func (h *LedgerHandler) CreateAccount(c *gin.Context) {
+	var req models.CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	account, err := h.service.CreateAccount(c.Request.Context(), &req)
+	if err != nil {
+		if errors.Is(err, service.ErrAccountExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrParentAccountNotFound) || errors.Is(err, service.ErrParentCurrencyMismatch) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to create account", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create account"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+// GetAccount handles GET /api/v1/ledger/accounts/:id
+func (h *LedgerHandler) GetAccount(c *gin.Context) {
+	account, err := h.service.GetAccount(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
+			return
+		}
+		h.logger.Error("failed to get account", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// ListAccounts handles GET /api/v1/ledger/accounts
+func (h *LedgerHandler) ListAccounts(c *gin.Context) {
+	accountType := models.AccountType(c.Query("type"))
+	switch accountType {
+	case "", models.AccountTypeAsset, models.AccountTypeLiability, models.AccountTypeEquity,
+		models.AccountTypeRevenue, models.AccountTypeExpense:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid account type"})
+		return
+	}
+
+	accounts, err := h.service.ListAccounts(c.Request.Context(), accountType)
+	if err != nil {
+		h.logger.Error("failed to list accounts", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list accounts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts": accounts})
+}
+
+// GetRollupBalance handles GET /api/v1/ledger/accounts/:id/rollup, the balance
+// of the account summed with every account beneath it
+func (h *LedgerHandler) GetRollupBalance(c *gin.Context) {
+	rollup, err := h.service.GetRollupBalance(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		if errors.Is(err, service.ErrAccountNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Account not found"})
+			return
+		}
+		h.logger.Error("failed to get rollup balance", zap.String("account_id", c.Param("id")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get rollup balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rollup)
+}
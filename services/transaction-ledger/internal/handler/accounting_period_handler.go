@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+// CreateAccountingPeriod handles POST /api/v1/ledger/periods
+func (h *LedgerHandler) CreateAccountingPeriod(c *gin.Context) {
+	var req models.CreateAccountingPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	period, err := h.service.CreateAccountingPeriod(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidPeriod):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPeriodOverlaps):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to create accounting period", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create accounting period"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, period)
+}
+
+// ListAccountingPeriods handles GET /api/v1/ledger/periods
+func (h *LedgerHandler) ListAccountingPeriods(c *gin.Context) {
+	periods, err := h.service.ListAccountingPeriods(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to list accounting periods", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list accounting periods"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"periods": periods})
+}
+
+// CloseAccountingPeriod handles POST /api/v1/ledger/periods/:id/close
+func (h *LedgerHandler) CloseAccountingPeriod(c *gin.Context) {
+	period, err := h.service.CloseAccountingPeriod(c.Request.Context(), c.Param("id"))
+	h.writeAccountingPeriod(c, period, err)
+}
+
+// OpenAccountingPeriod handles POST /api/v1/ledger/periods/:id/open
+func (h *LedgerHandler) OpenAccountingPeriod(c *gin.Context) {
+	period, err := h.service.OpenAccountingPeriod(c.Request.Context(), c.Param("id"))
+	h.writeAccountingPeriod(c, period, err)
+}
+
+func (h *LedgerHandler) writeAccountingPeriod(c *gin.Context, period *models.AccountingPeriod, err error) {
+	if err != nil {
+		if errors.Is(err, service.ErrPeriodNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Accounting period not found"})
+			return
+		}
+		h.logger.Error("failed to update accounting period", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update accounting period"})
+		return
+	}
+
+	c.JSON(http.StatusOK, period)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+// GetBalances handles POST /api/v1/ledger/balances
+func (h *LedgerHandler) GetBalances(c *gin.Context) {
+	var req models.BulkBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balances, err := h.service.GetBalances(c.Request.Context(), req.AccountIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyAccounts) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to get balances", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get balances"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"balances": balances})
+}
+
+// RecomputeBalance handles POST /api/v1/ledger/admin/balances/:account/recompute
+func (h *LedgerHandler) RecomputeBalance(c *gin.Context) {
+	balance, err := h.service.RecomputeBalance(c.Request.Context(), c.Param("account"))
+	if err != nil {
+		h.logger.Error("failed to recompute balance", zap.String("account_id", c.Param("account")), zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, balance)
+}
+
+// RecomputeAllBalances handles POST /api/v1/ledger/admin/balances/recompute
+func (h *LedgerHandler) RecomputeAllBalances(c *gin.Context) {
+	rebuilt, err := h.service.RecomputeAllBalances(c.Request.Context())
+	if err != nil {
+		h.logger.Error("failed to rebuild balances", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rebuild balances"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"accounts_rebuilt": rebuilt})
+}
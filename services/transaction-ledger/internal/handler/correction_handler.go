@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+// CorrectEntry handles POST /api/v1/ledger/corrections
+func (h *LedgerHandler) CorrectEntry(c *gin.Context) {
+	var req models.CorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	correction, err := h.service.CorrectEntry(c.Request.Context(), &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrEntryNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Entry not found"})
+		case errors.Is(err, service.ErrUnbalancedCorrection), errors.Is(err, service.ErrInvalidCorrection):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to correct entry", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to correct entry"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, correction)
+}
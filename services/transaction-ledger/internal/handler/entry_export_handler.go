@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// exportDateLayout is the format of the export's from and to dates
+const exportDateLayout = "2006-01-02"
+
+// exportContentTypes is the response Content-Type of each export format
+var exportContentTypes = map[string]string{
+	models.EntryExportNDJSON: "application/x-ndjson",
+	models.EntryExportCSV:    "text/csv; charset=utf-8",
+}
+
+// ExportEntries handles GET /api/v1/ledger/entries/export?from=2024-01-01&to=2024-12-31&format=ndjson.
+// Both dates are inclusive and account_id optionally narrows the export to one account.
+// The body is streamed as it is read, so a failure part way through ends the
+// response early rather than changing its status.
+func (h *LedgerHandler) ExportEntries(c *gin.Context) {
+	format := c.DefaultQuery("format", models.EntryExportNDJSON)
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unsupported export format %q, want ndjson or csv", format)})
+		return
+	}
+
+	start, err := time.Parse(exportDateLayout, c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date in YYYY-MM-DD format"})
+		return
+	}
+	end, err := time.Parse(exportDateLayout, c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date in YYYY-MM-DD format"})
+		return
+	}
+	if end.Before(start) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	filter := models.EntryExportFilter{
+		AccountID: c.Query("account_id"),
+		StartDate: start,
+		EndDate:   end.AddDate(0, 0, 1),
+	}
+
+	filename := fmt.Sprintf("ledger_entries_%s_%s.%s", start.Format(exportDateLayout), end.Format(exportDateLayout), format)
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Status(http.StatusOK)
+
+	err = h.service.ExportEntries(c.Request.Context(), c.Writer, format, filter, c.Writer.Flush)
+	// A client that disconnects mid-download is not an export failure
+	if err != nil && c.Request.Context().Err() == nil {
+		h.logger.Error("ledger entry export failed",
+			zap.String("from", start.Format(exportDateLayout)),
+			zap.String("to", end.Format(exportDateLayout)),
+			zap.String("format", format),
+			zap.Error(err))
+	}
+}
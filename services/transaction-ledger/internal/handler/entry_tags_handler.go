@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+const (
+	defaultTaggedEntriesLimit = 50
+	maxTaggedEntriesLimit     = 500
+)
+
+// CreateTaggedEntry handles POST /api/v1/ledger/entries/tagged
+func (h *LedgerHandler) CreateTaggedEntry(c *gin.Context) {
+	var req models.TaggedEntryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transaction, err := h.service.PostTaggedEntry(c.Request.Context(), &req.LedgerEntryRequest, req.Tags)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidTags):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrPeriodClosed):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		case errors.Is(err, service.ErrInvalidEntryAmount), errors.Is(err, service.ErrAccountCurrencyMismatch):
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		default:
+			h.logger.Error("failed to create tagged entry", zap.Error(err))
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create entry"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, transaction)
+}
+
+// ListTaggedEntries handles GET /api/v1/ledger/entries/tagged?tag=merchant_id:m_123
+func (h *LedgerHandler) ListTaggedEntries(c *gin.Context) {
+	filter, err := parseEntryTagFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.service.ListEntriesByTags(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTags) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to list tagged entries", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetTagTotals handles GET /api/v1/ledger/entries/tagged/totals?group_by=merchant_id
+func (h *LedgerHandler) GetTagTotals(c *gin.Context) {
+	filter, err := parseEntryTagFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	groupBy := c.Query("group_by")
+	totals, err := h.service.TotalsByTag(c.Request.Context(), groupBy, filter)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTags) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to total entries by tag", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to total entries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"group_by": groupBy, "totals": totals})
+}
+
+// parseEntryTagFilter reads account_id, limit, offset and repeated key:value tag parameters
+func parseEntryTagFilter(c *gin.Context) (models.EntryTagFilter, error) {
+	filter := models.EntryTagFilter{
+		AccountID: c.Query("account_id"),
+		Tags:      models.EntryTags{},
+		Limit:     defaultTaggedEntriesLimit,
+	}
+
+	for _, raw := range c.QueryArray("tag") {
+		key, value, ok := strings.Cut(raw, ":")
+		if !ok || key == "" {
+			return filter, fmt.Errorf("tag must be key:value, got %q", raw)
+		}
+		filter.Tags[key] = value
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxTaggedEntriesLimit {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxTaggedEntriesLimit)
+		}
+		filter.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return filter, errors.New("offset must be a non-negative integer")
+		}
+		filter.Offset = offset
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// GetExposure handles GET /api/v1/ledger/exposure
+func (h *LedgerHandler) GetExposure(c *gin.Context) {
+	asOf := time.Now()
+	if raw := c.Query("as_of"); raw != "" {
+		parsed, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "as_of must be an RFC3339 timestamp"})
+			return
+		}
+		asOf = parsed
+	}
+
+	report, err := h.service.CurrencyExposure(c.Request.Context(), asOf)
+	if err != nil {
+		h.logger.Error("failed to calculate currency exposure", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to calculate exposure"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+	"transaction-ledger/internal/service"
+)
+
+type ReconciliationHandler struct {
+	service *service.ReconciliationService
+	logger  *zap.Logger
+}
+
+func NewReconciliationHandler(service *service.ReconciliationService, logger *zap.Logger) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ReconcileProcessorFile handles POST /api/v1/ledger/reconcile/processor-file
+// with a multipart "file" upload and an optional "format" field (default stripe)
+func (h *ReconciliationHandler) ReconcileProcessorFile(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a settlement file must be uploaded as \"file\""})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	format := c.DefaultPostForm("format", "stripe")
+
+	report, err := h.service.ReconcileAgainstProcessorFile(c.Request.Context(), file, format)
+	if err != nil {
+		if errors.Is(err, service.ErrUnsupportedFileFormat) || errors.Is(err, service.ErrInvalidProcessorFile) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to reconcile processor file", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile processor file"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ReconcilePayments handles POST /api/v1/ledger/reconcile/payments?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD,
+// cross-checking succeeded payments against ledger postings. end_date is inclusive.
+func (h *ReconciliationHandler) ReconcilePayments(c *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be YYYY-MM-DD"})
+		return
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	report, err := h.service.ReconcilePayments(c.Request.Context(), startDate, endDate.AddDate(0, 0, 1))
+	if err != nil {
+		h.logger.Error("failed to reconcile payments", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile payments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ReconcilePeriod handles POST /api/v1/ledger/reconcile/period?start_date=YYYY-MM-DD&end_date=YYYY-MM-DD,
+// a manually triggered balance check of the period. end_date is inclusive.
+func (h *ReconciliationHandler) ReconcilePeriod(c *gin.Context) {
+	startDate, err := time.Parse("2006-01-02", c.Query("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date must be YYYY-MM-DD"})
+		return
+	}
+	endDate, err := time.Parse("2006-01-02", c.Query("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must be YYYY-MM-DD"})
+		return
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+
+	run, err := h.service.RunReconciliation(c.Request.Context(), startDate, endDate.AddDate(0, 0, 1), models.ReconciliationTriggerManual)
+	if err != nil {
+		h.logger.Error("failed to reconcile period", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile period"})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// ReconcileTransactions handles POST /api/v1/ledger/reconcile/transactions with
+// {"transaction_ids": [...]}, a balance check of just those transactions
+func (h *ReconciliationHandler) ReconcileTransactions(c *gin.Context) {
+	var req models.ReconcileTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.service.ReconcileTransactions(c.Request.Context(), req.TransactionIDs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidTransactionIDs) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to reconcile transactions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reconcile transactions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// CompareReports handles GET /api/v1/ledger/reconcile/compare?report_a=...&report_b=...,
+// listing the discrepancies unique to each saved report and those common to both
+func (h *ReconciliationHandler) CompareReports(c *gin.Context) {
+	idA, idB := c.Query("report_a"), c.Query("report_b")
+	if idA == "" || idB == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "report_a and report_b are required"})
+		return
+	}
+
+	comparison, err := h.service.CompareReports(c.Request.Context(), idA, idB)
+	if err != nil {
+		if errors.Is(err, service.ErrReconciliationReportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		h.logger.Error("failed to compare reconciliation reports", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compare reconciliation reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+type AccountType string
+
+const (
+	AccountTypeAsset     AccountType = "asset"
+	AccountTypeLiability AccountType = "liability"
+	AccountTypeEquity    AccountType = "equity"
+	AccountTypeRevenue   AccountType = "revenue"
+	AccountTypeExpense   AccountType = "expense"
+)
+
+// Account is a ledger account entries are posted to. Entries reference it by Name.
+type Account struct {
+	ID          string      `json:"id" db:"id"`
+	Name        string      `json:"name" db:"name"`
+	Type        AccountType `json:"type" db:"type"`
+	Currency    string      `json:"currency" db:"currency"`
+	Description string      `json:"description,omitempty" db:"description"`
+	// ParentAccountID is the account this one rolls up into; empty for a top-level account
+	ParentAccountID string    `json:"parent_account_id,omitempty" db:"parent_account_id"`
+	CreatedAt       time.Time `json:"created_at" db:"created_at"`
+}
+
+type CreateAccountRequest struct {
+	Name        string      `json:"name" binding:"required,max=100"`
+	Type        AccountType `json:"type" binding:"required,oneof=asset liability equity revenue expense"`
+	Currency    string      `json:"currency" binding:"required,len=3"`
+	Description string      `json:"description"`
+	// ParentAccountID optionally nests the account under another, held in the same currency
+	ParentAccountID string `json:"parent_account_id"`
+}
+
+// RollupBalance is an account's balance summed with every account beneath it
+type RollupBalance struct {
+	AccountID string  `json:"account_id"`
+	Currency  string  `json:"currency"`
+	Balance   float64 `json:"balance"`
+	// Accounts holds each account's own balance, the parent first
+	Accounts  []*AccountBalance `json:"accounts"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// BulkBalanceRequest asks for the balances of several accounts at once
+type BulkBalanceRequest struct {
+	AccountIDs []string `json:"account_ids" binding:"required,min=1"`
+}
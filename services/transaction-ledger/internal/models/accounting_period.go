@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+type AccountingPeriodStatus string
+
+const (
+	AccountingPeriodOpen   AccountingPeriodStatus = "open"
+	AccountingPeriodClosed AccountingPeriodStatus = "closed"
+)
+
+// AccountingPeriod is a span of days that finance can close, after which no entry
+// dated inside it may be posted. StartDate is inclusive and EndDate exclusive.
+type AccountingPeriod struct {
+	ID        string                 `json:"id" db:"id"`
+	StartDate time.Time              `json:"start_date" db:"start_date"`
+	EndDate   time.Time              `json:"end_date" db:"end_date"`
+	Status    AccountingPeriodStatus `json:"status" db:"status"`
+	ClosedAt  *time.Time             `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
+
+// Contains reports whether t falls within the period
+func (p *AccountingPeriod) Contains(t time.Time) bool {
+	return !t.Before(p.StartDate) && t.Before(p.EndDate)
+}
+
+// CreateAccountingPeriodRequest defines a period by its first and last day, both YYYY-MM-DD
+type CreateAccountingPeriodRequest struct {
+	StartDate string `json:"start_date" binding:"required"`
+	EndDate   string `json:"end_date" binding:"required"`
+}
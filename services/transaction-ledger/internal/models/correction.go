@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// CorrectionRequest posts compensating entries for a single mis-posted entry
+type CorrectionRequest struct {
+	EntryID string         `json:"entry_id" binding:"required"`
+	Reason  string         `json:"reason" binding:"required"`
+	Entries []EntryRequest `json:"entries" binding:"required,min=2,dive"`
+}
+
+// LedgerCorrection links a mis-posted entry to the transaction that compensates it,
+// and records how much of the entry it reverses. The original entry is never modified.
+type LedgerCorrection struct {
+	ID                      string             `json:"id" db:"id"`
+	OriginalEntryID         string             `json:"original_entry_id" db:"original_entry_id"`
+	OriginalTransactionID   string             `json:"original_transaction_id" db:"original_transaction_id"`
+	CorrectionTransactionID string             `json:"correction_transaction_id" db:"correction_transaction_id"`
+	ReversedAmount          float64            `json:"reversed_amount" db:"reversed_amount"`
+	Reason                  string             `json:"reason" db:"reason"`
+	CreatedAt               time.Time          `json:"created_at" db:"created_at"`
+	Transaction             *LedgerTransaction `json:"transaction,omitempty" db:"-"`
+}
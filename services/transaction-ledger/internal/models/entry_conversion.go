@@ -0,0 +1,10 @@
+package models
+
+// EntryConversion records how a reporting-currency entry was derived from the
+// original-currency posting, so the amount can be audited against the rate used
+type EntryConversion struct {
+	EntryID        string  `json:"entry_id" db:"id"`
+	SourceAmount   float64 `json:"source_amount" db:"source_amount"`
+	SourceCurrency string  `json:"source_currency" db:"source_currency"`
+	ConversionRate float64 `json:"conversion_rate" db:"conversion_rate"`
+}
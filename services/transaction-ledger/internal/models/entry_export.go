@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// Entry export formats
+const (
+	EntryExportNDJSON = "ndjson"
+	EntryExportCSV    = "csv"
+)
+
+// EntryExportFilter selects the entries posted in [StartDate, EndDate), optionally on one account
+type EntryExportFilter struct {
+	AccountID string
+	StartDate time.Time
+	EndDate   time.Time
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// EntryTags are the dimensions an entry is sliced by, e.g. merchant_id, product or region
+type EntryTags map[string]string
+
+// Value stores tags as a JSONB object
+func (t EntryTags) Value() (driver.Value, error) {
+	if t == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(t)
+}
+
+// Scan reads tags from a JSONB object
+func (t *EntryTags) Scan(src interface{}) error {
+	var data []byte
+	switch v := src.(type) {
+	case nil:
+		*t = EntryTags{}
+		return nil
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("cannot scan %T into EntryTags", src)
+	}
+	return json.Unmarshal(data, t)
+}
+
+// TaggedEntry is a ledger entry with its tags
+type TaggedEntry struct {
+	LedgerEntry
+	Tags EntryTags `json:"tags"`
+}
+
+// EntryTagFilter selects entries carrying every given tag, optionally on one
+// account and posted in [StartDate, EndDate); zero dates leave that end open
+type EntryTagFilter struct {
+	AccountID string
+	Tags      EntryTags
+	StartDate time.Time
+	EndDate   time.Time
+	Limit     int
+	Offset    int
+}
+
+// TagTotal sums the entries sharing one value of a tag in one currency
+type TagTotal struct {
+	Value    string  `json:"value"`
+	Currency string  `json:"currency"`
+	Debits   float64 `json:"debits"`
+	Credits  float64 `json:"credits"`
+	Net      float64 `json:"net"`
+	Entries  int     `json:"entries"`
+}
+
+// TaggedEntryRequest posts a double entry whose entries all carry Tags
+type TaggedEntryRequest struct {
+	LedgerEntryRequest
+	Tags EntryTags `json:"tags" binding:"required"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// BuiltinLiabilityAccounts are the liability accounts the ledger posts payments to
+// itself. They count towards exposure even without a ledger_accounts row typing them.
+var BuiltinLiabilityAccounts = []string{"payment_gateway_liability"}
+
+// CurrencyExposure is the net open liability position in a single currency
+type CurrencyExposure struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// ExposureReport summarizes liability exposure per currency at a point in time
+type ExposureReport struct {
+	AsOf      time.Time          `json:"as_of"`
+	Exposures []CurrencyExposure `json:"exposures"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Payment lifecycle event types consumed by the ledger
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentRefunded  = "payment.refunded"
+)
+
+// PaymentModeLive is the mode of payments made with real money; the ledger posts only these
+const PaymentModeLive = "live"
+
+// PaymentEvent is a payment lifecycle event published by the payment gateway
+type PaymentEvent struct {
+	ID        string  `json:"id"`
+	Type      string  `json:"type"`
+	PaymentID string  `json:"payment_id"`
+	Amount    float64 `json:"amount"`
+	Currency  string  `json:"currency"`
+	// Mode is "test" or "live"; test payments are never posted, so they stay out of
+	// live balances and reports. Events from before modes existed have none.
+	Mode string `json:"mode,omitempty"`
+	// Processor fee and net settlement, in FeeCurrency, once Stripe has reported them
+	FeeAmount   float64 `json:"fee_amount,omitempty"`
+	NetAmount   float64 `json:"net_amount,omitempty"`
+	FeeCurrency string  `json:"fee_currency,omitempty"`
+	// RefundReason is the reason code of a payment.refunded event, e.g. duplicate
+	RefundReason string    `json:"refund_reason,omitempty"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
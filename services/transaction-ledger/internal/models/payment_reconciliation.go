@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// DiscrepancyNoSucceededPayment is a ledger posting for a payment the gateway never marked succeeded
+const DiscrepancyNoSucceededPayment = "no_succeeded_payment"
+
+// SucceededPayment is a payment the gateway recorded as succeeded
+type SucceededPayment struct {
+	ID          string    `json:"id"`
+	Amount      float64   `json:"amount"`
+	Currency    string    `json:"currency"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// PaymentDiscrepancy is a payment found on only one side of the payments/ledger cross-check
+type PaymentDiscrepancy struct {
+	PaymentID   string  `json:"payment_id"`
+	Type        string  `json:"type"`
+	Amount      float64 `json:"amount"`
+	Currency    string  `json:"currency"`
+	Description string  `json:"description"`
+}
+
+// PaymentReconciliationReport cross-checks succeeded payments against ledger postings
+type PaymentReconciliationReport struct {
+	ID                string               `json:"id"`
+	StartDate         time.Time            `json:"start_date"`
+	EndDate           time.Time            `json:"end_date"`
+	SucceededPayments int                  `json:"succeeded_payments"`
+	LedgerPayments    int                  `json:"ledger_payments"`
+	Matched           int                  `json:"matched"`
+	Discrepancies     []PaymentDiscrepancy `json:"discrepancies"`
+	IsReconciled      bool                 `json:"is_reconciled"`
+	CreatedAt         time.Time            `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Processor file discrepancy types
+const (
+	DiscrepancyMissingInLedger = "missing_in_ledger"
+	DiscrepancyMissingInFile   = "missing_in_file"
+	DiscrepancyAmountMismatch  = "amount_mismatch"
+)
+
+// ProcessorRecord is one settled transaction from a processor's settlement file
+type ProcessorRecord struct {
+	PaymentID string    `json:"payment_id"`
+	Amount    float64   `json:"amount"`
+	Currency  string    `json:"currency"`
+	SettledAt time.Time `json:"settled_at"`
+}
+
+// ProcessorDiscrepancy is a payment on which the ledger and the processor file disagree
+type ProcessorDiscrepancy struct {
+	PaymentID    string  `json:"payment_id"`
+	Type         string  `json:"type"`
+	LedgerAmount float64 `json:"ledger_amount"`
+	FileAmount   float64 `json:"file_amount"`
+	Currency     string  `json:"currency"`
+	Description  string  `json:"description"`
+}
+
+// ProcessorReconciliationReport compares the ledger with a processor settlement file
+type ProcessorReconciliationReport struct {
+	ID            string                 `json:"id"`
+	Format        string                 `json:"format"`
+	StartDate     time.Time              `json:"start_date"`
+	EndDate       time.Time              `json:"end_date"`
+	FileRecords   int                    `json:"file_records"`
+	Matched       int                    `json:"matched"`
+	Discrepancies []ProcessorDiscrepancy `json:"discrepancies"`
+	IsReconciled  bool                   `json:"is_reconciled"`
+	CreatedAt     time.Time              `json:"created_at"`
+}
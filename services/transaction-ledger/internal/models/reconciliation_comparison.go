@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// ReconciliationComparison diffs the discrepancies of two reconciliation reports,
+// e.g. before and after a fix. Discrepancies only in ReportA were resolved by the
+// time ReportB ran; those only in ReportB are new.
+type ReconciliationComparison struct {
+	ReportA   string    `json:"report_a"`
+	ReportB   string    `json:"report_b"`
+	OnlyInA   []string  `json:"only_in_a"`
+	OnlyInB   []string  `json:"only_in_b"`
+	Common    []string  `json:"common"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// ReconciliationTrigger records what started a reconciliation run
+type ReconciliationTrigger string
+
+const (
+	ReconciliationTriggerCron   ReconciliationTrigger = "cron"
+	ReconciliationTriggerManual ReconciliationTrigger = "manual"
+)
+
+// ReconciliationRun is a ReconciliationReport with an audit of how it was run.
+// The report's fields are embedded, so trigger_source and duration_ms sit
+// alongside them in the JSON.
+type ReconciliationRun struct {
+	*ReconciliationReport
+	TriggerSource ReconciliationTrigger `json:"trigger_source"`
+	DurationMS    int64                 `json:"duration_ms"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ReconcileTransactionsRequest lists the transactions an auditor wants checked
+type ReconcileTransactionsRequest struct {
+	TransactionIDs []string `json:"transaction_ids" binding:"required"`
+}
+
+// TransactionBalance is the debit and credit total of one ledger transaction
+type TransactionBalance struct {
+	TransactionID string  `json:"transaction_id"`
+	Debits        float64 `json:"debits"`
+	Credits       float64 `json:"credits"`
+	Difference    float64 `json:"difference"`
+	IsBalanced    bool    `json:"is_balanced"`
+}
+
+// TransactionReconciliationReport checks the balance of a chosen set of transactions
+type TransactionReconciliationReport struct {
+	ID                string               `json:"id"`
+	TotalTransactions int                  `json:"total_transactions"`
+	TotalDebits       float64              `json:"total_debits"`
+	TotalCredits      float64              `json:"total_credits"`
+	Transactions      []TransactionBalance `json:"transactions"`
+	// NotFound lists requested IDs with no ledger entries
+	NotFound      []string  `json:"not_found"`
+	Discrepancies []string  `json:"discrepancies"`
+	IsBalanced    bool      `json:"is_balanced"`
+	CreatedAt     time.Time `json:"created_at"`
+}
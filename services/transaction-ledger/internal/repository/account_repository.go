@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+
+	"transaction-ledger/internal/models"
+)
+
+// CreateAccount inserts an account, returning false if the name is already taken
+func (r *LedgerRepository) CreateAccount(ctx context.Context, account *models.Account) (bool, error) {
+	query := `
+		INSERT INTO ledger_accounts (id, name, type, currency, description, parent_account_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		ON CONFLICT (name) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		account.ID,
+		account.Name,
+		account.Type,
+		account.Currency,
+		account.Description,
+		account.ParentAccountID,
+		account.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// GetAccount returns an account, or nil if it does not exist
+func (r *LedgerRepository) GetAccount(ctx context.Context, id string) (*models.Account, error) {
+	query := `
+		SELECT id, name, type, currency, COALESCE(description, ''), COALESCE(parent_account_id, ''), created_at
+		FROM ledger_accounts WHERE id = $1
+	`
+
+	account := &models.Account{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&account.ID,
+		&account.Name,
+		&account.Type,
+		&account.Currency,
+		&account.Description,
+		&account.ParentAccountID,
+		&account.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return account, err
+}
+
+// ListAccounts returns accounts ordered by name, optionally limited to one type
+func (r *LedgerRepository) ListAccounts(ctx context.Context, accountType models.AccountType) ([]*models.Account, error) {
+	query := `
+		SELECT id, name, type, currency, COALESCE(description, ''), COALESCE(parent_account_id, ''), created_at
+		FROM ledger_accounts
+		WHERE $1 = '' OR type = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, accountType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Type,
+			&account.Currency,
+			&account.Description,
+			&account.ParentAccountID,
+			&account.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// GetAccountsByName returns the accounts with the given names; names with no
+// account are left out
+func (r *LedgerRepository) GetAccountsByName(ctx context.Context, names []string) ([]*models.Account, error) {
+	query := `
+		SELECT id, name, type, currency, COALESCE(description, ''), COALESCE(parent_account_id, ''), created_at
+		FROM ledger_accounts
+		WHERE name = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(names))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Type,
+			&account.Currency,
+			&account.Description,
+			&account.ParentAccountID,
+			&account.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
+
+// ListChildAccounts returns the accounts directly beneath a parent, ordered by name
+func (r *LedgerRepository) ListChildAccounts(ctx context.Context, parentID string) ([]*models.Account, error) {
+	query := `
+		SELECT id, name, type, currency, COALESCE(description, ''), COALESCE(parent_account_id, ''), created_at
+		FROM ledger_accounts
+		WHERE parent_account_id = $1
+		ORDER BY name
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := []*models.Account{}
+	for rows.Next() {
+		account := &models.Account{}
+		if err := rows.Scan(
+			&account.ID,
+			&account.Name,
+			&account.Type,
+			&account.Currency,
+			&account.Description,
+			&account.ParentAccountID,
+			&account.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+
+	return accounts, rows.Err()
+}
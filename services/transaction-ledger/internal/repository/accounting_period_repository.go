@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"transaction-ledger/internal/models"
+)
+
+const accountingPeriodColumns = `id, start_date, end_date, status, closed_at, created_at`
+
+// CreateAccountingPeriod inserts a period, returning false if it overlaps an existing one
+func (r *LedgerRepository) CreateAccountingPeriod(ctx context.Context, period *models.AccountingPeriod) (bool, error) {
+	query := `
+		INSERT INTO accounting_periods (` + accountingPeriodColumns + `)
+		SELECT $1, $2, $3, $4, $5, $6
+		WHERE NOT EXISTS (
+			SELECT 1 FROM accounting_periods WHERE start_date < $3 AND end_date > $2
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		period.ID,
+		period.StartDate,
+		period.EndDate,
+		period.Status,
+		period.ClosedAt,
+		period.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// GetAccountingPeriod returns a period, or nil if it does not exist
+func (r *LedgerRepository) GetAccountingPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error) {
+	query := `SELECT ` + accountingPeriodColumns + ` FROM accounting_periods WHERE id = $1`
+
+	period, err := scanAccountingPeriod(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return period, err
+}
+
+// ListAccountingPeriods returns every period, earliest first
+func (r *LedgerRepository) ListAccountingPeriods(ctx context.Context) ([]*models.AccountingPeriod, error) {
+	query := `SELECT ` + accountingPeriodColumns + ` FROM accounting_periods ORDER BY start_date`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	periods := []*models.AccountingPeriod{}
+	for rows.Next() {
+		period, err := scanAccountingPeriod(rows)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, rows.Err()
+}
+
+// SetAccountingPeriodStatus opens or closes a period; closedAt is nil when reopening
+func (r *LedgerRepository) SetAccountingPeriodStatus(ctx context.Context, id string, status models.AccountingPeriodStatus, closedAt *time.Time) error {
+	query := `UPDATE accounting_periods SET status = $1, closed_at = $2 WHERE id = $3`
+
+	_, err := r.db.ExecContext(ctx, query, status, closedAt, id)
+	return err
+}
+
+// GetClosedPeriodAt returns the closed period containing at, or nil if at is in no closed period
+func (r *LedgerRepository) GetClosedPeriodAt(ctx context.Context, at time.Time) (*models.AccountingPeriod, error) {
+	query := `
+		SELECT ` + accountingPeriodColumns + ` FROM accounting_periods
+		WHERE status = $1 AND start_date <= $2 AND end_date > $2
+		LIMIT 1
+	`
+
+	period, err := scanAccountingPeriod(r.db.QueryRowContext(ctx, query, models.AccountingPeriodClosed, at))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return period, err
+}
+
+func scanAccountingPeriod(row interface{ Scan(...interface{}) error }) (*models.AccountingPeriod, error) {
+	period := &models.AccountingPeriod{}
+	var closedAt sql.NullTime
+	if err := row.Scan(
+		&period.ID,
+		&period.StartDate,
+		&period.EndDate,
+		&period.Status,
+		&closedAt,
+		&period.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if closedAt.Valid {
+		period.ClosedAt = &closedAt.Time
+	}
+	return period, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+
+	"transaction-ledger/internal/models"
+)
+
+// GetCachedBalance returns an account's materialized balance, or nil if none is stored
+func (r *LedgerRepository) GetCachedBalance(ctx context.Context, accountID string) (*models.AccountBalance, error) {
+	query := `
+		SELECT account_id, balance, currency, updated_at
+		FROM account_balances WHERE account_id = $1
+	`
+
+	balance := &models.AccountBalance{}
+	err := r.db.QueryRowContext(ctx, query, accountID).Scan(
+		&balance.AccountID,
+		&balance.Balance,
+		&balance.Currency,
+		&balance.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return balance, err
+}
+
+// SaveCachedBalance stores a freshly computed balance, replacing any stored one
+func (r *LedgerRepository) SaveCachedBalance(ctx context.Context, balance *models.AccountBalance) error {
+	query := `
+		INSERT INTO account_balances (account_id, balance, currency, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id) DO UPDATE
+		SET balance = EXCLUDED.balance, currency = EXCLUDED.currency, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		balance.AccountID,
+		balance.Balance,
+		balance.Currency,
+		balance.UpdatedAt,
+	)
+
+	return err
+}
+
+// CreateTransactionWithBalances saves a transaction and its entries and adds
+// deltas to the stored balances of their accounts in one database transaction, so
+// a stored balance never misses or double-counts an entry. Accounts without a
+// stored balance are left alone; their first read computes it from the full history.
+func (r *LedgerRepository) CreateTransactionWithBalances(ctx context.Context, transaction *models.LedgerTransaction, entries []*models.LedgerEntry, deltas map[string]float64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertTransaction(ctx, tx, transaction, entries, deltas); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// insertTransaction writes a transaction, its entries and their balance deltas within tx
+func insertTransaction(ctx context.Context, tx *sql.Tx, transaction *models.LedgerTransaction, entries []*models.LedgerEntry, deltas map[string]float64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO ledger_transactions (id, description, payment_id, status, created_at, updated_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6)
+	`,
+		transaction.ID,
+		transaction.Description,
+		transaction.PaymentID,
+		transaction.Status,
+		transaction.CreatedAt,
+		transaction.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO ledger_entries (id, transaction_id, account_id, type, amount, currency, description, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`,
+			entry.ID,
+			entry.TransactionID,
+			entry.AccountID,
+			entry.Type,
+			entry.Amount,
+			entry.Currency,
+			entry.Description,
+			entry.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for accountID, delta := range deltas {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE account_balances
+			SET balance = balance + $1, updated_at = $2
+			WHERE account_id = $3
+		`, delta, transaction.CreatedAt, accountID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// FillCachedBalance stores a balance computed on a cache miss unless one was stored
+// meanwhile. That one already includes any entries posted since, which the
+// computed balance may have missed.
+func (r *LedgerRepository) FillCachedBalance(ctx context.Context, balance *models.AccountBalance) error {
+	query := `
+		INSERT INTO account_balances (account_id, balance, currency, updated_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (account_id) DO NOTHING
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		balance.AccountID,
+		balance.Balance,
+		balance.Currency,
+		balance.UpdatedAt,
+	)
+
+	return err
+}
+
+// InvalidateCachedBalance drops a stored balance so it is recomputed on next read
+func (r *LedgerRepository) InvalidateCachedBalance(ctx context.Context, accountID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM account_balances WHERE account_id = $1`, accountID)
+	return err
+}
+
+// RebuildCachedBalances recomputes the stored balance of every account from its entries
+func (r *LedgerRepository) RebuildCachedBalances(ctx context.Context) (int64, error) {
+	query := `
+		INSERT INTO account_balances (account_id, balance, currency, updated_at)
+		SELECT account_id,
+			   SUM(CASE WHEN type = $1 THEN amount ELSE -amount END),
+			   'USD',
+			   NOW()
+		FROM ledger_entries
+		GROUP BY account_id
+		ON CONFLICT (account_id) DO UPDATE
+		SET balance = EXCLUDED.balance, updated_at = EXCLUDED.updated_at
+	`
+
+	result, err := r.db.ExecContext(ctx, query, models.EntryTypeDebit)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// SumBalances computes the balances of several accounts from their entries in a
+// single aggregate. Accounts without entries are absent from the result.
+func (r *LedgerRepository) SumBalances(ctx context.Context, accountIDs []string) (map[string]float64, error) {
+	query := `
+		SELECT account_id, SUM(CASE WHEN type = $1 THEN amount ELSE -amount END)
+		FROM ledger_entries
+		WHERE account_id = ANY($2)
+		GROUP BY account_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, models.EntryTypeDebit, pq.Array(accountIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make(map[string]float64, len(accountIDs))
+	for rows.Next() {
+		var accountID string
+		var balance float64
+		if err := rows.Scan(&accountID, &balance); err != nil {
+			return nil, err
+		}
+		balances[accountID] = balance
+	}
+
+	return balances, rows.Err()
+}
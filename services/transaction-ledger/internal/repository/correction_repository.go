@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"transaction-ledger/internal/models"
+)
+
+// GetEntryByID returns a single ledger entry, or nil if it does not exist
+func (r *LedgerRepository) GetEntryByID(ctx context.Context, id string) (*models.LedgerEntry, error) {
+	query := `
+		SELECT id, transaction_id, account_id, type, amount, currency,
+			   COALESCE(description, ''), created_at
+		FROM ledger_entries WHERE id = $1
+	`
+
+	entry := &models.LedgerEntry{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&entry.ID,
+		&entry.TransactionID,
+		&entry.AccountID,
+		&entry.Type,
+		&entry.Amount,
+		&entry.Currency,
+		&entry.Description,
+		&entry.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return entry, err
+}
+
+// CreateCorrection saves a correction with its compensating transaction, entries
+// and balance deltas in one database transaction. It returns false, saving nothing,
+// when the corrections of the original entry would together reverse more than it
+// posted. The original entry is locked so concurrent corrections are counted.
+func (r *LedgerRepository) CreateCorrection(ctx context.Context, correction *models.LedgerCorrection, transaction *models.LedgerTransaction, deltas map[string]float64) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var fits bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT COALESCE((
+			SELECT SUM(reversed_amount) FROM ledger_corrections WHERE original_entry_id = e.id
+		), 0) + $2 <= e.amount
+		FROM ledger_entries e
+		WHERE e.id = $1
+		FOR UPDATE
+	`, correction.OriginalEntryID, correction.ReversedAmount).Scan(&fits)
+	if err != nil || !fits {
+		return false, err
+	}
+
+	if err := insertTransaction(ctx, tx, transaction, transaction.Entries, deltas); err != nil {
+		return false, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO ledger_corrections (
+			id, original_entry_id, original_transaction_id,
+			correction_transaction_id, reversed_amount, reason, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`,
+		correction.ID,
+		correction.OriginalEntryID,
+		correction.OriginalTransactionID,
+		correction.CorrectionTransactionID,
+		correction.ReversedAmount,
+		correction.Reason,
+		correction.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return true, tx.Commit()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"transaction-ledger/internal/models"
+)
+
+// SetEntryConversion stores the source amount and rate a reporting-currency entry was converted at,
+// returning false if the entry does not exist
+func (r *LedgerRepository) SetEntryConversion(ctx context.Context, conversion *models.EntryConversion) (bool, error) {
+	query := `
+		UPDATE ledger_entries
+		SET source_amount = $1, source_currency = $2, conversion_rate = $3
+		WHERE id = $4
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		conversion.SourceAmount, conversion.SourceCurrency, conversion.ConversionRate, conversion.EntryID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// GetPaymentConversion returns the conversion recorded on a payment's entry to a
+// reporting-currency account, with the currency that entry was posted in, or nil
+// if the payment has no such entry
+func (r *LedgerRepository) GetPaymentConversion(ctx context.Context, paymentID, accountID string) (*models.EntryConversion, string, error) {
+	query := `
+		SELECT e.id, e.source_amount, e.source_currency, e.conversion_rate, e.currency
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.id = e.transaction_id
+		WHERE t.payment_id = $1 AND e.account_id = $2 AND e.conversion_rate IS NOT NULL
+		ORDER BY e.created_at
+		LIMIT 1
+	`
+
+	conversion := &models.EntryConversion{}
+	var currency string
+	err := r.db.QueryRowContext(ctx, query, paymentID, accountID).Scan(
+		&conversion.EntryID,
+		&conversion.SourceAmount,
+		&conversion.SourceCurrency,
+		&conversion.ConversionRate,
+		&currency,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, "", nil
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return conversion, currency, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"transaction-ledger/internal/models"
+)
+
+// StreamEntries calls fn for each entry matching the filter, oldest first, reading
+// rows as they arrive so a long period is never held in memory
+func (r *LedgerRepository) StreamEntries(ctx context.Context, filter models.EntryExportFilter, fn func(*models.LedgerEntry) error) error {
+	query := `
+		SELECT id, transaction_id, account_id, type, amount, currency,
+			   COALESCE(description, ''), created_at
+		FROM ledger_entries
+		WHERE created_at >= $1 AND created_at < $2
+		  AND ($3 = '' OR account_id = $3)
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, filter.StartDate, filter.EndDate, filter.AccountID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		entry := &models.LedgerEntry{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TransactionID,
+			&entry.AccountID,
+			&entry.Type,
+			&entry.Amount,
+			&entry.Currency,
+			&entry.Description,
+			&entry.CreatedAt,
+		); err != nil {
+			return err
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"transaction-ledger/internal/models"
+)
+
+// SetEntryTags tags an entry that has no tags yet, returning false if the entry
+// does not exist or is already tagged. Entries are append-only, so tags are
+// written once as part of posting and never replaced.
+func (r *LedgerRepository) SetEntryTags(ctx context.Context, entryID string, tags models.EntryTags) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE ledger_entries SET tags = $1 WHERE id = $2 AND tags = '{}'::jsonb`, tags, entryID)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// ListEntriesByTags returns entries matching the filter, newest first
+func (r *LedgerRepository) ListEntriesByTags(ctx context.Context, filter models.EntryTagFilter) ([]*models.TaggedEntry, error) {
+	where, args := entryTagConditions(filter)
+	args = append(args, filter.Limit, filter.Offset)
+	query := fmt.Sprintf(`
+		SELECT id, transaction_id, account_id, type, amount, currency,
+			   COALESCE(description, ''), created_at, tags
+		FROM ledger_entries
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)-1, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.TaggedEntry{}
+	for rows.Next() {
+		entry := &models.TaggedEntry{}
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.TransactionID,
+			&entry.AccountID,
+			&entry.Type,
+			&entry.Amount,
+			&entry.Currency,
+			&entry.Description,
+			&entry.CreatedAt,
+			&entry.Tags,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountEntriesByTags returns how many entries match the filter, ignoring its limit and offset
+func (r *LedgerRepository) CountEntriesByTags(ctx context.Context, filter models.EntryTagFilter) (int, error) {
+	where, args := entryTagConditions(filter)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM ledger_entries `+where, args...).Scan(&count)
+	return count, err
+}
+
+// SumEntriesByTag totals the entries matching the filter per value of the key tag
+// and currency. Entries without the tag are grouped under an empty value.
+func (r *LedgerRepository) SumEntriesByTag(ctx context.Context, key string, filter models.EntryTagFilter) ([]*models.TagTotal, error) {
+	where, args := entryTagConditions(filter)
+	args = append(args, key, models.EntryTypeDebit)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(tags->>$%[1]d, ''), currency,
+			   COALESCE(SUM(CASE WHEN type = $%[2]d THEN amount END), 0),
+			   COALESCE(SUM(CASE WHEN type <> $%[2]d THEN amount END), 0),
+			   COUNT(*)
+		FROM ledger_entries
+		%[3]s
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`, len(args)-1, len(args), where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := []*models.TagTotal{}
+	for rows.Next() {
+		total := &models.TagTotal{}
+		if err := rows.Scan(&total.Value, &total.Currency, &total.Debits, &total.Credits, &total.Entries); err != nil {
+			return nil, err
+		}
+		total.Net = total.Debits - total.Credits
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
+
+// entryTagConditions builds the WHERE clause for a filter; tags match by JSONB containment
+func entryTagConditions(filter models.EntryTagFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.AccountID != "" {
+		args = append(args, filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf(`account_id = $%d`, len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		conditions = append(conditions, fmt.Sprintf(`tags @> $%d`, len(args)))
+	}
+	if !filter.StartDate.IsZero() {
+		args = append(args, filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf(`created_at >= $%d`, len(args)))
+	}
+	if !filter.EndDate.IsZero() {
+		args = append(args, filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf(`created_at < $%d`, len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return `WHERE ` + strings.Join(conditions, ` AND `), args
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+
+	"transaction-ledger/internal/models"
+)
+
+// SumLiabilityExposure nets the entries posted to liability accounts at or before
+// asOf per currency. Liabilities are credit-normal, so credits increase exposure
+// and debits reduce it. The built-in liability accounts count unless an account
+// row gives them another type.
+func (r *LedgerRepository) SumLiabilityExposure(ctx context.Context, asOf time.Time) ([]models.CurrencyExposure, error) {
+	query := `
+		SELECT e.currency,
+			   SUM(CASE WHEN e.type = $2 THEN e.amount ELSE -e.amount END)
+		FROM ledger_entries e
+		LEFT JOIN ledger_accounts a ON a.name = e.account_id
+		WHERE (a.type = $3 OR (a.id IS NULL AND e.account_id = ANY($4)))
+		  AND e.created_at <= $1
+		GROUP BY e.currency
+		ORDER BY e.currency
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		asOf, models.EntryTypeCredit, models.AccountTypeLiability, pq.Array(models.BuiltinLiabilityAccounts))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exposures := []models.CurrencyExposure{}
+	for rows.Next() {
+		var exposure models.CurrencyExposure
+		if err := rows.Scan(&exposure.Currency, &exposure.Amount); err != nil {
+			return nil, err
+		}
+		exposures = append(exposures, exposure)
+	}
+
+	return exposures, rows.Err()
+}
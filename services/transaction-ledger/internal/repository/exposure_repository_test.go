@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+
+	"transaction-ledger/internal/models"
+)
+
+// openTestDB connects to LEDGER_TEST_DATABASE_URL and gives the test its own
+// schema, dropped afterwards. Tests are skipped without a database.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	url := os.Getenv("LEDGER_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("LEDGER_TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One connection, so the search_path below applies to every query
+	db.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("ledger_test_%d", time.Now().UnixNano())
+	for _, stmt := range []string{
+		"CREATE SCHEMA " + schema,
+		"SET search_path TO " + schema,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		db.Close()
+	})
+	return db
+}
+
+func TestSumLiabilityExposure(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`CREATE TABLE ledger_accounts (
+			id VARCHAR(36) PRIMARY KEY,
+			name VARCHAR(100) NOT NULL UNIQUE,
+			type VARCHAR(20) NOT NULL
+		)`,
+		`CREATE TABLE ledger_entries (
+			id SERIAL PRIMARY KEY,
+			account_id VARCHAR(100) NOT NULL,
+			type VARCHAR(10) NOT NULL,
+			amount DECIMAL(19, 4) NOT NULL,
+			currency VARCHAR(3) NOT NULL,
+			created_at TIMESTAMP NOT NULL
+		)`,
+		// payment_gateway_liability has no account row, as on a fresh install
+		`INSERT INTO ledger_accounts (id, name, type) VALUES
+			('acc_1', 'merchant_payables', 'liability'),
+			('acc_2', 'customer_receivables', 'asset')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	asOf := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	posted := asOf.Add(-time.Hour)
+	for _, entry := range []models.LedgerEntry{
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 150, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeDebit, Amount: 30, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 200, Currency: "EUR", CreatedAt: posted},
+		{AccountID: "merchant_payables", Type: models.EntryTypeCredit, Amount: 25, Currency: "EUR", CreatedAt: posted},
+		// Asset accounts, untyped accounts and entries posted after asOf do not count
+		{AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 150, Currency: "USD", CreatedAt: posted},
+		{AccountID: "suspense", Type: models.EntryTypeCredit, Amount: 5, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 70, Currency: "USD", CreatedAt: asOf.Add(time.Hour)},
+	} {
+		_, err := db.ExecContext(ctx,
+			`INSERT INTO ledger_entries (account_id, type, amount, currency, created_at) VALUES ($1, $2, $3, $4, $5)`,
+			entry.AccountID, entry.Type, entry.Amount, entry.Currency, entry.CreatedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := (&LedgerRepository{db: db}).SumLiabilityExposure(ctx, asOf)
+	if err != nil {
+		t.Fatalf("SumLiabilityExposure() error = %v", err)
+	}
+
+	want := []models.CurrencyExposure{
+		{Currency: "EUR", Amount: 225},
+		{Currency: "USD", Amount: 120},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d exposures, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("exposure[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+)
+
+// MarkEventProcessed records a payment event key, returning false if it was already recorded
+func (r *LedgerRepository) MarkEventProcessed(ctx context.Context, eventKey, eventType string) (bool, error) {
+	query := `
+		INSERT INTO ledger_processed_events (event_key, type, processed_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (event_key) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, eventKey, eventType)
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// UnmarkEvent removes a processed event record so a failed posting can be retried
+func (r *LedgerRepository) UnmarkEvent(ctx context.Context, eventKey string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM ledger_processed_events WHERE event_key = $1`, eventKey)
+	return err
+}
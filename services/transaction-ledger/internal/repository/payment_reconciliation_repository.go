@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"transaction-ledger/internal/models"
+)
+
+// ListSucceededPayments reads the payment gateway's payments table, which shares
+// the ledger's database, for payments that succeeded in the range
+func (r *LedgerRepository) ListSucceededPayments(ctx context.Context, startDate, endDate time.Time) ([]*models.SucceededPayment, error) {
+	query := `
+		SELECT id, amount, currency, completed_at
+		FROM payments
+		WHERE status = 'succeeded' AND completed_at >= $1 AND completed_at < $2
+		ORDER BY completed_at
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	payments := []*models.SucceededPayment{}
+	for rows.Next() {
+		payment := &models.SucceededPayment{}
+		if err := rows.Scan(&payment.ID, &payment.Amount, &payment.Currency, &payment.CompletedAt); err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
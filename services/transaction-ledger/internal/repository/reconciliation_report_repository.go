@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"transaction-ledger/internal/models"
+)
+
+// SaveReconciliationReport stores a reconciliation report so later runs can be compared with it
+func (r *LedgerRepository) SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error {
+	discrepancies, err := json.Marshal(report.Discrepancies)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO reconciliation_reports (
+			id, start_date, end_date, total_transactions, total_debits, total_credits,
+			is_balanced, discrepancies, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	_, err = r.db.ExecContext(ctx, query,
+		report.ID,
+		report.StartDate,
+		report.EndDate,
+		report.TotalTransactions,
+		report.TotalDebits,
+		report.TotalCredits,
+		report.IsBalanced,
+		discrepancies,
+		report.CreatedAt,
+	)
+	return err
+}
+
+// GetReconciliationReport returns a saved reconciliation report, or nil if it does not exist
+func (r *LedgerRepository) GetReconciliationReport(ctx context.Context, id string) (*models.ReconciliationReport, error) {
+	query := `
+		SELECT id, start_date, end_date, total_transactions, total_debits, total_credits,
+			is_balanced, discrepancies, created_at
+		FROM reconciliation_reports
+		WHERE id = $1
+	`
+
+	report := &models.ReconciliationReport{}
+	var discrepancies []byte
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&report.ID,
+		&report.StartDate,
+		&report.EndDate,
+		&report.TotalTransactions,
+		&report.TotalDebits,
+		&report.TotalCredits,
+		&report.IsBalanced,
+		&discrepancies,
+		&report.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(discrepancies, &report.Discrepancies); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+var (
+	ErrAccountNotFound         = errors.New("account not found")
+	ErrAccountExists           = errors.New("account name already exists")
+	ErrAccountCurrencyMismatch = errors.New("entry currency does not match account currency")
+	ErrParentAccountNotFound   = errors.New("parent account not found")
+	ErrParentCurrencyMismatch  = errors.New("account currency does not match its parent's")
+	ErrAccountCycle            = errors.New("account hierarchy contains a cycle")
+)
+
+// CreateAccount registers a ledger account
+func (s *LedgerService) CreateAccount(ctx context.Context, req *models.CreateAccountRequest) (*models.Account, error) {
+	account := &models.Account{
+		ID:              ids.New(),
+		Name:            req.Name,
+		Type:            req.Type,
+		Currency:        strings.ToUpper(req.Currency),
+		Description:     req.Description,
+		ParentAccountID: req.ParentAccountID,
+		CreatedAt:       time.Now(),
+	}
+
+	// Sub-accounts are held in their parent's currency so rollups can sum them
+	if account.ParentAccountID != "" {
+		parent, err := s.repo.GetAccount(ctx, account.ParentAccountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up parent account: %w", err)
+		}
+		if parent == nil {
+			return nil, fmt.Errorf("%w: %s", ErrParentAccountNotFound, account.ParentAccountID)
+		}
+		if parent.Currency != account.Currency {
+			return nil, fmt.Errorf("%w: %s is held in %s, parent %s in %s",
+				ErrParentCurrencyMismatch, req.Name, account.Currency, parent.Name, parent.Currency)
+		}
+	}
+
+	created, err := s.repo.CreateAccount(ctx, account)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create account: %w", err)
+	}
+	if !created {
+		return nil, fmt.Errorf("%w: %s", ErrAccountExists, req.Name)
+	}
+
+	s.logger.Info("ledger account created",
+		zap.String("account_id", account.ID),
+		zap.String("name", account.Name),
+		zap.String("type", string(account.Type)))
+	return account, nil
+}
+
+// GetAccount looks up a ledger account by ID
+func (s *LedgerService) GetAccount(ctx context.Context, id string) (*models.Account, error) {
+	account, err := s.repo.GetAccount(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+// ListAccounts returns ledger accounts, optionally filtered by type
+func (s *LedgerService) ListAccounts(ctx context.Context, accountType models.AccountType) ([]*models.Account, error) {
+	return s.repo.ListAccounts(ctx, accountType)
+}
+
+// GetRollupBalance sums the balance of an account and every account beneath it.
+// A hierarchy that loops back on itself is reported rather than walked forever.
+func (s *LedgerService) GetRollupBalance(ctx context.Context, parentID string) (*models.RollupBalance, error) {
+	parent, err := s.GetAccount(ctx, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk the subtree breadth first, parent first
+	subtree := []*models.Account{parent}
+	visited := map[string]bool{parent.ID: true}
+	for i := 0; i < len(subtree); i++ {
+		children, err := s.repo.ListChildAccounts(ctx, subtree[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list child accounts of %s: %w", subtree[i].ID, err)
+		}
+		for _, child := range children {
+			if visited[child.ID] {
+				return nil, fmt.Errorf("%w: %s is reached twice under %s", ErrAccountCycle, child.ID, parentID)
+			}
+			visited[child.ID] = true
+			subtree = append(subtree, child)
+		}
+	}
+
+	// Entries reference accounts by name
+	names := make([]string, 0, len(subtree))
+	for _, account := range subtree {
+		names = append(names, account.Name)
+	}
+	sums, err := s.repo.SumBalances(ctx, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum balances: %w", err)
+	}
+
+	now := time.Now()
+	rollup := &models.RollupBalance{
+		AccountID: parent.ID,
+		Currency:  parent.Currency,
+		Accounts:  make([]*models.AccountBalance, 0, len(subtree)),
+		UpdatedAt: now,
+	}
+	for _, account := range subtree {
+		rollup.Balance += sums[account.Name]
+		rollup.Accounts = append(rollup.Accounts, &models.AccountBalance{
+			AccountID: account.Name,
+			Balance:   sums[account.Name],
+			Currency:  account.Currency,
+			UpdatedAt: now,
+		})
+	}
+
+	return rollup, nil
+}
+
+// checkAccountCurrencies rejects entries posted in a currency other than their
+// account's. Entries to account names that were never registered are not checked.
+func (s *LedgerService) checkAccountCurrencies(ctx context.Context, entries []models.EntryRequest) error {
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.AccountID)
+	}
+
+	accounts, err := s.repo.GetAccountsByName(ctx, names)
+	if err != nil {
+		return fmt.Errorf("failed to look up entry accounts: %w", err)
+	}
+	currencies := make(map[string]string, len(accounts))
+	for _, account := range accounts {
+		currencies[account.Name] = account.Currency
+	}
+
+	for i, entry := range entries {
+		currency, ok := currencies[entry.AccountID]
+		if !ok || strings.EqualFold(entry.Currency, currency) {
+			continue
+		}
+		return fmt.Errorf("%w: entry %d posts %s to account %s, which is held in %s",
+			ErrAccountCurrencyMismatch, i, strings.ToUpper(entry.Currency), entry.AccountID, currency)
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestCreateAccount(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+
+	account, err := s.CreateAccount(ctx, &models.CreateAccountRequest{
+		Name:     "customer_receivables",
+		Type:     models.AccountTypeAsset,
+		Currency: "usd",
+	})
+	if err != nil {
+		t.Fatalf("CreateAccount() error = %v", err)
+	}
+	if account.ID == "" || account.Currency != "USD" {
+		t.Errorf("account = %+v, want an ID and currency USD", account)
+	}
+
+	got, err := s.GetAccount(ctx, account.ID)
+	if err != nil {
+		t.Fatalf("GetAccount() error = %v", err)
+	}
+	if got.Name != "customer_receivables" {
+		t.Errorf("GetAccount() name = %q, want customer_receivables", got.Name)
+	}
+
+	if _, err := s.GetAccount(ctx, "missing"); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("GetAccount(missing) error = %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func TestCreateAccountRejectsDuplicateName(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+	req := &models.CreateAccountRequest{Name: "payment_gateway_liability", Type: models.AccountTypeLiability, Currency: "USD"}
+
+	if _, err := s.CreateAccount(ctx, req); err != nil {
+		t.Fatalf("first CreateAccount() error = %v", err)
+	}
+	if _, err := s.CreateAccount(ctx, req); !errors.Is(err, ErrAccountExists) {
+		t.Errorf("duplicate CreateAccount() error = %v, want %v", err, ErrAccountExists)
+	}
+}
+
+func TestListAccounts(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+	for _, req := range []*models.CreateAccountRequest{
+		{Name: "payment_gateway_liability", Type: models.AccountTypeLiability, Currency: "USD"},
+		{Name: "customer_receivables", Type: models.AccountTypeAsset, Currency: "USD"},
+		{Name: "processor_fees", Type: models.AccountTypeExpense, Currency: "USD"},
+	} {
+		if _, err := s.CreateAccount(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name        string
+		accountType models.AccountType
+		want        []string
+	}{
+		{name: "All accounts", want: []string{"customer_receivables", "payment_gateway_liability", "processor_fees"}},
+		{name: "Liabilities", accountType: models.AccountTypeLiability, want: []string{"payment_gateway_liability"}},
+		{name: "No matches", accountType: models.AccountTypeRevenue, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accounts, err := s.ListAccounts(ctx, tt.accountType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(accounts) != len(tt.want) {
+				t.Fatalf("ListAccounts() returned %d accounts, want %d", len(accounts), len(tt.want))
+			}
+			for i, account := range accounts {
+				if account.Name != tt.want[i] {
+					t.Errorf("account %d = %q, want %q", i, account.Name, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCreateDoubleEntryChecksAccountCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		wantErr  error
+	}{
+		{"Matching currency", "USD", nil},
+		{"Matching currency in lower case", "usd", nil},
+		{"EUR entry to a USD account", "EUR", ErrAccountCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newMockStore()
+			s := NewLedgerService(store, zap.NewNop())
+			if _, err := s.CreateAccount(ctx, &models.CreateAccountRequest{
+				Name:     "usd_settlement",
+				Type:     models.AccountTypeAsset,
+				Currency: "USD",
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			// merchant_payables is not registered, so only the settlement entry is checked
+			_, err := s.CreateDoubleEntry(ctx, &models.LedgerEntryRequest{
+				PaymentID: "pay_1",
+				Entries: []models.EntryRequest{
+					{AccountID: "usd_settlement", Type: models.EntryTypeDebit, Amount: 100, Currency: tt.currency},
+					{AccountID: "merchant_payables", Type: models.EntryTypeCredit, Amount: 100, Currency: tt.currency},
+				},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateDoubleEntry() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil && (len(store.transactions) != 0 || len(store.entries) != 0) {
+				t.Errorf("stored %d transactions and %d entries, want none", len(store.transactions), len(store.entries))
+			}
+		})
+	}
+}
+
+func TestGetRollupBalanceSumsTwoLevels(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	s := NewLedgerService(store, zap.NewNop())
+
+	create := func(name, parentID string) *models.Account {
+		t.Helper()
+		account, err := s.CreateAccount(ctx, &models.CreateAccountRequest{
+			Name:            name,
+			Type:            models.AccountTypeLiability,
+			Currency:        "USD",
+			ParentAccountID: parentID,
+		})
+		if err != nil {
+			t.Fatalf("CreateAccount(%s) error = %v", name, err)
+		}
+		return account
+	}
+	platform := create("platform", "")
+	merchantA := create("merchant_a", platform.ID)
+	create("merchant_b", platform.ID)
+	create("merchant_a_payouts", merchantA.ID)
+	create("unrelated", "")
+
+	for name, amount := range map[string]float64{
+		"platform":           10,
+		"merchant_a":         100,
+		"merchant_b":         50,
+		"merchant_a_payouts": 25,
+		"unrelated":          1000,
+	} {
+		store.entries = append(store.entries, &models.LedgerEntry{AccountID: name, Type: models.EntryTypeDebit, Amount: amount, Currency: "USD"})
+	}
+
+	rollup, err := s.GetRollupBalance(ctx, platform.ID)
+	if err != nil {
+		t.Fatalf("GetRollupBalance() error = %v", err)
+	}
+	if rollup.Balance != 185 || rollup.Currency != "USD" {
+		t.Errorf("rollup = %v %s, want 185 USD", rollup.Balance, rollup.Currency)
+	}
+	if len(rollup.Accounts) != 4 || rollup.Accounts[0].AccountID != "platform" {
+		t.Errorf("accounts = %d starting with %q, want 4 starting with platform", len(rollup.Accounts), rollup.Accounts[0].AccountID)
+	}
+
+	// A mid-level account rolls up only its own subtree
+	rollup, err = s.GetRollupBalance(ctx, merchantA.ID)
+	if err != nil {
+		t.Fatalf("GetRollupBalance(merchant_a) error = %v", err)
+	}
+	if rollup.Balance != 125 {
+		t.Errorf("merchant_a rollup = %v, want 125", rollup.Balance)
+	}
+}
+
+func TestGetRollupBalanceDetectsCycle(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	store.accounts["acc_a"] = &models.Account{ID: "acc_a", Name: "a", Currency: "USD", ParentAccountID: "acc_b"}
+	store.accounts["acc_b"] = &models.Account{ID: "acc_b", Name: "b", Currency: "USD", ParentAccountID: "acc_a"}
+	s := NewLedgerService(store, zap.NewNop())
+
+	if _, err := s.GetRollupBalance(ctx, "acc_a"); !errors.Is(err, ErrAccountCycle) {
+		t.Errorf("GetRollupBalance() error = %v, want %v", err, ErrAccountCycle)
+	}
+}
+
+func TestCreateAccountValidatesParent(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+	parent, err := s.CreateAccount(ctx, &models.CreateAccountRequest{Name: "platform", Type: models.AccountTypeLiability, Currency: "USD"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.CreateAccount(ctx, &models.CreateAccountRequest{
+		Name: "merchant_a", Type: models.AccountTypeLiability, Currency: "USD", ParentAccountID: "missing",
+	}); !errors.Is(err, ErrParentAccountNotFound) {
+		t.Errorf("CreateAccount() under a missing parent error = %v, want %v", err, ErrParentAccountNotFound)
+	}
+	if _, err := s.CreateAccount(ctx, &models.CreateAccountRequest{
+		Name: "merchant_eu", Type: models.AccountTypeLiability, Currency: "EUR", ParentAccountID: parent.ID,
+	}); !errors.Is(err, ErrParentCurrencyMismatch) {
+		t.Errorf("CreateAccount() in another currency error = %v, want %v", err, ErrParentCurrencyMismatch)
+	}
+}
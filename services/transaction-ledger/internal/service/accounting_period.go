@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+var (
+	ErrPeriodNotFound = errors.New("accounting period not found")
+	ErrPeriodOverlaps = errors.New("accounting period overlaps an existing period")
+	ErrInvalidPeriod  = errors.New("invalid accounting period")
+	ErrPeriodClosed   = errors.New("accounting period is closed")
+)
+
+// CreateAccountingPeriod opens a period covering start_date through end_date inclusive
+func (s *LedgerService) CreateAccountingPeriod(ctx context.Context, req *models.CreateAccountingPeriodRequest) (*models.AccountingPeriod, error) {
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: start_date must be YYYY-MM-DD", ErrInvalidPeriod)
+	}
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: end_date must be YYYY-MM-DD", ErrInvalidPeriod)
+	}
+	if endDate.Before(startDate) {
+		return nil, fmt.Errorf("%w: end_date must not be before start_date", ErrInvalidPeriod)
+	}
+
+	period := &models.AccountingPeriod{
+		ID:        ids.New(),
+		StartDate: startDate,
+		EndDate:   endDate.AddDate(0, 0, 1),
+		Status:    models.AccountingPeriodOpen,
+		CreatedAt: time.Now(),
+	}
+
+	created, err := s.repo.CreateAccountingPeriod(ctx, period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create accounting period: %w", err)
+	}
+	if !created {
+		return nil, fmt.Errorf("%w: %s to %s", ErrPeriodOverlaps, req.StartDate, req.EndDate)
+	}
+
+	return period, nil
+}
+
+// ListAccountingPeriods returns every accounting period, earliest first
+func (s *LedgerService) ListAccountingPeriods(ctx context.Context) ([]*models.AccountingPeriod, error) {
+	return s.repo.ListAccountingPeriods(ctx)
+}
+
+// CloseAccountingPeriod stops any further entries being posted into the period
+func (s *LedgerService) CloseAccountingPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error) {
+	now := time.Now()
+	return s.setAccountingPeriodStatus(ctx, id, models.AccountingPeriodClosed, &now)
+}
+
+// OpenAccountingPeriod reopens a closed period so entries can be posted into it again
+func (s *LedgerService) OpenAccountingPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error) {
+	return s.setAccountingPeriodStatus(ctx, id, models.AccountingPeriodOpen, nil)
+}
+
+func (s *LedgerService) setAccountingPeriodStatus(ctx context.Context, id string, status models.AccountingPeriodStatus, closedAt *time.Time) (*models.AccountingPeriod, error) {
+	period, err := s.repo.GetAccountingPeriod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if period == nil {
+		return nil, ErrPeriodNotFound
+	}
+	if period.Status == status {
+		return period, nil
+	}
+
+	if err := s.repo.SetAccountingPeriodStatus(ctx, id, status, closedAt); err != nil {
+		return nil, fmt.Errorf("failed to update accounting period: %w", err)
+	}
+	period.Status = status
+	period.ClosedAt = closedAt
+
+	s.logger.Info("accounting period status changed",
+		zap.String("period_id", id),
+		zap.String("status", string(status)))
+	return period, nil
+}
+
+// checkPeriodOpen rejects posting entries dated at postedAt into a closed period
+func (s *LedgerService) checkPeriodOpen(ctx context.Context, postedAt time.Time) error {
+	period, err := s.repo.GetClosedPeriodAt(ctx, postedAt)
+	if err != nil {
+		return fmt.Errorf("failed to check accounting period: %w", err)
+	}
+	if period != nil {
+		return fmt.Errorf("%w: %s to %s", ErrPeriodClosed,
+			period.StartDate.Format("2006-01-02"), period.EndDate.AddDate(0, 0, -1).Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func balancedEntryRequest() *models.LedgerEntryRequest {
+	return &models.LedgerEntryRequest{
+		PaymentID: "pay_1",
+		Entries: []models.EntryRequest{
+			{AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 100, Currency: "USD"},
+			{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 100, Currency: "USD"},
+		},
+	}
+}
+
+func TestCreateDoubleEntryRejectsClosedPeriod(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	s := NewLedgerService(store, zap.NewNop())
+
+	today := time.Now().UTC().Format("2006-01-02")
+	period, err := s.CreateAccountingPeriod(ctx, &models.CreateAccountingPeriodRequest{StartDate: today, EndDate: today})
+	if err != nil {
+		t.Fatalf("CreateAccountingPeriod() error = %v", err)
+	}
+	if period.Status != models.AccountingPeriodOpen {
+		t.Errorf("new period status = %q, want open", period.Status)
+	}
+
+	// Open periods accept entries
+	if _, err := s.CreateDoubleEntry(ctx, balancedEntryRequest()); err != nil {
+		t.Fatalf("CreateDoubleEntry() into open period error = %v", err)
+	}
+
+	closed, err := s.CloseAccountingPeriod(ctx, period.ID)
+	if err != nil {
+		t.Fatalf("CloseAccountingPeriod() error = %v", err)
+	}
+	if closed.Status != models.AccountingPeriodClosed || closed.ClosedAt == nil {
+		t.Errorf("closed period = %+v, want closed with a closed_at", closed)
+	}
+
+	if _, err := s.CreateDoubleEntry(ctx, balancedEntryRequest()); !errors.Is(err, ErrPeriodClosed) {
+		t.Fatalf("CreateDoubleEntry() into closed period error = %v, want ErrPeriodClosed", err)
+	}
+	if len(store.transactions) != 1 {
+		t.Errorf("stored %d transactions, want only the one posted while open", len(store.transactions))
+	}
+
+	// Reopening lets entries through again
+	if _, err := s.OpenAccountingPeriod(ctx, period.ID); err != nil {
+		t.Fatalf("OpenAccountingPeriod() error = %v", err)
+	}
+	if _, err := s.CreateDoubleEntry(ctx, balancedEntryRequest()); err != nil {
+		t.Errorf("CreateDoubleEntry() after reopening error = %v", err)
+	}
+}
+
+func TestClosedPeriodOnlyCoversItsDays(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+
+	period, err := s.CreateAccountingPeriod(ctx, &models.CreateAccountingPeriodRequest{StartDate: "2024-01-01", EndDate: "2024-01-31"})
+	if err != nil {
+		t.Fatalf("CreateAccountingPeriod() error = %v", err)
+	}
+	if _, err := s.CloseAccountingPeriod(ctx, period.ID); err != nil {
+		t.Fatalf("CloseAccountingPeriod() error = %v", err)
+	}
+
+	// Today is outside January 2024
+	if _, err := s.CreateDoubleEntry(ctx, balancedEntryRequest()); err != nil {
+		t.Errorf("CreateDoubleEntry() outside the closed period error = %v", err)
+	}
+}
+
+func TestCreateAccountingPeriodValidation(t *testing.T) {
+	ctx := context.Background()
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+
+	if _, err := s.CreateAccountingPeriod(ctx, &models.CreateAccountingPeriodRequest{StartDate: "2024-01-01", EndDate: "2024-01-31"}); err != nil {
+		t.Fatalf("CreateAccountingPeriod() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		want       error
+	}{
+		{"Overlapping", "2024-01-31", "2024-02-29", ErrPeriodOverlaps},
+		{"End before start", "2024-03-31", "2024-03-01", ErrInvalidPeriod},
+		{"Bad date", "March", "2024-03-31", ErrInvalidPeriod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.CreateAccountingPeriod(ctx, &models.CreateAccountingPeriodRequest{StartDate: tt.start, EndDate: tt.end})
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := s.CloseAccountingPeriod(ctx, "missing"); !errors.Is(err, ErrPeriodNotFound) {
+		t.Errorf("CloseAccountingPeriod(missing) error = %v, want ErrPeriodNotFound", err)
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// maxBulkBalanceAccounts caps how many accounts one GetBalances call may ask for
+const maxBulkBalanceAccounts = 100
+
+var ErrTooManyAccounts = errors.New("too many accounts requested")
+
+// balanceDeltas is how much newly posted entries move the balance of each of their
+// accounts, debits positive
+func balanceDeltas(entries []*models.LedgerEntry) map[string]float64 {
+	deltas := make(map[string]float64)
+	for _, entry := range entries {
+		if entry.Type == models.EntryTypeDebit {
+			deltas[entry.AccountID] += entry.Amount
+		} else {
+			deltas[entry.AccountID] -= entry.Amount
+		}
+	}
+	return deltas
+}
+
+// RecomputeBalance rebuilds one account's cached balance from its entries,
+// overwriting whatever was cached. Running it again yields the same balance.
+func (s *LedgerService) RecomputeBalance(ctx context.Context, accountID string) (*models.AccountBalance, error) {
+	balance, err := s.computeBalance(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute balance: %w", err)
+	}
+
+	previous, err := s.repo.GetCachedBalance(ctx, accountID)
+	if err != nil {
+		s.logger.Warn("failed to read cached balance", zap.String("account_id", accountID), zap.Error(err))
+	}
+
+	if err := s.repo.SaveCachedBalance(ctx, balance); err != nil {
+		return nil, fmt.Errorf("failed to save balance: %w", err)
+	}
+
+	if previous != nil && previous.Balance != balance.Balance {
+		s.logger.Warn("cached balance had drifted",
+			zap.String("account_id", accountID),
+			zap.Float64("cached", previous.Balance),
+			zap.Float64("recomputed", balance.Balance))
+	}
+	return balance, nil
+}
+
+// RecomputeAllBalances rebuilds every cached balance from the ledger entries in
+// a single aggregate pass. Running it again yields the same balances.
+func (s *LedgerService) RecomputeAllBalances(ctx context.Context) (int64, error) {
+	rebuilt, err := s.repo.RebuildCachedBalances(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild balances: %w", err)
+	}
+
+	s.logger.Info("account balances rebuilt", zap.Int64("accounts", rebuilt))
+	return rebuilt, nil
+}
+
+// GetBalances returns the current balances of several accounts, in request order,
+// computed from their entries in one query. Duplicate IDs are returned once.
+func (s *LedgerService) GetBalances(ctx context.Context, accountIDs []string) ([]*models.AccountBalance, error) {
+	unique := make([]string, 0, len(accountIDs))
+	seen := make(map[string]bool, len(accountIDs))
+	for _, accountID := range accountIDs {
+		if !seen[accountID] {
+			seen[accountID] = true
+			unique = append(unique, accountID)
+		}
+	}
+	if len(unique) > maxBulkBalanceAccounts {
+		return nil, fmt.Errorf("%w: at most %d", ErrTooManyAccounts, maxBulkBalanceAccounts)
+	}
+
+	sums, err := s.repo.SumBalances(ctx, unique)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum balances: %w", err)
+	}
+
+	now := time.Now()
+	balances := make([]*models.AccountBalance, 0, len(unique))
+	for _, accountID := range unique {
+		balances = append(balances, &models.AccountBalance{
+			AccountID: accountID,
+			Balance:   sums[accountID],
+			Currency:  "USD", // Default
+			UpdatedAt: now,
+		})
+	}
+
+	return balances, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestPostingEntryUpdatesCachedBalance(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	// First read computes the balance from history and caches it
+	balance, err := ledger.GetBalance(ctx, "customer_receivables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 100 {
+		t.Fatalf("balance = %v, want 100", balance.Balance)
+	}
+	if cached := store.balances["customer_receivables"]; cached == nil || cached.Balance != 100 {
+		t.Fatalf("cached balance = %+v, want 100", cached)
+	}
+
+	if err := ledger.RecordPayment(ctx, "pay_2", 40, "USD"); err != nil {
+		t.Fatal(err)
+	}
+
+	if cached := store.balances["customer_receivables"]; cached.Balance != 140 {
+		t.Errorf("cached customer_receivables balance = %v, want 140", cached.Balance)
+	}
+
+	// Served from the cache, not by rescanning entries
+	store.entries = nil
+	balance, err = ledger.GetBalance(ctx, "customer_receivables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 140 {
+		t.Errorf("balance = %v, want 140", balance.Balance)
+	}
+}
+
+func TestFailedPostLeavesCachedBalance(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+	store.balances["customer_receivables"] = &models.AccountBalance{AccountID: "customer_receivables", Balance: 100, Currency: "USD"}
+
+	store.createErr = errors.New("database unavailable")
+	if err := ledger.RecordPayment(ctx, "pay_1", 40, "USD"); err == nil {
+		t.Fatal("expected the post to fail")
+	}
+
+	if got := store.balances["customer_receivables"].Balance; got != 100 {
+		t.Errorf("cached balance = %v, want 100 untouched by the failed post", got)
+	}
+}
+
+func TestRecomputeBalanceCorrectsDrift(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ledger.GetBalance(ctx, "customer_receivables"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Corrupt the cache so reads no longer match the entries
+	store.balances["customer_receivables"].Balance = 999
+
+	// Recomputing twice must land on the same, correct balance
+	for i := 0; i < 2; i++ {
+		balance, err := ledger.RecomputeBalance(ctx, "customer_receivables")
+		if err != nil {
+			t.Fatalf("RecomputeBalance() error = %v", err)
+		}
+		if balance.Balance != 100 {
+			t.Errorf("recomputed balance = %v, want 100", balance.Balance)
+		}
+	}
+
+	balance, err := ledger.GetBalance(ctx, "customer_receivables")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance.Balance != 100 {
+		t.Errorf("balance after recompute = %v, want 100", balance.Balance)
+	}
+}
+
+func TestRecomputeAllBalances(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.RecordRefund(ctx, "pay_1", 30, "USD", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	store.balances["payment_gateway_liability"] = &models.AccountBalance{AccountID: "payment_gateway_liability", Balance: 12345}
+
+	rebuilt, err := ledger.RecomputeAllBalances(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rebuilt != 2 {
+		t.Errorf("rebuilt %d accounts, want 2", rebuilt)
+	}
+	if got := store.balances["payment_gateway_liability"].Balance; got != -70 {
+		t.Errorf("payment_gateway_liability balance = %v, want -70", got)
+	}
+}
+
+func TestGetBalancesMatchesGetBalance(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.RecordPayment(ctx, "pay_2", 40, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.RecordRefund(ctx, "pay_1", 30, "USD", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	accountIDs := []string{"payment_gateway_liability", "customer_receivables", "unused_account", "customer_receivables"}
+	balances, err := ledger.GetBalances(ctx, accountIDs)
+	if err != nil {
+		t.Fatalf("GetBalances() error = %v", err)
+	}
+	if store.sumCalls != 1 {
+		t.Errorf("SumBalances called %d times, want 1", store.sumCalls)
+	}
+	if len(balances) != 3 {
+		t.Fatalf("got %d balances, want 3 (duplicates collapsed)", len(balances))
+	}
+
+	for i, balance := range balances {
+		if balance.AccountID != accountIDs[i] {
+			t.Errorf("balances[%d] = %s, want %s", i, balance.AccountID, accountIDs[i])
+		}
+
+		single, err := ledger.GetBalance(ctx, balance.AccountID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Balance != single.Balance {
+			t.Errorf("%s bulk balance = %v, GetBalance = %v", balance.AccountID, balance.Balance, single.Balance)
+		}
+	}
+}
+
+func TestGetBalancesCapsAccounts(t *testing.T) {
+	ledger := NewLedgerService(newMockStore(), zap.NewNop())
+
+	accountIDs := make([]string, maxBulkBalanceAccounts+1)
+	for i := range accountIDs {
+		accountIDs[i] = fmt.Sprintf("account_%d", i)
+	}
+
+	if _, err := ledger.GetBalances(context.Background(), accountIDs); !errors.Is(err, ErrTooManyAccounts) {
+		t.Errorf("GetBalances() error = %v, want %v", err, ErrTooManyAccounts)
+	}
+}
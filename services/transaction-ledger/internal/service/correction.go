@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+var (
+	ErrEntryNotFound        = errors.New("ledger entry not found")
+	ErrUnbalancedCorrection = errors.New("correction debits must equal credits")
+	ErrInvalidCorrection    = errors.New("invalid correction")
+)
+
+// CorrectEntry posts a balanced compensating transaction for a mis-posted entry and
+// records why, both in one database transaction. The original entry is left
+// untouched so the ledger stays append-only, and all of its corrections together
+// may reverse no more than it posted.
+func (s *LedgerService) CorrectEntry(ctx context.Context, req *models.CorrectionRequest) (*models.LedgerCorrection, error) {
+	original, err := s.repo.GetEntryByID(ctx, req.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entry: %w", err)
+	}
+	if original == nil {
+		return nil, ErrEntryNotFound
+	}
+
+	reversed, err := validateCorrection(original, req.Entries)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.prepareDoubleEntry(ctx, &models.LedgerEntryRequest{
+		Description: fmt.Sprintf("Correction of entry %s: %s", original.ID, req.Reason),
+		Entries:     req.Entries,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	correction := &models.LedgerCorrection{
+		ID:                      ids.New(),
+		OriginalEntryID:         original.ID,
+		OriginalTransactionID:   original.TransactionID,
+		CorrectionTransactionID: transaction.ID,
+		ReversedAmount:          reversed,
+		Reason:                  req.Reason,
+		CreatedAt:               transaction.CreatedAt,
+		Transaction:             transaction,
+	}
+
+	created, err := s.repo.CreateCorrection(ctx, correction, transaction, balanceDeltas(transaction.Entries))
+	if err != nil {
+		return nil, fmt.Errorf("failed to save correction: %w", err)
+	}
+	if !created {
+		return nil, fmt.Errorf("%w: with earlier corrections it would reverse more than the original entry's %.2f",
+			ErrInvalidCorrection, original.Amount)
+	}
+	s.completeTransaction(ctx, transaction)
+
+	s.logger.Info("ledger entry corrected",
+		zap.String("entry_id", original.ID),
+		zap.String("correction_transaction_id", transaction.ID),
+		zap.String("reason", req.Reason))
+
+	return correction, nil
+}
+
+// validateCorrection checks that the compensating entries balance, stay in the
+// original currency and reverse no more than the original entry posted, and
+// returns how much they reverse
+func validateCorrection(original *models.LedgerEntry, entries []models.EntryRequest) (float64, error) {
+	var debits, credits, reversed float64
+	for _, entry := range entries {
+		if entry.Amount <= 0 {
+			return 0, fmt.Errorf("%w: entry amounts must be positive", ErrInvalidCorrection)
+		}
+		if entry.Currency != original.Currency {
+			return 0, fmt.Errorf("%w: entries must be in %s like the original entry", ErrInvalidCorrection, original.Currency)
+		}
+
+		if entry.Type == models.EntryTypeDebit {
+			debits += entry.Amount
+		} else {
+			credits += entry.Amount
+		}
+
+		if entry.AccountID == original.AccountID && entry.Type != original.Type {
+			reversed += entry.Amount
+		}
+	}
+
+	if debits != credits {
+		return 0, fmt.Errorf("%w: debits %.2f, credits %.2f", ErrUnbalancedCorrection, debits, credits)
+	}
+	if reversed == 0 {
+		return 0, fmt.Errorf("%w: no entry reverses the original on account %s", ErrInvalidCorrection, original.AccountID)
+	}
+	if reversed > original.Amount {
+		return 0, fmt.Errorf("%w: reverses %.2f but the original entry is %.2f", ErrInvalidCorrection, reversed, original.Amount)
+	}
+
+	return reversed, nil
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestCorrectEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []models.EntryRequest
+		wantErr error
+	}{
+		{
+			name: "Move entry to the right account",
+			entries: []models.EntryRequest{
+				{AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: 100, Currency: "USD"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 100, Currency: "USD"},
+			},
+		},
+		{
+			name: "Unbalanced",
+			entries: []models.EntryRequest{
+				{AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: 100, Currency: "USD"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 90, Currency: "USD"},
+			},
+			wantErr: ErrUnbalancedCorrection,
+		},
+		{
+			name: "Different currency",
+			entries: []models.EntryRequest{
+				{AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: 100, Currency: "EUR"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 100, Currency: "EUR"},
+			},
+			wantErr: ErrInvalidCorrection,
+		},
+		{
+			name: "Reverses more than was posted",
+			entries: []models.EntryRequest{
+				{AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: 150, Currency: "USD"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 150, Currency: "USD"},
+			},
+			wantErr: ErrInvalidCorrection,
+		},
+		{
+			name: "Does not touch the original account",
+			entries: []models.EntryRequest{
+				{AccountID: "fees", Type: models.EntryTypeCredit, Amount: 100, Currency: "USD"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 100, Currency: "USD"},
+			},
+			wantErr: ErrInvalidCorrection,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := newMockStore()
+			ledger := NewLedgerService(store, zap.NewNop())
+
+			if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+				t.Fatal(err)
+			}
+			var original models.LedgerEntry
+			for _, entry := range store.entries {
+				if entry.AccountID == "customer_receivables" {
+					original = *entry
+				}
+			}
+
+			correction, err := ledger.CorrectEntry(ctx, &models.CorrectionRequest{
+				EntryID: original.ID,
+				Reason:  "posted to the wrong receivables account",
+				Entries: tt.entries,
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CorrectEntry() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				if len(store.transactions) != 1 || len(store.corrections) != 0 {
+					t.Errorf("rejected correction posted %d transactions and %d corrections",
+						len(store.transactions)-1, len(store.corrections))
+				}
+				return
+			}
+
+			if correction.OriginalEntryID != original.ID || correction.OriginalTransactionID != original.TransactionID {
+				t.Errorf("correction references %s/%s, want %s/%s",
+					correction.OriginalTransactionID, correction.OriginalEntryID, original.TransactionID, original.ID)
+			}
+			if _, ok := store.transactions[correction.CorrectionTransactionID]; !ok {
+				t.Error("compensating transaction was not posted")
+			}
+			if len(store.corrections) != 1 {
+				t.Errorf("saved %d corrections, want 1", len(store.corrections))
+			}
+
+			stored, _ := store.GetEntryByID(ctx, original.ID)
+			if stored.AccountID != original.AccountID || stored.Type != original.Type || stored.Amount != original.Amount {
+				t.Errorf("original entry was modified: %+v, want %+v", stored, original)
+			}
+		})
+	}
+}
+
+func TestCorrectEntryCumulativeLimit(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "USD"); err != nil {
+		t.Fatal(err)
+	}
+	var originalID string
+	for _, entry := range store.entries {
+		if entry.AccountID == "customer_receivables" {
+			originalID = entry.ID
+		}
+	}
+
+	correct := func(amount float64) error {
+		_, err := ledger.CorrectEntry(ctx, &models.CorrectionRequest{
+			EntryID: originalID,
+			Reason:  "partly posted to the wrong receivables account",
+			Entries: []models.EntryRequest{
+				{AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: amount, Currency: "USD"},
+				{AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: amount, Currency: "USD"},
+			},
+		})
+		return err
+	}
+
+	if err := correct(60); err != nil {
+		t.Fatalf("first correction: %v", err)
+	}
+	transactions := len(store.transactions)
+	if err := correct(60); !errors.Is(err, ErrInvalidCorrection) {
+		t.Fatalf("second correction error = %v, want %v", err, ErrInvalidCorrection)
+	}
+	if len(store.transactions) != transactions || len(store.corrections) != 1 {
+		t.Errorf("rejected correction posted %d transactions and %d corrections",
+			len(store.transactions)-transactions, len(store.corrections)-1)
+	}
+	if err := correct(40); err != nil {
+		t.Errorf("correcting the remainder: %v", err)
+	}
+}
+
+func TestCorrectEntryNotFound(t *testing.T) {
+	ledger := NewLedgerService(newMockStore(), zap.NewNop())
+
+	_, err := ledger.CorrectEntry(context.Background(), &models.CorrectionRequest{EntryID: "missing", Reason: "typo"})
+	if !errors.Is(err, ErrEntryNotFound) {
+		t.Errorf("CorrectEntry() error = %v, want %v", err, ErrEntryNotFound)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"transaction-ledger/internal/models"
+)
+
+// exportFlushEvery is how many rows are buffered before they are written out
+const exportFlushEvery = 500
+
+var (
+	ErrInvalidExportPeriod     = errors.New("invalid export period")
+	ErrUnsupportedExportFormat = errors.New("unsupported export format")
+)
+
+// entryCSVHeader is the column layout of a CSV entry export
+var entryCSVHeader = []string{
+	"entry_id", "transaction_id", "account_id", "type",
+	"amount", "currency", "description", "created_at",
+}
+
+// entryRowWriter writes one exported entry; flush pushes buffered rows to the output
+type entryRowWriter interface {
+	write(entry *models.LedgerEntry) error
+	flush() error
+}
+
+// ExportEntries writes every entry matching the filter to w as NDJSON or CSV.
+// Entries are streamed from the database and flushed in batches, so the size of
+// the period does not bound memory. flush, if non-nil, is called after each batch.
+func (s *LedgerService) ExportEntries(ctx context.Context, w io.Writer, format string, filter models.EntryExportFilter, flush func()) error {
+	if !filter.EndDate.After(filter.StartDate) {
+		return ErrInvalidExportPeriod
+	}
+
+	var rows entryRowWriter
+	switch format {
+	case models.EntryExportNDJSON:
+		rows = newNDJSONEntryWriter(w)
+	case models.EntryExportCSV:
+		csvRows, err := newCSVEntryWriter(w)
+		if err != nil {
+			return err
+		}
+		rows = csvRows
+	default:
+		return fmt.Errorf("%w: %q, want ndjson or csv", ErrUnsupportedExportFormat, format)
+	}
+
+	flushed := func() error {
+		if err := rows.flush(); err != nil {
+			return err
+		}
+		if flush != nil {
+			flush()
+		}
+		return nil
+	}
+
+	count := 0
+	err := s.repo.StreamEntries(ctx, filter, func(entry *models.LedgerEntry) error {
+		if err := rows.write(entry); err != nil {
+			return err
+		}
+		count++
+		if count%exportFlushEvery == 0 {
+			return flushed()
+		}
+		return nil
+	})
+
+	if flushErr := flushed(); err == nil {
+		err = flushErr
+	}
+	return err
+}
+
+// ndjsonEntryWriter writes one JSON object per line
+type ndjsonEntryWriter struct {
+	encoder *json.Encoder
+}
+
+func newNDJSONEntryWriter(w io.Writer) *ndjsonEntryWriter {
+	return &ndjsonEntryWriter{encoder: json.NewEncoder(w)}
+}
+
+func (n *ndjsonEntryWriter) write(entry *models.LedgerEntry) error {
+	return n.encoder.Encode(entry)
+}
+
+// flush is a no-op; the encoder writes each line through as it is encoded
+func (n *ndjsonEntryWriter) flush() error {
+	return nil
+}
+
+// csvEntryWriter writes a header row followed by one row per entry
+type csvEntryWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVEntryWriter(w io.Writer) (*csvEntryWriter, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(entryCSVHeader); err != nil {
+		return nil, err
+	}
+	return &csvEntryWriter{writer: writer}, nil
+}
+
+func (c *csvEntryWriter) write(entry *models.LedgerEntry) error {
+	return c.writer.Write([]string{
+		entry.ID,
+		entry.TransactionID,
+		entry.AccountID,
+		string(entry.Type),
+		strconv.FormatFloat(entry.Amount, 'f', -1, 64),
+		entry.Currency,
+		entry.Description,
+		entry.CreatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (c *csvEntryWriter) flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
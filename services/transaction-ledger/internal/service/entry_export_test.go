@@ -0,0 +1,133 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// windowWriter records how much output arrives between flushes, discarding it
+// so a large export is not itself held in memory
+type windowWriter struct {
+	lines     int
+	window    int
+	maxWindow int
+	flushes   int
+}
+
+func (w *windowWriter) Write(p []byte) (int, error) {
+	w.lines += bytes.Count(p, []byte("\n"))
+	w.window += len(p)
+	if w.window > w.maxWindow {
+		w.maxWindow = w.window
+	}
+	return len(p), nil
+}
+
+func (w *windowWriter) flush() {
+	w.flushes++
+	w.window = 0
+}
+
+func seedExportEntries(store *mockStore, n int, start time.Time) {
+	store.entries = make([]*models.LedgerEntry, 0, n)
+	for i := 0; i < n; i++ {
+		store.entries = append(store.entries, &models.LedgerEntry{
+			ID:            fmt.Sprintf("e%d", i),
+			TransactionID: fmt.Sprintf("t%d", i/2),
+			AccountID:     "customer_receivables",
+			Type:          models.EntryTypeDebit,
+			Amount:        10.25,
+			Currency:      "USD",
+			Description:   "Customer payment received",
+			CreatedAt:     start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+}
+
+func TestExportEntriesStreamsLargeSet(t *testing.T) {
+	const entries = 100000
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store := newMockStore()
+	seedExportEntries(store, entries, start)
+	s := NewLedgerService(store, zap.NewNop())
+	filter := models.EntryExportFilter{StartDate: start, EndDate: start.AddDate(1, 0, 0)}
+
+	for _, tt := range []struct {
+		format string
+		lines  int
+	}{
+		{format: models.EntryExportNDJSON, lines: entries},
+		{format: models.EntryExportCSV, lines: entries + 1},
+	} {
+		t.Run(tt.format, func(t *testing.T) {
+			w := &windowWriter{}
+			if err := s.ExportEntries(context.Background(), w, tt.format, filter, w.flush); err != nil {
+				t.Fatalf("ExportEntries() error = %v", err)
+			}
+
+			if w.lines != tt.lines {
+				t.Errorf("wrote %d lines, want %d", w.lines, tt.lines)
+			}
+			if want := entries / exportFlushEvery; w.flushes < want {
+				t.Errorf("flushed %d times, want at least %d", w.flushes, want)
+			}
+			// Output arrives batch by batch rather than all at the end
+			if limit := exportFlushEvery * 512; w.maxWindow > limit {
+				t.Errorf("%d bytes written between flushes, want at most %d", w.maxWindow, limit)
+			}
+		})
+	}
+}
+
+func TestExportEntriesNDJSONRows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	seedExportEntries(store, 3, start)
+	s := NewLedgerService(store, zap.NewNop())
+
+	var out bytes.Buffer
+	filter := models.EntryExportFilter{StartDate: start.Add(time.Minute), EndDate: start.AddDate(0, 0, 1)}
+	if err := s.ExportEntries(context.Background(), &out, models.EntryExportNDJSON, filter, nil); err != nil {
+		t.Fatalf("ExportEntries() error = %v", err)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var entry models.LedgerEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		ids = append(ids, entry.ID)
+	}
+	if got := strings.Join(ids, ","); got != "e1,e2" {
+		t.Errorf("exported entries = %s, want e1,e2", got)
+	}
+}
+
+func TestExportEntriesRejectsBadRequests(t *testing.T) {
+	s := NewLedgerService(newMockStore(), zap.NewNop())
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	err := s.ExportEntries(context.Background(), &bytes.Buffer{}, "xml", models.EntryExportFilter{StartDate: start, EndDate: start.AddDate(0, 0, 1)}, nil)
+	if !errors.Is(err, ErrUnsupportedExportFormat) {
+		t.Errorf("xml export error = %v, want ErrUnsupportedExportFormat", err)
+	}
+
+	err = s.ExportEntries(context.Background(), &bytes.Buffer{}, models.EntryExportCSV, models.EntryExportFilter{StartDate: start, EndDate: start}, nil)
+	if !errors.Is(err, ErrInvalidExportPeriod) {
+		t.Errorf("empty period error = %v, want ErrInvalidExportPeriod", err)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/pagination"
+	"transaction-ledger/internal/models"
+)
+
+const (
+	maxEntryTags      = 20
+	maxTagKeyLength   = 64
+	maxTagValueLength = 255
+)
+
+var ErrInvalidTags = errors.New("invalid entry tags")
+
+// PostTaggedEntry posts a double entry and tags each of its entries. Entries are
+// append-only, so tags are only ever written here, as part of posting.
+func (s *LedgerService) PostTaggedEntry(ctx context.Context, req *models.LedgerEntryRequest, tags models.EntryTags) (*models.LedgerTransaction, error) {
+	if err := validateEntryTags(tags); err != nil {
+		return nil, err
+	}
+
+	transaction, err := s.CreateDoubleEntry(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return transaction, nil
+	}
+
+	// The transaction is posted either way; a missing tag only leaves the entry out of tag reports
+	for _, entry := range transaction.Entries {
+		if _, err := s.repo.SetEntryTags(ctx, entry.ID, tags); err != nil {
+			s.logger.Warn("failed to tag ledger entry",
+				zap.String("entry_id", entry.ID),
+				zap.String("transaction_id", transaction.ID),
+				zap.Error(err))
+		}
+	}
+	return transaction, nil
+}
+
+// ListEntriesByTags returns a page of entries carrying every tag in the filter
+func (s *LedgerService) ListEntriesByTags(ctx context.Context, filter models.EntryTagFilter) (pagination.Page[*models.TaggedEntry], error) {
+	var empty pagination.Page[*models.TaggedEntry]
+
+	if err := validateEntryTags(filter.Tags); err != nil {
+		return empty, err
+	}
+
+	entries, err := s.repo.ListEntriesByTags(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+	total, err := s.repo.CountEntriesByTags(ctx, filter)
+	if err != nil {
+		return empty, err
+	}
+
+	return pagination.NewPage(entries, total, filter.Limit, filter.Offset), nil
+}
+
+// TotalsByTag sums the entries matching the filter per value of the key tag and currency
+func (s *LedgerService) TotalsByTag(ctx context.Context, key string, filter models.EntryTagFilter) ([]*models.TagTotal, error) {
+	if key == "" || len(key) > maxTagKeyLength {
+		return nil, fmt.Errorf("%w: group_by must be a tag key of 1-%d characters", ErrInvalidTags, maxTagKeyLength)
+	}
+	if err := validateEntryTags(filter.Tags); err != nil {
+		return nil, err
+	}
+	return s.repo.SumEntriesByTag(ctx, key, filter)
+}
+
+func validateEntryTags(tags models.EntryTags) error {
+	if len(tags) > maxEntryTags {
+		return fmt.Errorf("%w: at most %d tags per entry", ErrInvalidTags, maxEntryTags)
+	}
+	for key, value := range tags {
+		if key == "" || len(key) > maxTagKeyLength {
+			return fmt.Errorf("%w: tag keys must be 1-%d characters", ErrInvalidTags, maxTagKeyLength)
+		}
+		if len(value) > maxTagValueLength {
+			return fmt.Errorf("%w: tag %q value exceeds %d characters", ErrInvalidTags, key, maxTagValueLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func newTaggedEntryService(t *testing.T) *LedgerService {
+	t.Helper()
+
+	store := newMockStore()
+	store.entries = []*models.LedgerEntry{
+		{ID: "e1", AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 100, Currency: "USD"},
+		{ID: "e2", AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 40, Currency: "USD"},
+		{ID: "e3", AccountID: "merchant_receivables", Type: models.EntryTypeCredit, Amount: 25, Currency: "USD"},
+		{ID: "e4", AccountID: "fees", Type: models.EntryTypeCredit, Amount: 3, Currency: "USD"},
+		{ID: "e5", AccountID: "merchant_receivables", Type: models.EntryTypeDebit, Amount: 60, Currency: "EUR"},
+	}
+	store.tags = map[string]models.EntryTags{
+		"e1": {"merchant_id": "m_1", "region": "eu"},
+		"e2": {"merchant_id": "m_2"},
+		"e3": {"merchant_id": "m_1"},
+		"e4": {"merchant_id": "m_1"},
+		"e5": {"merchant_id": "m_1", "region": "eu"},
+	}
+	return NewLedgerService(store, zap.NewNop())
+}
+
+func TestListEntriesByMerchantTag(t *testing.T) {
+	s := newTaggedEntryService(t)
+
+	tests := []struct {
+		name   string
+		filter models.EntryTagFilter
+		want   []string
+	}{
+		{
+			name:   "Merchant across accounts",
+			filter: models.EntryTagFilter{Tags: models.EntryTags{"merchant_id": "m_1"}},
+			want:   []string{"e1", "e3", "e4", "e5"},
+		},
+		{
+			name:   "Merchant on one account",
+			filter: models.EntryTagFilter{AccountID: "merchant_receivables", Tags: models.EntryTags{"merchant_id": "m_1"}},
+			want:   []string{"e1", "e3", "e5"},
+		},
+		{
+			name:   "Every tag must match",
+			filter: models.EntryTagFilter{Tags: models.EntryTags{"merchant_id": "m_1", "region": "eu"}},
+			want:   []string{"e1", "e5"},
+		},
+		{
+			name:   "Unknown merchant",
+			filter: models.EntryTagFilter{Tags: models.EntryTags{"merchant_id": "m_9"}},
+			want:   []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.ListEntriesByTags(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("ListEntriesByTags() error = %v", err)
+			}
+			if len(page.Items) != len(tt.want) || page.Total != len(tt.want) {
+				t.Fatalf("got %d of %d entries, want %d", len(page.Items), page.Total, len(tt.want))
+			}
+			for i, entry := range page.Items {
+				if entry.ID != tt.want[i] {
+					t.Errorf("entry %d = %s, want %s", i, entry.ID, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTotalsByMerchantTag(t *testing.T) {
+	s := newTaggedEntryService(t)
+
+	totals, err := s.TotalsByTag(context.Background(), "merchant_id", models.EntryTagFilter{AccountID: "merchant_receivables"})
+	if err != nil {
+		t.Fatalf("TotalsByTag() error = %v", err)
+	}
+
+	// Amounts in different currencies are never summed together
+	want := []models.TagTotal{
+		{Value: "m_1", Currency: "EUR", Debits: 60, Net: 60, Entries: 1},
+		{Value: "m_1", Currency: "USD", Debits: 100, Credits: 25, Net: 75, Entries: 2},
+		{Value: "m_2", Currency: "USD", Debits: 40, Net: 40, Entries: 1},
+	}
+	if len(totals) != len(want) {
+		t.Fatalf("got %d totals, want %d", len(totals), len(want))
+	}
+	for i, total := range totals {
+		if *total != want[i] {
+			t.Errorf("total %d = %+v, want %+v", i, *total, want[i])
+		}
+	}
+}
+
+func TestPostTaggedEntry(t *testing.T) {
+	store := newMockStore()
+	s := NewLedgerService(store, zap.NewNop())
+	req := &models.LedgerEntryRequest{
+		Description: "Order 42",
+		Entries: []models.EntryRequest{
+			{AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 30, Currency: "USD"},
+			{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 30, Currency: "USD"},
+		},
+	}
+
+	if _, err := s.PostTaggedEntry(context.Background(), req, models.EntryTags{"": "m_1"}); !errors.Is(err, ErrInvalidTags) {
+		t.Fatalf("PostTaggedEntry() with empty key error = %v, want %v", err, ErrInvalidTags)
+	}
+	if len(store.entries) != 0 {
+		t.Fatal("entry with invalid tags was posted")
+	}
+
+	transaction, err := s.PostTaggedEntry(context.Background(), req, models.EntryTags{"merchant_id": "m_1"})
+	if err != nil {
+		t.Fatalf("PostTaggedEntry() error = %v", err)
+	}
+
+	page, err := s.ListEntriesByTags(context.Background(), models.EntryTagFilter{Tags: models.EntryTags{"merchant_id": "m_1"}})
+	if err != nil {
+		t.Fatalf("ListEntriesByTags() error = %v", err)
+	}
+	if page.Total != len(transaction.Entries) {
+		t.Errorf("tagged entries = %d, want %d", page.Total, len(transaction.Entries))
+	}
+
+	if _, err := s.TotalsByTag(context.Background(), "", models.EntryTagFilter{}); !errors.Is(err, ErrInvalidTags) {
+		t.Errorf("TotalsByTag() without group_by error = %v, want %v", err, ErrInvalidTags)
+	}
+}
@@ -7,21 +7,66 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"shared/pkg/ids"
 	"transaction-ledger/internal/models"
-	"transaction-ledger/internal/repository"
 )
 
+// LedgerStore persists ledger transactions; implemented by repository.LedgerRepository
+type LedgerStore interface {
+	CreateTransactionWithBalances(ctx context.Context, transaction *models.LedgerTransaction, entries []*models.LedgerEntry, deltas map[string]float64) error
+	UpdateTransactionStatus(ctx context.Context, transactionID string, status models.TransactionStatus) error
+	GetEntriesByAccount(ctx context.Context, accountID string) ([]*models.LedgerEntry, error)
+	GetEntriesByTransaction(ctx context.Context, transactionID string) ([]*models.LedgerEntry, error)
+	SumLiabilityExposure(ctx context.Context, asOf time.Time) ([]models.CurrencyExposure, error)
+	StreamEntries(ctx context.Context, filter models.EntryExportFilter, fn func(*models.LedgerEntry) error) error
+	GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.LedgerTransaction, error)
+	SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error
+	GetReconciliationReport(ctx context.Context, id string) (*models.ReconciliationReport, error)
+	MarkEventProcessed(ctx context.Context, eventKey, eventType string) (bool, error)
+	UnmarkEvent(ctx context.Context, eventKey string) error
+	GetCachedBalance(ctx context.Context, accountID string) (*models.AccountBalance, error)
+	SaveCachedBalance(ctx context.Context, balance *models.AccountBalance) error
+	FillCachedBalance(ctx context.Context, balance *models.AccountBalance) error
+	RebuildCachedBalances(ctx context.Context) (int64, error)
+	SumBalances(ctx context.Context, accountIDs []string) (map[string]float64, error)
+	GetEntryByID(ctx context.Context, id string) (*models.LedgerEntry, error)
+	SetEntryTags(ctx context.Context, entryID string, tags models.EntryTags) (bool, error)
+	ListEntriesByTags(ctx context.Context, filter models.EntryTagFilter) ([]*models.TaggedEntry, error)
+	CountEntriesByTags(ctx context.Context, filter models.EntryTagFilter) (int, error)
+	SumEntriesByTag(ctx context.Context, key string, filter models.EntryTagFilter) ([]*models.TagTotal, error)
+	CreateCorrection(ctx context.Context, correction *models.LedgerCorrection, transaction *models.LedgerTransaction, deltas map[string]float64) (bool, error)
+	CreateAccount(ctx context.Context, account *models.Account) (bool, error)
+	GetAccount(ctx context.Context, id string) (*models.Account, error)
+	ListAccounts(ctx context.Context, accountType models.AccountType) ([]*models.Account, error)
+	ListChildAccounts(ctx context.Context, parentID string) ([]*models.Account, error)
+	GetAccountsByName(ctx context.Context, names []string) ([]*models.Account, error)
+	CreateAccountingPeriod(ctx context.Context, period *models.AccountingPeriod) (bool, error)
+	GetAccountingPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error)
+	ListAccountingPeriods(ctx context.Context) ([]*models.AccountingPeriod, error)
+	SetAccountingPeriodStatus(ctx context.Context, id string, status models.AccountingPeriodStatus, closedAt *time.Time) error
+	GetClosedPeriodAt(ctx context.Context, at time.Time) (*models.AccountingPeriod, error)
+	SetEntryConversion(ctx context.Context, conversion *models.EntryConversion) (bool, error)
+	GetPaymentConversion(ctx context.Context, paymentID, accountID string) (*models.EntryConversion, string, error)
+}
+
+// ErrInvalidEntryAmount rejects entries that are not strictly positive and finite;
+// direction comes from the entry type, never the amount's sign
+var ErrInvalidEntryAmount = errors.New("entry amounts must be positive and finite")
+
 type LedgerService struct {
-	repo   *repository.LedgerRepository
+	repo   LedgerStore
 	logger *zap.Logger
+
+	reportingCurrency string
+	converter         CurrencyConverter
 }
 
-func NewLedgerService(repo *repository.LedgerRepository, logger *zap.Logger) *LedgerService {
+func NewLedgerService(repo LedgerStore, logger *zap.Logger) *LedgerService {
 	return &LedgerService{
 		repo:   repo,
 		logger: logger,
@@ -30,9 +75,30 @@ func NewLedgerService(repo *repository.LedgerRepository, logger *zap.Logger) *Le
 
 // CreateDoubleEntry creates a double-entry ledger transaction
 func (s *LedgerService) CreateDoubleEntry(ctx context.Context, req *models.LedgerEntryRequest) (*models.LedgerTransaction, error) {
-	// Validate that debits equal credits
+	transaction, err := s.prepareDoubleEntry(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Save to database, moving materialized balances in the same transaction
+	if err := s.repo.CreateTransactionWithBalances(ctx, transaction, transaction.Entries, balanceDeltas(transaction.Entries)); err != nil {
+		return nil, fmt.Errorf("failed to create ledger transaction: %w", err)
+	}
+
+	s.completeTransaction(ctx, transaction)
+	return transaction, nil
+}
+
+// prepareDoubleEntry validates a double-entry request and builds its pending
+// transaction and entries, without saving them
+func (s *LedgerService) prepareDoubleEntry(ctx context.Context, req *models.LedgerEntryRequest) (*models.LedgerTransaction, error) {
+	// Validate that debits equal credits. Binding only guards the HTTP path, so
+	// amounts are re-checked here for programmatic callers.
 	var totalDebits, totalCredits float64
-	for _, entry := range req.Entries {
+	for i, entry := range req.Entries {
+		if entry.Amount <= 0 || math.IsNaN(entry.Amount) || math.IsInf(entry.Amount, 0) {
+			return nil, fmt.Errorf("%w: entry %d has amount %v", ErrInvalidEntryAmount, i, entry.Amount)
+		}
 		if entry.Type == models.EntryTypeDebit {
 			totalDebits += entry.Amount
 		} else {
@@ -44,52 +110,60 @@ func (s *LedgerService) CreateDoubleEntry(ctx context.Context, req *models.Ledge
 		return nil, errors.New("debits must equal credits in double-entry bookkeeping")
 	}
 
+	if err := s.checkAccountCurrencies(ctx, req.Entries); err != nil {
+		return nil, err
+	}
+
+	// Entries are dated when posted, so that date must not fall in a closed period
+	postedAt := time.Now()
+	if err := s.checkPeriodOpen(ctx, postedAt); err != nil {
+		return nil, err
+	}
+
 	// Create transaction
-	txnID := uuid.New().String()
+	txnID := ids.Transaction()
 	transaction := &models.LedgerTransaction{
 		ID:          txnID,
 		Description: req.Description,
 		PaymentID:   req.PaymentID,
 		Status:      models.TxnStatusPending,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+		CreatedAt:   postedAt,
+		UpdatedAt:   postedAt,
 	}
 
 	// Create entries
 	var entries []*models.LedgerEntry
 	for _, entryReq := range req.Entries {
 		entry := &models.LedgerEntry{
-			ID:            uuid.New().String(),
+			ID:            ids.New(),
 			TransactionID: txnID,
 			AccountID:     entryReq.AccountID,
 			Type:          entryReq.Type,
 			Amount:        entryReq.Amount,
 			Currency:      entryReq.Currency,
 			Description:   entryReq.Description,
-			CreatedAt:     time.Now(),
+			CreatedAt:     postedAt,
 		}
 		entries = append(entries, entry)
 	}
 
-	// Save to database (transactional)
-	if err := s.repo.CreateTransaction(ctx, transaction, entries); err != nil {
-		return nil, fmt.Errorf("failed to create ledger transaction: %w", err)
-	}
-
 	transaction.Entries = entries
+	return transaction, nil
+}
+
+// completeTransaction marks a saved transaction completed
+func (s *LedgerService) completeTransaction(ctx context.Context, transaction *models.LedgerTransaction) {
 	transaction.Status = models.TxnStatusCompleted
 	transaction.UpdatedAt = time.Now()
 
 	// Update transaction status
-	if err := s.repo.UpdateTransactionStatus(ctx, txnID, models.TxnStatusCompleted); err != nil {
+	if err := s.repo.UpdateTransactionStatus(ctx, transaction.ID, models.TxnStatusCompleted); err != nil {
 		s.logger.Error("failed to update transaction status", zap.Error(err))
 	}
 
 	s.logger.Info("double-entry transaction created",
-		zap.String("transaction_id", txnID),
-		zap.String("payment_id", req.PaymentID))
-
-	return transaction, nil
+		zap.String("transaction_id", transaction.ID),
+		zap.String("payment_id", transaction.PaymentID))
 }
 
 // RecordPayment records a payment in the ledger with double-entry
@@ -119,12 +193,49 @@ func (s *LedgerService) RecordPayment(ctx context.Context, paymentID string, amo
 		},
 	}
 
-	_, err := s.CreateDoubleEntry(ctx, req)
-	return err
+	// Also post the payment converted into the reporting currency, when one is set
+	reporting, rate, err := s.reportingEntries(ctx, amount, currency)
+	if err != nil {
+		return err
+	}
+	req.Entries = append(req.Entries, reporting...)
+
+	transaction, err := s.CreateDoubleEntry(ctx, req)
+	if err != nil {
+		return err
+	}
+	if len(reporting) > 0 {
+		s.recordConversions(ctx, transaction.Entries, amount, currency, rate)
+	}
+	return nil
 }
 
-// GetBalance calculates the current balance for an account
+// GetBalance returns the materialized balance for an account, recomputing it from
+// the account's entries when no cached balance exists
 func (s *LedgerService) GetBalance(ctx context.Context, accountID string) (*models.AccountBalance, error) {
+	cached, err := s.repo.GetCachedBalance(ctx, accountID)
+	if err != nil {
+		s.logger.Warn("failed to read cached balance", zap.String("account_id", accountID), zap.Error(err))
+	}
+	if cached != nil {
+		return cached, nil
+	}
+
+	balance, err := s.computeBalance(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never overwrite a balance cached meanwhile; it already counts newer entries
+	if err := s.repo.FillCachedBalance(ctx, balance); err != nil {
+		s.logger.Warn("failed to cache balance", zap.String("account_id", accountID), zap.Error(err))
+	}
+
+	return balance, nil
+}
+
+// computeBalance calculates an account's balance by scanning all of its entries
+func (s *LedgerService) computeBalance(ctx context.Context, accountID string) (*models.AccountBalance, error) {
 	entries, err := s.repo.GetEntriesByAccount(ctx, accountID)
 	if err != nil {
 		return nil, err
@@ -147,6 +258,19 @@ func (s *LedgerService) GetBalance(ctx context.Context, accountID string) (*mode
 	return balance, nil
 }
 
+// CurrencyExposure sums the signed balances of all liability accounts per currency as of a point in time
+func (s *LedgerService) CurrencyExposure(ctx context.Context, asOf time.Time) (*models.ExposureReport, error) {
+	exposures, err := s.repo.SumLiabilityExposure(ctx, asOf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum liability exposure: %w", err)
+	}
+
+	return &models.ExposureReport{
+		AsOf:      asOf,
+		Exposures: exposures,
+	}, nil
+}
+
 // Reconcile performs reconciliation for a time period
 func (s *LedgerService) Reconcile(ctx context.Context, startDate, endDate time.Time) (*models.ReconciliationReport, error) {
 	transactions, err := s.repo.GetTransactionsByDateRange(ctx, startDate, endDate)
@@ -155,7 +279,7 @@ func (s *LedgerService) Reconcile(ctx context.Context, startDate, endDate time.T
 	}
 
 	report := &models.ReconciliationReport{
-		ID:               uuid.New().String(),
+		ID:               ids.New(),
 		StartDate:        startDate,
 		EndDate:          endDate,
 		TotalTransactions: len(transactions),
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestCurrencyExposure(t *testing.T) {
+	asOf := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	for _, account := range []*models.Account{
+		{ID: "acc_1", Name: "payment_gateway_liability", Type: models.AccountTypeLiability},
+		{ID: "acc_2", Name: "merchant_payables", Type: models.AccountTypeLiability},
+		{ID: "acc_3", Name: "customer_receivables", Type: models.AccountTypeAsset},
+	} {
+		store.accounts[account.ID] = account
+	}
+
+	posted := asOf.Add(-time.Hour)
+	store.entries = []*models.LedgerEntry{
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 100, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 50, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeDebit, Amount: 30, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 200, Currency: "EUR", CreatedAt: posted},
+		{AccountID: "merchant_payables", Type: models.EntryTypeCredit, Amount: 25, Currency: "EUR", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeDebit, Amount: 10, Currency: "GBP", CreatedAt: posted},
+		// Asset accounts and entries posted after asOf do not count towards exposure
+		{AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 150, Currency: "USD", CreatedAt: posted},
+		{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: 70, Currency: "USD", CreatedAt: asOf.Add(time.Hour)},
+	}
+
+	report, err := NewLedgerService(store, zap.NewNop()).CurrencyExposure(context.Background(), asOf)
+	if err != nil {
+		t.Fatalf("CurrencyExposure() error = %v", err)
+	}
+
+	want := []models.CurrencyExposure{
+		{Currency: "EUR", Amount: 225},
+		{Currency: "GBP", Amount: -10},
+		{Currency: "USD", Amount: 120},
+	}
+
+	got := report.Exposures
+	if len(got) != len(want) {
+		t.Fatalf("got %d exposures, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("exposure[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestCreateDoubleEntryRejectsInvalidAmounts(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+	}{
+		{"Negative", -50},
+		{"Zero", 0},
+		{"NaN", math.NaN()},
+		{"Infinite", math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			s := NewLedgerService(store, zap.NewNop())
+
+			// Both sides carry the same amount, so the entries balance and only
+			// the amount check can reject them
+			_, err := s.CreateDoubleEntry(context.Background(), &models.LedgerEntryRequest{
+				PaymentID: "pay_1",
+				Entries: []models.EntryRequest{
+					{AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: tt.amount, Currency: "USD"},
+					{AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: tt.amount, Currency: "USD"},
+				},
+			})
+			if !errors.Is(err, ErrInvalidEntryAmount) {
+				t.Errorf("error = %v, want ErrInvalidEntryAmount", err)
+			}
+			if len(store.transactions) != 0 || len(store.entries) != 0 {
+				t.Errorf("stored %d transactions and %d entries, want none", len(store.transactions), len(store.entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"transaction-ledger/internal/models"
+)
+
+// mockStore is an in-memory LedgerStore for service tests
+type mockStore struct {
+	transactions map[string]*models.LedgerTransaction
+	entries      []*models.LedgerEntry
+	events       map[string]bool
+	balances     map[string]*models.AccountBalance
+	corrections  []*models.LedgerCorrection
+	accounts     map[string]*models.Account
+	tags         map[string]models.EntryTags
+	periods      map[string]*models.AccountingPeriod
+	conversions  map[string]*models.EntryConversion
+	reports      map[string]*models.ReconciliationReport
+	createErr    error
+	sumCalls     int
+}
+
+func newMockStore() *mockStore {
+	return &mockStore{
+		transactions: make(map[string]*models.LedgerTransaction),
+		events:       make(map[string]bool),
+		balances:     make(map[string]*models.AccountBalance),
+		accounts:     make(map[string]*models.Account),
+		tags:         make(map[string]models.EntryTags),
+		periods:      make(map[string]*models.AccountingPeriod),
+		conversions:  make(map[string]*models.EntryConversion),
+		reports:      make(map[string]*models.ReconciliationReport),
+	}
+}
+
+func (m *mockStore) CreateTransactionWithBalances(ctx context.Context, transaction *models.LedgerTransaction, entries []*models.LedgerEntry, deltas map[string]float64) error {
+	if m.createErr != nil {
+		return m.createErr
+	}
+	m.transactions[transaction.ID] = transaction
+	m.entries = append(m.entries, entries...)
+	for accountID, delta := range deltas {
+		if balance, ok := m.balances[accountID]; ok {
+			balance.Balance += delta
+			balance.UpdatedAt = transaction.CreatedAt
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) UpdateTransactionStatus(ctx context.Context, transactionID string, status models.TransactionStatus) error {
+	if txn, ok := m.transactions[transactionID]; ok {
+		txn.Status = status
+	}
+	return nil
+}
+
+func (m *mockStore) GetEntriesByAccount(ctx context.Context, accountID string) ([]*models.LedgerEntry, error) {
+	var entries []*models.LedgerEntry
+	for _, entry := range m.entries {
+		if entry.AccountID == accountID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockStore) GetEntriesByTransaction(ctx context.Context, transactionID string) ([]*models.LedgerEntry, error) {
+	var entries []*models.LedgerEntry
+	for _, entry := range m.entries {
+		if entry.TransactionID == transactionID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *mockStore) SumLiabilityExposure(ctx context.Context, asOf time.Time) ([]models.CurrencyExposure, error) {
+	liabilities := make(map[string]bool)
+	for _, name := range models.BuiltinLiabilityAccounts {
+		liabilities[name] = true
+	}
+	for _, account := range m.accounts {
+		liabilities[account.Name] = account.Type == models.AccountTypeLiability
+	}
+
+	totals := make(map[string]float64)
+	for _, entry := range m.entries {
+		if !liabilities[entry.AccountID] || entry.CreatedAt.After(asOf) {
+			continue
+		}
+		if entry.Type == models.EntryTypeCredit {
+			totals[entry.Currency] += entry.Amount
+		} else {
+			totals[entry.Currency] -= entry.Amount
+		}
+	}
+
+	exposures := []models.CurrencyExposure{}
+	for currency, amount := range totals {
+		exposures = append(exposures, models.CurrencyExposure{Currency: currency, Amount: amount})
+	}
+	sort.Slice(exposures, func(i, j int) bool { return exposures[i].Currency < exposures[j].Currency })
+	return exposures, nil
+}
+
+func (m *mockStore) StreamEntries(ctx context.Context, filter models.EntryExportFilter, fn func(*models.LedgerEntry) error) error {
+	for _, entry := range m.entries {
+		if entry.CreatedAt.Before(filter.StartDate) || !entry.CreatedAt.Before(filter.EndDate) {
+			continue
+		}
+		if filter.AccountID != "" && entry.AccountID != filter.AccountID {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockStore) GetTransactionsByDateRange(ctx context.Context, startDate, endDate time.Time) ([]*models.LedgerTransaction, error) {
+	var transactions []*models.LedgerTransaction
+	for _, txn := range m.transactions {
+		if !txn.CreatedAt.Before(startDate) && txn.CreatedAt.Before(endDate) {
+			transactions = append(transactions, txn)
+		}
+	}
+	return transactions, nil
+}
+
+func (m *mockStore) SaveReconciliationReport(ctx context.Context, report *models.ReconciliationReport) error {
+	m.reports[report.ID] = report
+	return nil
+}
+
+func (m *mockStore) GetReconciliationReport(ctx context.Context, id string) (*models.ReconciliationReport, error) {
+	return m.reports[id], nil
+}
+
+func (m *mockStore) MarkEventProcessed(ctx context.Context, eventKey, eventType string) (bool, error) {
+	if m.events[eventKey] {
+		return false, nil
+	}
+	m.events[eventKey] = true
+	return true, nil
+}
+
+func (m *mockStore) UnmarkEvent(ctx context.Context, eventKey string) error {
+	delete(m.events, eventKey)
+	return nil
+}
+
+func (m *mockStore) GetCachedBalance(ctx context.Context, accountID string) (*models.AccountBalance, error) {
+	balance, ok := m.balances[accountID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *balance
+	return &copied, nil
+}
+
+func (m *mockStore) SaveCachedBalance(ctx context.Context, balance *models.AccountBalance) error {
+	stored := *balance
+	m.balances[balance.AccountID] = &stored
+	return nil
+}
+
+func (m *mockStore) FillCachedBalance(ctx context.Context, balance *models.AccountBalance) error {
+	if _, ok := m.balances[balance.AccountID]; !ok {
+		stored := *balance
+		m.balances[balance.AccountID] = &stored
+	}
+	return nil
+}
+
+func (m *mockStore) RebuildCachedBalances(ctx context.Context) (int64, error) {
+	m.balances = make(map[string]*models.AccountBalance)
+	for _, entry := range m.entries {
+		balance, ok := m.balances[entry.AccountID]
+		if !ok {
+			balance = &models.AccountBalance{AccountID: entry.AccountID, Currency: "USD"}
+			m.balances[entry.AccountID] = balance
+		}
+		if entry.Type == models.EntryTypeDebit {
+			balance.Balance += entry.Amount
+		} else {
+			balance.Balance -= entry.Amount
+		}
+	}
+	return int64(len(m.balances)), nil
+}
+
+func (m *mockStore) SumBalances(ctx context.Context, accountIDs []string) (map[string]float64, error) {
+	m.sumCalls++
+	requested := make(map[string]bool, len(accountIDs))
+	for _, accountID := range accountIDs {
+		requested[accountID] = true
+	}
+
+	sums := make(map[string]float64)
+	for _, entry := range m.entries {
+		if !requested[entry.AccountID] {
+			continue
+		}
+		if entry.Type == models.EntryTypeDebit {
+			sums[entry.AccountID] += entry.Amount
+		} else {
+			sums[entry.AccountID] -= entry.Amount
+		}
+	}
+	return sums, nil
+}
+
+func (m *mockStore) GetEntryByID(ctx context.Context, id string) (*models.LedgerEntry, error) {
+	for _, entry := range m.entries {
+		if entry.ID == id {
+			copied := *entry
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) SetEntryTags(ctx context.Context, entryID string, tags models.EntryTags) (bool, error) {
+	for _, entry := range m.entries {
+		if entry.ID == entryID {
+			if len(m.tags[entryID]) > 0 {
+				return false, nil
+			}
+			m.tags[entryID] = tags
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockStore) ListEntriesByTags(ctx context.Context, filter models.EntryTagFilter) ([]*models.TaggedEntry, error) {
+	entries := []*models.TaggedEntry{}
+	for _, entry := range m.entries {
+		if filter.AccountID != "" && entry.AccountID != filter.AccountID {
+			continue
+		}
+		if !hasTags(m.tags[entry.ID], filter.Tags) {
+			continue
+		}
+		if !filter.StartDate.IsZero() && entry.CreatedAt.Before(filter.StartDate) {
+			continue
+		}
+		if !filter.EndDate.IsZero() && !entry.CreatedAt.Before(filter.EndDate) {
+			continue
+		}
+		entries = append(entries, &models.TaggedEntry{LedgerEntry: *entry, Tags: m.tags[entry.ID]})
+	}
+	return entries, nil
+}
+
+func (m *mockStore) CountEntriesByTags(ctx context.Context, filter models.EntryTagFilter) (int, error) {
+	entries, err := m.ListEntriesByTags(ctx, filter)
+	return len(entries), err
+}
+
+func (m *mockStore) SumEntriesByTag(ctx context.Context, key string, filter models.EntryTagFilter) ([]*models.TagTotal, error) {
+	entries, _ := m.ListEntriesByTags(ctx, filter)
+	byGroup := make(map[[2]string]*models.TagTotal)
+	totals := []*models.TagTotal{}
+	for _, entry := range entries {
+		group := [2]string{entry.Tags[key], entry.Currency}
+		total, ok := byGroup[group]
+		if !ok {
+			total = &models.TagTotal{Value: group[0], Currency: group[1]}
+			byGroup[group] = total
+			totals = append(totals, total)
+		}
+		if entry.Type == models.EntryTypeDebit {
+			total.Debits += entry.Amount
+		} else {
+			total.Credits += entry.Amount
+		}
+		total.Net = total.Debits - total.Credits
+		total.Entries++
+	}
+	sort.Slice(totals, func(i, j int) bool {
+		if totals[i].Value != totals[j].Value {
+			return totals[i].Value < totals[j].Value
+		}
+		return totals[i].Currency < totals[j].Currency
+	})
+	return totals, nil
+}
+
+// hasTags reports whether tags contains every key and value in want, like JSONB @>
+func hasTags(tags, want models.EntryTags) bool {
+	for key, value := range want {
+		if got, ok := tags[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *mockStore) CreateCorrection(ctx context.Context, correction *models.LedgerCorrection, transaction *models.LedgerTransaction, deltas map[string]float64) (bool, error) {
+	original, _ := m.GetEntryByID(ctx, correction.OriginalEntryID)
+	reversed := correction.ReversedAmount
+	for _, existing := range m.corrections {
+		if existing.OriginalEntryID == correction.OriginalEntryID {
+			reversed += existing.ReversedAmount
+		}
+	}
+	if original == nil || reversed > original.Amount {
+		return false, nil
+	}
+
+	if err := m.CreateTransactionWithBalances(ctx, transaction, transaction.Entries, deltas); err != nil {
+		return false, err
+	}
+	m.corrections = append(m.corrections, correction)
+	return true, nil
+}
+
+func (m *mockStore) CreateAccount(ctx context.Context, account *models.Account) (bool, error) {
+	for _, existing := range m.accounts {
+		if existing.Name == account.Name {
+			return false, nil
+		}
+	}
+	m.accounts[account.ID] = account
+	return true, nil
+}
+
+func (m *mockStore) GetAccount(ctx context.Context, id string) (*models.Account, error) {
+	return m.accounts[id], nil
+}
+
+func (m *mockStore) ListAccounts(ctx context.Context, accountType models.AccountType) ([]*models.Account, error) {
+	accounts := []*models.Account{}
+	for _, account := range m.accounts {
+		if accountType == "" || account.Type == accountType {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
+
+func (m *mockStore) ListChildAccounts(ctx context.Context, parentID string) ([]*models.Account, error) {
+	accounts := []*models.Account{}
+	for _, account := range m.accounts {
+		if account.ParentAccountID == parentID {
+			accounts = append(accounts, account)
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
+
+func (m *mockStore) GetAccountsByName(ctx context.Context, names []string) ([]*models.Account, error) {
+	accounts := []*models.Account{}
+	for _, account := range m.accounts {
+		for _, name := range names {
+			if account.Name == name {
+				accounts = append(accounts, account)
+				break
+			}
+		}
+	}
+	return accounts, nil
+}
+
+func (m *mockStore) CreateAccountingPeriod(ctx context.Context, period *models.AccountingPeriod) (bool, error) {
+	for _, existing := range m.periods {
+		if existing.StartDate.Before(period.EndDate) && existing.EndDate.After(period.StartDate) {
+			return false, nil
+		}
+	}
+	stored := *period
+	m.periods[period.ID] = &stored
+	return true, nil
+}
+
+func (m *mockStore) GetAccountingPeriod(ctx context.Context, id string) (*models.AccountingPeriod, error) {
+	period, ok := m.periods[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *period
+	return &copied, nil
+}
+
+func (m *mockStore) ListAccountingPeriods(ctx context.Context) ([]*models.AccountingPeriod, error) {
+	periods := []*models.AccountingPeriod{}
+	for _, period := range m.periods {
+		copied := *period
+		periods = append(periods, &copied)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].StartDate.Before(periods[j].StartDate) })
+	return periods, nil
+}
+
+func (m *mockStore) SetAccountingPeriodStatus(ctx context.Context, id string, status models.AccountingPeriodStatus, closedAt *time.Time) error {
+	if period, ok := m.periods[id]; ok {
+		period.Status = status
+		period.ClosedAt = closedAt
+	}
+	return nil
+}
+
+func (m *mockStore) GetClosedPeriodAt(ctx context.Context, at time.Time) (*models.AccountingPeriod, error) {
+	for _, period := range m.periods {
+		if period.Status == models.AccountingPeriodClosed && period.Contains(at) {
+			copied := *period
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockStore) SetEntryConversion(ctx context.Context, conversion *models.EntryConversion) (bool, error) {
+	for _, entry := range m.entries {
+		if entry.ID == conversion.EntryID {
+			m.conversions[conversion.EntryID] = conversion
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *mockStore) GetPaymentConversion(ctx context.Context, paymentID, accountID string) (*models.EntryConversion, string, error) {
+	for _, entry := range m.entries {
+		txn, ok := m.transactions[entry.TransactionID]
+		if !ok || txn.PaymentID != paymentID || entry.AccountID != accountID {
+			continue
+		}
+		if conversion, ok := m.conversions[entry.ID]; ok {
+			return conversion, entry.Currency, nil
+		}
+	}
+	return nil, "", nil
+}
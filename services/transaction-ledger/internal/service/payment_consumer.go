@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// PaymentEventsChannel is the Redis channel the payment gateway publishes lifecycle events on
+const PaymentEventsChannel = "payment.events"
+
+// PaymentEventConsumer posts ledger entries for payment lifecycle events
+type PaymentEventConsumer struct {
+	ledger *LedgerService
+	logger *zap.Logger
+}
+
+func NewPaymentEventConsumer(ledger *LedgerService, logger *zap.Logger) *PaymentEventConsumer {
+	return &PaymentEventConsumer{
+		ledger: ledger,
+		logger: logger,
+	}
+}
+
+// Run consumes raw event messages until the channel is closed
+func (c *PaymentEventConsumer) Run(ctx context.Context, messages <-chan string) {
+	for message := range messages {
+		var event models.PaymentEvent
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			c.logger.Error("failed to decode payment event", zap.Error(err))
+			continue
+		}
+
+		posted, err := c.ledger.HandlePaymentEvent(ctx, &event)
+		if err != nil {
+			c.logger.Error("failed to post payment event",
+				zap.String("event_type", event.Type),
+				zap.String("payment_id", event.PaymentID),
+				zap.Error(err))
+			continue
+		}
+		if !posted {
+			c.logger.Debug("skipped payment event",
+				zap.String("event_type", event.Type),
+				zap.String("payment_id", event.PaymentID))
+		}
+	}
+}
+
+// HandlePaymentEvent posts the double entries for a payment event at most once.
+// It returns false when the event is not relevant to the ledger, such as a test
+// mode payment, or was already posted.
+func (s *LedgerService) HandlePaymentEvent(ctx context.Context, event *models.PaymentEvent) (bool, error) {
+	// The ledger's accounts hold real money only
+	if event.Mode != "" && event.Mode != models.PaymentModeLive {
+		return false, nil
+	}
+
+	var post func() error
+	switch event.Type {
+	case models.EventPaymentSucceeded:
+		post = func() error { return s.RecordPayment(ctx, event.PaymentID, event.Amount, event.Currency) }
+	case models.EventPaymentRefunded:
+		post = func() error {
+			return s.RecordRefund(ctx, event.PaymentID, event.Amount, event.Currency, event.RefundReason)
+		}
+	default:
+		return false, nil
+	}
+
+	key := paymentEventKey(event)
+	firstSeen, err := s.repo.MarkEventProcessed(ctx, key, event.Type)
+	if err != nil {
+		return false, fmt.Errorf("failed to record payment event: %w", err)
+	}
+	if !firstSeen {
+		return false, nil
+	}
+
+	if err := post(); err != nil {
+		// Forget the event so a redelivery can post it again
+		if unmarkErr := s.repo.UnmarkEvent(ctx, key); unmarkErr != nil {
+			return false, fmt.Errorf("%v (and failed to release event: %v)", err, unmarkErr)
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// paymentEventKey identifies the posting an event causes. A payment succeeds
+// once, however many times that is announced, while each refund is its own event.
+func paymentEventKey(event *models.PaymentEvent) string {
+	if event.Type == models.EventPaymentSucceeded {
+		return fmt.Sprintf("%s:%s", event.Type, event.PaymentID)
+	}
+	return fmt.Sprintf("%s:%s", event.Type, event.ID)
+}
+
+// RefundReasonTag is the entry tag carrying a refund's reason code, so
+// settlement reports can break refunds down by reason
+const RefundReasonTag = "refund_reason"
+
+// RecordRefund reverses a refunded amount of a payment in the ledger, including
+// its reporting-currency entries, tagging the entries with the refund's reason
+// code when one is given
+func (s *LedgerService) RecordRefund(ctx context.Context, paymentID string, amount float64, currency, reason string) error {
+	req := &models.LedgerEntryRequest{
+		Description: fmt.Sprintf("Refund of payment %s", paymentID),
+		PaymentID:   paymentID,
+		Entries: []models.EntryRequest{
+			{
+				AccountID:   "payment_gateway_liability",
+				Type:        models.EntryTypeDebit,
+				Amount:      amount,
+				Currency:    currency,
+				Description: "Payment gateway liability released",
+			},
+			{
+				AccountID:   "customer_receivables",
+				Type:        models.EntryTypeCredit,
+				Amount:      amount,
+				Currency:    currency,
+				Description: "Customer payment refunded",
+			},
+		},
+	}
+
+	reporting, rate, err := s.reportingReversalEntries(ctx, paymentID, amount, currency)
+	if err != nil {
+		return err
+	}
+	req.Entries = append(req.Entries, reporting...)
+
+	var tags models.EntryTags
+	if reason != "" {
+		tags = models.EntryTags{RefundReasonTag: reason}
+	}
+	transaction, err := s.PostTaggedEntry(ctx, req, tags)
+	if err != nil {
+		return err
+	}
+	if len(reporting) > 0 {
+		s.recordConversions(ctx, transaction.Entries, amount, currency, rate)
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestPaymentEventConsumerPostsSucceededOnce(t *testing.T) {
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+	consumer := NewPaymentEventConsumer(ledger, zap.NewNop())
+
+	event := models.PaymentEvent{
+		ID:        "evt_1",
+		Type:      models.EventPaymentSucceeded,
+		PaymentID: "pay_1",
+		Amount:    100,
+		Currency:  "USD",
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("failed to marshal event: %v", err)
+	}
+
+	// The same payment announced twice, e.g. by confirmation and by the Stripe webhook
+	redelivered := event
+	redelivered.ID = "evt_2"
+	redeliveredData, _ := json.Marshal(redelivered)
+
+	messages := make(chan string, 3)
+	messages <- string(data)
+	messages <- string(data)
+	messages <- string(redeliveredData)
+	close(messages)
+
+	consumer.Run(context.Background(), messages)
+
+	if len(store.transactions) != 1 {
+		t.Fatalf("got %d ledger transactions, want 1", len(store.transactions))
+	}
+	for _, txn := range store.transactions {
+		if txn.PaymentID != "pay_1" {
+			t.Errorf("PaymentID = %q, want pay_1", txn.PaymentID)
+		}
+	}
+
+	if len(store.entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(store.entries))
+	}
+	for _, entry := range store.entries {
+		switch entry.AccountID {
+		case "customer_receivables":
+			if entry.Type != models.EntryTypeDebit {
+				t.Errorf("customer_receivables entry type = %v, want debit", entry.Type)
+			}
+		case "payment_gateway_liability":
+			if entry.Type != models.EntryTypeCredit {
+				t.Errorf("payment_gateway_liability entry type = %v, want credit", entry.Type)
+			}
+		default:
+			t.Errorf("unexpected account %q", entry.AccountID)
+		}
+		if entry.Amount != 100 || entry.Currency != "USD" {
+			t.Errorf("entry amount = %v %s, want 100 USD", entry.Amount, entry.Currency)
+		}
+	}
+}
+
+func TestHandlePaymentEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      models.PaymentEvent
+		createErr  error
+		wantPosted bool
+		wantErr    bool
+	}{
+		{
+			name:       "Succeeded",
+			event:      models.PaymentEvent{ID: "evt_1", Type: models.EventPaymentSucceeded, PaymentID: "pay_1", Amount: 50, Currency: "EUR"},
+			wantPosted: true,
+		},
+		{
+			name:       "Refunded",
+			event:      models.PaymentEvent{ID: "evt_2", Type: models.EventPaymentRefunded, PaymentID: "pay_1", Amount: 20, Currency: "EUR"},
+			wantPosted: true,
+		},
+		{
+			name:       "Live mode",
+			event:      models.PaymentEvent{ID: "evt_5", Type: models.EventPaymentSucceeded, PaymentID: "pay_3", Amount: 50, Currency: "EUR", Mode: "live"},
+			wantPosted: true,
+		},
+		{
+			name:  "Test mode",
+			event: models.PaymentEvent{ID: "evt_6", Type: models.EventPaymentSucceeded, PaymentID: "pay_4", Amount: 50, Currency: "EUR", Mode: "test"},
+		},
+		{
+			name:  "Test mode refund",
+			event: models.PaymentEvent{ID: "evt_7", Type: models.EventPaymentRefunded, PaymentID: "pay_4", Amount: 20, Currency: "EUR", Mode: "test"},
+		},
+		{
+			name:  "Ignored event type",
+			event: models.PaymentEvent{ID: "evt_3", Type: "payment.created", PaymentID: "pay_1", Amount: 50, Currency: "EUR"},
+		},
+		{
+			name:      "Posting fails",
+			event:     models.PaymentEvent{ID: "evt_4", Type: models.EventPaymentSucceeded, PaymentID: "pay_2", Amount: 50, Currency: "EUR"},
+			createErr: errors.New("database unavailable"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.createErr = tt.createErr
+			ledger := NewLedgerService(store, zap.NewNop())
+
+			posted, err := ledger.HandlePaymentEvent(context.Background(), &tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("HandlePaymentEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if posted != tt.wantPosted {
+				t.Errorf("HandlePaymentEvent() posted = %v, want %v", posted, tt.wantPosted)
+			}
+			if tt.wantErr && len(store.events) != 0 {
+				t.Error("failed event was not released for retry")
+			}
+			if !tt.wantPosted && len(store.entries) != 0 {
+				t.Errorf("posted %d entries for a skipped event", len(store.entries))
+			}
+		})
+	}
+}
+
+func TestSettlementReportBreaksDownRefundReasons(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	refunds := []struct {
+		paymentID string
+		amount    float64
+		currency  string
+		reason    string
+	}{
+		{"pay_1", 30, "USD", "duplicate"},
+		{"pay_2", 20, "USD", "duplicate"},
+		{"pay_3", 15, "EUR", "duplicate"},
+		{"pay_4", 50, "USD", "fraudulent"},
+		{"pay_5", 10, "USD", ""},
+	}
+	for _, refund := range refunds {
+		if err := ledger.RecordRefund(ctx, refund.paymentID, refund.amount, refund.currency, refund.reason); err != nil {
+			t.Fatalf("RecordRefund(%s) error = %v", refund.paymentID, err)
+		}
+	}
+
+	start := time.Now().Add(-time.Hour)
+	report, err := NewReconciliationService(store, zap.NewNop()).GenerateSettlementReport(ctx, start, start.Add(2*time.Hour), "stripe")
+	if err != nil {
+		t.Fatalf("GenerateSettlementReport() error = %v", err)
+	}
+
+	// Refunds without a reason are left out and currencies are never summed together
+	want := []RefundReasonTotal{
+		{Reason: "duplicate", Currency: "EUR", Amount: 15, Refunds: 1},
+		{Reason: "duplicate", Currency: "USD", Amount: 50, Refunds: 2},
+		{Reason: "fraudulent", Currency: "USD", Amount: 50, Refunds: 1},
+	}
+	if len(report.RefundReasons) != len(want) {
+		t.Fatalf("got %d refund reasons, want %d: %+v", len(report.RefundReasons), len(want), report.RefundReasons)
+	}
+	for i, total := range report.RefundReasons {
+		if total != want[i] {
+			t.Errorf("refund reason %d = %+v, want %+v", i, total, want[i])
+		}
+	}
+}
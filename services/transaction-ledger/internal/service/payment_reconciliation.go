@@ -0,0 +1,152 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+var ErrPaymentSourceNotConfigured = errors.New("payment source is not configured")
+
+// paymentMatchWindow is how far outside the period a payment and its ledger posting
+// are still matched, so one landing just across a boundary isn't reported missing
+const paymentMatchWindow = time.Hour
+
+// PaymentSource lists the payments the gateway completed; implemented by repository.LedgerRepository
+type PaymentSource interface {
+	ListSucceededPayments(ctx context.Context, startDate, endDate time.Time) ([]*models.SucceededPayment, error)
+}
+
+// SetPaymentSource sets where ReconcilePayments reads succeeded payments from
+func (s *ReconciliationService) SetPaymentSource(payments PaymentSource) {
+	s.payments = payments
+}
+
+// ReconcilePayments reports succeeded payments in the period with no ledger posting,
+// and ledger payment postings in the period with no succeeded payment
+func (s *ReconciliationService) ReconcilePayments(ctx context.Context, startDate, endDate time.Time) (*models.PaymentReconciliationReport, error) {
+	if s.payments == nil {
+		return nil, ErrPaymentSourceNotConfigured
+	}
+
+	report := &models.PaymentReconciliationReport{
+		ID:            ids.New(),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		Discrepancies: []models.PaymentDiscrepancy{},
+		CreatedAt:     time.Now(),
+	}
+
+	windowStart, windowEnd := startDate.Add(-paymentMatchWindow), endDate.Add(paymentMatchWindow)
+
+	payments, err := s.payments.ListSucceededPayments(ctx, windowStart, windowEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list succeeded payments: %w", err)
+	}
+
+	posted, err := s.ledgerPayments(ctx, windowStart, windowEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	succeeded := make(map[string]bool, len(payments))
+	for _, payment := range payments {
+		succeeded[payment.ID] = true
+		if !inPeriod(payment.CompletedAt, startDate, endDate) {
+			continue
+		}
+
+		report.SucceededPayments++
+		if _, ok := posted[payment.ID]; ok {
+			report.Matched++
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, models.PaymentDiscrepancy{
+			PaymentID:   payment.ID,
+			Type:        models.DiscrepancyMissingInLedger,
+			Amount:      payment.Amount,
+			Currency:    payment.Currency,
+			Description: fmt.Sprintf("Succeeded payment of %.2f %s has no ledger entry", payment.Amount, payment.Currency),
+		})
+	}
+
+	for paymentID, txn := range posted {
+		if !inPeriod(txn.CreatedAt, startDate, endDate) {
+			continue
+		}
+
+		report.LedgerPayments++
+		if succeeded[paymentID] {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, models.PaymentDiscrepancy{
+			PaymentID:   paymentID,
+			Type:        models.DiscrepancyNoSucceededPayment,
+			Amount:      txn.Amount,
+			Currency:    txn.Currency,
+			Description: fmt.Sprintf("Ledger posting of %.2f %s has no succeeded payment", txn.Amount, txn.Currency),
+		})
+	}
+
+	sort.Slice(report.Discrepancies, func(i, j int) bool {
+		return report.Discrepancies[i].PaymentID < report.Discrepancies[j].PaymentID
+	})
+	report.IsReconciled = len(report.Discrepancies) == 0
+
+	s.logger.Info("payment reconciliation complete",
+		zap.Int("succeeded_payments", report.SucceededPayments),
+		zap.Int("ledger_payments", report.LedgerPayments),
+		zap.Int("discrepancies", len(report.Discrepancies)))
+
+	return report, nil
+}
+
+// ledgerPosting is the first ledger transaction that recorded a payment
+type ledgerPosting struct {
+	Amount    float64
+	Currency  string
+	CreatedAt time.Time
+}
+
+// ledgerPayments returns the payment postings in the range, keyed by payment ID.
+// Only transactions debiting customer receivables count; refunds credit them.
+func (s *ReconciliationService) ledgerPayments(ctx context.Context, startDate, endDate time.Time) (map[string]*ledgerPosting, error) {
+	transactions, err := s.repo.GetTransactionsByDateRange(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	posted := make(map[string]*ledgerPosting)
+	for _, txn := range transactions {
+		if txn.PaymentID == "" {
+			continue
+		}
+		entries, err := s.repo.GetEntriesByTransaction(ctx, txn.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entries for transaction %s: %w", txn.ID, err)
+		}
+
+		for _, entry := range entries {
+			if entry.AccountID != "customer_receivables" || entry.Type != models.EntryTypeDebit {
+				continue
+			}
+			if existing, ok := posted[txn.PaymentID]; ok && !txn.CreatedAt.Before(existing.CreatedAt) {
+				continue
+			}
+			posted[txn.PaymentID] = &ledgerPosting{Amount: entry.Amount, Currency: entry.Currency, CreatedAt: txn.CreatedAt}
+		}
+	}
+
+	return posted, nil
+}
+
+func inPeriod(t, startDate, endDate time.Time) bool {
+	return !t.Before(startDate) && t.Before(endDate)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// stubPaymentSource serves a fixed list of succeeded payments
+type stubPaymentSource []*models.SucceededPayment
+
+func (p stubPaymentSource) ListSucceededPayments(ctx context.Context, startDate, endDate time.Time) ([]*models.SucceededPayment, error) {
+	var payments []*models.SucceededPayment
+	for _, payment := range p {
+		if inPeriod(payment.CompletedAt, startDate, endDate) {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+func TestReconcilePayments(t *testing.T) {
+	store := newMockStore()
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", day.Add(9*time.Hour))
+	addLedgerTransaction(store, "ltx_3", "pay_3", 30, "USD", day.Add(12*time.Hour))
+	// Posted just after midnight for a payment that completed just before
+	addLedgerTransaction(store, "ltx_4", "pay_4", 20, "USD", day.AddDate(0, 0, 1).Add(10*time.Minute))
+
+	// A refund credits receivables and is not a payment posting
+	store.transactions["ltx_5"] = &models.LedgerTransaction{ID: "ltx_5", PaymentID: "pay_1", CreatedAt: day.Add(15 * time.Hour)}
+	store.entries = append(store.entries,
+		&models.LedgerEntry{TransactionID: "ltx_5", AccountID: "customer_receivables", Type: models.EntryTypeCredit, Amount: 10, Currency: "USD"},
+		&models.LedgerEntry{TransactionID: "ltx_5", AccountID: "payment_gateway_liability", Type: models.EntryTypeDebit, Amount: 10, Currency: "USD"},
+	)
+
+	s := NewReconciliationService(store, zap.NewNop())
+	s.SetPaymentSource(stubPaymentSource{
+		{ID: "pay_1", Amount: 100, Currency: "USD", CompletedAt: day.Add(9 * time.Hour)},
+		// RecordPayment was never called for this one
+		{ID: "pay_2", Amount: 55, Currency: "USD", CompletedAt: day.Add(10 * time.Hour)},
+		{ID: "pay_4", Amount: 20, Currency: "USD", CompletedAt: day.Add(23*time.Hour + 55*time.Minute)},
+	})
+
+	report, err := s.ReconcilePayments(context.Background(), day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("ReconcilePayments() error = %v", err)
+	}
+
+	if report.SucceededPayments != 3 || report.LedgerPayments != 2 || report.Matched != 2 {
+		t.Errorf("succeeded = %d, ledger = %d, matched = %d, want 3, 2, 2",
+			report.SucceededPayments, report.LedgerPayments, report.Matched)
+	}
+	if report.IsReconciled {
+		t.Error("report should not be reconciled")
+	}
+
+	want := []models.PaymentDiscrepancy{
+		{PaymentID: "pay_2", Type: models.DiscrepancyMissingInLedger, Amount: 55, Currency: "USD"},
+		{PaymentID: "pay_3", Type: models.DiscrepancyNoSucceededPayment, Amount: 30, Currency: "USD"},
+	}
+	if len(report.Discrepancies) != len(want) {
+		t.Fatalf("got %d discrepancies, want %d: %+v", len(report.Discrepancies), len(want), report.Discrepancies)
+	}
+	for i, got := range report.Discrepancies {
+		got.Description = ""
+		if got != want[i] {
+			t.Errorf("discrepancy[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestReconcilePaymentsRequiresPaymentSource(t *testing.T) {
+	s := NewReconciliationService(newMockStore(), zap.NewNop())
+
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	if _, err := s.ReconcilePayments(context.Background(), day, day.AddDate(0, 0, 1)); !errors.Is(err, ErrPaymentSourceNotConfigured) {
+		t.Errorf("ReconcilePayments() error = %v, want %v", err, ErrPaymentSourceNotConfigured)
+	}
+}
@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+var (
+	ErrUnsupportedFileFormat = errors.New("unsupported processor file format")
+	ErrInvalidProcessorFile  = errors.New("invalid processor file")
+)
+
+// processorColumns names the CSV columns a settlement file format uses
+type processorColumns struct {
+	PaymentID string
+	Amount    string
+	Currency  string
+	SettledAt string
+}
+
+// processorFileFormats maps a format name to its columns. Stripe's itemized
+// reconciliation report carries our payment ID as payment metadata.
+var processorFileFormats = map[string]processorColumns{
+	"stripe": {
+		PaymentID: "payment_metadata[payment_id]",
+		Amount:    "gross",
+		Currency:  "currency",
+		SettledAt: "created_utc",
+	},
+	"generic": {
+		PaymentID: "payment_id",
+		Amount:    "amount",
+		Currency:  "currency",
+		SettledAt: "settled_at",
+	},
+}
+
+var settledAtLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02"}
+
+// ReconcileAgainstProcessorFile compares the ledger's payments with a processor's
+// settlement file, covering every whole day the file spans
+func (s *ReconciliationService) ReconcileAgainstProcessorFile(ctx context.Context, reader io.Reader, format string) (*models.ProcessorReconciliationReport, error) {
+	records, err := parseProcessorFile(reader, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.ProcessorReconciliationReport{
+		ID:            ids.New(),
+		Format:        format,
+		FileRecords:   len(records),
+		Discrepancies: []models.ProcessorDiscrepancy{},
+		IsReconciled:  true,
+		CreatedAt:     time.Now(),
+	}
+	if len(records) == 0 {
+		return report, nil
+	}
+
+	report.StartDate, report.EndDate = settlementPeriod(records)
+
+	transactions, err := s.repo.GetTransactionsByDateRange(ctx, report.StartDate, report.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transactions: %w", err)
+	}
+
+	ledger := make(map[string]*models.ProcessorRecord)
+	for _, txn := range transactions {
+		if txn.PaymentID == "" {
+			continue
+		}
+		entries, err := s.repo.GetEntriesByTransaction(ctx, txn.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entries for transaction %s: %w", txn.ID, err)
+		}
+		addLedgerPayment(ledger, txn.PaymentID, entries)
+	}
+
+	report.Matched, report.Discrepancies = s.compareSettlements(ledger, records)
+	report.IsReconciled = len(report.Discrepancies) == 0
+
+	s.logger.Info("processor file reconciliation complete",
+		zap.String("format", format),
+		zap.Int("file_records", report.FileRecords),
+		zap.Int("matched", report.Matched),
+		zap.Int("discrepancies", len(report.Discrepancies)))
+
+	return report, nil
+}
+
+// parseProcessorFile reads settled transactions from a CSV with a header row,
+// summing rows that share a payment ID
+func parseProcessorFile(reader io.Reader, format string) (map[string]*models.ProcessorRecord, error) {
+	columns, ok := processorFileFormats[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFileFormat, format)
+	}
+
+	r := csv.NewReader(reader)
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %v", ErrInvalidProcessorFile, err)
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.TrimSpace(name)] = i
+	}
+	for _, name := range []string{columns.PaymentID, columns.Amount, columns.Currency, columns.SettledAt} {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", ErrInvalidProcessorFile, name)
+		}
+	}
+
+	records := make(map[string]*models.ProcessorRecord)
+	for line := 2; ; line++ {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidProcessorFile, line, err)
+		}
+
+		paymentID := strings.TrimSpace(row[index[columns.PaymentID]])
+		if paymentID == "" {
+			// Fees, payouts and adjustments are not tied to a payment
+			continue
+		}
+
+		amount, err := strconv.ParseFloat(strings.TrimSpace(row[index[columns.Amount]]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: invalid amount: %v", ErrInvalidProcessorFile, line, err)
+		}
+
+		settledAt, err := parseSettledAt(row[index[columns.SettledAt]])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidProcessorFile, line, err)
+		}
+
+		currency := strings.ToUpper(strings.TrimSpace(row[index[columns.Currency]]))
+		if record, ok := records[paymentID]; ok {
+			record.Amount += amount
+			continue
+		}
+		records[paymentID] = &models.ProcessorRecord{
+			PaymentID: paymentID,
+			Amount:    amount,
+			Currency:  currency,
+			SettledAt: settledAt,
+		}
+	}
+
+	return records, nil
+}
+
+func parseSettledAt(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range settledAtLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid settlement time %q", value)
+}
+
+// settlementPeriod returns the whole UTC days spanned by the file's records
+func settlementPeriod(records map[string]*models.ProcessorRecord) (time.Time, time.Time) {
+	var first, last time.Time
+	for _, record := range records {
+		if first.IsZero() || record.SettledAt.Before(first) {
+			first = record.SettledAt
+		}
+		if record.SettledAt.After(last) {
+			last = record.SettledAt
+		}
+	}
+
+	first = first.UTC()
+	last = last.UTC()
+	start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+	return start, end
+}
+
+// addLedgerPayment nets a payment's customer receivables: payments debit them and refunds credit them
+func addLedgerPayment(ledger map[string]*models.ProcessorRecord, paymentID string, entries []*models.LedgerEntry) {
+	for _, entry := range entries {
+		if entry.AccountID != "customer_receivables" {
+			continue
+		}
+
+		record, ok := ledger[paymentID]
+		if !ok {
+			record = &models.ProcessorRecord{PaymentID: paymentID, Currency: entry.Currency}
+			ledger[paymentID] = record
+		}
+
+		if entry.Type == models.EntryTypeDebit {
+			record.Amount += entry.Amount
+		} else {
+			record.Amount -= entry.Amount
+		}
+	}
+}
+
+// compareSettlements matches ledger payments to file records by payment ID
+func (s *ReconciliationService) compareSettlements(ledger, file map[string]*models.ProcessorRecord) (int, []models.ProcessorDiscrepancy) {
+	matched := 0
+	discrepancies := []models.ProcessorDiscrepancy{}
+
+	for paymentID, record := range file {
+		posted, ok := ledger[paymentID]
+		if !ok {
+			discrepancies = append(discrepancies, models.ProcessorDiscrepancy{
+				PaymentID:   paymentID,
+				Type:        models.DiscrepancyMissingInLedger,
+				FileAmount:  record.Amount,
+				Currency:    record.Currency,
+				Description: fmt.Sprintf("Settled %.2f %s has no ledger entry", record.Amount, record.Currency),
+			})
+			continue
+		}
+
+		if posted.Currency != record.Currency || !s.isBalanced(posted.Amount, record.Amount, record.Currency) {
+			discrepancies = append(discrepancies, models.ProcessorDiscrepancy{
+				PaymentID:    paymentID,
+				Type:         models.DiscrepancyAmountMismatch,
+				LedgerAmount: posted.Amount,
+				FileAmount:   record.Amount,
+				Currency:     record.Currency,
+				Description: fmt.Sprintf("Ledger %.2f %s != settled %.2f %s",
+					posted.Amount, posted.Currency, record.Amount, record.Currency),
+			})
+			continue
+		}
+
+		matched++
+	}
+
+	for paymentID, posted := range ledger {
+		if _, ok := file[paymentID]; ok {
+			continue
+		}
+		discrepancies = append(discrepancies, models.ProcessorDiscrepancy{
+			PaymentID:    paymentID,
+			Type:         models.DiscrepancyMissingInFile,
+			LedgerAmount: posted.Amount,
+			Currency:     posted.Currency,
+			Description:  fmt.Sprintf("Ledger %.2f %s was not settled by the processor", posted.Amount, posted.Currency),
+		})
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		return discrepancies[i].PaymentID < discrepancies[j].PaymentID
+	})
+
+	return matched, discrepancies
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+const sampleStripeFile = `balance_transaction_id,created_utc,gross,fee,net,currency,payment_metadata[payment_id]
+txn_1,2024-03-10 09:15:00,100.00,3.20,96.80,usd,pay_1
+txn_2,2024-03-10 11:30:00,55.00,1.90,53.10,usd,pay_2
+txn_3,2024-03-10 14:00:00,-20.00,0.00,-20.00,usd,
+txn_4,2024-03-10 16:45:00,75.00,2.48,72.52,eur,pay_4
+`
+
+func addLedgerTransaction(store *mockStore, id, paymentID string, amount float64, currency string, at time.Time) {
+	store.transactions[id] = &models.LedgerTransaction{ID: id, PaymentID: paymentID, CreatedAt: at}
+	store.entries = append(store.entries,
+		&models.LedgerEntry{TransactionID: id, AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: amount, Currency: currency, CreatedAt: at},
+		&models.LedgerEntry{TransactionID: id, AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: amount, Currency: currency, CreatedAt: at},
+	)
+}
+
+func TestReconcileAgainstProcessorFile(t *testing.T) {
+	store := newMockStore()
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", day.Add(9*time.Hour))
+	addLedgerTransaction(store, "ltx_2", "pay_2", 50, "USD", day.Add(11*time.Hour))
+	addLedgerTransaction(store, "ltx_3", "pay_3", 30, "USD", day.Add(20*time.Hour))
+	// Outside the file's settlement day
+	addLedgerTransaction(store, "ltx_4", "pay_5", 10, "USD", day.AddDate(0, 0, 1).Add(time.Hour))
+
+	s := NewReconciliationService(store, zap.NewNop())
+	report, err := s.ReconcileAgainstProcessorFile(context.Background(), strings.NewReader(sampleStripeFile), "stripe")
+	if err != nil {
+		t.Fatalf("ReconcileAgainstProcessorFile() error = %v", err)
+	}
+
+	if report.FileRecords != 3 {
+		t.Errorf("FileRecords = %d, want 3", report.FileRecords)
+	}
+	if report.Matched != 1 {
+		t.Errorf("Matched = %d, want 1", report.Matched)
+	}
+	if report.IsReconciled {
+		t.Error("report should not be reconciled")
+	}
+
+	want := []models.ProcessorDiscrepancy{
+		{PaymentID: "pay_2", Type: models.DiscrepancyAmountMismatch, LedgerAmount: 50, FileAmount: 55, Currency: "USD"},
+		{PaymentID: "pay_3", Type: models.DiscrepancyMissingInFile, LedgerAmount: 30, Currency: "USD"},
+		{PaymentID: "pay_4", Type: models.DiscrepancyMissingInLedger, FileAmount: 75, Currency: "EUR"},
+	}
+	if len(report.Discrepancies) != len(want) {
+		t.Fatalf("got %d discrepancies, want %d: %+v", len(report.Discrepancies), len(want), report.Discrepancies)
+	}
+	for i, w := range want {
+		got := report.Discrepancies[i]
+		if got.PaymentID != w.PaymentID || got.Type != w.Type || got.LedgerAmount != w.LedgerAmount ||
+			got.FileAmount != w.FileAmount || got.Currency != w.Currency {
+			t.Errorf("discrepancy[%d] = %+v, want %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseProcessorFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		format  string
+		wantErr error
+	}{
+		{
+			name:    "Unknown format",
+			file:    sampleStripeFile,
+			format:  "paypal",
+			wantErr: ErrUnsupportedFileFormat,
+		},
+		{
+			name:    "Missing column",
+			file:    "payment_id,amount,currency\npay_1,10.00,USD\n",
+			format:  "generic",
+			wantErr: ErrInvalidProcessorFile,
+		},
+		{
+			name:    "Invalid amount",
+			file:    "payment_id,amount,currency,settled_at\npay_1,ten,USD,2024-03-10\n",
+			format:  "generic",
+			wantErr: ErrInvalidProcessorFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseProcessorFile(strings.NewReader(tt.file), tt.format)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("parseProcessorFile() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
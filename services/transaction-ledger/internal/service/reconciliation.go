@@ -6,29 +6,55 @@ package service
 import (
 	"context"
 	"fmt"
+	"math"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 
+	"shared/pkg/ids"
+	"shared/pkg/money"
 	"transaction-ledger/internal/models"
-	"transaction-ledger/internal/repository"
 )
 
 // ReconciliationService handles financial reconciliation
 type ReconciliationService struct {
-	repo   *repository.LedgerRepository
-	logger *zap.Logger
+	repo     LedgerStore
+	payments PaymentSource
+	logger   *zap.Logger
+
+	// roundingTolerance is how far debits may differ from credits and still
+	// balance; zero means half of the currency's minor unit
+	roundingTolerance float64
+
+	// notifiers are told when a period reconciliation does not balance
+	notifiers []ReconciliationNotifier
+	// notifying tracks notifications still being sent
+	notifying sync.WaitGroup
 }
 
 // NewReconciliationService creates a new reconciliation service
-func NewReconciliationService(repo *repository.LedgerRepository, logger *zap.Logger) *ReconciliationService {
+func NewReconciliationService(repo LedgerStore, logger *zap.Logger) *ReconciliationService {
 	return &ReconciliationService{
 		repo:   repo,
 		logger: logger,
 	}
 }
 
+// SetRoundingTolerance lets balance checks accept debits and credits that differ
+// by at most tolerance rather than half a minor unit. Any nonzero tolerance is
+// logged so it stays visible.
+func (s *ReconciliationService) SetRoundingTolerance(tolerance float64) error {
+	if tolerance < 0 || math.IsNaN(tolerance) || math.IsInf(tolerance, 0) {
+		return fmt.Errorf("rounding tolerance must be a finite, non-negative amount, got %v", tolerance)
+	}
+	s.roundingTolerance = tolerance
+	if tolerance > 0 {
+		s.logger.Warn("reconciliation accepts rounding differences", zap.Float64("rounding_tolerance", tolerance))
+	}
+	return nil
+}
+
 // ReconcileDaily performs daily reconciliation
 func (s *ReconciliationService) ReconcileDaily(ctx context.Context, date time.Time) (*models.ReconciliationReport, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
@@ -44,7 +70,7 @@ func (s *ReconciliationService) ReconcilePeriod(ctx context.Context, startDate,
 		zap.Time("end_date", endDate))
 
 	report := &models.ReconciliationReport{
-		ID:           uuid.New().String(),
+		ID:           ids.New(),
 		StartDate:    startDate,
 		EndDate:      endDate,
 		CreatedAt:    time.Now(),
@@ -85,7 +111,7 @@ func (s *ReconciliationService) ReconcilePeriod(ctx context.Context, startDate,
 		}
 
 		// Check if transaction is balanced
-		if !isBalanced(txnDebits, txnCredits) {
+		if !s.isBalanced(txnDebits, txnCredits, entriesCurrency(entries)) {
 			discrepancy := fmt.Sprintf("Transaction %s: debits=%.2f, credits=%.2f (diff=%.2f)",
 				txn.ID, txnDebits, txnCredits, txnDebits-txnCredits)
 			report.Discrepancies = append(report.Discrepancies, discrepancy)
@@ -97,8 +123,9 @@ func (s *ReconciliationService) ReconcilePeriod(ctx context.Context, startDate,
 	report.TotalDebits = totalDebits
 	report.TotalCredits = totalCredits
 
-	// Overall balance check
-	if !isBalanced(totalDebits, totalCredits) {
+	// Overall balance check, in the default two-decimal minor unit since the
+	// period's totals span currencies
+	if !s.isBalanced(totalDebits, totalCredits, "") {
 		report.IsBalanced = false
 		report.Discrepancies = append(report.Discrepancies,
 			fmt.Sprintf("Overall imbalance: debits=%.2f, credits=%.2f (diff=%.2f)",
@@ -121,6 +148,7 @@ func (s *ReconciliationService) ReconcilePeriod(ctx context.Context, startDate,
 			zap.Int("transactions", report.TotalTransactions),
 			zap.Int("discrepancies", len(report.Discrepancies)),
 			zap.Strings("unbalanced_txns", unbalancedTransactions))
+		s.notifyUnbalanced(report)
 	}
 
 	return report, nil
@@ -193,7 +221,7 @@ func (s *ReconciliationService) FindDiscrepancies(ctx context.Context) ([]models
 			}
 		}
 
-		if !isBalanced(debits, credits) {
+		if !s.isBalanced(debits, credits, entriesCurrency(entries)) {
 			discrepancies = append(discrepancies, models.Discrepancy{
 				TransactionID: txn.ID,
 				Type:          "unbalanced_transaction",
@@ -224,9 +252,9 @@ func (s *ReconciliationService) AutoCorrectDiscrepancies(ctx context.Context, di
 }
 
 // GenerateSettlementReport generates a settlement report for payment processors
-func (s *ReconciliationService) GenerateSettlementReport(ctx context.Context, startDate, endDate time.Time, processor string) (*models.SettlementReport, error) {
-	report := &models.SettlementReport{
-		ID:              uuid.New().String(),
+func (s *ReconciliationService) GenerateSettlementReport(ctx context.Context, startDate, endDate time.Time, processor string) (*SettlementReport, error) {
+	report := &SettlementReport{
+		ID:              ids.New(),
 		Processor:       processor,
 		StartDate:       startDate,
 		EndDate:         endDate,
@@ -239,16 +267,67 @@ func (s *ReconciliationService) GenerateSettlementReport(ctx context.Context, st
 	report.TotalAmount = 0.0
 	report.TotalFees = 0.0
 
+	refunds, err := s.refundReasonTotals(ctx, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total refunds by reason: %w", err)
+	}
+	report.RefundReasons = refunds
+
 	return report, nil
 }
 
+// refundReasonTotals sums the refunds posted in [startDate, endDate) per reason
+// code and currency. Refunds reverse customer receivables, so each is one credit
+// there; payments and refunds without a reason code carry no tag and are left out.
+func (s *ReconciliationService) refundReasonTotals(ctx context.Context, startDate, endDate time.Time) ([]RefundReasonTotal, error) {
+	totals, err := s.repo.SumEntriesByTag(ctx, RefundReasonTag, models.EntryTagFilter{
+		AccountID: "customer_receivables",
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reasons := []RefundReasonTotal{}
+	for _, total := range totals {
+		if total.Value == "" {
+			continue
+		}
+		reasons = append(reasons, RefundReasonTotal{
+			Reason:   total.Value,
+			Currency: total.Currency,
+			Amount:   total.Credits,
+			Refunds:  total.Entries,
+		})
+	}
+	return reasons, nil
+}
+
 // Helper functions
 
-func isBalanced(debits, credits float64) bool {
-	// Allow for small floating point differences
-	tolerance := 0.01
-	diff := debits - credits
-	return diff >= -tolerance && diff <= tolerance
+// isBalanced reports whether debits equal credits in currency. The totals are
+// compared as exact decimals, so float drift from summing entries never counts, and
+// may differ by up to half a minor unit, or the configured rounding tolerance.
+func (s *ReconciliationService) isBalanced(debits, credits float64, currency string) bool {
+	tolerance := money.NewDecimal(s.roundingTolerance)
+	if s.roundingTolerance == 0 {
+		tolerance = money.NewDecimal(0.5 * math.Pow10(-money.Exponent(currency)))
+	}
+
+	diff := money.NewDecimal(debits).Sub(money.NewDecimal(credits))
+	if diff.Sign() < 0 {
+		diff = money.Decimal{}.Sub(diff)
+	}
+	return diff.Cmp(tolerance) <= 0
+}
+
+// entriesCurrency is the currency a transaction's entries are posted in
+func entriesCurrency(entries []*models.LedgerEntry) string {
+	if len(entries) == 0 {
+		return ""
+	}
+	return entries[0].Currency
 }
 
 // Additional models for reconciliation
@@ -280,5 +359,14 @@ type SettlementReport struct {
 	TotalTransactions int
 	TotalAmount       float64
 	TotalFees         float64
+	RefundReasons     []RefundReasonTotal
 	CreatedAt         time.Time
+}
+
+// RefundReasonTotal is the amount refunded for one reason in one currency
+type RefundReasonTotal struct {
+	Reason   string
+	Currency string
+	Amount   float64
+	Refunds  int
 }
\ No newline at end of file
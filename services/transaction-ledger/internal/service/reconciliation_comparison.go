@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"transaction-ledger/internal/models"
+)
+
+var ErrReconciliationReportNotFound = errors.New("reconciliation report not found")
+
+// CompareReports diffs the discrepancies of two saved reconciliation reports,
+// splitting them into those unique to each report and those common to both.
+// Discrepancies are matched by what they are about, such as the transaction ID,
+// so one whose amounts changed between runs is common, shown as in reportB.
+func (s *ReconciliationService) CompareReports(ctx context.Context, idA, idB string) (*models.ReconciliationComparison, error) {
+	reportA, err := s.getReport(ctx, idA)
+	if err != nil {
+		return nil, err
+	}
+	reportB, err := s.getReport(ctx, idB)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &models.ReconciliationComparison{
+		ReportA:   reportA.ID,
+		ReportB:   reportB.ID,
+		OnlyInA:   []string{},
+		OnlyInB:   []string{},
+		Common:    []string{},
+		CreatedAt: time.Now(),
+	}
+
+	inA := discrepancySet(reportA.Discrepancies)
+	inB := discrepancySet(reportB.Discrepancies)
+	for _, discrepancy := range uniqueDiscrepancies(reportA.Discrepancies) {
+		if _, ok := inB[discrepancyKey(discrepancy)]; !ok {
+			comparison.OnlyInA = append(comparison.OnlyInA, discrepancy)
+		}
+	}
+	for _, discrepancy := range uniqueDiscrepancies(reportB.Discrepancies) {
+		if _, ok := inA[discrepancyKey(discrepancy)]; ok {
+			comparison.Common = append(comparison.Common, discrepancy)
+		} else {
+			comparison.OnlyInB = append(comparison.OnlyInB, discrepancy)
+		}
+	}
+
+	return comparison, nil
+}
+
+// getReport loads a saved reconciliation report, failing if it does not exist
+func (s *ReconciliationService) getReport(ctx context.Context, id string) (*models.ReconciliationReport, error) {
+	report, err := s.repo.GetReconciliationReport(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reconciliation report %s: %w", id, err)
+	}
+	if report == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReconciliationReportNotFound, id)
+	}
+	return report, nil
+}
+
+// discrepancyKey is what a discrepancy is about, without its amounts: the
+// "Transaction <id>" or "Overall imbalance" before the colon
+func discrepancyKey(discrepancy string) string {
+	if i := strings.Index(discrepancy, ":"); i >= 0 {
+		return discrepancy[:i]
+	}
+	return discrepancy
+}
+
+func discrepancySet(discrepancies []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		set[discrepancyKey(discrepancy)] = struct{}{}
+	}
+	return set
+}
+
+// uniqueDiscrepancies keeps the first discrepancy for each key, in report order
+func uniqueDiscrepancies(discrepancies []string) []string {
+	seen := make(map[string]bool, len(discrepancies))
+	unique := make([]string, 0, len(discrepancies))
+	for _, discrepancy := range discrepancies {
+		key := discrepancyKey(discrepancy)
+		if !seen[key] {
+			seen[key] = true
+			unique = append(unique, discrepancy)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestCompareReportsSplitsOverlappingDiscrepancies(t *testing.T) {
+	store := newMockStore()
+	store.reports["rec_before"] = &models.ReconciliationReport{
+		ID: "rec_before",
+		Discrepancies: []string{
+			"Transaction ltx_1: debits=100.00, credits=90.00 (diff=10.00)",
+			"Transaction ltx_2: debits=50.00, credits=0.00 (diff=50.00)",
+			"Transaction ltx_3: debits=20.00, credits=25.00 (diff=-5.00)",
+		},
+	}
+	store.reports["rec_after"] = &models.ReconciliationReport{
+		ID: "rec_after",
+		Discrepancies: []string{
+			"Transaction ltx_2: debits=50.00, credits=0.00 (diff=50.00)",
+			"Transaction ltx_4: debits=10.00, credits=0.00 (diff=10.00)",
+		},
+	}
+
+	s := NewReconciliationService(store, zap.NewNop())
+	comparison, err := s.CompareReports(context.Background(), "rec_before", "rec_after")
+	if err != nil {
+		t.Fatalf("CompareReports() error = %v", err)
+	}
+
+	wantOnlyInA := []string{
+		"Transaction ltx_1: debits=100.00, credits=90.00 (diff=10.00)",
+		"Transaction ltx_3: debits=20.00, credits=25.00 (diff=-5.00)",
+	}
+	wantOnlyInB := []string{"Transaction ltx_4: debits=10.00, credits=0.00 (diff=10.00)"}
+	wantCommon := []string{"Transaction ltx_2: debits=50.00, credits=0.00 (diff=50.00)"}
+
+	if !reflect.DeepEqual(comparison.OnlyInA, wantOnlyInA) {
+		t.Errorf("OnlyInA = %v, want %v", comparison.OnlyInA, wantOnlyInA)
+	}
+	if !reflect.DeepEqual(comparison.OnlyInB, wantOnlyInB) {
+		t.Errorf("OnlyInB = %v, want %v", comparison.OnlyInB, wantOnlyInB)
+	}
+	if !reflect.DeepEqual(comparison.Common, wantCommon) {
+		t.Errorf("Common = %v, want %v", comparison.Common, wantCommon)
+	}
+	if comparison.ReportA != "rec_before" || comparison.ReportB != "rec_after" {
+		t.Errorf("compared %s with %s, want rec_before with rec_after", comparison.ReportA, comparison.ReportB)
+	}
+}
+
+func TestCompareReportsMissingReport(t *testing.T) {
+	store := newMockStore()
+	store.reports["rec_before"] = &models.ReconciliationReport{ID: "rec_before", Discrepancies: []string{}}
+
+	s := NewReconciliationService(store, zap.NewNop())
+	if _, err := s.CompareReports(context.Background(), "rec_before", "rec_missing"); !errors.Is(err, ErrReconciliationReportNotFound) {
+		t.Errorf("CompareReports() error = %v, want %v", err, ErrReconciliationReportNotFound)
+	}
+}
+
+func TestCompareReportsMatchesByTransaction(t *testing.T) {
+	store := newMockStore()
+	store.reports["rec_before"] = &models.ReconciliationReport{
+		ID:            "rec_before",
+		Discrepancies: []string{"Transaction ltx_1: debits=100.00, credits=90.00 (diff=10.00)"},
+	}
+	// A partial fix changed the amounts but ltx_1 is still unbalanced
+	store.reports["rec_after"] = &models.ReconciliationReport{
+		ID:            "rec_after",
+		Discrepancies: []string{"Transaction ltx_1: debits=100.00, credits=95.00 (diff=5.00)"},
+	}
+
+	s := NewReconciliationService(store, zap.NewNop())
+	comparison, err := s.CompareReports(context.Background(), "rec_before", "rec_after")
+	if err != nil {
+		t.Fatalf("CompareReports() error = %v", err)
+	}
+
+	if len(comparison.OnlyInA) != 0 || len(comparison.OnlyInB) != 0 {
+		t.Errorf("OnlyInA = %v, OnlyInB = %v; want neither", comparison.OnlyInA, comparison.OnlyInB)
+	}
+	want := []string{"Transaction ltx_1: debits=100.00, credits=95.00 (diff=5.00)"}
+	if !reflect.DeepEqual(comparison.Common, want) {
+		t.Errorf("Common = %v, want %v", comparison.Common, want)
+	}
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+const (
+	// maxNotifiedDiscrepancies bounds how many discrepancies a notification lists;
+	// the full set is in the report saved under its ID
+	maxNotifiedDiscrepancies = 20
+
+	// notificationTimeout bounds sending one report to every notifier
+	notificationTimeout = 30 * time.Second
+)
+
+// ReconciliationNotifier tells someone that a reconciliation did not balance
+type ReconciliationNotifier interface {
+	NotifyUnbalanced(ctx context.Context, report *models.ReconciliationReport) error
+}
+
+// SetNotifiers sets who is told when a period reconciliation finds discrepancies.
+// Notifying is best effort: a failed notification is logged, not returned.
+func (s *ReconciliationService) SetNotifiers(notifiers ...ReconciliationNotifier) {
+	s.notifiers = notifiers
+}
+
+// notifyUnbalanced sends report to every notifier in the background, so a slow
+// Slack or SMTP server cannot hold up the reconciliation request that found it
+func (s *ReconciliationService) notifyUnbalanced(report *models.ReconciliationReport) {
+	if len(s.notifiers) == 0 {
+		return
+	}
+
+	s.notifying.Add(1)
+	go func() {
+		defer s.notifying.Done()
+
+		// The request may finish first, so sending gets its own deadline
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+
+		for _, notifier := range s.notifiers {
+			if err := notifier.NotifyUnbalanced(ctx, report); err != nil {
+				s.logger.Error("failed to send reconciliation notification",
+					zap.String("report_id", report.ID),
+					zap.Error(err))
+			}
+		}
+	}()
+}
+
+// WaitForNotifications blocks until every notification already started has been
+// sent or has failed
+func (s *ReconciliationService) WaitForNotifications() {
+	s.notifying.Wait()
+}
+
+// summarizeReport returns a subject line and plain-text body describing an
+// unbalanced report
+func summarizeReport(report *models.ReconciliationReport) (string, string) {
+	subject := fmt.Sprintf("Ledger reconciliation unbalanced for %s to %s",
+		report.StartDate.Format("2006-01-02"), report.EndDate.Format("2006-01-02"))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "Report %s: %d transactions, debits=%.2f, credits=%.2f, %d discrepancies\n",
+		report.ID, report.TotalTransactions, report.TotalDebits, report.TotalCredits, len(report.Discrepancies))
+
+	for i, discrepancy := range report.Discrepancies {
+		if i == maxNotifiedDiscrepancies {
+			fmt.Fprintf(&body, "... and %d more\n", len(report.Discrepancies)-i)
+			break
+		}
+		fmt.Fprintf(&body, "- %s\n", discrepancy)
+	}
+	return subject, body.String()
+}
+
+// SlackNotifier posts unbalanced reconciliations to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier creates a notifier for a Slack incoming webhook URL
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NotifyUnbalanced posts a summary of report to the webhook
+func (n *SlackNotifier) NotifyUnbalanced(ctx context.Context, report *models.ReconciliationReport) error {
+	subject, body := summarizeReport(report)
+	payload, err := json.Marshal(map[string]string{"text": fmt.Sprintf("*%s*\n%s", subject, body)})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailConfig is the SMTP server and addresses reconciliation emails use
+type EmailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+}
+
+// EmailNotifier emails unbalanced reconciliations over SMTP
+type EmailNotifier struct {
+	config EmailConfig
+	send   func(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates a notifier that sends through the configured SMTP
+// server, authenticating when a username is set
+func NewEmailNotifier(config EmailConfig) (*EmailNotifier, error) {
+	if config.Host == "" || config.From == "" || len(config.To) == 0 {
+		return nil, fmt.Errorf("email notifications need an SMTP host, a sender and at least one recipient")
+	}
+	return &EmailNotifier{config: config, send: sendMail}, nil
+}
+
+// NotifyUnbalanced emails a summary of report to the configured recipients
+func (n *EmailNotifier) NotifyUnbalanced(ctx context.Context, report *models.ReconciliationReport) error {
+	subject, body := summarizeReport(report)
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(n.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if n.config.Username != "" {
+		auth = smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)
+	}
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	if err := n.send(ctx, addr, auth, n.config.From, n.config.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send reconciliation email: %w", err)
+	}
+	return nil
+}
+
+// sendMail does what smtp.SendMail does, but the whole exchange must finish
+// before ctx's deadline; smtp.SendMail would wait on a stalled server forever
+func sendMail(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(notificationTimeout)
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+
+	host, _, _ := net.SplitHostPort(addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// recordingNotifier records the reports it is sent and fails with err
+type recordingNotifier struct {
+	reports []*models.ReconciliationReport
+	err     error
+}
+
+func (n *recordingNotifier) NotifyUnbalanced(ctx context.Context, report *models.ReconciliationReport) error {
+	n.reports = append(n.reports, report)
+	return n.err
+}
+
+func TestReconcilePeriodNotifiesWhenUnbalanced(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		unbalanced bool
+		wantCalls  int
+	}{
+		{name: "Balanced", wantCalls: 0},
+		{name: "Unbalanced", unbalanced: true, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", day.Add(9*time.Hour))
+			if tt.unbalanced {
+				store.entries = append(store.entries, &models.LedgerEntry{
+					TransactionID: "ltx_1", AccountID: "fees", Type: models.EntryTypeDebit, Amount: 5, Currency: "USD", CreatedAt: day.Add(9 * time.Hour),
+				})
+			}
+
+			// A failing notifier must not fail the reconciliation or stop the others
+			failing := &recordingNotifier{err: errors.New("smtp unavailable")}
+			notifier := &recordingNotifier{}
+			s := NewReconciliationService(store, zap.NewNop())
+			s.SetNotifiers(failing, notifier)
+
+			report, err := s.ReconcilePeriod(context.Background(), day, day.AddDate(0, 0, 1))
+			if err != nil {
+				t.Fatalf("ReconcilePeriod() error = %v", err)
+			}
+			s.WaitForNotifications()
+
+			if len(notifier.reports) != tt.wantCalls || len(failing.reports) != tt.wantCalls {
+				t.Fatalf("notified %d and %d times, want %d", len(failing.reports), len(notifier.reports), tt.wantCalls)
+			}
+			if tt.wantCalls > 0 && notifier.reports[0] != report {
+				t.Error("notifier was not sent the reconciliation report")
+			}
+		})
+	}
+}
+
+func TestSlackNotifierPostsSummary(t *testing.T) {
+	var text string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("invalid webhook body: %v", err)
+		}
+		text = body["text"]
+	}))
+	defer server.Close()
+
+	report := &models.ReconciliationReport{
+		ID:            "rec_1",
+		StartDate:     time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC),
+		EndDate:       time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC),
+		Discrepancies: []string{"Transaction ltx_1: debits=105.00, credits=100.00 (diff=5.00)"},
+	}
+	if err := NewSlackNotifier(server.URL).NotifyUnbalanced(context.Background(), report); err != nil {
+		t.Fatalf("NotifyUnbalanced() error = %v", err)
+	}
+
+	if !strings.Contains(text, "2024-03-10 to 2024-03-11") || !strings.Contains(text, "ltx_1") {
+		t.Errorf("text = %q, want the period and the discrepancy", text)
+	}
+}
+
+func TestEmailNotifierSendsToRecipients(t *testing.T) {
+	notifier, err := NewEmailNotifier(EmailConfig{
+		Host: "smtp.example.com",
+		Port: 587,
+		From: "ledger@example.com",
+		To:   []string{"finance@example.com", "ops@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier() error = %v", err)
+	}
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	notifier.send = func(ctx context.Context, addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	report := &models.ReconciliationReport{ID: "rec_1", Discrepancies: []string{"Overall imbalance"}}
+	if err := notifier.NotifyUnbalanced(context.Background(), report); err != nil {
+		t.Fatalf("NotifyUnbalanced() error = %v", err)
+	}
+
+	if gotAddr != "smtp.example.com:587" || len(gotTo) != 2 {
+		t.Errorf("sent to %s %v, want smtp.example.com:587 and two recipients", gotAddr, gotTo)
+	}
+	if !strings.Contains(gotMsg, "Subject: Ledger reconciliation unbalanced") || !strings.Contains(gotMsg, "Overall imbalance") {
+		t.Errorf("message = %q, want the subject and discrepancy", gotMsg)
+	}
+}
+
+func TestReconcilePeriodDoesNotWaitForNotifiers(t *testing.T) {
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	store := newMockStore()
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", day.Add(9*time.Hour))
+	store.entries = append(store.entries, &models.LedgerEntry{
+		TransactionID: "ltx_1", AccountID: "fees", Type: models.EntryTypeDebit, Amount: 5, Currency: "USD", CreatedAt: day.Add(9 * time.Hour),
+	})
+
+	release := make(chan struct{})
+	blocked := &blockingNotifier{release: release}
+	s := NewReconciliationService(store, zap.NewNop())
+	s.SetNotifiers(blocked)
+
+	// The caller's context ends with its request; the notification must not
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := s.ReconcilePeriod(ctx, day, day.AddDate(0, 0, 1)); err != nil {
+		t.Fatalf("ReconcilePeriod() error = %v", err)
+	}
+	cancel()
+
+	close(release)
+	s.WaitForNotifications()
+	if blocked.err != nil {
+		t.Errorf("notification context error = %v, want none", blocked.err)
+	}
+}
+
+// blockingNotifier waits for release, then records whether its context had ended
+type blockingNotifier struct {
+	release chan struct{}
+	err     error
+}
+
+func (n *blockingNotifier) NotifyUnbalanced(ctx context.Context, report *models.ReconciliationReport) error {
+	<-n.release
+	n.err = ctx.Err()
+	return nil
+}
+
+func TestSendMailTimesOut(t *testing.T) {
+	// A server that accepts connections but never greets
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		<-done
+		conn.Close()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = sendMail(ctx, listener.Addr().String(), nil, "ledger@example.com", []string{"finance@example.com"}, []byte("body"))
+	if err == nil {
+		t.Fatal("sendMail() to a silent server succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("sendMail() took %v, want it to give up at the deadline", elapsed)
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// RunReconciliation reconciles a period and records what triggered the run
+// and how long it took
+func (s *ReconciliationService) RunReconciliation(ctx context.Context, startDate, endDate time.Time, trigger models.ReconciliationTrigger) (*models.ReconciliationRun, error) {
+	started := time.Now()
+
+	report, err := s.ReconcilePeriod(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.ReconciliationRun{
+		ReconciliationReport: report,
+		TriggerSource:        trigger,
+		DurationMS:           time.Since(started).Milliseconds(),
+	}
+
+	s.logger.Info("reconciliation run recorded",
+		zap.String("report_id", report.ID),
+		zap.String("trigger_source", string(trigger)),
+		zap.Int64("duration_ms", run.DurationMS))
+
+	return run, nil
+}
+
+// RunScheduled reconciles the previous day every interval until ctx is cancelled
+func (s *ReconciliationService) RunScheduled(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, -1)
+			if _, err := s.RunReconciliation(ctx, day, day.AddDate(0, 0, 1), models.ReconciliationTriggerCron); err != nil {
+				s.logger.Error("scheduled reconciliation failed", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestRunReconciliationRecordsManualTrigger(t *testing.T) {
+	store := newMockStore()
+	day := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", day.Add(9*time.Hour))
+
+	s := NewReconciliationService(store, zap.NewNop())
+	run, err := s.RunReconciliation(context.Background(), day, day.AddDate(0, 0, 1), models.ReconciliationTriggerManual)
+	if err != nil {
+		t.Fatalf("RunReconciliation() error = %v", err)
+	}
+
+	if run.TriggerSource != models.ReconciliationTriggerManual {
+		t.Errorf("TriggerSource = %q, want manual", run.TriggerSource)
+	}
+	if run.DurationMS < 0 {
+		t.Errorf("DurationMS = %d, want >= 0", run.DurationMS)
+	}
+	if run.TotalTransactions != 1 || !run.IsBalanced {
+		t.Errorf("report = %+v, want 1 balanced transaction", run.ReconciliationReport)
+	}
+
+	// The audit fields are exposed alongside the report's own
+	data, err := json.Marshal(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(data, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["trigger_source"] != "manual" {
+		t.Errorf("trigger_source = %v, want manual", body["trigger_source"])
+	}
+	if _, ok := body["duration_ms"]; !ok {
+		t.Error("duration_ms missing from report JSON")
+	}
+}
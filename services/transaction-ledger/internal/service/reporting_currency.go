@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/money"
+	"transaction-ledger/internal/models"
+)
+
+// Reporting-currency entries go to their own accounts so every account's balance stays in one currency
+const (
+	reportingReceivablesAccount = "customer_receivables_reporting"
+	reportingLiabilityAccount   = "payment_gateway_liability_reporting"
+)
+
+// SetReportingCurrency makes RecordPayment also post each payment converted into currency,
+// at the rate converter returns when the payment is posted; RecordRefund reverses those
+// entries at the same rate. An empty currency disables it.
+func (s *LedgerService) SetReportingCurrency(currency string, converter CurrencyConverter) {
+	s.reportingCurrency = currency
+	s.converter = converter
+}
+
+// reportingEntries converts a payment into the reporting currency, returning no entries
+// when reporting is disabled or the payment is already in the reporting currency
+func (s *LedgerService) reportingEntries(ctx context.Context, amount float64, currency string) ([]models.EntryRequest, float64, error) {
+	if s.reportingCurrency == "" || s.converter == nil || currency == s.reportingCurrency {
+		return nil, 0, nil
+	}
+
+	rate, err := s.converter.GetRate(ctx, currency, s.reportingCurrency)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get %s to %s reporting rate: %w", currency, s.reportingCurrency, err)
+	}
+	if rate <= 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return nil, 0, fmt.Errorf("invalid %s to %s reporting rate %v", currency, s.reportingCurrency, rate)
+	}
+
+	converted := money.NewDecimal(amount).
+		Mul(money.NewDecimal(rate)).
+		Round(money.Exponent(s.reportingCurrency)).
+		Float64()
+
+	return []models.EntryRequest{
+		{
+			AccountID:   reportingReceivablesAccount,
+			Type:        models.EntryTypeDebit,
+			Amount:      converted,
+			Currency:    s.reportingCurrency,
+			Description: "Customer payment received (reporting currency)",
+		},
+		{
+			AccountID:   reportingLiabilityAccount,
+			Type:        models.EntryTypeCredit,
+			Amount:      converted,
+			Currency:    s.reportingCurrency,
+			Description: "Payment gateway liability (reporting currency)",
+		},
+	}, rate, nil
+}
+
+// reportingReversalEntries reverses a refunded amount of a payment in the reporting
+// currency. It converts at the rate the payment was posted at, not today's, so a
+// full refund returns the reporting accounts to zero. A payment posted without
+// reporting entries gets none, and the rate is 0.
+func (s *LedgerService) reportingReversalEntries(ctx context.Context, paymentID string, amount float64, currency string) ([]models.EntryRequest, float64, error) {
+	conversion, reportingCurrency, err := s.repo.GetPaymentConversion(ctx, paymentID, reportingReceivablesAccount)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to look up reporting conversion of payment %s: %w", paymentID, err)
+	}
+	if conversion == nil {
+		return nil, 0, nil
+	}
+	if conversion.SourceCurrency != currency {
+		return nil, 0, fmt.Errorf("refund of payment %s is in %s but the payment was posted in %s",
+			paymentID, currency, conversion.SourceCurrency)
+	}
+
+	converted := money.NewDecimal(amount).
+		Mul(money.NewDecimal(conversion.ConversionRate)).
+		Round(money.Exponent(reportingCurrency)).
+		Float64()
+
+	return []models.EntryRequest{
+		{
+			AccountID:   reportingLiabilityAccount,
+			Type:        models.EntryTypeDebit,
+			Amount:      converted,
+			Currency:    reportingCurrency,
+			Description: "Payment gateway liability released (reporting currency)",
+		},
+		{
+			AccountID:   reportingReceivablesAccount,
+			Type:        models.EntryTypeCredit,
+			Amount:      converted,
+			Currency:    reportingCurrency,
+			Description: "Customer payment refunded (reporting currency)",
+		},
+	}, conversion.ConversionRate, nil
+}
+
+// recordConversions stores the source amount and rate on each reporting-currency entry.
+// The transaction is already posted, so failures are logged rather than returned.
+func (s *LedgerService) recordConversions(ctx context.Context, entries []*models.LedgerEntry, amount float64, currency string, rate float64) {
+	for _, entry := range entries {
+		if entry.AccountID != reportingReceivablesAccount && entry.AccountID != reportingLiabilityAccount {
+			continue
+		}
+		conversion := &models.EntryConversion{
+			EntryID:        entry.ID,
+			SourceAmount:   amount,
+			SourceCurrency: currency,
+			ConversionRate: rate,
+		}
+		if _, err := s.repo.SetEntryConversion(ctx, conversion); err != nil {
+			s.logger.Error("failed to record reporting conversion rate",
+				zap.String("entry_id", entry.ID),
+				zap.Float64("rate", rate),
+				zap.Error(err))
+		}
+	}
+}
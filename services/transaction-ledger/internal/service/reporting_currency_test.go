@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+// fixedRates is a CurrencyConverter with static rates keyed by "FROM:TO"
+type fixedRates map[string]float64
+
+func (r fixedRates) GetRate(ctx context.Context, from, to string) (float64, error) {
+	rate, ok := r[from+":"+to]
+	if !ok {
+		return 0, errors.New("no rate")
+	}
+	return rate, nil
+}
+
+func TestRecordPaymentPostsReportingCurrencyEntry(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+	ledger.SetReportingCurrency("USD", fixedRates{"EUR:USD": 1.0845})
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "EUR"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(store.entries) != 4 {
+		t.Fatalf("posted %d entries, want 4", len(store.entries))
+	}
+	byAccount := make(map[string]*models.LedgerEntry)
+	for _, entry := range store.entries {
+		byAccount[entry.AccountID] = entry
+	}
+
+	if original := byAccount["customer_receivables"]; original == nil || original.Amount != 100 || original.Currency != "EUR" {
+		t.Errorf("original entry = %+v, want 100 EUR", original)
+	}
+	for _, account := range []string{reportingReceivablesAccount, reportingLiabilityAccount} {
+		entry := byAccount[account]
+		if entry == nil {
+			t.Fatalf("no %s entry posted", account)
+		}
+		if entry.Amount != 108.45 || entry.Currency != "USD" {
+			t.Errorf("%s entry = %v %s, want 108.45 USD", account, entry.Amount, entry.Currency)
+		}
+		conversion := store.conversions[entry.ID]
+		if conversion == nil {
+			t.Fatalf("%s entry has no conversion recorded", account)
+		}
+		if conversion.ConversionRate != 1.0845 || conversion.SourceAmount != 100 || conversion.SourceCurrency != "EUR" {
+			t.Errorf("%s conversion = %+v, want 100 EUR at 1.0845", account, conversion)
+		}
+	}
+}
+
+func TestRecordRefundReversesReportingCurrencyEntries(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+	rates := fixedRates{"EUR:USD": 1.0845}
+	ledger.SetReportingCurrency("USD", rates)
+
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "EUR"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The rate moves before the refunds, which must still reverse at the posted rate
+	rates["EUR:USD"] = 1.2
+	if err := ledger.RecordRefund(ctx, "pay_1", 40, "EUR", "duplicate"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ledger.RecordRefund(ctx, "pay_1", 60, "EUR", "duplicate"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, account := range []string{"customer_receivables", "payment_gateway_liability", reportingReceivablesAccount, reportingLiabilityAccount} {
+		balance, err := ledger.GetBalance(ctx, account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if balance.Balance != 0 {
+			t.Errorf("%s balance = %v after a full refund, want 0", account, balance.Balance)
+		}
+	}
+}
+
+func TestRecordRefundWithoutReportingEntries(t *testing.T) {
+	ctx := context.Background()
+	store := newMockStore()
+	ledger := NewLedgerService(store, zap.NewNop())
+
+	// Posted before a reporting currency was configured
+	if err := ledger.RecordPayment(ctx, "pay_1", 100, "EUR"); err != nil {
+		t.Fatal(err)
+	}
+	ledger.SetReportingCurrency("USD", fixedRates{"EUR:USD": 1.0845})
+
+	if err := ledger.RecordRefund(ctx, "pay_1", 100, "EUR", ""); err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range store.entries {
+		if entry.AccountID == reportingReceivablesAccount || entry.AccountID == reportingLiabilityAccount {
+			t.Errorf("refund posted %s entry for a payment without reporting entries", entry.AccountID)
+		}
+	}
+}
+
+func TestRecordPaymentReportingCurrency(t *testing.T) {
+	tests := []struct {
+		name        string
+		currency    string
+		rates       fixedRates
+		wantEntries int
+		wantErr     bool
+	}{
+		{
+			name:        "Already in reporting currency",
+			currency:    "USD",
+			rates:       fixedRates{},
+			wantEntries: 2,
+		},
+		{
+			name:     "Rate unavailable",
+			currency: "GBP",
+			rates:    fixedRates{},
+			wantErr:  true,
+		},
+		{
+			name:     "Non-positive rate",
+			currency: "EUR",
+			rates:    fixedRates{"EUR:USD": 0},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			ledger := NewLedgerService(store, zap.NewNop())
+			ledger.SetReportingCurrency("USD", tt.rates)
+
+			err := ledger.RecordPayment(context.Background(), "pay_1", 100, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RecordPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(store.entries) != tt.wantEntries {
+				t.Errorf("posted %d entries, want %d", len(store.entries), tt.wantEntries)
+			}
+			if len(store.conversions) != 0 {
+				t.Errorf("recorded %d conversions, want none", len(store.conversions))
+			}
+		})
+	}
+}
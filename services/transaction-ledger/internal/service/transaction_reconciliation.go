@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"shared/pkg/ids"
+	"transaction-ledger/internal/models"
+)
+
+// maxReconcileTransactions bounds a single batch reconciliation
+const maxReconcileTransactions = 1000
+
+var ErrInvalidTransactionIDs = errors.New("between 1 and 1000 transaction IDs are required")
+
+// ReconcileTransactions checks that each of the given transactions balances, for
+// audits of a specific set rather than a whole period. IDs with no ledger entries
+// are reported as not found and make the report unbalanced.
+func (s *ReconciliationService) ReconcileTransactions(ctx context.Context, transactionIDs []string) (*models.TransactionReconciliationReport, error) {
+	transactionIDs = uniqueIDs(transactionIDs)
+	if len(transactionIDs) == 0 || len(transactionIDs) > maxReconcileTransactions {
+		return nil, ErrInvalidTransactionIDs
+	}
+
+	report := &models.TransactionReconciliationReport{
+		ID:            ids.New(),
+		Transactions:  []models.TransactionBalance{},
+		NotFound:      []string{},
+		Discrepancies: []string{},
+		IsBalanced:    true,
+		CreatedAt:     time.Now(),
+	}
+
+	for _, id := range transactionIDs {
+		entries, err := s.repo.GetEntriesByTransaction(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get entries for %s: %w", id, err)
+		}
+		if len(entries) == 0 {
+			report.NotFound = append(report.NotFound, id)
+			report.Discrepancies = append(report.Discrepancies, fmt.Sprintf("Transaction %s: not found", id))
+			report.IsBalanced = false
+			continue
+		}
+
+		balance := models.TransactionBalance{TransactionID: id}
+		for _, entry := range entries {
+			if entry.Type == models.EntryTypeDebit {
+				balance.Debits += entry.Amount
+			} else {
+				balance.Credits += entry.Amount
+			}
+		}
+		balance.Difference = balance.Debits - balance.Credits
+		balance.IsBalanced = s.isBalanced(balance.Debits, balance.Credits, entriesCurrency(entries))
+
+		if !balance.IsBalanced {
+			report.Discrepancies = append(report.Discrepancies, fmt.Sprintf("Transaction %s: debits=%.2f, credits=%.2f (diff=%.2f)",
+				id, balance.Debits, balance.Credits, balance.Difference))
+			report.IsBalanced = false
+		}
+
+		report.Transactions = append(report.Transactions, balance)
+		report.TotalDebits += balance.Debits
+		report.TotalCredits += balance.Credits
+	}
+	report.TotalTransactions = len(report.Transactions)
+
+	s.logger.Info("transaction reconciliation complete",
+		zap.String("report_id", report.ID),
+		zap.Int("requested", len(transactionIDs)),
+		zap.Int("not_found", len(report.NotFound)),
+		zap.Int("discrepancies", len(report.Discrepancies)),
+		zap.Bool("balanced", report.IsBalanced))
+
+	return report, nil
+}
+
+// uniqueIDs drops blank and repeated IDs, keeping the first occurrence's order
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"transaction-ledger/internal/models"
+)
+
+func TestReconcileTransactions(t *testing.T) {
+	store := newMockStore()
+	at := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", at)
+	addLedgerTransaction(store, "ltx_2", "pay_2", 50, "USD", at)
+	// A one-sided posting: 30 debited with no matching credit
+	store.transactions["ltx_3"] = &models.LedgerTransaction{ID: "ltx_3", CreatedAt: at}
+	store.entries = append(store.entries,
+		&models.LedgerEntry{TransactionID: "ltx_3", AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 30, Currency: "USD", CreatedAt: at},
+	)
+	// Not requested, so its imbalance must not show up
+	store.transactions["ltx_4"] = &models.LedgerTransaction{ID: "ltx_4", CreatedAt: at}
+	store.entries = append(store.entries,
+		&models.LedgerEntry{TransactionID: "ltx_4", AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: 5, Currency: "USD", CreatedAt: at},
+	)
+
+	s := NewReconciliationService(store, zap.NewNop())
+	report, err := s.ReconcileTransactions(context.Background(), []string{"ltx_1", "ltx_3", "ltx_2", "ltx_1", "ltx_missing"})
+	if err != nil {
+		t.Fatalf("ReconcileTransactions() error = %v", err)
+	}
+
+	if report.IsBalanced {
+		t.Error("report should not be balanced")
+	}
+	if report.TotalTransactions != 3 {
+		t.Errorf("TotalTransactions = %d, want 3 (duplicates collapsed, missing excluded)", report.TotalTransactions)
+	}
+	if report.TotalDebits != 180 || report.TotalCredits != 150 {
+		t.Errorf("totals = %.2f debits, %.2f credits; want 180 and 150", report.TotalDebits, report.TotalCredits)
+	}
+	if len(report.NotFound) != 1 || report.NotFound[0] != "ltx_missing" {
+		t.Errorf("NotFound = %v, want [ltx_missing]", report.NotFound)
+	}
+	if len(report.Discrepancies) != 2 {
+		t.Errorf("Discrepancies = %v, want ltx_3 and ltx_missing", report.Discrepancies)
+	}
+
+	want := map[string]bool{"ltx_1": true, "ltx_2": true, "ltx_3": false}
+	for _, balance := range report.Transactions {
+		if balance.IsBalanced != want[balance.TransactionID] {
+			t.Errorf("%s IsBalanced = %v, want %v", balance.TransactionID, balance.IsBalanced, want[balance.TransactionID])
+		}
+	}
+	if report.Transactions[1].TransactionID != "ltx_3" || report.Transactions[1].Difference != 30 {
+		t.Errorf("Transactions[1] = %+v, want ltx_3 off by 30", report.Transactions[1])
+	}
+}
+
+func TestReconcileTransactionsAllBalanced(t *testing.T) {
+	store := newMockStore()
+	at := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	addLedgerTransaction(store, "ltx_1", "pay_1", 100, "USD", at)
+	addLedgerTransaction(store, "ltx_2", "pay_2", 50, "USD", at)
+
+	s := NewReconciliationService(store, zap.NewNop())
+	report, err := s.ReconcileTransactions(context.Background(), []string{"ltx_1", "ltx_2"})
+	if err != nil {
+		t.Fatalf("ReconcileTransactions() error = %v", err)
+	}
+	if !report.IsBalanced || len(report.Discrepancies) != 0 {
+		t.Errorf("report = %+v, want balanced with no discrepancies", report)
+	}
+}
+
+func TestReconcileTransactionsRequiresIDs(t *testing.T) {
+	s := NewReconciliationService(newMockStore(), zap.NewNop())
+	if _, err := s.ReconcileTransactions(context.Background(), []string{"", ""}); !errors.Is(err, ErrInvalidTransactionIDs) {
+		t.Errorf("error = %v, want ErrInvalidTransactionIDs", err)
+	}
+}
+
+func TestReconcileTransactionsRoundingTolerance(t *testing.T) {
+	at := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		tolerance    float64
+		currency     string
+		debits       []float64
+		credit       float64
+		wantBalanced bool
+	}{
+		{name: "float drift", currency: "USD", debits: []float64{0.1, 0.2}, credit: 0.3, wantBalanced: true},
+		{name: "half a cent by default", currency: "USD", debits: []float64{100}, credit: 99.995, wantBalanced: true},
+		{name: "a cent off by default", currency: "USD", debits: []float64{100}, credit: 99.99, wantBalanced: false},
+		{name: "half a yen by default", currency: "JPY", debits: []float64{1000}, credit: 999.5, wantBalanced: true},
+		{name: "a yen off by default", currency: "JPY", debits: []float64{1000}, credit: 999, wantBalanced: false},
+		{name: "within configured tolerance", tolerance: 0.01, currency: "USD", debits: []float64{100}, credit: 99.99, wantBalanced: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newMockStore()
+			store.transactions["ltx_1"] = &models.LedgerTransaction{ID: "ltx_1", CreatedAt: at}
+			for _, debit := range tt.debits {
+				store.entries = append(store.entries,
+					&models.LedgerEntry{TransactionID: "ltx_1", AccountID: "customer_receivables", Type: models.EntryTypeDebit, Amount: debit, Currency: tt.currency, CreatedAt: at})
+			}
+			store.entries = append(store.entries,
+				&models.LedgerEntry{TransactionID: "ltx_1", AccountID: "payment_gateway_liability", Type: models.EntryTypeCredit, Amount: tt.credit, Currency: tt.currency, CreatedAt: at})
+
+			s := NewReconciliationService(store, zap.NewNop())
+			if err := s.SetRoundingTolerance(tt.tolerance); err != nil {
+				t.Fatalf("SetRoundingTolerance() error = %v", err)
+			}
+
+			report, err := s.ReconcileTransactions(context.Background(), []string{"ltx_1"})
+			if err != nil {
+				t.Fatalf("ReconcileTransactions() error = %v", err)
+			}
+			if report.IsBalanced != tt.wantBalanced {
+				t.Errorf("IsBalanced = %v, want %v (discrepancies %v)", report.IsBalanced, tt.wantBalanced, report.Discrepancies)
+			}
+			if !tt.wantBalanced && len(report.Discrepancies) != 1 {
+				t.Errorf("Discrepancies = %v, want the imbalance reported", report.Discrepancies)
+			}
+		})
+	}
+}
+
+func TestSetRoundingToleranceRejectsNegative(t *testing.T) {
+	s := NewReconciliationService(newMockStore(), zap.NewNop())
+	if err := s.SetRoundingTolerance(-0.01); err == nil {
+		t.Error("SetRoundingTolerance(-0.01) should fail")
+	}
+}
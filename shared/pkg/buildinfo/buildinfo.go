@@ -0,0 +1,58 @@
+// shared/pkg/buildinfo/buildinfo.go
+package buildinfo
+
+import (
+	"net/http"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X shared/pkg/buildinfo.Version=1.4.0 -X shared/pkg/buildinfo.Commit=$(git rev-parse HEAD) -X shared/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+var buildInfo = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "build_info",
+		Help: "Always 1; labels describe the running build",
+	},
+	[]string{"service", "version", "commit", "build_time", "go_version"},
+)
+
+// Info describes the running binary
+type Info struct {
+	Service   string `json:"service"`
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build info for service
+func Get(service string) Info {
+	return Info{
+		Service:   service,
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// Handler serves the build info as JSON and publishes the build_info gauge
+func Handler(service string) gin.HandlerFunc {
+	info := Get(service)
+	buildInfo.WithLabelValues(info.Service, info.Version, info.Commit, info.BuildTime, info.GoVersion).Set(1)
+
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, info)
+	}
+}
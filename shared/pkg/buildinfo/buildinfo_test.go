@@ -0,0 +1,50 @@
+package buildinfo
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestHandlerReportsBuildInfo(t *testing.T) {
+	Version, Commit, BuildTime = "1.2.3", "abc123", "2024-01-02T03:04:05Z"
+	defer func() { Version, Commit, BuildTime = "dev", "unknown", "unknown" }()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/version", Handler("payment-gateway"))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+	}
+	want := map[string]string{
+		"service":    "payment-gateway",
+		"version":    "1.2.3",
+		"commit":     "abc123",
+		"build_time": "2024-01-02T03:04:05Z",
+	}
+	for field, value := range want {
+		if body[field] != value {
+			t.Errorf("%s = %q, want %q", field, body[field], value)
+		}
+	}
+	if body["go_version"] == "" {
+		t.Error("go_version is empty")
+	}
+
+	gauge := buildInfo.WithLabelValues("payment-gateway", "1.2.3", "abc123", "2024-01-02T03:04:05Z", body["go_version"])
+	if got := testutil.ToFloat64(gauge); got != 1 {
+		t.Errorf("build_info = %v, want 1", got)
+	}
+}
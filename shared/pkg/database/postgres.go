@@ -7,6 +7,9 @@ import (
 	"time"
 
 	_ "github.com/lib/pq"
+	"go.uber.org/zap"
+
+	"shared/pkg/retry"
 )
 
 type PostgresDB struct {
@@ -27,12 +30,25 @@ func NewPostgresDB(connectionString string) (*PostgresDB, error) {
 
 	// Test connection
 	if err := db.Ping(); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
 	return &PostgresDB{db}, nil
 }
 
+// NewPostgresDBWithRetry connects like NewPostgresDB, retrying with backoff so a
+// service started just before its database is ready waits for it
+func NewPostgresDBWithRetry(connectionString string, config retry.Config, logger *zap.Logger) (*PostgresDB, error) {
+	var db *PostgresDB
+	err := retry.Connect(config, logger, "postgres", func() error {
+		var err error
+		db, err = NewPostgresDB(connectionString)
+		return err
+	})
+	return db, err
+}
+
 // Close closes the database connection
 func (db *PostgresDB) Close() error {
 	return db.DB.Close()
@@ -44,33 +60,99 @@ package redis
 import (
 	"context"
 	"fmt"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/go-redis/redis/v8"
+	"go.uber.org/zap"
+
+	"shared/pkg/retry"
 )
 
 type Client struct {
 	client *redis.Client
+	prefix string
+}
+
+// Options configures a Redis client
+type Options struct {
+	Addr string
+	// DB is the Redis database number to select
+	DB int
+	// KeyPrefix namespaces every key the client reads or writes, e.g. "payment-gateway:".
+	// Pub/sub channels are not prefixed so services can still message each other.
+	KeyPrefix string
+}
+
+// OptionsFromEnv reads REDIS_DB and REDIS_KEY_PREFIX. The prefix defaults to the
+// service name and a colon; set REDIS_KEY_PREFIX empty to use bare keys.
+func OptionsFromEnv(addr, service string) (Options, error) {
+	opts := Options{Addr: addr, KeyPrefix: service + ":"}
+
+	if raw := os.Getenv("REDIS_DB"); raw != "" {
+		db, err := strconv.Atoi(raw)
+		if err != nil || db < 0 {
+			return Options{}, fmt.Errorf("REDIS_DB must be a non-negative integer, got %q", raw)
+		}
+		opts.DB = db
+	}
+	if prefix, ok := os.LookupEnv("REDIS_KEY_PREFIX"); ok {
+		opts.KeyPrefix = prefix
+	}
+
+	return opts, nil
 }
 
-// NewRedisClient creates a new Redis client
+// NewRedisClient creates a new Redis client on DB 0 with unprefixed keys
 func NewRedisClient(addr string) *Client {
+	return NewClient(Options{Addr: addr})
+}
+
+// NewClient creates a Redis client for the configured database and key prefix
+func NewClient(opts Options) *Client {
 	client := redis.NewClient(&redis.Options{
-		Addr:         addr,
+		Addr:         opts.Addr,
 		Password:     "",
-		DB:           0,
+		DB:           opts.DB,
 		DialTimeout:  5 * time.Second,
 		ReadTimeout:  3 * time.Second,
 		WriteTimeout: 3 * time.Second,
 		PoolSize:     10,
 	})
 
-	return &Client{client: client}
+	return &Client{client: client, prefix: opts.KeyPrefix}
+}
+
+// NewClientWithRetry creates a Redis client and pings it, retrying with backoff so a
+// service started just before Redis is ready waits for it
+func NewClientWithRetry(opts Options, config retry.Config, logger *zap.Logger) (*Client, error) {
+	c := NewClient(opts)
+	err := retry.Connect(config, logger, "redis", func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return c.Ping(ctx)
+	})
+	if err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Ping checks that Redis is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// key namespaces a key with the client's prefix
+func (c *Client) key(key string) string {
+	return c.prefix + key
 }
 
 // Get retrieves a value from Redis
 func (c *Client) Get(ctx context.Context, key string) (string, error) {
-	val, err := c.client.Get(ctx, key).Result()
+	val, err := c.client.Get(ctx, c.key(key)).Result()
 	if err == redis.Nil {
 		return "", fmt.Errorf("key not found")
 	}
@@ -79,20 +161,74 @@ func (c *Client) Get(ctx context.Context, key string) (string, error) {
 
 // Set stores a value in Redis
 func (c *Client) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
-	return c.client.Set(ctx, key, value, expiration).Err()
+	return c.client.Set(ctx, c.key(key), value, expiration).Err()
 }
 
 // Delete removes a key from Redis
 func (c *Client) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
+	return c.client.Del(ctx, c.key(key)).Err()
+}
+
+// GetDel returns a value and deletes its key in one step, so only one caller can claim it.
+// A missing key returns an empty value rather than an error.
+func (c *Client) GetDel(ctx context.Context, key string) (string, error) {
+	val, err := c.client.GetDel(ctx, c.key(key)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return val, err
+}
+
+// Eval runs a Lua script atomically against the given keys
+func (c *Client) Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error) {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = c.key(key)
+	}
+	return c.client.Eval(ctx, script, prefixed, args...).Result()
 }
 
 // Exists checks if a key exists
 func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
-	n, err := c.client.Exists(ctx, key).Result()
+	n, err := c.client.Exists(ctx, c.key(key)).Result()
 	return n > 0, err
 }
 
+// Publish sends a message to a pub/sub channel
+func (c *Client) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.client.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns the payloads published to a channel until ctx is cancelled
+func (c *Client) Subscribe(ctx context.Context, channel string) <-chan string {
+	pubsub := c.client.Subscribe(ctx, channel)
+	messages := make(chan string)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		incoming := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-incoming:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages
+}
+
 // Close closes the Redis connection
 func (c *Client) Close() error {
 	return c.client.Close()
@@ -0,0 +1,42 @@
+// shared/pkg/events/events.go
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Publisher announces events to other services on a named channel
+type Publisher interface {
+	Publish(ctx context.Context, channel string, event interface{}) error
+}
+
+// NopPublisher discards every event; it is the default until a real publisher is configured
+type NopPublisher struct{}
+
+func (NopPublisher) Publish(ctx context.Context, channel string, event interface{}) error {
+	return nil
+}
+
+// MessageSender sends a raw pub/sub message; implemented by the shared Redis client
+type MessageSender interface {
+	Publish(ctx context.Context, channel string, message interface{}) error
+}
+
+// RedisPublisher publishes events as JSON over Redis pub/sub
+type RedisPublisher struct {
+	sender MessageSender
+}
+
+func NewRedisPublisher(sender MessageSender) *RedisPublisher {
+	return &RedisPublisher{sender: sender}
+}
+
+func (p *RedisPublisher) Publish(ctx context.Context, channel string, event interface{}) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	return p.sender.Publish(ctx, channel, data)
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"testing"
+)
+
+type recordedMessage struct {
+	channel string
+	message interface{}
+}
+
+type recordingSender struct {
+	sent []recordedMessage
+}
+
+func (s *recordingSender) Publish(ctx context.Context, channel string, message interface{}) error {
+	s.sent = append(s.sent, recordedMessage{channel: channel, message: message})
+	return nil
+}
+
+func TestRedisPublisherSendsJSON(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewRedisPublisher(sender)
+
+	event := struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}{Type: "conversion.completed", ID: "conv_1"}
+
+	if err := publisher.Publish(context.Background(), "conversion.events", event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+
+	if len(sender.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(sender.sent))
+	}
+	if sender.sent[0].channel != "conversion.events" {
+		t.Errorf("channel = %q, want conversion.events", sender.sent[0].channel)
+	}
+	data, ok := sender.sent[0].message.([]byte)
+	if !ok {
+		t.Fatalf("message is %T, want []byte", sender.sent[0].message)
+	}
+	if got, want := string(data), `{"type":"conversion.completed","id":"conv_1"}`; got != want {
+		t.Errorf("message = %s, want %s", got, want)
+	}
+}
+
+func TestRedisPublisherRejectsUnencodableEvent(t *testing.T) {
+	sender := &recordingSender{}
+	publisher := NewRedisPublisher(sender)
+
+	if err := publisher.Publish(context.Background(), "conversion.events", make(chan int)); err == nil {
+		t.Error("Publish() should fail for an event that cannot be encoded")
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("sent %d messages, want none", len(sender.sent))
+	}
+}
@@ -0,0 +1,47 @@
+// shared/pkg/ids/ids.go
+package ids
+
+import (
+	"github.com/google/uuid"
+)
+
+// Prefixes name the kind of record an ID belongs to, so an ID in a log line or
+// support ticket says what it is
+const (
+	PrefixPayment     = "pay"
+	PrefixConversion  = "conv"
+	PrefixTransaction = "txn"
+	PrefixCustomer    = "cus"
+	PrefixQuote       = "quote"
+	PrefixRefund      = "ref"
+)
+
+// New returns a random UUID, for records that are only referenced inside one service
+func New() string {
+	return uuid.New().String()
+}
+
+// WithPrefix returns a random UUID after prefix and an underscore, such as pay_<uuid>
+func WithPrefix(prefix string) string {
+	return prefix + "_" + New()
+}
+
+// Payment returns a new payment ID
+func Payment() string {
+	return WithPrefix(PrefixPayment)
+}
+
+// Conversion returns a new currency conversion ID
+func Conversion() string {
+	return WithPrefix(PrefixConversion)
+}
+
+// Transaction returns a new ledger transaction ID
+func Transaction() string {
+	return WithPrefix(PrefixTransaction)
+}
+
+// Refund returns a new refund ID
+func Refund() string {
+	return WithPrefix(PrefixRefund)
+}
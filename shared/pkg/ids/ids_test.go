@@ -0,0 +1,61 @@
+package ids
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrefixedIDs(t *testing.T) {
+	tests := []struct {
+		name   string
+		id     string
+		prefix string
+	}{
+		{name: "Payment", id: Payment(), prefix: "pay_"},
+		{name: "Conversion", id: Conversion(), prefix: "conv_"},
+		{name: "Transaction", id: Transaction(), prefix: "txn_"},
+		{name: "Refund", id: Refund(), prefix: "ref_"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.HasPrefix(tt.id, tt.prefix) {
+				t.Errorf("id = %q, want prefix %q", tt.id, tt.prefix)
+			}
+			if got := len(strings.TrimPrefix(tt.id, tt.prefix)); got != 36 {
+				t.Errorf("id %q has a %d character UUID, want 36", tt.id, got)
+			}
+		})
+	}
+}
+
+func TestConcurrentIDsDoNotCollide(t *testing.T) {
+	const workers = 50
+	const perWorker = 2000
+
+	generated := make(chan string, workers*perWorker)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < perWorker; j++ {
+				generated <- Conversion()
+			}
+		}()
+	}
+	wg.Wait()
+	close(generated)
+
+	seen := make(map[string]bool, workers*perWorker)
+	for id := range generated {
+		if seen[id] {
+			t.Fatalf("duplicate id %s", id)
+		}
+		seen[id] = true
+	}
+	if len(seen) != workers*perWorker {
+		t.Errorf("generated %d unique ids, want %d", len(seen), workers*perWorker)
+	}
+}
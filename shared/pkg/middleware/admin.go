@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultAdminName identifies whoever holds the shared admin token
+const DefaultAdminName = "admin"
+
+const adminIdentityKey = "admin_identity"
+
+// AdminOnly admits requests carrying "Authorization: Bearer <token>". With no token
+// configured every request is refused, so admin routes are closed by default.
+func AdminOnly(token string) gin.HandlerFunc {
+	return AdminUsers(map[string]string{DefaultAdminName: token})
+}
+
+// AdminUsers admits requests carrying the bearer token of any of the named admins
+// and records which admin it was for AdminIdentity. Admins without a token are
+// ignored; with none left every request is refused.
+func AdminUsers(tokens map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		configured := false
+		for _, token := range tokens {
+			configured = configured || token != ""
+		}
+		if !configured {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "Admin API is disabled",
+			})
+			return
+		}
+
+		presented, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		identity := ""
+		for name, token := range tokens {
+			if ok && token != "" && subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+				identity = name
+			}
+		}
+		if identity == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "Admin credentials required",
+			})
+			return
+		}
+
+		c.Set(adminIdentityKey, identity)
+		c.Next()
+	}
+}
+
+// AdminIdentity is the name of the admin AdminOnly or AdminUsers authenticated, or
+// "" outside admin routes
+func AdminIdentity(c *gin.Context) string {
+	return c.GetString(adminIdentityKey)
+}
+
+// AdminUsersFromEnv reads ADMIN_API_USERS as a JSON object of admin name to bearer
+// token, e.g. {"alice@example.com": "..."}, so admin actions are attributed to a
+// person. A non-empty ADMIN_API_TOKEN is added as DefaultAdminName.
+func AdminUsersFromEnv() (map[string]string, error) {
+	tokens := make(map[string]string)
+	if raw := os.Getenv("ADMIN_API_USERS"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &tokens); err != nil {
+			return nil, fmt.Errorf("invalid ADMIN_API_USERS: %w", err)
+		}
+	}
+	if token := os.Getenv("ADMIN_API_TOKEN"); token != "" {
+		tokens[DefaultAdminName] = token
+	}
+	return tokens, nil
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{name: "valid token", token: "s3cret", authorization: "Bearer s3cret", wantStatus: http.StatusOK},
+		{name: "wrong token", token: "s3cret", authorization: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", token: "s3cret", authorization: "Basic s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "no credentials", token: "s3cret", wantStatus: http.StatusUnauthorized},
+		{name: "no token configured", token: "", authorization: "Bearer ", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/admin", AdminOnly(tt.token), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestAdminUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantIdentity  string
+	}{
+		{name: "first admin", authorization: "Bearer alice-token", wantStatus: http.StatusOK, wantIdentity: "alice"},
+		{name: "second admin", authorization: "Bearer bob-token", wantStatus: http.StatusOK, wantIdentity: "bob"},
+		{name: "disabled admin", authorization: "Bearer ", wantStatus: http.StatusUnauthorized},
+		{name: "unknown token", authorization: "Bearer mallory-token", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity := ""
+			router := gin.New()
+			router.POST("/admin", AdminUsers(map[string]string{"alice": "alice-token", "bob": "bob-token", "carol": ""}), func(c *gin.Context) {
+				identity = AdminIdentity(c)
+				c.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/admin", nil)
+			req.Header.Set("Authorization", tt.authorization)
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if identity != tt.wantIdentity {
+				t.Errorf("identity = %q, want %q", identity, tt.wantIdentity)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMaxBodyBytes is the largest request body accepted unless configured otherwise
+const DefaultMaxBodyBytes int64 = 1 << 20
+
+// MaxBodyBytesFromEnv reads MAX_REQUEST_BODY_BYTES, defaulting to DefaultMaxBodyBytes
+func MaxBodyBytesFromEnv() (int64, error) {
+	raw := os.Getenv("MAX_REQUEST_BODY_BYTES")
+	if raw == "" {
+		return DefaultMaxBodyBytes, nil
+	}
+	maxBytes, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || maxBytes <= 0 {
+		return 0, fmt.Errorf("MAX_REQUEST_BODY_BYTES must be a positive number of bytes, got %q", raw)
+	}
+	return maxBytes, nil
+}
+
+// BodyLimit refuses POST, PUT and PATCH requests whose body is larger than
+// maxBytes with 413. The body is read up front, so handlers never see more than
+// maxBytes of it whatever Content-Length claims.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength > maxBytes {
+			abortTooLarge(c, maxBytes)
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				abortTooLarge(c, maxBytes)
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Failed to read request body",
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.Next()
+	}
+}
+
+func abortTooLarge(c *gin.Context, maxBytes int64) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{
+		"error": fmt.Sprintf("Request body must not exceed %d bytes", maxBytes),
+	})
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(maxBytes int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimit(maxBytes))
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/entries", echo)
+	router.GET("/entries", echo)
+	return router
+}
+
+func TestBodyLimit(t *testing.T) {
+	router := newBodyLimitRouter(16)
+
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		chunked    bool
+		wantStatus int
+	}{
+		{name: "under the limit", method: http.MethodPost, body: `{"amount": 10}`, wantStatus: http.StatusOK},
+		{name: "at the limit", method: http.MethodPost, body: strings.Repeat("a", 16), wantStatus: http.StatusOK},
+		{name: "oversized", method: http.MethodPost, body: strings.Repeat("a", 17), wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "oversized without a length", method: http.MethodPost, body: strings.Repeat("a", 1024), chunked: true, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "GET is not limited", method: http.MethodGet, body: strings.Repeat("a", 1024), wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, "/entries", strings.NewReader(tt.body))
+			if tt.chunked {
+				req.ContentLength = -1
+			}
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != tt.body {
+				t.Errorf("handler read %q, want the whole body", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestMaxBodyBytesFromEnv(t *testing.T) {
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "")
+	if got, err := MaxBodyBytesFromEnv(); err != nil || got != DefaultMaxBodyBytes {
+		t.Errorf("MaxBodyBytesFromEnv() = %d, %v; want the default", got, err)
+	}
+
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "4096")
+	if got, err := MaxBodyBytesFromEnv(); err != nil || got != 4096 {
+		t.Errorf("MaxBodyBytesFromEnv() = %d, %v; want 4096", got, err)
+	}
+
+	t.Setenv("MAX_REQUEST_BODY_BYTES", "0")
+	if _, err := MaxBodyBytesFromEnv(); err == nil {
+		t.Error("MaxBodyBytesFromEnv() accepted 0")
+	}
+}
@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig lists what cross-origin browser requests may do. Origins are matched
+// exactly as scheme://host[:port]; "*" allows any origin but is never combined
+// with credentials.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// DefaultCORSConfig allows no origins, so browsers can't call the API from
+// another site until origins are configured
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete},
+		AllowedHeaders: []string{"Authorization", "Content-Type", "Idempotency-Key", "X-Request-ID"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// CORSConfigFromEnv reads CORS_CONFIG as JSON, e.g.
+// {"allowed_origins": ["https://checkout.example.com"], "allow_credentials": true, "max_age_seconds": 600},
+// then the comma-separated CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS and
+// CORS_ALLOWED_HEADERS. Anything left unset keeps its default.
+func CORSConfigFromEnv() (CORSConfig, error) {
+	config := DefaultCORSConfig()
+
+	if raw := os.Getenv("CORS_CONFIG"); raw != "" {
+		var data struct {
+			AllowedOrigins   []string `json:"allowed_origins"`
+			AllowedMethods   []string `json:"allowed_methods"`
+			AllowedHeaders   []string `json:"allowed_headers"`
+			AllowCredentials bool     `json:"allow_credentials"`
+			MaxAgeSeconds    int      `json:"max_age_seconds"`
+		}
+		if err := json.Unmarshal([]byte(raw), &data); err != nil {
+			return CORSConfig{}, fmt.Errorf("invalid CORS_CONFIG: %w", err)
+		}
+		config.AllowedOrigins = data.AllowedOrigins
+		if len(data.AllowedMethods) > 0 {
+			config.AllowedMethods = data.AllowedMethods
+		}
+		if len(data.AllowedHeaders) > 0 {
+			config.AllowedHeaders = data.AllowedHeaders
+		}
+		config.AllowCredentials = data.AllowCredentials
+		if data.MaxAgeSeconds > 0 {
+			config.MaxAge = time.Duration(data.MaxAgeSeconds) * time.Second
+		}
+	}
+	if raw := os.Getenv("CORS_ALLOWED_ORIGINS"); raw != "" {
+		config.AllowedOrigins = splitList(raw)
+	}
+	if raw := os.Getenv("CORS_ALLOWED_METHODS"); raw != "" {
+		config.AllowedMethods = splitList(raw)
+	}
+	if raw := os.Getenv("CORS_ALLOWED_HEADERS"); raw != "" {
+		config.AllowedHeaders = splitList(raw)
+	}
+
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" && config.AllowCredentials {
+			return CORSConfig{}, fmt.Errorf("CORS origin \"*\" cannot be combined with credentials")
+		}
+	}
+	return config, nil
+}
+
+// CORS answers preflight requests and adds CORS headers for allowed origins only.
+// Requests from other origins get no CORS headers, so browsers block the response;
+// their preflights are refused with 403.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	origins := make(map[string]bool, len(config.AllowedOrigins))
+	anyOrigin := false
+	for _, origin := range config.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+		origins[normalizeOrigin(origin)] = true
+	}
+	methods := make(map[string]bool, len(config.AllowedMethods))
+	for _, method := range config.AllowedMethods {
+		methods[strings.ToUpper(method)] = true
+	}
+	headers := make(map[string]bool, len(config.AllowedHeaders))
+	for _, header := range config.AllowedHeaders {
+		headers[http.CanonicalHeaderKey(header)] = true
+	}
+
+	allowMethods := strings.Join(config.AllowedMethods, ", ")
+	allowHeaders := strings.Join(config.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !anyOrigin && !origins[normalizeOrigin(origin)] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if config.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if !preflight {
+			c.Next()
+			return
+		}
+
+		if !methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		for _, header := range splitList(c.GetHeader("Access-Control-Request-Headers")) {
+			if !headers[http.CanonicalHeaderKey(header)] {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+		}
+
+		c.Header("Access-Control-Allow-Methods", allowMethods)
+		c.Header("Access-Control-Allow-Headers", allowHeaders)
+		c.Header("Access-Control-Max-Age", maxAge)
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// normalizeOrigin lower-cases an origin and drops any trailing slash
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
+
+func splitList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
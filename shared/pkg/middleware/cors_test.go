@@ -0,0 +1,142 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(config CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config))
+	router.GET("/payments", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/payments", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func checkoutCORSConfig() CORSConfig {
+	config := DefaultCORSConfig()
+	config.AllowedOrigins = []string{"https://checkout.example.com"}
+	config.AllowCredentials = true
+	return config
+}
+
+func TestCORSAllowedOrigin(t *testing.T) {
+	router := newCORSRouter(checkoutCORSConfig())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+	req.Header.Set("Origin", "https://checkout.example.com")
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://checkout.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want the request origin", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want true", got)
+	}
+	if got := rec.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Vary = %q, want Origin", got)
+	}
+}
+
+func TestCORSDisallowedOrigin(t *testing.T) {
+	router := newCORSRouter(checkoutCORSConfig())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter(checkoutCORSConfig())
+
+	tests := []struct {
+		name        string
+		origin      string
+		method      string
+		headers     string
+		wantStatus  int
+		wantAllowed bool
+	}{
+		{name: "allowed", origin: "https://checkout.example.com", method: "POST", headers: "content-type, idempotency-key", wantStatus: http.StatusNoContent, wantAllowed: true},
+		{name: "disallowed origin", origin: "https://evil.example.com", method: "POST", wantStatus: http.StatusForbidden},
+		{name: "disallowed method", origin: "https://checkout.example.com", method: "PATCH", wantStatus: http.StatusForbidden},
+		{name: "disallowed header", origin: "https://checkout.example.com", method: "POST", headers: "X-Internal-Token", wantStatus: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodOptions, "/payments", nil)
+			req.Header.Set("Origin", tt.origin)
+			req.Header.Set("Access-Control-Request-Method", tt.method)
+			if tt.headers != "" {
+				req.Header.Set("Access-Control-Request-Headers", tt.headers)
+			}
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			allowed := rec.Header().Get("Access-Control-Allow-Methods") != ""
+			if allowed != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Methods = %q, want allowed %v", rec.Header().Get("Access-Control-Allow-Methods"), tt.wantAllowed)
+			}
+			if tt.wantAllowed && rec.Header().Get("Access-Control-Max-Age") != "600" {
+				t.Errorf("Access-Control-Max-Age = %q, want 600", rec.Header().Get("Access-Control-Max-Age"))
+			}
+		})
+	}
+}
+
+func TestCORSDefaultAllowsNoOrigins(t *testing.T) {
+	router := newCORSRouter(DefaultCORSConfig())
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/payments", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none by default", got)
+	}
+}
+
+func TestCORSConfigFromEnv(t *testing.T) {
+	t.Setenv("CORS_CONFIG", `{"allowed_origins": ["https://a.example.com"], "allow_credentials": true, "max_age_seconds": 60}`)
+	t.Setenv("CORS_ALLOWED_METHODS", "GET, POST")
+
+	config, err := CORSConfigFromEnv()
+	if err != nil {
+		t.Fatalf("CORSConfigFromEnv() error = %v", err)
+	}
+	if len(config.AllowedOrigins) != 1 || !config.AllowCredentials || config.MaxAge.Seconds() != 60 {
+		t.Errorf("config = %+v, want the JSON origins, credentials and max age", config)
+	}
+	if len(config.AllowedMethods) != 2 || config.AllowedMethods[1] != "POST" {
+		t.Errorf("AllowedMethods = %v, want GET and POST", config.AllowedMethods)
+	}
+	if len(config.AllowedHeaders) == 0 {
+		t.Error("AllowedHeaders is empty, want the defaults")
+	}
+
+	t.Setenv("CORS_CONFIG", `{"allowed_origins": ["*"], "allow_credentials": true}`)
+	if _, err := CORSConfigFromEnv(); err == nil {
+		t.Error("CORSConfigFromEnv() accepted \"*\" with credentials")
+	}
+}
@@ -2,11 +2,15 @@
 package middleware
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
+
+	"shared/pkg/ids"
 )
 
 // RequestID adds a unique request ID to each request
@@ -14,7 +18,7 @@ func RequestID() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
 		if requestID == "" {
-			requestID = uuid.New().String()
+			requestID = ids.New()
 		}
 		c.Set("request_id", requestID)
 		c.Header("X-Request-ID", requestID)
@@ -49,7 +53,14 @@ func Logger(logger *zap.Logger) gin.HandlerFunc {
 	}
 }
 
-// Recovery recovers from panics
+var panicsRecovered = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "http_panics_recovered_total",
+	Help: "Handler panics recovered by the Recovery middleware, by route.",
+}, []string{"route"})
+
+// Recovery recovers from panics, answering 500 with the request ID so the failure
+// can be found in the logs. If the handler had already started its response, the
+// connection is left as it is.
 func Recovery(logger *zap.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
@@ -59,36 +70,18 @@ func Recovery(logger *zap.Logger) gin.HandlerFunc {
 					zap.String("request_id", requestID),
 					zap.Any("error", err),
 				)
-				c.AbortWithStatusJSON(500, gin.H{
-					"error": "Internal server error",
+				panicsRecovered.WithLabelValues(c.FullPath()).Inc()
+
+				if c.Writer.Written() {
+					c.Abort()
+					return
+				}
+				c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+					"error":      "internal error",
+					"request_id": requestID,
 				})
 			}
 		}()
 		c.Next()
 	}
 }
-
-// CORS handles Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
-
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
-			return
-		}
-
-		c.Next()
-	}
-}
-
-// RateLimiter implements basic rate limiting (simplified)
-func RateLimiter() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// In production, use Redis-backed rate limiter
-		c.Next()
-	}
-}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.uber.org/zap"
+)
+
+func TestRecoveryWritesJSONError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.Use(Recovery(zap.NewNop()))
+	router.GET("/boom", func(c *gin.Context) { panic("boom") })
+
+	before := testutil.ToFloat64(panicsRecovered.WithLabelValues("/boom"))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/boom", nil)
+	req.Header.Set("X-Request-ID", "req_123")
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body %q is not JSON: %v", rec.Body.String(), err)
+	}
+	if body["error"] != "internal error" || body["request_id"] != "req_123" {
+		t.Errorf("body = %v, want internal error with request_id req_123", body)
+	}
+	if got := testutil.ToFloat64(panicsRecovered.WithLabelValues("/boom")); got != before+1 {
+		t.Errorf("http_panics_recovered_total{route=/boom} = %v, want %v", got, before+1)
+	}
+}
+
+func TestRecoveryAfterResponseStarted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Recovery(zap.NewNop()))
+	router.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("boom")
+	})
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/partial", nil))
+
+	if rec.Code != http.StatusOK || rec.Body.String() != "partial" {
+		t.Errorf("response = %d %q, want the handler's partial response left alone", rec.Code, rec.Body.String())
+	}
+}
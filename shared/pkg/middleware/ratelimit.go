@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimit is a token bucket: it refills at Rate tokens per second up to Burst,
+// and each request takes one token
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// TokenBucket takes a token from the bucket for key, reporting whether one was
+// available and how many tokens are left afterwards
+type TokenBucket interface {
+	Take(ctx context.Context, key string, limit RateLimit, now time.Time) (bool, float64, error)
+}
+
+// RateLimiter limits each client IP with a token bucket, so short bursts up to
+// limit.Burst are allowed while the sustained rate is held to limit.Rate. A zero
+// rate disables limiting. If the bucket store fails, requests are let through.
+func RateLimiter(bucket TokenBucket, limit RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit.Rate <= 0 || limit.Burst <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, tokens, err := bucket.Take(c.Request.Context(), "ratelimit:"+c.ClientIP(), limit, time.Now())
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		c.Header("X-RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(int(math.Floor(tokens))))
+		c.Header("X-RateLimit-Reset", strconv.Itoa(secondsUntil(float64(limit.Burst)-tokens, limit.Rate)))
+
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(secondsUntil(1-tokens, limit.Rate)))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error": "Rate limit exceeded",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// secondsUntil is how many whole seconds it takes to refill the given number of tokens
+func secondsUntil(tokens, rate float64) int {
+	if tokens <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / rate))
+}
+
+// refill tops up a bucket for the time elapsed since it was last updated, then
+// takes one token if there is one
+func refill(tokens float64, last, now time.Time, limit RateLimit) (bool, float64) {
+	if elapsed := now.Sub(last).Seconds(); elapsed > 0 {
+		tokens = math.Min(float64(limit.Burst), tokens+elapsed*limit.Rate)
+	}
+	if tokens < 1 {
+		return false, tokens
+	}
+	return true, tokens - 1
+}
+
+// tokenBucketScript is refill as a Redis script, so concurrent requests across
+// instances take tokens atomically. State is a hash of tokens and the update
+// time in milliseconds; it expires once the bucket would be full again.
+const tokenBucketScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+if now > ts then
+	tokens = math.min(burst, tokens + (now - ts) * rate / 1000)
+	ts = now
+end
+
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', ts)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * 1000 / rate) + 1000)
+return {allowed, tostring(tokens)}
+`
+
+// ScriptEvaluator runs a Lua script; implemented by the shared Redis client
+type ScriptEvaluator interface {
+	Eval(ctx context.Context, script string, keys []string, args ...interface{}) (interface{}, error)
+}
+
+// RedisTokenBucket keeps buckets in Redis so every instance shares one limit
+type RedisTokenBucket struct {
+	redis ScriptEvaluator
+}
+
+// NewRedisTokenBucket creates a token bucket store backed by Redis
+func NewRedisTokenBucket(redis ScriptEvaluator) *RedisTokenBucket {
+	return &RedisTokenBucket{redis: redis}
+}
+
+// Take runs the token bucket script for key
+func (b *RedisTokenBucket) Take(ctx context.Context, key string, limit RateLimit, now time.Time) (bool, float64, error) {
+	result, err := b.redis.Eval(ctx, tokenBucketScript, []string{key}, limit.Rate, limit.Burst, now.UnixMilli())
+	if err != nil {
+		return false, 0, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected token bucket result %v", result)
+	}
+	allowed, _ := values[0].(int64)
+	raw, _ := values[1].(string)
+	tokens, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return false, 0, fmt.Errorf("unexpected token count %v: %w", values[1], err)
+	}
+
+	return allowed == 1, tokens, nil
+}
+
+// MemoryTokenBucket keeps buckets in process memory, for single-instance use and tests
+type MemoryTokenBucket struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewMemoryTokenBucket creates an in-memory token bucket store
+func NewMemoryTokenBucket() *MemoryTokenBucket {
+	return &MemoryTokenBucket{buckets: make(map[string]*memoryBucket)}
+}
+
+// Take takes a token from the in-memory bucket for key
+func (b *MemoryTokenBucket) Take(ctx context.Context, key string, limit RateLimit, now time.Time) (bool, float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bucket, ok := b.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(limit.Burst), updated: now}
+		b.buckets[key] = bucket
+	}
+
+	allowed, tokens := refill(bucket.tokens, bucket.updated, now, limit)
+	bucket.tokens = tokens
+	if now.After(bucket.updated) {
+		bucket.updated = now
+	}
+	return allowed, tokens, nil
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMemoryTokenBucketBurstAndRefill(t *testing.T) {
+	bucket := NewMemoryTokenBucket()
+	limit := RateLimit{Rate: 2, Burst: 3}
+	start := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	// A full bucket absorbs a burst of three requests
+	for i := 0; i < 3; i++ {
+		allowed, tokens, _ := bucket.Take(ctx, "client", limit, start)
+		if !allowed {
+			t.Fatalf("request %d in burst rejected", i+1)
+		}
+		if want := float64(2 - i); tokens != want {
+			t.Errorf("request %d left %v tokens, want %v", i+1, tokens, want)
+		}
+	}
+	if allowed, _, _ := bucket.Take(ctx, "client", limit, start); allowed {
+		t.Error("request beyond the burst should be rejected")
+	}
+
+	// Half a second refills one token at 2 per second
+	if allowed, _, _ := bucket.Take(ctx, "client", limit, start.Add(500*time.Millisecond)); !allowed {
+		t.Error("request after refill should be allowed")
+	}
+	if allowed, _, _ := bucket.Take(ctx, "client", limit, start.Add(500*time.Millisecond)); allowed {
+		t.Error("refilled token should already be spent")
+	}
+
+	// Refill stops at the burst size
+	_, tokens, _ := bucket.Take(ctx, "client", limit, start.Add(time.Minute))
+	if tokens != 2 {
+		t.Errorf("tokens after long idle = %v, want 2", tokens)
+	}
+
+	// Buckets are per key
+	if allowed, _, _ := bucket.Take(ctx, "other", limit, start); !allowed {
+		t.Error("another client's bucket should be full")
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimiter(NewMemoryTokenBucket(), RateLimit{Rate: 1, Burst: 2}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		wantStatus    int
+		wantRemaining string
+		wantReset     string
+	}{
+		{http.StatusOK, "1", "1"},
+		{http.StatusOK, "0", "2"},
+		{http.StatusTooManyRequests, "0", "2"},
+	}
+
+	for i, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "203.0.113.7:4000"
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.wantStatus {
+			t.Errorf("request %d status = %d, want %d", i+1, rec.Code, tt.wantStatus)
+		}
+		if got := rec.Header().Get("X-RateLimit-Remaining"); got != tt.wantRemaining {
+			t.Errorf("request %d X-RateLimit-Remaining = %q, want %q", i+1, got, tt.wantRemaining)
+		}
+		if got := rec.Header().Get("X-RateLimit-Reset"); got != tt.wantReset {
+			t.Errorf("request %d X-RateLimit-Reset = %q, want %q", i+1, got, tt.wantReset)
+		}
+		if tt.wantStatus == http.StatusTooManyRequests && rec.Header().Get("Retry-After") != "1" {
+			t.Errorf("request %d Retry-After = %q, want 1", i+1, rec.Header().Get("Retry-After"))
+		}
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimiter(NewMemoryTokenBucket(), RateLimit{}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 5; i++ {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200 with limiting disabled", i+1, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// DefaultRequestTimeout bounds ordinary routes unless configured otherwise
+	DefaultRequestTimeout = 10 * time.Second
+	// DefaultLongRequestTimeout bounds reconciliation and export routes unless configured otherwise
+	DefaultLongRequestTimeout = 5 * time.Minute
+)
+
+// RequestTimeouts are the deadlines applied to ordinary and long-running routes
+type RequestTimeouts struct {
+	Default time.Duration
+	Long    time.Duration
+}
+
+// RequestTimeoutsFromEnv reads REQUEST_TIMEOUT and LONG_REQUEST_TIMEOUT as Go
+// durations, e.g. "10s" or "5m", defaulting to DefaultRequestTimeout and
+// DefaultLongRequestTimeout
+func RequestTimeoutsFromEnv() (RequestTimeouts, error) {
+	timeouts := RequestTimeouts{Default: DefaultRequestTimeout, Long: DefaultLongRequestTimeout}
+
+	for _, setting := range []struct {
+		key    string
+		target *time.Duration
+	}{
+		{"REQUEST_TIMEOUT", &timeouts.Default},
+		{"LONG_REQUEST_TIMEOUT", &timeouts.Long},
+	} {
+		raw := os.Getenv(setting.key)
+		if raw == "" {
+			continue
+		}
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			return timeouts, fmt.Errorf("%s must be a positive duration, got %q", setting.key, raw)
+		}
+		*setting.target = timeout
+	}
+
+	if timeouts.Long < timeouts.Default {
+		return timeouts, fmt.Errorf("LONG_REQUEST_TIMEOUT (%s) must not be shorter than REQUEST_TIMEOUT (%s)", timeouts.Long, timeouts.Default)
+	}
+	return timeouts, nil
+}
+
+// Timeout gives the request context a deadline of timeout and responds 504 if it
+// passes before the handler has started its response; anything the handler writes
+// after that is discarded. A response already under way, such as a streamed export,
+// is left to end when its context is cancelled. Handlers run on the request's own
+// goroutine, so one that ignores its context delays the 504 until it returns.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if writer.expired() {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
+				"error": "Request timed out",
+			})
+		}
+	}
+}
+
+// timeoutWriter drops a handler's response if its deadline passes before the
+// response has started
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+// expired reports whether the deadline passed before anything was written
+func (w *timeoutWriter) expired() bool {
+	if !w.timedOut && !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+	}
+	return w.timedOut
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+	if w.expired() {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
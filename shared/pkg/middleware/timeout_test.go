@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTimeoutRouter(timeout time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Timeout(timeout))
+	router.GET("/fast", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	// slow waits on its context like a database query would, then reports the failure
+	router.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+		case <-time.After(time.Second):
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		}
+	})
+	// streamed starts its response before the deadline, so keeps writing to it
+	router.GET("/streamed", func(c *gin.Context) {
+		c.String(http.StatusOK, "header\n")
+		c.Writer.Flush()
+		<-c.Request.Context().Done()
+		c.String(http.StatusOK, "late row\n")
+	})
+	return router
+}
+
+func TestTimeout(t *testing.T) {
+	router := newTimeoutRouter(20 * time.Millisecond)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "fast handler", path: "/fast", wantStatus: http.StatusOK, wantBody: `{"status":"ok"}`},
+		{name: "slow handler", path: "/slow", wantStatus: http.StatusGatewayTimeout, wantBody: `{"error":"Request timed out"}`},
+		{name: "response already started", path: "/streamed", wantStatus: http.StatusOK, wantBody: "header\nlate row\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", rec.Body.String(), tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestRequestTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("REQUEST_TIMEOUT", "")
+	t.Setenv("LONG_REQUEST_TIMEOUT", "")
+	got, err := RequestTimeoutsFromEnv()
+	if err != nil || got != (RequestTimeouts{Default: DefaultRequestTimeout, Long: DefaultLongRequestTimeout}) {
+		t.Errorf("RequestTimeoutsFromEnv() = %+v, %v; want the defaults", got, err)
+	}
+
+	t.Setenv("REQUEST_TIMEOUT", "3s")
+	t.Setenv("LONG_REQUEST_TIMEOUT", "10m")
+	got, err = RequestTimeoutsFromEnv()
+	if err != nil || got != (RequestTimeouts{Default: 3 * time.Second, Long: 10 * time.Minute}) {
+		t.Errorf("RequestTimeoutsFromEnv() = %+v, %v; want 3s and 10m", got, err)
+	}
+
+	for _, tt := range []struct{ timeout, long string }{
+		{"0s", "10m"},
+		{"ten seconds", "10m"},
+		{"30s", "10s"},
+	} {
+		t.Setenv("REQUEST_TIMEOUT", tt.timeout)
+		t.Setenv("LONG_REQUEST_TIMEOUT", tt.long)
+		if _, err := RequestTimeoutsFromEnv(); err == nil {
+			t.Errorf("RequestTimeoutsFromEnv() accepted REQUEST_TIMEOUT=%q LONG_REQUEST_TIMEOUT=%q", tt.timeout, tt.long)
+		}
+	}
+}
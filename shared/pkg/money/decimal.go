@@ -0,0 +1,234 @@
+// shared/pkg/money/decimal.go
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Decimal is an exact decimal number, for values such as exchange rates where
+// float64 drift compounds. The zero value is 0. Decimals are immutable; every
+// operation returns a new value.
+type Decimal struct {
+	rat *big.Rat
+}
+
+// NewDecimal converts f to the shortest decimal that reads back as f, so 0.92
+// becomes exactly 0.92 rather than its binary approximation
+func NewDecimal(f float64) Decimal {
+	d, _ := ParseDecimal(strconv.FormatFloat(f, 'f', -1, 64))
+	return d
+}
+
+// ParseDecimal parses a decimal string such as "1.0869565217"
+func ParseDecimal(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Decimal{}, fmt.Errorf("invalid decimal %q", s)
+	}
+	return Decimal{rat: rat}, nil
+}
+
+func (d Decimal) value() *big.Rat {
+	if d.rat == nil {
+		return new(big.Rat)
+	}
+	return d.rat
+}
+
+// Add returns d + o
+func (d Decimal) Add(o Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Add(d.value(), o.value())}
+}
+
+// Sub returns d - o
+func (d Decimal) Sub(o Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Sub(d.value(), o.value())}
+}
+
+// Mul returns d * o
+func (d Decimal) Mul(o Decimal) Decimal {
+	return Decimal{rat: new(big.Rat).Mul(d.value(), o.value())}
+}
+
+// Div returns d / o rounded to places decimal places. o must not be zero.
+func (d Decimal) Div(o Decimal, places int) Decimal {
+	return Decimal{rat: new(big.Rat).Quo(d.value(), o.value())}.Round(places)
+}
+
+// RoundingMode selects how a value between two representable amounts is rounded
+type RoundingMode string
+
+const (
+	// RoundNearest rounds to the nearest value, halves away from zero
+	RoundNearest RoundingMode = "nearest"
+	// RoundFloor rounds towards negative infinity
+	RoundFloor RoundingMode = "floor"
+	// RoundCeil rounds towards positive infinity
+	RoundCeil RoundingMode = "ceil"
+	// RoundBanker rounds to the nearest value, halves to the even neighbour
+	RoundBanker RoundingMode = "banker"
+)
+
+// Valid reports whether m is one of the known rounding modes
+func (m RoundingMode) Valid() bool {
+	switch m {
+	case RoundNearest, RoundFloor, RoundCeil, RoundBanker:
+		return true
+	}
+	return false
+}
+
+// Round rounds to places decimal places, halves away from zero
+func (d Decimal) Round(places int) Decimal {
+	return d.RoundMode(places, RoundNearest)
+}
+
+// RoundMode rounds to places decimal places using mode
+func (d Decimal) RoundMode(places int, mode RoundingMode) Decimal {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	scaled := new(big.Rat).Mul(d.value(), new(big.Rat).SetInt(scale))
+
+	// QuoRem truncates towards zero, so quotient is the candidate nearer zero
+	quotient, remainder := new(big.Int).QuoRem(scaled.Num(), scaled.Denom(), new(big.Int))
+	if remainder.Sign() != 0 {
+		away := false
+		switch mode {
+		case RoundFloor:
+			away = scaled.Sign() < 0
+		case RoundCeil:
+			away = scaled.Sign() > 0
+		default:
+			twiceRemainder := new(big.Int).Lsh(new(big.Int).Abs(remainder), 1)
+			switch twiceRemainder.Cmp(scaled.Denom()) {
+			case 1:
+				away = true
+			case 0:
+				away = mode != RoundBanker || quotient.Bit(0) == 1
+			}
+		}
+
+		if away {
+			if scaled.Sign() < 0 {
+				quotient.Sub(quotient, big.NewInt(1))
+			} else {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+
+	return Decimal{rat: new(big.Rat).SetFrac(quotient, scale)}
+}
+
+// Cmp compares d and o, returning -1, 0 or +1
+func (d Decimal) Cmp(o Decimal) int {
+	return d.value().Cmp(o.value())
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of d
+func (d Decimal) Sign() int {
+	return d.value().Sign()
+}
+
+// Float64 returns the nearest float64 to d
+func (d Decimal) Float64() float64 {
+	f, _ := d.value().Float64()
+	return f
+}
+
+// maxStringPlaces bounds String for the rare value with no finite decimal form
+const maxStringPlaces = 30
+
+// String formats d with exactly as many decimal places as it needs
+func (d Decimal) String() string {
+	return d.value().FloatString(decimalPlaces(d.value()))
+}
+
+// StringFixed formats d rounded to exactly places decimal places
+func (d Decimal) StringFixed(places int) string {
+	return d.Round(places).value().FloatString(places)
+}
+
+// decimalPlaces is the number of places needed to write rat exactly: a fraction
+// terminates when its denominator has no prime factors other than 2 and 5
+func decimalPlaces(rat *big.Rat) int {
+	denom := new(big.Int).Set(rat.Denom())
+	two, five := big.NewInt(2), big.NewInt(5)
+	var twos, fives int
+	for new(big.Int).Mod(denom, two).Sign() == 0 {
+		denom.Quo(denom, two)
+		twos++
+	}
+	for new(big.Int).Mod(denom, five).Sign() == 0 {
+		denom.Quo(denom, five)
+		fives++
+	}
+
+	places := twos
+	if fives > places {
+		places = fives
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 || places > maxStringPlaces {
+		return maxStringPlaces
+	}
+	return places
+}
+
+// MarshalJSON writes d as a JSON number without going through float64
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON reads a JSON number or numeric string
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	parsed, err := ParseDecimal(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores d as its exact decimal string, for NUMERIC columns
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// Scan reads a NUMERIC column
+func (d *Decimal) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+	case []byte:
+		return d.scanString(string(v))
+	case string:
+		return d.scanString(v)
+	case float64:
+		*d = NewDecimal(v)
+	case int64:
+		*d = Decimal{rat: new(big.Rat).SetInt64(v)}
+	default:
+		return fmt.Errorf("cannot scan %T into Decimal", src)
+	}
+	return nil
+}
+
+func (d *Decimal) scanString(s string) error {
+	parsed, err := ParseDecimal(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
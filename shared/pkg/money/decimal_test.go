@@ -0,0 +1,137 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustDecimal(t *testing.T, s string) Decimal {
+	t.Helper()
+	d, err := ParseDecimal(s)
+	if err != nil {
+		t.Fatalf("ParseDecimal(%q) error = %v", s, err)
+	}
+	return d
+}
+
+func TestDecimalArithmeticIsExact(t *testing.T) {
+	// 0.1 + 0.2 is exactly 0.3, unlike with float64
+	if got := NewDecimal(0.1).Add(NewDecimal(0.2)).String(); got != "0.3" {
+		t.Errorf("0.1 + 0.2 = %s, want 0.3", got)
+	}
+
+	amount := mustDecimal(t, "1000000")
+	rate := mustDecimal(t, "0.9212345678")
+	if got := amount.Mul(rate).String(); got != "921234.5678" {
+		t.Errorf("1000000 * 0.9212345678 = %s, want 921234.5678", got)
+	}
+	if got := mustDecimal(t, "921234.5678").Sub(mustDecimal(t, "4606.172839")).String(); got != "916628.394961" {
+		t.Errorf("subtraction = %s, want 916628.394961", got)
+	}
+}
+
+func TestDecimalRound(t *testing.T) {
+	tests := []struct {
+		value  string
+		places int
+		want   string
+	}{
+		{"1.005", 2, "1.01"},
+		{"1.004999", 2, "1"},
+		{"-1.005", 2, "-1.01"},
+		{"2.5", 0, "3"},
+		{"1234.5678", 0, "1235"},
+		{"0.33333333333", 10, "0.3333333333"},
+	}
+
+	for _, tt := range tests {
+		if got := mustDecimal(t, tt.value).Round(tt.places).String(); got != tt.want {
+			t.Errorf("Round(%s, %d) = %s, want %s", tt.value, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalDiv(t *testing.T) {
+	if got := NewDecimal(1).Div(NewDecimal(0.92), 10).String(); got != "1.0869565217" {
+		t.Errorf("1 / 0.92 = %s, want 1.0869565217", got)
+	}
+	if got := NewDecimal(1).Div(NewDecimal(3), 10).String(); got != "0.3333333333" {
+		t.Errorf("1 / 3 = %s, want 0.3333333333", got)
+	}
+}
+
+func TestDecimalStringFixed(t *testing.T) {
+	if got := NewDecimal(0.92).StringFixed(10); got != "0.9200000000" {
+		t.Errorf("StringFixed(10) = %s, want 0.9200000000", got)
+	}
+	if got := mustDecimal(t, "1.23456789015").StringFixed(10); got != "1.2345678902" {
+		t.Errorf("StringFixed(10) = %s, want 1.2345678902", got)
+	}
+}
+
+func TestDecimalJSONRoundTrip(t *testing.T) {
+	var decoded struct {
+		Rate Decimal `json:"rate"`
+	}
+	if err := json.Unmarshal([]byte(`{"rate": 0.1234567891}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if got := decoded.Rate.String(); got != "0.1234567891" {
+		t.Errorf("decoded rate = %s, want 0.1234567891", got)
+	}
+
+	data, err := json.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"rate":0.1234567891}` {
+		t.Errorf("encoded = %s, want the rate as an exact JSON number", data)
+	}
+
+	if err := json.Unmarshal([]byte(`{"rate": "1.5"}`), &decoded); err != nil || decoded.Rate.String() != "1.5" {
+		t.Errorf("quoted rate decoded as %s, %v; want 1.5", decoded.Rate, err)
+	}
+}
+
+func TestDecimalScan(t *testing.T) {
+	var d Decimal
+	if err := d.Scan([]byte("149.5000000000")); err != nil || d.String() != "149.5" {
+		t.Errorf("Scan(bytes) = %s, %v; want 149.5", d, err)
+	}
+	if err := d.Scan(int64(3)); err != nil || d.String() != "3" {
+		t.Errorf("Scan(int64) = %s, %v; want 3", d, err)
+	}
+	if err := d.Scan(true); err == nil {
+		t.Error("Scan(bool) should fail")
+	}
+}
+
+func TestDecimalRoundMode(t *testing.T) {
+	tests := []struct {
+		value string
+		mode  RoundingMode
+		want  string
+	}{
+		{"2.345", RoundNearest, "2.35"},
+		{"2.345", RoundBanker, "2.34"},
+		{"2.355", RoundBanker, "2.36"},
+		{"2.341", RoundCeil, "2.35"},
+		{"2.349", RoundFloor, "2.34"},
+		{"-2.345", RoundNearest, "-2.35"},
+		{"-2.345", RoundBanker, "-2.34"},
+		{"-2.341", RoundFloor, "-2.35"},
+		{"-2.349", RoundCeil, "-2.34"},
+		{"2.34", RoundCeil, "2.34"},
+		{"2.3451", RoundBanker, "2.35"},
+	}
+
+	for _, tt := range tests {
+		if got := mustDecimal(t, tt.value).RoundMode(2, tt.mode).String(); got != tt.want {
+			t.Errorf("RoundMode(%s, %s) = %s, want %s", tt.value, tt.mode, got, tt.want)
+		}
+	}
+
+	if RoundingMode("up").Valid() || !RoundBanker.Valid() {
+		t.Error("Valid() should accept only the known modes")
+	}
+}
@@ -0,0 +1,81 @@
+// shared/pkg/money/money.go
+package money
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxAmount is the largest amount, in major units, any request may carry
+const MaxAmount = 1_000_000_000
+
+var (
+	ErrAmountTooPrecise = errors.New("amount has more decimal places than the currency allows")
+	ErrAmountOutOfRange = errors.New("amount out of range")
+	ErrInvalidRounding  = errors.New("rounding mode must be nearest, floor, ceil or banker")
+)
+
+// exponents lists the currencies whose minor unit is not a hundredth, per ISO 4217
+// and Stripe's currency docs; every other currency has two decimal places
+var exponents = map[string]int{
+	// Zero-decimal currencies
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "JPY": 0, "KMF": 0, "KRW": 0, "MGA": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	// Three-decimal currencies
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+}
+
+// Exponent is the number of decimal places in the currency's minor unit
+func Exponent(currency string) int {
+	if exponent, ok := exponents[strings.ToUpper(currency)]; ok {
+		return exponent
+	}
+	return 2
+}
+
+// NormalizeCurrency trims and uppercases a currency code, so "usd" and " USD "
+// name the same currency
+func NormalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
+
+// FieldError is a validation failure on one request field
+type FieldError struct {
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// ValidateAmount checks that amount is positive, at most MaxAmount and has no more
+// decimal places than currency allows. Failures are returned as a *FieldError for field.
+func ValidateAmount(field string, amount float64, currency string) error {
+	if math.IsNaN(amount) || amount <= 0 || amount > MaxAmount {
+		return &FieldError{Field: field, Err: fmt.Errorf("%w: must be greater than 0 and at most %d", ErrAmountOutOfRange, MaxAmount)}
+	}
+
+	exponent := Exponent(currency)
+	if decimals(amount) > exponent {
+		return &FieldError{Field: field, Err: fmt.Errorf("%w: %s allows %d", ErrAmountTooPrecise, strings.ToUpper(currency), exponent)}
+	}
+	return nil
+}
+
+// decimals counts the decimal places in the shortest representation of amount,
+// so 19.99 has 2 but the result of 0.1 + 0.2 has 17
+func decimals(amount float64) int {
+	formatted := strconv.FormatFloat(amount, 'f', -1, 64)
+	if dot := strings.IndexByte(formatted, '.'); dot >= 0 {
+		return len(formatted) - dot - 1
+	}
+	return 0
+}
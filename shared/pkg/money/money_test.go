@@ -0,0 +1,59 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateAmount(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		currency string
+		wantErr  error
+	}{
+		{name: "USD cents", amount: 19.99, currency: "USD"},
+		{name: "USD whole", amount: 20, currency: "USD"},
+		{name: "USD sub-cent", amount: 19.999, currency: "USD", wantErr: ErrAmountTooPrecise},
+		{name: "USD float artifact", amount: 0.30000000000000004, currency: "USD", wantErr: ErrAmountTooPrecise},
+		{name: "JPY whole", amount: 1000, currency: "JPY"},
+		{name: "JPY fractional", amount: 1000.5, currency: "jpy", wantErr: ErrAmountTooPrecise},
+		{name: "KRW fractional", amount: 0.01, currency: "KRW", wantErr: ErrAmountTooPrecise},
+		{name: "KWD thousandths", amount: 12.345, currency: "KWD"},
+		{name: "KWD ten-thousandths", amount: 12.3456, currency: "KWD", wantErr: ErrAmountTooPrecise},
+		{name: "Zero", amount: 0, currency: "USD", wantErr: ErrAmountOutOfRange},
+		{name: "Negative", amount: -5, currency: "USD", wantErr: ErrAmountOutOfRange},
+		{name: "Too large", amount: MaxAmount + 1, currency: "USD", wantErr: ErrAmountOutOfRange},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAmount("amount", tt.amount, tt.currency)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateAmount(%v, %s) error = %v, want %v", tt.amount, tt.currency, err, tt.wantErr)
+			}
+
+			var fieldErr *FieldError
+			if err != nil && (!errors.As(err, &fieldErr) || fieldErr.Field != "amount") {
+				t.Errorf("error = %#v, want a FieldError for amount", err)
+			}
+		})
+	}
+}
+
+func TestExponent(t *testing.T) {
+	tests := map[string]int{"USD": 2, "eur": 2, "JPY": 0, "krw": 0, "BHD": 3}
+	for currency, want := range tests {
+		if got := Exponent(currency); got != want {
+			t.Errorf("Exponent(%s) = %d, want %d", currency, got, want)
+		}
+	}
+}
+
+func TestNormalizeCurrency(t *testing.T) {
+	for _, currency := range []string{"usd", "Usd", "USD", " usd\t"} {
+		if got := NormalizeCurrency(currency); got != "USD" {
+			t.Errorf("NormalizeCurrency(%q) = %q, want USD", currency, got)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+// shared/pkg/pagination/pagination.go
+package pagination
+
+// Page is the response body every list endpoint returns: one page of items and
+// enough about the full result set for a client to fetch the next one
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"has_more"`
+}
+
+// NewPage builds the page of items found at offset, out of total matches. Items
+// is never nil, so an empty page encodes as [] rather than null.
+func NewPage[T any](items []T, total, limit, offset int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{
+		Items:   items,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(items) < total,
+	}
+}
@@ -0,0 +1,48 @@
+package pagination
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewPageHasMore(t *testing.T) {
+	tests := []struct {
+		name        string
+		items       int
+		total       int
+		limit       int
+		offset      int
+		wantHasMore bool
+	}{
+		{name: "First of several pages", items: 10, total: 25, limit: 10, offset: 0, wantHasMore: true},
+		{name: "Page ends one before the last item", items: 10, total: 21, limit: 10, offset: 10, wantHasMore: true},
+		{name: "Page ends exactly on the last item", items: 10, total: 20, limit: 10, offset: 10, wantHasMore: false},
+		{name: "Short last page", items: 5, total: 25, limit: 10, offset: 20, wantHasMore: false},
+		{name: "Offset past the end", items: 0, total: 25, limit: 10, offset: 30, wantHasMore: false},
+		{name: "No matches", items: 0, total: 0, limit: 10, offset: 0, wantHasMore: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := NewPage(make([]int, tt.items), tt.total, tt.limit, tt.offset)
+			if page.HasMore != tt.wantHasMore {
+				t.Errorf("HasMore = %v, want %v", page.HasMore, tt.wantHasMore)
+			}
+			if page.Total != tt.total || page.Limit != tt.limit || page.Offset != tt.offset {
+				t.Errorf("page = %+v, want total %d, limit %d, offset %d", page, tt.total, tt.limit, tt.offset)
+			}
+		})
+	}
+}
+
+func TestNewPageEncodesEmptyItems(t *testing.T) {
+	body, err := json.Marshal(NewPage[string](nil, 0, 20, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"items":[],"total":0,"limit":20,"offset":0,"has_more":false}`
+	if string(body) != want {
+		t.Errorf("body = %s, want %s", body, want)
+	}
+}
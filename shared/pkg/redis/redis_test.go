@@ -0,0 +1,111 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// recordKeys captures the arguments of every command before it is sent, so keys can
+// be checked without a Redis server
+type recordKeys struct {
+	args [][]interface{}
+}
+
+func (h *recordKeys) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	h.args = append(h.args, cmd.Args())
+	return ctx, nil
+}
+
+func (h *recordKeys) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+func (h *recordKeys) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	return ctx, nil
+}
+
+func (h *recordKeys) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+func TestClientPrefixesKeys(t *testing.T) {
+	tests := []struct {
+		name   string
+		prefix string
+		call   func(ctx context.Context, c *Client)
+		want   []interface{}
+	}{
+		{
+			name:   "Set",
+			prefix: "payment-gateway:",
+			call:   func(ctx context.Context, c *Client) { c.Set(ctx, "idempotency:abc", "1", time.Minute) },
+			want:   []interface{}{"set", "payment-gateway:idempotency:abc", "1"},
+		},
+		{
+			name:   "Get",
+			prefix: "currency-conversion:",
+			call:   func(ctx context.Context, c *Client) { c.Get(ctx, "rate:USD:EUR") },
+			want:   []interface{}{"get", "currency-conversion:rate:USD:EUR"},
+		},
+		{
+			name:   "Eval keys",
+			prefix: "payment-gateway:",
+			call: func(ctx context.Context, c *Client) {
+				c.Eval(ctx, "return 1", []string{"ratelimit:1.2.3.4"}, 5)
+			},
+			want: []interface{}{"eval", "return 1", 1, "payment-gateway:ratelimit:1.2.3.4", 5},
+		},
+		{
+			name:   "No prefix",
+			prefix: "",
+			call:   func(ctx context.Context, c *Client) { c.Delete(ctx, "quote:q1") },
+			want:   []interface{}{"del", "quote:q1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Nothing listens on port 1, so each command fails after the hook records it
+			c := NewClient(Options{Addr: "127.0.0.1:1", KeyPrefix: tt.prefix})
+			defer c.Close()
+			hook := &recordKeys{}
+			c.client.AddHook(hook)
+
+			tt.call(context.Background(), c)
+
+			if len(hook.args) == 0 {
+				t.Fatal("no command was sent")
+			}
+			got := hook.args[0]
+			for i, want := range tt.want {
+				if i >= len(got) || got[i] != want {
+					t.Fatalf("command args = %v, want prefix %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestOptionsFromEnv(t *testing.T) {
+	t.Setenv("REDIS_DB", "3")
+	opts, err := OptionsFromEnv("localhost:6379", "fraud-detection")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.DB != 3 || opts.KeyPrefix != "fraud-detection:" {
+		t.Errorf("options = %+v, want DB 3 with prefix fraud-detection:", opts)
+	}
+
+	t.Setenv("REDIS_KEY_PREFIX", "")
+	if opts, _ := OptionsFromEnv("localhost:6379", "fraud-detection"); opts.KeyPrefix != "" {
+		t.Errorf("prefix = %q, want empty when REDIS_KEY_PREFIX is set empty", opts.KeyPrefix)
+	}
+
+	t.Setenv("REDIS_DB", "-1")
+	if _, err := OptionsFromEnv("localhost:6379", "fraud-detection"); err == nil {
+		t.Error("expected an error for a negative REDIS_DB")
+	}
+}
@@ -0,0 +1,83 @@
+// shared/pkg/retry/retry.go
+package retry
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config bounds how long startup waits for a dependency. The wait between attempts
+// starts at Interval and doubles after each failure, up to MaxInterval.
+type Config struct {
+	Attempts    int
+	Interval    time.Duration
+	MaxInterval time.Duration
+}
+
+// DefaultConfig waits about 15 seconds over five attempts
+var DefaultConfig = Config{Attempts: 5, Interval: time.Second, MaxInterval: 30 * time.Second}
+
+// ConfigFromEnv reads STARTUP_CONNECT_ATTEMPTS and STARTUP_CONNECT_INTERVAL,
+// defaulting to DefaultConfig
+func ConfigFromEnv() (Config, error) {
+	config := DefaultConfig
+
+	if raw := os.Getenv("STARTUP_CONNECT_ATTEMPTS"); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil || attempts < 1 {
+			return Config{}, fmt.Errorf("STARTUP_CONNECT_ATTEMPTS must be a positive integer, got %q", raw)
+		}
+		config.Attempts = attempts
+	}
+	if raw := os.Getenv("STARTUP_CONNECT_INTERVAL"); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("STARTUP_CONNECT_INTERVAL must be a positive duration, got %q", raw)
+		}
+		config.Interval = interval
+	}
+
+	return config, nil
+}
+
+// Connect calls connect until it succeeds or the attempts run out, logging each
+// failure, and returns the last error if every attempt failed
+func Connect(config Config, logger *zap.Logger, name string, connect func() error) error {
+	attempts := config.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	wait := config.Interval
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = connect(); err == nil {
+			if attempt > 1 {
+				logger.Info("connected after retrying", zap.String("dependency", name), zap.Int("attempt", attempt))
+			}
+			return nil
+		}
+		if attempt == attempts {
+			break
+		}
+
+		logger.Warn("connection attempt failed, retrying",
+			zap.String("dependency", name),
+			zap.Int("attempt", attempt),
+			zap.Int("max_attempts", attempts),
+			zap.Duration("retry_in", wait),
+			zap.Error(err))
+		time.Sleep(wait)
+
+		wait *= 2
+		if config.MaxInterval > 0 && wait > config.MaxInterval {
+			wait = config.MaxInterval
+		}
+	}
+
+	return fmt.Errorf("failed to connect to %s after %d attempts: %w", name, attempts, err)
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func TestConnect(t *testing.T) {
+	tests := []struct {
+		name      string
+		attempts  int
+		failures  int
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "First attempt succeeds", attempts: 3, failures: 0, wantCalls: 1},
+		{name: "Succeeds after retries", attempts: 3, failures: 2, wantCalls: 3},
+		{name: "Gives up after max attempts", attempts: 3, failures: 5, wantErr: true, wantCalls: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			config := Config{Attempts: tt.attempts, Interval: time.Millisecond}
+			err := Connect(config, zap.NewNop(), "test", func() error {
+				calls++
+				if calls <= tt.failures {
+					return errors.New("connection refused")
+				}
+				return nil
+			})
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Connect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("connect called %d times, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestConnectWaitsForDelayedListener(t *testing.T) {
+	// Reserve a free port, then only start listening on it after a delay
+	reserved, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := reserved.Addr().String()
+	reserved.Close()
+
+	ready := make(chan net.Listener, 1)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			ready <- nil
+			return
+		}
+		ready <- listener
+	}()
+
+	config := Config{Attempts: 10, Interval: 10 * time.Millisecond, MaxInterval: 20 * time.Millisecond}
+	err = Connect(config, zap.NewNop(), "delayed", func() error {
+		conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	})
+
+	if listener := <-ready; listener != nil {
+		defer listener.Close()
+	} else {
+		t.Skip("could not re-listen on the reserved port")
+	}
+	if err != nil {
+		t.Errorf("Connect() error = %v, want the delayed listener to be reached", err)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("STARTUP_CONNECT_ATTEMPTS", "8")
+	t.Setenv("STARTUP_CONNECT_INTERVAL", "250ms")
+	config, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Attempts != 8 || config.Interval != 250*time.Millisecond {
+		t.Errorf("config = %+v, want 8 attempts every 250ms", config)
+	}
+
+	t.Setenv("STARTUP_CONNECT_ATTEMPTS", "0")
+	if _, err := ConfigFromEnv(); err == nil {
+		t.Error("expected an error for zero attempts")
+	}
+}